@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 
 	"path/filepath"
 
 	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/highlight"
 )
 
 var (
@@ -16,6 +18,7 @@ var (
 	debug      = flag.Bool("debug", false, "debug flag to output more parsing process detail")
 	permissive = flag.Bool("permissive", true, "permissive flag to allow the permissive parsing rather than the just documented spec")
 	unordered  = flag.Bool("unordered", false, "unordered flag to output another one without interface{}")
+	color      = flag.Bool("color", false, "color flag to output the source highlighted for the terminal instead of the JSON")
 )
 
 func run() int {
@@ -32,6 +35,19 @@ func run() int {
 		}
 	}()
 
+	if *color {
+		src, err := ioutil.ReadAll(reader)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read, err %v\n", err)
+			return 1
+		}
+		if err := highlight.ANSI(os.Stdout, src, highlight.WithFilename(filepath.Base(*proto))); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to highlight, err %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
 	got, err := protoparser.Parse(
 		reader,
 		protoparser.WithDebug(*debug),
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/breaking"
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+type importPaths []string
+
+func (p *importPaths) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *importPaths) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+var (
+	imports  importPaths
+	warnings = flag.Bool("warnings", true, "report the findings which break only the JSON compatibility")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: protobreak [flags] OLD NEW\n")
+	fmt.Fprintf(os.Stderr, "OLD and NEW are either .proto files or directories which hold .proto files.\n")
+	flag.PrintDefaults()
+}
+
+func run() int {
+	flag.Var(&imports, "I", "directory in which the imports are searched, in addition to OLD and NEW (repeatable)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		usage()
+		return 2
+	}
+
+	findings, err := compare(flag.Arg(0), flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+
+	exitCode := 0
+	for _, f := range findings {
+		if f.Severity == breaking.SeverityError {
+			exitCode = 1
+		} else if !*warnings {
+			continue
+		}
+		fmt.Println(f)
+	}
+	return exitCode
+}
+
+func compare(oldPath, newPath string) ([]*breaking.Finding, error) {
+	oldInfo, err := os.Stat(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newInfo, err := os.Stat(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !oldInfo.IsDir() && !newInfo.IsDir():
+		oldProto, err := parseFile(oldPath)
+		if err != nil {
+			return nil, err
+		}
+		newProto, err := parseFile(newPath)
+		if err != nil {
+			return nil, err
+		}
+		return breaking.Compare(oldProto, newProto), nil
+	case oldInfo.IsDir() && newInfo.IsDir():
+		oldSet, err := parseDir(oldPath)
+		if err != nil {
+			return nil, err
+		}
+		newSet, err := parseDir(newPath)
+		if err != nil {
+			return nil, err
+		}
+		return breaking.CompareFileSets(oldSet, newSet), nil
+	}
+	return nil, fmt.Errorf("%s and %s must be both files or both directories", oldPath, newPath)
+}
+
+func parseFile(path string) (*parser.Proto, error) {
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	proto, err := protoparser.Parse(reader, protoparser.WithFilename(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s, err %v", path, err)
+	}
+	return proto, nil
+}
+
+// parseDir parses all of the .proto files under the dir, along with the files they import.
+func parseDir(dir string) (*fileset.FileSet, error) {
+	var roots []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".proto") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		roots = append(roots, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := fileset.Parse(roots, fileset.WithImportPaths(append([]string{dir}, imports...)...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s, err %v", dir, err)
+	}
+	return set, nil
+}
+
+func main() {
+	os.Exit(run())
+}
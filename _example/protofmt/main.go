@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/format"
+)
+
+var (
+	write = flag.Bool("w", false, "write result to (source) file instead of stdout")
+	list  = flag.Bool("l", false, "list files whose formatting differs from protofmt's")
+	diff  = flag.Bool("d", false, "display diffs instead of rewriting files")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: protofmt [flags] [path ...]\n")
+	flag.PrintDefaults()
+}
+
+func run() int {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintf(os.Stderr, "error: cannot use -w with standard input\n")
+			return 2
+		}
+		src, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read stdin, err %v\n", err)
+			return 2
+		}
+		if err := processSource("<standard input>", src, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 2
+		}
+		return 0
+	}
+
+	exitCode := 0
+	for _, path := range flag.Args() {
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(path, ".proto") {
+				return nil
+			}
+			return processFile(path, info)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			exitCode = 2
+		}
+	}
+	return exitCode
+}
+
+func processFile(path string, info os.FileInfo) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return processSource(path, src, info)
+}
+
+// processSource formats the src. The info is nil for the standard input.
+func processSource(path string, src []byte, info os.FileInfo) error {
+	res, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("failed to format %s, err %v", path, err)
+	}
+
+	if !*list && !*write && !*diff {
+		_, err = os.Stdout.Write(res)
+		return err
+	}
+	if bytes.Equal(src, res) {
+		return nil
+	}
+
+	if *list {
+		fmt.Println(path)
+	}
+	if *write && info != nil {
+		if err := ioutil.WriteFile(path, res, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if *diff {
+		fmt.Print(unifiedDiff(path, string(src), string(res)))
+	}
+	return nil
+}
+
+func main() {
+	os.Exit(run())
+}
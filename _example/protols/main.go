@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/lsp"
+)
+
+type importPaths []string
+
+func (p *importPaths) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *importPaths) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+var imports importPaths
+
+func main() {
+	flag.Var(&imports, "I", "directory in which the imports are searched (repeatable)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: protols [flags]\n")
+		fmt.Fprintf(os.Stderr, "protols speaks the Language Server Protocol over stdin and stdout.\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	server := lsp.NewServer(lsp.WithImportPaths(imports...))
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
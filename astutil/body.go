@@ -0,0 +1,83 @@
+package astutil
+
+import (
+	"fmt"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// body is a list of the statements in a field of the parent, which the cursor modifies.
+type body struct {
+	len    func() int
+	get    func(i int) parser.Visitee
+	set    func(i int, n parser.Visitee)
+	insert func(i int, n parser.Visitee)
+	delete func(i int)
+}
+
+// visiteeBody returns the body of a field like MessageBody.
+func visiteeBody(list *[]parser.Visitee) *body {
+	return &body{
+		len: func() int { return len(*list) },
+		get: func(i int) parser.Visitee { return (*list)[i] },
+		set: func(i int, n parser.Visitee) { (*list)[i] = n },
+		insert: func(i int, n parser.Visitee) {
+			*list = append(*list, nil)
+			copy((*list)[i+1:], (*list)[i:])
+			(*list)[i] = n
+		},
+		delete: func(i int) {
+			*list = append((*list)[:i], (*list)[i+1:]...)
+		},
+	}
+}
+
+// oneofFieldsBody returns the body of the OneofFields. It panics when a statement which is not a field is put there.
+func oneofFieldsBody(oneof *parser.Oneof) *body {
+	field := func(n parser.Visitee) *parser.OneofField {
+		f, ok := n.(*parser.OneofField)
+		if !ok {
+			panic(fmt.Sprintf("astutil: %T can't be in the OneofFields", n))
+		}
+		return f
+	}
+	return &body{
+		len: func() int { return len(oneof.OneofFields) },
+		get: func(i int) parser.Visitee { return oneof.OneofFields[i] },
+		set: func(i int, n parser.Visitee) { oneof.OneofFields[i] = field(n) },
+		insert: func(i int, n parser.Visitee) {
+			f := field(n)
+			oneof.OneofFields = append(oneof.OneofFields, nil)
+			copy(oneof.OneofFields[i+1:], oneof.OneofFields[i:])
+			oneof.OneofFields[i] = f
+		},
+		delete: func(i int) {
+			oneof.OneofFields = append(oneof.OneofFields[:i], oneof.OneofFields[i+1:]...)
+		},
+	}
+}
+
+// oneofOptionsBody returns the body of the Options of the oneof. It panics when a statement which is not an option is put there.
+func oneofOptionsBody(oneof *parser.Oneof) *body {
+	option := func(n parser.Visitee) *parser.Option {
+		o, ok := n.(*parser.Option)
+		if !ok {
+			panic(fmt.Sprintf("astutil: %T can't be in the Options of the oneof", n))
+		}
+		return o
+	}
+	return &body{
+		len: func() int { return len(oneof.Options) },
+		get: func(i int) parser.Visitee { return oneof.Options[i] },
+		set: func(i int, n parser.Visitee) { oneof.Options[i] = option(n) },
+		insert: func(i int, n parser.Visitee) {
+			o := option(n)
+			oneof.Options = append(oneof.Options, nil)
+			copy(oneof.Options[i+1:], oneof.Options[i:])
+			oneof.Options[i] = o
+		},
+		delete: func(i int) {
+			oneof.Options = append(oneof.Options[:i], oneof.Options[i+1:]...)
+		},
+	}
+}
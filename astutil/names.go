@@ -0,0 +1,44 @@
+package astutil
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// TypeNameMeta returns the meta of the type name of the node which linker.Reference.Node holds, like the TypeMeta of a Field.
+// The meta is recorded only when the node is parsed with parser.WithDetailedPositions.
+func TypeNameMeta(node interface{}) meta.Meta {
+	switch n := node.(type) {
+	case *parser.Field:
+		return n.TypeMeta
+	case *parser.MapField:
+		return n.TypeMeta
+	case *parser.OneofField:
+		return n.TypeMeta
+	case *parser.RPCRequest:
+		return n.MessageTypeMeta
+	case *parser.RPCResponse:
+		return n.MessageTypeMeta
+	case *parser.Extend:
+		return n.MessageTypeMeta
+	}
+	return meta.Meta{}
+}
+
+// NameMeta returns the meta of the name of the node which linker.Symbol.Node holds, like the MessageNameMeta of a Message.
+// The meta is recorded only when the node is parsed with parser.WithDetailedPositions.
+func NameMeta(node parser.Visitee) meta.Meta {
+	switch n := node.(type) {
+	case *parser.Message:
+		return n.MessageNameMeta
+	case *parser.GroupField:
+		return n.GroupNameMeta
+	case *parser.Enum:
+		return n.EnumNameMeta
+	case *parser.Service:
+		return n.ServiceNameMeta
+	case *parser.Field:
+		return n.FieldNameMeta
+	}
+	return meta.Meta{}
+}
@@ -0,0 +1,96 @@
+package astutil
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// PathEnclosingPosition returns the path of the nodes from the root to the deepest node which covers the position,
+// like golang.org/x/tools/go/ast/astutil.PathEnclosingInterval, but in the opposite order.
+// The position is compared by its Line and Column.
+//
+// A node covers the characters from its Meta.Pos to its Meta.LastPos. The nodes are the ones which parser.Walk
+// traverses, so the comments of a node are in the node even if they are placed before it.
+// The path consists of only the root when no node covers the position.
+func PathEnclosingPosition(root *parser.Proto, pos meta.Position) []parser.Visitee {
+	return pathEnclosing(root, func(mt meta.Meta) bool {
+		return !before(pos, mt.Pos) && !before(mt.LastPos, pos)
+	})
+}
+
+// PathEnclosingOffset is like PathEnclosingPosition, but compares the byte offset with the ones of the nodes.
+func PathEnclosingOffset(root *parser.Proto, offset int) []parser.Visitee {
+	return pathEnclosing(root, func(mt meta.Meta) bool {
+		return mt.Pos.Offset <= offset && offset <= mt.LastPos.Offset
+	})
+}
+
+func pathEnclosing(root *parser.Proto, covers func(meta.Meta) bool) []parser.Visitee {
+	path := []parser.Visitee{root}
+	parser.Walk(root, func(c *parser.Cursor) bool {
+		if c.Leaving() || c.Node() == root {
+			return true
+		}
+		mt, ok := nodeMeta(c.Node())
+		// the nodes don't overlap except the ones in each other, so the deepest one is in the longest path.
+		if ok && covers(mt) && len(path) <= len(c.Parents()) {
+			path = append(append([]parser.Visitee{}, c.Parents()...), c.Node())
+		}
+		// the comments of the nodes in a node may be placed out of it, so all of the nodes are traversed.
+		return true
+	})
+	return path
+}
+
+// before reports whether the position a is before b.
+func before(a, b meta.Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+}
+
+// nodeMeta returns the Meta of the node. It returns false for the node which is not parsed, whose Meta is zero.
+func nodeMeta(node parser.Visitee) (meta.Meta, bool) {
+	var mt meta.Meta
+	switch n := node.(type) {
+	case *parser.Comment:
+		mt = n.Meta
+	case *parser.Edition:
+		mt = n.Meta
+	case *parser.EmptyStatement:
+		mt = n.Meta
+	case *parser.Enum:
+		mt = n.Meta
+	case *parser.EnumField:
+		mt = n.Meta
+	case *parser.Extend:
+		mt = n.Meta
+	case *parser.Extensions:
+		mt = n.Meta
+	case *parser.Field:
+		mt = n.Meta
+	case *parser.GroupField:
+		mt = n.Meta
+	case *parser.Import:
+		mt = n.Meta
+	case *parser.MapField:
+		mt = n.Meta
+	case *parser.Message:
+		mt = n.Meta
+	case *parser.Oneof:
+		mt = n.Meta
+	case *parser.OneofField:
+		mt = n.Meta
+	case *parser.Option:
+		mt = n.Meta
+	case *parser.Package:
+		mt = n.Meta
+	case *parser.Reserved:
+		mt = n.Meta
+	case *parser.RPC:
+		mt = n.Meta
+	case *parser.Service:
+		mt = n.Meta
+	case *parser.Syntax:
+		mt = n.Meta
+	}
+	return mt, mt.Pos.Line != 0
+}
@@ -0,0 +1,136 @@
+package astutil_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/astutil"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+func pathTypes(path []parser.Visitee) []string {
+	var types []string
+	for _, node := range path {
+		types = append(types, fmt.Sprintf("%T", node))
+	}
+	return types
+}
+
+func TestPathEnclosingPosition(t *testing.T) {
+	proto := parse(t, input)
+	lines := meta.NewLineIndex([]byte(input))
+
+	tests := []struct {
+		name   string
+		line   int
+		column int
+		want   []string
+	}{
+		{
+			name:   "syntax",
+			line:   1,
+			column: 1,
+			want:   []string{"*parser.Proto", "*parser.Syntax"},
+		},
+		{
+			name:   "a blank line",
+			line:   2,
+			column: 1,
+			want:   []string{"*parser.Proto"},
+		},
+		{
+			name:   "a comment placed before the message",
+			line:   3,
+			column: 5,
+			want:   []string{"*parser.Proto", "*parser.Message", "*parser.Comment"},
+		},
+		{
+			name:   "a message name",
+			line:   4,
+			column: 9,
+			want:   []string{"*parser.Proto", "*parser.Message"},
+		},
+		{
+			name:   "a comment placed before the field",
+			line:   5,
+			column: 5,
+			want:   []string{"*parser.Proto", "*parser.Message", "*parser.Field", "*parser.Comment"},
+		},
+		{
+			name:   "the semicolon of a field",
+			line:   6,
+			column: 22,
+			want:   []string{"*parser.Proto", "*parser.Message", "*parser.Field"},
+		},
+		{
+			name:   "an inline comment",
+			line:   7,
+			column: 22,
+			want:   []string{"*parser.Proto", "*parser.Message", "*parser.Field", "*parser.Comment"},
+		},
+		{
+			name:   "an option in a oneof",
+			line:   9,
+			column: 10,
+			want:   []string{"*parser.Proto", "*parser.Message", "*parser.Oneof", "*parser.Option"},
+		},
+		{
+			name:   "a oneof field",
+			line:   10,
+			column: 5,
+			want:   []string{"*parser.Proto", "*parser.Message", "*parser.Oneof", "*parser.OneofField"},
+		},
+		{
+			name:   "an enum field",
+			line:   13,
+			column: 10,
+			want:   []string{"*parser.Proto", "*parser.Message", "*parser.Enum", "*parser.EnumField"},
+		},
+		{
+			name:   "the right curly of the message",
+			line:   16,
+			column: 1,
+			want:   []string{"*parser.Proto", "*parser.Message"},
+		},
+		{
+			name:   "beyond the end of the file",
+			line:   100,
+			column: 1,
+			want:   []string{"*parser.Proto"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := pathTypes(astutil.PathEnclosingPosition(proto, meta.Position{Line: test.line, Column: test.column}))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, but want %v", got, test.want)
+			}
+
+			if lines.LineCount() < test.line {
+				return
+			}
+			offset := lines.Offset(test.line, test.column)
+			got = pathTypes(astutil.PathEnclosingOffset(proto, offset))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v at the offset %d, but want %v", got, offset, test.want)
+			}
+		})
+	}
+}
+
+func TestPathEnclosingPosition_node(t *testing.T) {
+	proto := parse(t, input)
+
+	path := astutil.PathEnclosingPosition(proto, meta.Position{Line: 10, Column: 11})
+	field, ok := path[len(path)-1].(*parser.OneofField)
+	if !ok || field.FieldName != "a" {
+		t.Errorf("got %v, but want the field a", path[len(path)-1])
+	}
+	if path[0] != proto {
+		t.Errorf("got %v, but want the root", path[0])
+	}
+}
@@ -0,0 +1,224 @@
+// Package astutil rewrites and queries the parser's nodes, like golang.org/x/tools/go/ast/astutil.
+package astutil
+
+import (
+	"fmt"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// An ApplyFunc is invoked by Apply for each statement before and/or after the statements in it,
+// using a Cursor describing the current statement and providing operations on it.
+//
+// The return value of ApplyFunc controls the syntax tree traversal. See Apply for details.
+type ApplyFunc func(*Cursor) bool
+
+// Apply traverses the statements of the root recursively, starting with the root,
+// and calling pre and post for each statement:
+//
+// If pre is not nil, it is called for each statement before the statements in it are traversed (pre-order).
+// If pre returns false, no statements in it are traversed, and post is not called for it.
+//
+// If post is not nil, and a prior call of pre didn't return false, post is called for each statement
+// after the statements in it are traversed (post-order). If post returns false, the traversal is terminated
+// and Apply returns immediately.
+//
+// The statements are the Syntax, the Edition and the elements of ProtoBody, MessageBody, EnumBody, ServiceBody,
+// ExtendBody, and OneofFields and Options of a Oneof. The comments attached to a statement, like Comments and
+// InlineComment, are not traversed, and they move together with the statement.
+//
+// Only the fields of the statements in the bodies may be modified through the Cursor while traversing.
+// Apply returns the root, which is the new one if it's replaced.
+func Apply(root parser.Visitee, pre, post ApplyFunc) (result parser.Visitee) {
+	parent := &rootHolder{node: root}
+	defer func() {
+		if r := recover(); r != nil && r != abort {
+			panic(r)
+		}
+		result = parent.node
+	}()
+
+	a := &application{pre: pre, post: post}
+	a.apply(parent, "Node", nil, root)
+	return
+}
+
+var abort = new(int) // singleton, to signal termination of Apply
+
+// rootHolder is the parent of the root, which holds the root replaced by the cursor.
+type rootHolder struct {
+	node parser.Visitee
+}
+
+// Accept implements parser.Visitee. It visits nothing, because the holder is not a statement.
+func (*rootHolder) Accept(parser.Visitor) {}
+
+// A Cursor describes a statement encountered during Apply.
+// Information about the statement and its parent is available from the Node, Parent, Name and Index methods.
+//
+// The methods Replace, Delete, InsertBefore and InsertAfter can be used to change the syntax tree.
+type Cursor struct {
+	parent parser.Visitee
+	name   string
+	iter   *iterator // valid if the statement is the element of a body
+	node   parser.Visitee
+}
+
+// Node returns the current statement.
+func (c *Cursor) Node() parser.Visitee { return c.node }
+
+// Parent returns the parent of the current statement, like *parser.Message.
+// It returns nil for the root.
+func (c *Cursor) Parent() parser.Visitee {
+	if _, ok := c.parent.(*rootHolder); ok {
+		return nil
+	}
+	return c.parent
+}
+
+// Name returns the name of the parent field which contains the current statement, like "MessageBody".
+// It returns "Node" for the root.
+func (c *Cursor) Name() string { return c.name }
+
+// Index reports the index of the current statement in the body of the parent, or a value < 0 if it's not in a body.
+func (c *Cursor) Index() int {
+	if c.iter != nil {
+		return c.iter.index
+	}
+	return -1
+}
+
+// Replace replaces the current statement with n. The replacement statement is not traversed by Apply.
+// It panics when n can't be placed there, like a *parser.Field in the Options of a Oneof.
+func (c *Cursor) Replace(n parser.Visitee) {
+	if c.iter != nil {
+		c.iter.list.set(c.iter.index, n)
+		c.node = n
+		return
+	}
+
+	switch p := c.parent.(type) {
+	case *rootHolder:
+		p.node = n
+	case *parser.Proto:
+		switch c.name {
+		case "Syntax":
+			s, ok := n.(*parser.Syntax)
+			if !ok {
+				panic(fmt.Sprintf("astutil: %T can't be the Syntax", n))
+			}
+			p.Syntax = s
+		case "Edition":
+			e, ok := n.(*parser.Edition)
+			if !ok {
+				panic(fmt.Sprintf("astutil: %T can't be the Edition", n))
+			}
+			p.Edition = e
+		}
+	}
+	c.node = n
+}
+
+// Delete deletes the current statement from its body.
+// If the current statement is not in a body, Delete panics.
+func (c *Cursor) Delete() {
+	i := c.index("Delete")
+	c.iter.list.delete(i)
+	c.iter.step--
+}
+
+// InsertAfter inserts n after the current statement in its body.
+// If the current statement is not in a body, InsertAfter panics.
+// Apply does not walk n.
+func (c *Cursor) InsertAfter(n parser.Visitee) {
+	i := c.index("InsertAfter")
+	c.iter.list.insert(i+1, n)
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current statement in its body.
+// If the current statement is not in a body, InsertBefore panics.
+// Apply does not walk n.
+func (c *Cursor) InsertBefore(n parser.Visitee) {
+	i := c.index("InsertBefore")
+	c.iter.list.insert(i, n)
+	c.iter.index++
+}
+
+func (c *Cursor) index(method string) int {
+	if c.iter == nil {
+		panic(fmt.Sprintf("astutil: %s on a statement which is not in a body", method))
+	}
+	return c.iter.index
+}
+
+// iterator is the state of the traversal over a body.
+type iterator struct {
+	list  *body
+	index int
+	step  int
+}
+
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+}
+
+func (a *application) apply(parent parser.Visitee, name string, iter *iterator, n parser.Visitee) {
+	saved := a.cursor
+	a.cursor.parent = parent
+	a.cursor.name = name
+	a.cursor.iter = iter
+	a.cursor.node = n
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		a.cursor = saved
+		return
+	}
+
+	// the node may be replaced by pre.
+	n = a.cursor.node
+	switch n := n.(type) {
+	case *parser.Proto:
+		if n.Syntax != nil {
+			a.apply(n, "Syntax", nil, n.Syntax)
+		}
+		if n.Edition != nil {
+			a.apply(n, "Edition", nil, n.Edition)
+		}
+		a.applyList(n, "ProtoBody", visiteeBody(&n.ProtoBody))
+	case *parser.Message:
+		a.applyList(n, "MessageBody", visiteeBody(&n.MessageBody))
+	case *parser.GroupField:
+		a.applyList(n, "MessageBody", visiteeBody(&n.MessageBody))
+	case *parser.Enum:
+		a.applyList(n, "EnumBody", visiteeBody(&n.EnumBody))
+	case *parser.Service:
+		a.applyList(n, "ServiceBody", visiteeBody(&n.ServiceBody))
+	case *parser.Extend:
+		a.applyList(n, "ExtendBody", visiteeBody(&n.ExtendBody))
+	case *parser.Oneof:
+		a.applyList(n, "OneofFields", oneofFieldsBody(n))
+		a.applyList(n, "Options", oneofOptionsBody(n))
+	}
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(abort)
+	}
+
+	a.cursor = saved
+}
+
+func (a *application) applyList(parent parser.Visitee, name string, list *body) {
+	// a.iter is reused for the bodies, and restored after the nested ones.
+	saved := a.iter
+	a.iter.list = list
+	a.iter.index = 0
+	for a.iter.index < list.len() {
+		a.iter.step = 1
+		a.apply(parent, name, &a.iter, list.get(a.iter.index))
+		a.iter.index += a.iter.step
+	}
+	a.iter = saved
+}
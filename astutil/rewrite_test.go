@@ -0,0 +1,315 @@
+package astutil_test
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/astutil"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/printer"
+)
+
+const input = `syntax = "proto3";
+
+// Outer is a message.
+message Outer {
+  // the name
+  string userName = 1;
+  string old = 2; // to be removed
+  oneof choice {
+    option (o) = true;
+    int32 a = 3;
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+    KIND_OLD = 1;
+  }
+}
+`
+
+func parse(t *testing.T, input string) *parser.Proto {
+	t.Helper()
+	proto, err := protoparser.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return proto
+}
+
+func printProto(t *testing.T, node parser.Visitee) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := printer.Fprint(&b, node); err != nil {
+		t.Fatalf("failed to print: %v", err)
+	}
+	return b.String()
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name string
+		pre  astutil.ApplyFunc
+		post astutil.ApplyFunc
+		want string
+	}{
+		{
+			name: "renaming the fields and adding the options",
+			pre: func(c *astutil.Cursor) bool {
+				if f, ok := c.Node().(*parser.Field); ok && f.FieldName == "userName" {
+					f.FieldName = "user_name"
+					f.FieldOptions = append(f.FieldOptions, &parser.FieldOption{OptionName: "json_name", Constant: `"userName"`})
+				}
+				return true
+			},
+			want: `syntax = "proto3";
+
+// Outer is a message.
+message Outer {
+  // the name
+  string user_name = 1 [json_name = "userName"];
+  string old = 2; // to be removed
+  oneof choice {
+    option (o) = true;
+    int32 a = 3;
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+    KIND_OLD = 1;
+  }
+}
+`,
+		},
+		{
+			name: "deleting the statements and inserting the reserved ones",
+			pre: func(c *astutil.Cursor) bool {
+				switch n := c.Node().(type) {
+				case *parser.Field:
+					if n.FieldName == "old" {
+						c.InsertBefore(&parser.Reserved{Ranges: []*parser.Range{{Begin: "2"}}})
+						c.InsertAfter(&parser.Reserved{FieldNames: []string{`"old"`}})
+						c.Delete()
+					}
+				case *parser.EnumField:
+					if n.Ident == "KIND_OLD" {
+						c.Delete()
+					}
+				}
+				return true
+			},
+			want: `syntax = "proto3";
+
+// Outer is a message.
+message Outer {
+  // the name
+  string userName = 1;
+  reserved 2;
+  reserved "old";
+  oneof choice {
+    option (o) = true;
+    int32 a = 3;
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+  }
+}
+`,
+		},
+		{
+			name: "inserting the fields into the oneof",
+			pre: func(c *astutil.Cursor) bool {
+				if f, ok := c.Node().(*parser.OneofField); ok && f.FieldName == "a" {
+					c.InsertAfter(&parser.OneofField{Type: "string", FieldName: "b", FieldNumber: "4"})
+				}
+				return true
+			},
+			want: `syntax = "proto3";
+
+// Outer is a message.
+message Outer {
+  // the name
+  string userName = 1;
+  string old = 2; // to be removed
+  oneof choice {
+    option (o) = true;
+    int32 a = 3;
+    string b = 4;
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+    KIND_OLD = 1;
+  }
+}
+`,
+		},
+		{
+			name: "replacing the statements and keeping their comments",
+			post: func(c *astutil.Cursor) bool {
+				if m, ok := c.Node().(*parser.Message); ok {
+					c.Replace(&parser.Message{
+						MessageName: "Renamed",
+						MessageBody: m.MessageBody[:1],
+						Comments:    m.Comments,
+					})
+				}
+				return true
+			},
+			want: `syntax = "proto3";
+
+// Outer is a message.
+message Renamed {
+  // the name
+  string userName = 1;
+}
+`,
+		},
+		{
+			name: "skipping and terminating the traversal",
+			pre: func(c *astutil.Cursor) bool {
+				if _, ok := c.Node().(*parser.Oneof); ok {
+					return false
+				}
+				if f, ok := c.Node().(*parser.OneofField); ok {
+					f.FieldName = "skipped"
+				}
+				return true
+			},
+			post: func(c *astutil.Cursor) bool {
+				if f, ok := c.Node().(*parser.Field); ok {
+					f.FieldName = strings.ToUpper(f.FieldName)
+					return false
+				}
+				return true
+			},
+			want: `syntax = "proto3";
+
+// Outer is a message.
+message Outer {
+  // the name
+  string USERNAME = 1;
+  string old = 2; // to be removed
+  oneof choice {
+    option (o) = true;
+    int32 a = 3;
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+    KIND_OLD = 1;
+  }
+}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			proto := parse(t, input)
+			got := astutil.Apply(proto, test.pre, test.post)
+			if got != proto {
+				t.Errorf("got %v, but want the root", got)
+			}
+			if printProto(t, got) != test.want {
+				t.Errorf("got %s, but want %s", printProto(t, got), test.want)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	proto := parse(t, input)
+
+	var got []string
+	astutil.Apply(proto, func(c *astutil.Cursor) bool {
+		parent := "<nil>"
+		switch p := c.Parent().(type) {
+		case *parser.Proto:
+			parent = "Proto"
+		case *parser.Message:
+			parent = p.MessageName
+		case *parser.Oneof:
+			parent = p.OneofName
+		case *parser.Enum:
+			parent = p.EnumName
+		}
+		got = append(got, parent+"."+c.Name()+"["+strconv.Itoa(c.Index())+"]")
+		return true
+	}, nil)
+
+	want := []string{
+		"<nil>.Node[-1]",
+		"Proto.Syntax[-1]",
+		"Proto.ProtoBody[0]",
+		"Outer.MessageBody[0]",
+		"Outer.MessageBody[1]",
+		"Outer.MessageBody[2]",
+		"choice.OneofFields[0]",
+		"choice.Options[0]",
+		"Outer.MessageBody[3]",
+		"Kind.EnumBody[0]",
+		"Kind.EnumBody[1]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+}
+
+func TestApply_replaceRoot(t *testing.T) {
+	proto := parse(t, input)
+	message := proto.ProtoBody[0]
+
+	got := astutil.Apply(message, func(c *astutil.Cursor) bool {
+		if c.Parent() == nil {
+			c.Replace(&parser.Enum{EnumName: "E"})
+			return false
+		}
+		return true
+	}, nil)
+	if e, ok := got.(*parser.Enum); !ok || e.EnumName != "E" {
+		t.Errorf("got %v, but want the enum E", got)
+	}
+}
+
+func TestApply_panic(t *testing.T) {
+	tests := []struct {
+		name string
+		pre  astutil.ApplyFunc
+		want string
+	}{
+		{
+			name: "deleting the statement which is not in a body",
+			pre: func(c *astutil.Cursor) bool {
+				if _, ok := c.Node().(*parser.Syntax); ok {
+					c.Delete()
+				}
+				return true
+			},
+			want: "astutil: Delete on a statement which is not in a body",
+		},
+		{
+			name: "inserting a field into the options of a oneof",
+			pre: func(c *astutil.Cursor) bool {
+				if _, ok := c.Node().(*parser.Option); ok {
+					c.InsertAfter(&parser.Field{})
+				}
+				return true
+			},
+			want: "astutil: *parser.Field can't be in the Options of the oneof",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if got := recover(); got != test.want {
+					t.Errorf("got %v, but want %v", got, test.want)
+				}
+			}()
+			astutil.Apply(parse(t, input), test.pre, nil)
+		})
+	}
+}
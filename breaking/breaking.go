@@ -0,0 +1,284 @@
+// Package breaking detects the changes which break the wire or the JSON compatibility between two versions of a schema.
+//
+// The definitions are matched by their fully-qualified names, so moving a definition between the files is not a change.
+// The fields and the enum values are matched by their numbers, which identify them on the wire.
+package breaking
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// Compare compares the old proto with the new one and returns the findings in the order of the positions in the new one,
+// followed by the ones of the removed elements.
+// The type names are resolved within each proto, and the ones defined in the imported files are compared as they are written.
+func Compare(oldProto, newProto *parser.Proto) []*Finding {
+	return compare(protoSchema(oldProto), protoSchema(newProto))
+}
+
+// CompareFileSets compares all of the files in the old set with the ones in the new set.
+// The type names are resolved in each set.
+func CompareFileSets(oldSet, newSet *fileset.FileSet) []*Finding {
+	return compare(setSchema(oldSet), setSchema(newSet))
+}
+
+func protoSchema(proto *parser.Proto) *schema {
+	path := ""
+	if proto.Meta != nil {
+		path = proto.Meta.Filename
+	}
+	files := []*fileset.File{{Path: path, Filename: path, Proto: proto}}
+	// the unresolved names are kept as they are written.
+	table, _ := linker.Link(files)
+	return newSchema(files, table)
+}
+
+func setSchema(set *fileset.FileSet) *schema {
+	files := set.Sorted()
+	table, _ := linker.Link(files)
+	return newSchema(files, table)
+}
+
+type comparator struct {
+	findings []*Finding
+}
+
+func (c *comparator) report(finding *Finding, format string, args ...interface{}) {
+	finding.Message = fmt.Sprintf(format, args...)
+	c.findings = append(c.findings, finding)
+}
+
+func compare(oldSchema, newSchema *schema) []*Finding {
+	c := &comparator{}
+	for _, name := range sorted(oldSchema.messageNames()) {
+		old := oldSchema.messages[name]
+		m, ok := newSchema.messages[name]
+		if !ok {
+			c.report(&Finding{Kind: KindMessageRemoved, Severity: SeverityError, Name: name, OldPos: old.pos},
+				"message %q is removed", name)
+			continue
+		}
+		c.message(old, m)
+	}
+	for _, name := range sorted(oldSchema.enumNames()) {
+		old := oldSchema.enums[name]
+		e, ok := newSchema.enums[name]
+		if !ok {
+			c.report(&Finding{Kind: KindEnumRemoved, Severity: SeverityError, Name: name, OldPos: old.pos},
+				"enum %q is removed", name)
+			continue
+		}
+		c.enum(old, e)
+	}
+	for _, name := range sorted(oldSchema.serviceNames()) {
+		old := oldSchema.services[name]
+		s, ok := newSchema.services[name]
+		if !ok {
+			c.report(&Finding{Kind: KindServiceRemoved, Severity: SeverityError, Name: name, OldPos: old.pos},
+				"service %q is removed", name)
+			continue
+		}
+		c.service(old, s)
+	}
+
+	sort.SliceStable(c.findings, func(i, j int) bool {
+		a, b := c.findings[i].NewPos, c.findings[j].NewPos
+		if (a.Line == 0) != (b.Line == 0) {
+			return b.Line == 0
+		}
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return c.findings
+}
+
+// sorted returns the names in order, so that the findings are reported in a stable order.
+func sorted(names []string) []string {
+	sort.Strings(names)
+	return names
+}
+
+func (c *comparator) message(old, m *message) {
+	byNumber := make(map[int]*field)
+	byName := make(map[string]*field)
+	for _, f := range m.fields {
+		byNumber[f.number] = f
+		byName[f.name] = f
+	}
+
+	for _, of := range old.fields {
+		name := join(old.fullName, of.name)
+		nf, ok := byNumber[of.number]
+		if !ok {
+			if renumbered, ok := byName[of.name]; ok {
+				c.report(&Finding{Kind: KindFieldRenumbered, Severity: SeverityError, Name: name, OldPos: of.pos, NewPos: renumbered.pos},
+					"field %q is renumbered from %d to %d", name, of.number, renumbered.number)
+				continue
+			}
+			if m.reserved.hasNumber(of.number) {
+				// the number can't be reused, so removing it is safe on the wire.
+				continue
+			}
+			c.report(&Finding{Kind: KindFieldRemoved, Severity: SeverityError, Name: name, OldPos: of.pos},
+				"field %q (%d) is removed without reserving the number", name, of.number)
+			continue
+		}
+
+		if nf.name != of.name {
+			c.report(&Finding{Kind: KindFieldRenamed, Severity: SeverityError, Name: name, OldPos: of.pos, NewPos: nf.pos},
+				"field %d is renamed from %q to %q", of.number, of.name, nf.name)
+		}
+		if nf.typ != of.typ {
+			severity := SeverityError
+			if nf.kind == of.kind {
+				severity = SeverityWarning
+			}
+			c.report(&Finding{Kind: KindFieldTypeChanged, Severity: severity, Name: name, OldPos: of.pos, NewPos: nf.pos},
+				"field %q changes the type from %q to %q", name, of.typ, nf.typ)
+		}
+		if nf.label != of.label {
+			severity := SeverityWarning
+			if isWireLabel(of.label) || isWireLabel(nf.label) {
+				severity = SeverityError
+			}
+			c.report(&Finding{Kind: KindFieldLabelChanged, Severity: severity, Name: name, OldPos: of.pos, NewPos: nf.pos},
+				"field %q changes the label from %q to %q", name, of.label, nf.label)
+		}
+		if nf.oneof != of.oneof {
+			c.report(&Finding{Kind: KindFieldOneofChanged, Severity: SeverityError, Name: name, OldPos: of.pos, NewPos: nf.pos},
+				"field %q moves from %s to %s", name, oneofText(of.oneof), oneofText(nf.oneof))
+		}
+	}
+
+	for _, nf := range m.fields {
+		name := join(m.fullName, nf.name)
+		if old.reserved.hasNumber(nf.number) {
+			c.report(&Finding{Kind: KindReservedReused, Severity: SeverityError, Name: name, NewPos: nf.pos},
+				"field %q uses the number %d, which is reserved in the old message", name, nf.number)
+		}
+		if old.reserved.names[nf.name] {
+			c.report(&Finding{Kind: KindReservedReused, Severity: SeverityWarning, Name: name, NewPos: nf.pos},
+				"field %q uses the name reserved in the old message", name)
+		}
+	}
+}
+
+// isWireLabel reports whether changing the label changes the encoding or the validity on the wire.
+func isWireLabel(label string) bool {
+	return label == "repeated" || label == "required"
+}
+
+func oneofText(oneof string) string {
+	if oneof == "" {
+		return "no oneof"
+	}
+	return fmt.Sprintf("oneof %q", oneof)
+}
+
+func (c *comparator) enum(old, e *enum) {
+	byNumber := make(map[int]*enumValue)
+	for _, v := range e.values {
+		if _, ok := byNumber[v.number]; !ok {
+			byNumber[v.number] = v
+		}
+	}
+	names := make(map[string]bool)
+	for _, v := range e.values {
+		names[v.name] = true
+	}
+
+	for _, ov := range old.values {
+		name := join(old.fullName, ov.name)
+		nv, ok := byNumber[ov.number]
+		if !ok {
+			if e.reserved.hasNumber(ov.number) {
+				continue
+			}
+			c.report(&Finding{Kind: KindEnumValueRemoved, Severity: SeverityError, Name: name, OldPos: ov.pos},
+				"enum value %q (%d) is removed without reserving the number", name, ov.number)
+			continue
+		}
+		if !names[ov.name] {
+			// the name is used in JSON, unless it's kept as an alias.
+			c.report(&Finding{Kind: KindEnumValueRenamed, Severity: SeverityWarning, Name: name, OldPos: ov.pos, NewPos: nv.pos},
+				"enum value %d is renamed from %q to %q", ov.number, ov.name, nv.name)
+		}
+	}
+
+	for _, nv := range e.values {
+		name := join(e.fullName, nv.name)
+		if old.reserved.hasNumber(nv.number) {
+			c.report(&Finding{Kind: KindReservedReused, Severity: SeverityError, Name: name, NewPos: nv.pos},
+				"enum value %q uses the number %d, which is reserved in the old enum", name, nv.number)
+		}
+		if old.reserved.names[nv.name] {
+			c.report(&Finding{Kind: KindReservedReused, Severity: SeverityWarning, Name: name, NewPos: nv.pos},
+				"enum value %q uses the name reserved in the old enum", name)
+		}
+	}
+}
+
+func (c *comparator) service(old, s *service) {
+	byName := make(map[string]*rpc)
+	for _, r := range s.rpcs {
+		byName[r.name] = r
+	}
+	oldNames := make(map[string]bool)
+	for _, r := range old.rpcs {
+		oldNames[r.name] = true
+	}
+	// added are the RPCs which are not in the old one. They may be the renamed ones.
+	var added []*rpc
+	for _, r := range s.rpcs {
+		if !oldNames[r.name] {
+			added = append(added, r)
+		}
+	}
+
+	for _, or := range old.rpcs {
+		name := join(old.fullName, or.name)
+		nr, ok := byName[or.name]
+		if !ok {
+			if renamed := takeSameSignature(&added, or); renamed != nil {
+				c.report(&Finding{Kind: KindRPCRenamed, Severity: SeverityError, Name: name, OldPos: or.pos, NewPos: renamed.pos},
+					"rpc %q is renamed to %q", name, renamed.name)
+				continue
+			}
+			c.report(&Finding{Kind: KindRPCRemoved, Severity: SeverityError, Name: name, OldPos: or.pos},
+				"rpc %q is removed", name)
+			continue
+		}
+		if nr.request != or.request || nr.response != or.response {
+			c.report(&Finding{Kind: KindRPCTypeChanged, Severity: SeverityError, Name: name, OldPos: or.pos, NewPos: nr.pos},
+				"rpc %q changes the types from (%s) returns (%s) to (%s) returns (%s)",
+				name, or.request, or.response, nr.request, nr.response)
+		}
+		if nr.clientStreaming != or.clientStreaming {
+			c.report(&Finding{Kind: KindRPCStreamingChanged, Severity: SeverityError, Name: name, OldPos: or.pos, NewPos: nr.pos},
+				"rpc %q changes the request streaming from %t to %t", name, or.clientStreaming, nr.clientStreaming)
+		}
+		if nr.serverStreaming != or.serverStreaming {
+			c.report(&Finding{Kind: KindRPCStreamingChanged, Severity: SeverityError, Name: name, OldPos: or.pos, NewPos: nr.pos},
+				"rpc %q changes the response streaming from %t to %t", name, or.serverStreaming, nr.serverStreaming)
+		}
+	}
+}
+
+// takeSameSignature removes the first RPC which has the same request and response as the old one from the list,
+// and returns it. It returns nil when there is no such RPC.
+func takeSameSignature(rpcs *[]*rpc, old *rpc) *rpc {
+	for i, r := range *rpcs {
+		if r.request == old.request && r.response == old.response &&
+			r.clientStreaming == old.clientStreaming && r.serverStreaming == old.serverStreaming {
+			*rpcs = append((*rpcs)[:i], (*rpcs)[i+1:]...)
+			return r
+		}
+	}
+	return nil
+}
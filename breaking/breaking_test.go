@@ -0,0 +1,420 @@
+package breaking_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/breaking"
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+func parseProto(t *testing.T, filename, input string) *parser.Proto {
+	t.Helper()
+	proto, err := protoparser.Parse(strings.NewReader(input), protoparser.WithFilename(filename))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return proto
+}
+
+// summary returns the kind, the severity and the lines of the positions in the old and the new file.
+func summary(findings []*breaking.Finding) []string {
+	var got []string
+	for _, f := range findings {
+		got = append(got, fmt.Sprintf("%s %s %d %d", f.Kind, f.Severity, f.OldPos.Line, f.NewPos.Line))
+	}
+	return got
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldInput string
+		newInput string
+		want     []string
+	}{
+		{
+			name: "no changes",
+			oldInput: `syntax = "proto3";
+message A {
+  string a = 1;
+}
+`,
+			newInput: `syntax = "proto3";
+// A is a message.
+message A {
+  string a = 1;
+}
+`,
+		},
+		{
+			name: "removed and renumbered fields",
+			oldInput: `syntax = "proto3";
+message A {
+  string a = 1;
+  string b = 2;
+  string c = 3;
+  string d = 4;
+}
+`,
+			newInput: `syntax = "proto3";
+message A {
+  string a = 1;
+  string b = 5;
+  reserved 3;
+}
+`,
+			want: []string{
+				"FIELD_RENUMBERED error 4 4",
+				"FIELD_REMOVED error 6 0",
+			},
+		},
+		{
+			name: "removed fields into the hexadecimal reserved range",
+			oldInput: `syntax = "proto3";
+message A {
+  string a = 1;
+  string b = 16;
+}
+`,
+			newInput: `syntax = "proto3";
+message A {
+  string a = 1;
+  reserved 0x10 to 0x20;
+}
+`,
+		},
+		{
+			name: "changed names, types and labels",
+			oldInput: `syntax = "proto3";
+message A {
+  string a = 1;
+  int32 b = 2;
+  int32 c = 3;
+  int32 d = 4;
+  optional int32 e = 5;
+}
+`,
+			newInput: `syntax = "proto3";
+message A {
+  string renamed = 1;
+  uint64 b = 2;
+  sint32 c = 3;
+  repeated int32 d = 4;
+  int32 e = 5;
+}
+`,
+			want: []string{
+				"FIELD_RENAMED error 3 3",
+				"FIELD_TYPE_CHANGED warning 4 4",
+				"FIELD_TYPE_CHANGED error 5 5",
+				"FIELD_LABEL_CHANGED error 6 6",
+				"FIELD_LABEL_CHANGED warning 7 7",
+			},
+		},
+		{
+			name: "resolved message types",
+			oldInput: `syntax = "proto3";
+message A {
+  B b = 1;
+  message B {}
+}
+message B {}
+`,
+			newInput: `syntax = "proto3";
+message A {
+  .B b = 1;
+  message B {}
+}
+message B {}
+`,
+			want: []string{
+				"FIELD_TYPE_CHANGED error 3 3",
+			},
+		},
+		{
+			name: "fields moved into and out of oneofs",
+			oldInput: `syntax = "proto3";
+message A {
+  string a = 1;
+  oneof choice {
+    string b = 2;
+  }
+}
+`,
+			newInput: `syntax = "proto3";
+message A {
+  oneof choice {
+    string a = 1;
+  }
+  string b = 2;
+}
+`,
+			want: []string{
+				"FIELD_ONEOF_CHANGED error 3 4",
+				"FIELD_ONEOF_CHANGED error 5 6",
+			},
+		},
+		{
+			name: "removed and renamed enum values",
+			oldInput: `syntax = "proto3";
+enum E {
+  E_UNSPECIFIED = 0;
+  E_A = 1;
+  E_B = 2;
+  E_C = 3;
+}
+`,
+			newInput: `syntax = "proto3";
+enum E {
+  E_UNSPECIFIED = 0;
+  E_RENAMED = 1;
+  reserved 3;
+}
+`,
+			want: []string{
+				"ENUM_VALUE_RENAMED warning 4 4",
+				"ENUM_VALUE_REMOVED error 5 0",
+			},
+		},
+		{
+			name: "enum value aliases",
+			oldInput: `syntax = "proto3";
+enum E {
+  option allow_alias = true;
+  E_UNSPECIFIED = 0;
+  E_A = 1;
+}
+`,
+			newInput: `syntax = "proto3";
+enum E {
+  option allow_alias = true;
+  E_UNSPECIFIED = 0;
+  E_B = 1;
+  E_A = 1;
+}
+`,
+		},
+		{
+			name: "changed RPCs",
+			oldInput: `syntax = "proto3";
+message Req {}
+message Res {}
+service S {
+  rpc Get(Req) returns (Res);
+  rpc List(Req) returns (stream Res);
+  rpc Put(Req) returns (Res);
+  rpc Watch(Req) returns (Req);
+  rpc Delete(Res) returns (Res);
+}
+`,
+			newInput: `syntax = "proto3";
+message Req {}
+message Res {}
+service S {
+  rpc Fetch(Req) returns (Res);
+  rpc List(stream Req) returns (Res);
+  rpc Put(Req) returns (Req);
+}
+`,
+			want: []string{
+				"RPC_RENAMED error 5 5",
+				"RPC_STREAMING_CHANGED error 6 6",
+				"RPC_STREAMING_CHANGED error 6 6",
+				"RPC_TYPE_CHANGED error 7 7",
+				"RPC_REMOVED error 8 0",
+				"RPC_REMOVED error 9 0",
+			},
+		},
+		{
+			name: "reused reserved numbers and names",
+			oldInput: `syntax = "proto3";
+message A {
+  reserved 2, 10 to max;
+  reserved "b";
+}
+enum E {
+  E_UNSPECIFIED = 0;
+  reserved 1;
+  reserved "E_B";
+}
+`,
+			newInput: `syntax = "proto3";
+message A {
+  string a = 2;
+  string b = 3;
+}
+enum E {
+  E_UNSPECIFIED = 0;
+  E_A = 1;
+  E_B = 2;
+}
+`,
+			want: []string{
+				"RESERVED_REUSED error 0 3",
+				"RESERVED_REUSED warning 0 4",
+				"RESERVED_REUSED error 0 8",
+				"RESERVED_REUSED warning 0 9",
+			},
+		},
+		{
+			name: "removed definitions",
+			oldInput: `syntax = "proto3";
+package foo;
+message A {
+  message B {}
+}
+enum E {
+  E_UNSPECIFIED = 0;
+}
+service S {}
+`,
+			newInput: `syntax = "proto3";
+package foo;
+message A {}
+`,
+			want: []string{
+				"MESSAGE_REMOVED error 4 0",
+				"ENUM_REMOVED error 6 0",
+				"SERVICE_REMOVED error 9 0",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			oldProto := parseProto(t, "old.proto", test.oldInput)
+			newProto := parseProto(t, "new.proto", test.newInput)
+
+			got := summary(breaking.Compare(oldProto, newProto))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, but want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCompare_unparsableNumbers(t *testing.T) {
+	oldProto := parseProto(t, "old.proto", `syntax = "proto2";
+enum E {
+  E_UNSPECIFIED = 0;
+  E_BROKEN = 1;
+}
+`)
+	newProto := parseProto(t, "new.proto", `syntax = "proto2";
+enum E {
+  E_UNSPECIFIED = 0;
+  E_NEGATIVE = -1;
+}
+`)
+	// the value whose number can't be parsed is not mistaken for the one numbered -1.
+	oldProto.ProtoBody[0].(*parser.Enum).EnumBody[1].(*parser.EnumField).Number = "broken"
+
+	got := summary(breaking.Compare(oldProto, newProto))
+	if len(got) != 0 {
+		t.Errorf("got %v, but want no findings", got)
+	}
+}
+
+func TestFinding_String(t *testing.T) {
+	oldProto := parseProto(t, "old.proto", `syntax = "proto3";
+package foo;
+message A {
+  string a = 1;
+  string b = 2;
+}
+`)
+	newProto := parseProto(t, "new.proto", `syntax = "proto3";
+package foo;
+message A {
+  string renamed = 1;
+}
+`)
+
+	var got []string
+	for _, f := range breaking.Compare(oldProto, newProto) {
+		got = append(got, f.String())
+	}
+	want := []string{
+		`new.proto:4:3: error: field 1 is renamed from "a" to "renamed" [FIELD_RENAMED]`,
+		`old.proto:5:3: error: field "foo.A.b" (2) is removed without reserving the number [FIELD_REMOVED]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+}
+
+func parseFileSet(t *testing.T, files map[string]string, roots ...string) *fileset.FileSet {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	set, err := fileset.Parse(roots, fileset.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return set
+}
+
+func TestCompareFileSets(t *testing.T) {
+	oldSet := parseFileSet(t, map[string]string{
+		"a.proto": `syntax = "proto3";
+package foo;
+import "b.proto";
+message A {
+  bar.B b = 1;
+  bar.E e = 2;
+}
+`,
+		"b.proto": `syntax = "proto3";
+package bar;
+message B {}
+message C {}
+enum E {
+  E_UNSPECIFIED = 0;
+}
+`,
+	}, "a.proto")
+	// C is moved into another file, and the type of A.e is changed from the enum to the message.
+	newSet := parseFileSet(t, map[string]string{
+		"a.proto": `syntax = "proto3";
+package foo;
+import "b.proto";
+import "c.proto";
+message A {
+  bar.B b = 1;
+  bar.C e = 2;
+}
+`,
+		"b.proto": `syntax = "proto3";
+package bar;
+message B {}
+enum E {
+  E_UNSPECIFIED = 0;
+}
+`,
+		"c.proto": `syntax = "proto3";
+package bar;
+message C {}
+`,
+	}, "a.proto")
+
+	findings := breaking.CompareFileSets(oldSet, newSet)
+	if len(findings) != 1 {
+		t.Fatalf("got %v, but want one finding", findings)
+	}
+	got := findings[0]
+	if got.Kind != breaking.KindFieldTypeChanged || got.Severity != breaking.SeverityError || got.Name != "foo.A.e" {
+		t.Errorf("got %v, but want the type change of foo.A.e", got)
+	}
+	if got.OldPos.String() != "a.proto:6:3" || got.NewPos.String() != "a.proto:7:3" {
+		t.Errorf("got %v and %v, but want a.proto:6:3 and a.proto:7:3", got.OldPos, got.NewPos)
+	}
+}
@@ -0,0 +1,127 @@
+package breaking
+
+import (
+	"fmt"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Kind is a kind of the Finding.
+type Kind int
+
+// Kind values.
+const (
+	// KindMessageRemoved means the message is removed.
+	KindMessageRemoved Kind = iota
+	// KindFieldRemoved means the field number is no longer used.
+	KindFieldRemoved
+	// KindFieldRenumbered means the field of the same name has another number.
+	KindFieldRenumbered
+	// KindFieldRenamed means the field of the same number has another name, which changes its JSON and text format name.
+	KindFieldRenamed
+	// KindFieldTypeChanged means the field of the same number has another type.
+	KindFieldTypeChanged
+	// KindFieldLabelChanged means the field of the same number has another label, like repeated or optional.
+	KindFieldLabelChanged
+	// KindFieldOneofChanged means the field is moved into, out of or between the oneofs.
+	KindFieldOneofChanged
+	// KindEnumRemoved means the enum is removed.
+	KindEnumRemoved
+	// KindEnumValueRemoved means the enum value number is no longer used.
+	KindEnumValueRemoved
+	// KindEnumValueRenamed means the enum value of the same number has another name, which is used in JSON.
+	KindEnumValueRenamed
+	// KindServiceRemoved means the service is removed.
+	KindServiceRemoved
+	// KindRPCRemoved means the RPC is removed.
+	KindRPCRemoved
+	// KindRPCRenamed means the RPC is removed and a new one with the same request and response is added.
+	KindRPCRenamed
+	// KindRPCTypeChanged means the request or the response type of the RPC is changed.
+	KindRPCTypeChanged
+	// KindRPCStreamingChanged means the streaming of the request or the response of the RPC is changed.
+	KindRPCStreamingChanged
+	// KindReservedReused means the number or the name reserved in the old one is used in the new one.
+	KindReservedReused
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindMessageRemoved:
+		return "MESSAGE_REMOVED"
+	case KindFieldRemoved:
+		return "FIELD_REMOVED"
+	case KindFieldRenumbered:
+		return "FIELD_RENUMBERED"
+	case KindFieldRenamed:
+		return "FIELD_RENAMED"
+	case KindFieldTypeChanged:
+		return "FIELD_TYPE_CHANGED"
+	case KindFieldLabelChanged:
+		return "FIELD_LABEL_CHANGED"
+	case KindFieldOneofChanged:
+		return "FIELD_ONEOF_CHANGED"
+	case KindEnumRemoved:
+		return "ENUM_REMOVED"
+	case KindEnumValueRemoved:
+		return "ENUM_VALUE_REMOVED"
+	case KindEnumValueRenamed:
+		return "ENUM_VALUE_RENAMED"
+	case KindServiceRemoved:
+		return "SERVICE_REMOVED"
+	case KindRPCRemoved:
+		return "RPC_REMOVED"
+	case KindRPCRenamed:
+		return "RPC_RENAMED"
+	case KindRPCTypeChanged:
+		return "RPC_TYPE_CHANGED"
+	case KindRPCStreamingChanged:
+		return "RPC_STREAMING_CHANGED"
+	case KindReservedReused:
+		return "RESERVED_REUSED"
+	}
+	return "UNKNOWN"
+}
+
+// Severity is how much the change breaks the compatibility.
+type Severity int
+
+// Severity values.
+const (
+	// SeverityWarning means the change keeps the wire compatibility, but breaks the JSON compatibility.
+	SeverityWarning Severity = iota
+	// SeverityError means the change breaks the wire compatibility, the field names in the JSON and the text format,
+	// or the API of the service.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// Finding is a breaking change found by the comparison.
+type Finding struct {
+	Kind     Kind
+	Severity Severity
+	// Name is the fully-qualified name of the changed element, like "pkg.Message.field".
+	Name string
+	// OldPos is the position in the old file. It is zero when the element is added.
+	OldPos meta.Position
+	// NewPos is the position in the new file. It is zero when the element is removed.
+	NewPos  meta.Position
+	Message string
+}
+
+func (f *Finding) String() string {
+	pos := f.NewPos
+	if pos.Line == 0 {
+		pos = f.OldPos
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", pos, f.Severity, f.Message, f.Kind)
+}
@@ -0,0 +1,372 @@
+package breaking
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+const (
+	// maxFieldNumber is the largest field number, which "max" means in a message.
+	maxFieldNumber = 536870911
+	// maxEnumNumber is the largest enum value number, which "max" means in an enum.
+	maxEnumNumber = 2147483647
+)
+
+// schema holds the definitions of the files keyed by their fully-qualified names.
+type schema struct {
+	messages map[string]*message
+	enums    map[string]*enum
+	services map[string]*service
+}
+
+func (s *schema) messageNames() []string {
+	var names []string
+	for name := range s.messages {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (s *schema) enumNames() []string {
+	var names []string
+	for name := range s.enums {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (s *schema) serviceNames() []string {
+	var names []string
+	for name := range s.services {
+		names = append(names, name)
+	}
+	return names
+}
+
+// numberRange is an inclusive range of the reserved numbers.
+type numberRange struct {
+	begin, end int
+}
+
+// reserved holds the reserved numbers and names.
+type reserved struct {
+	ranges []numberRange
+	names  map[string]bool
+}
+
+func (r *reserved) add(n *parser.Reserved, max int) {
+	for _, rg := range n.Ranges {
+		begin, ok := atoi(rg.Begin)
+		if !ok {
+			continue
+		}
+		end := begin
+		switch rg.End {
+		case "":
+		case "max":
+			end = max
+		default:
+			if end, ok = atoi(rg.End); !ok {
+				continue
+			}
+		}
+		r.ranges = append(r.ranges, numberRange{begin: begin, end: end})
+	}
+	for _, name := range n.FieldNames {
+		r.names[strings.Trim(name, `"'`)] = true
+	}
+}
+
+func (r *reserved) hasNumber(number int) bool {
+	for _, rg := range r.ranges {
+		if rg.begin <= number && number <= rg.end {
+			return true
+		}
+	}
+	return false
+}
+
+type message struct {
+	fullName string
+	pos      meta.Position
+	fields   []*field
+	reserved reserved
+}
+
+// field is a field, a map field, a group or a field in a oneof.
+type field struct {
+	name   string
+	number int
+	// typ is the scalar type, the fully-qualified name of the type, or "map<key, value>".
+	typ string
+	// kind is the category of the type, which tells the compatibility on the wire.
+	kind string
+	// label is "repeated", "required", "optional" or "" for the implicit presence.
+	label string
+	// oneof is the name of the oneof which has the field, or "".
+	oneof string
+	pos   meta.Position
+}
+
+type enum struct {
+	fullName string
+	pos      meta.Position
+	values   []*enumValue
+	reserved reserved
+}
+
+type enumValue struct {
+	name   string
+	number int
+	pos    meta.Position
+}
+
+type service struct {
+	fullName string
+	pos      meta.Position
+	rpcs     []*rpc
+}
+
+type rpc struct {
+	name                             string
+	request, response                string
+	clientStreaming, serverStreaming bool
+	pos                              meta.Position
+}
+
+// newSchema builds the schema of the files. The references are resolved with the table.
+func newSchema(files []*fileset.File, table *linker.Table) *schema {
+	s := &schema{
+		messages: make(map[string]*message),
+		enums:    make(map[string]*enum),
+		services: make(map[string]*service),
+	}
+	for _, file := range files {
+		b := &builder{schema: s, table: table}
+		pkg := ""
+		for _, stmt := range file.Proto.ProtoBody {
+			if p, ok := stmt.(*parser.Package); ok {
+				pkg = p.Name
+			}
+		}
+		b.body(pkg, file.Proto.ProtoBody)
+	}
+	return s
+}
+
+type builder struct {
+	schema *schema
+	table  *linker.Table
+}
+
+func join(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// body adds the definitions in the body of the file or the message in the scope.
+func (b *builder) body(scope string, body []parser.Visitee) {
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *parser.Message:
+			b.message(join(scope, n.MessageName), n.MessageBody, n.Meta.Pos)
+		case *parser.Enum:
+			b.enum(join(scope, n.EnumName), n)
+		case *parser.Service:
+			b.service(join(scope, n.ServiceName), n)
+		}
+	}
+}
+
+func (b *builder) message(fullName string, body []parser.Visitee, pos meta.Position) *message {
+	m := &message{
+		fullName: fullName,
+		pos:      pos,
+		reserved: reserved{names: make(map[string]bool)},
+	}
+	b.schema.messages[fullName] = m
+
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *parser.Field:
+			number, ok := atoi(n.FieldNumber)
+			if !ok {
+				continue
+			}
+			typ, kind := b.typeOf(n.Type, n)
+			m.fields = append(m.fields, &field{
+				name:   n.FieldName,
+				number: number,
+				typ:    typ,
+				kind:   kind,
+				label:  label(n.IsRepeated, n.IsRequired, n.IsOptional),
+				pos:    n.Meta.Pos,
+			})
+		case *parser.MapField:
+			number, ok := atoi(n.FieldNumber)
+			if !ok {
+				continue
+			}
+			value, _ := b.typeOf(n.Type, n)
+			typ := "map<" + n.KeyType + ", " + value + ">"
+			m.fields = append(m.fields, &field{
+				name:   n.MapName,
+				number: number,
+				typ:    typ,
+				kind:   typ,
+				label:  "repeated",
+				pos:    n.Meta.Pos,
+			})
+		case *parser.GroupField:
+			group := join(fullName, n.GroupName)
+			b.message(group, n.MessageBody, n.Meta.Pos)
+			number, ok := atoi(n.FieldNumber)
+			if !ok {
+				continue
+			}
+			m.fields = append(m.fields, &field{
+				name:   strings.ToLower(n.GroupName),
+				number: number,
+				typ:    group,
+				kind:   "group " + group,
+				label:  label(n.IsRepeated, n.IsRequired, n.IsOptional),
+				pos:    n.Meta.Pos,
+			})
+		case *parser.Oneof:
+			for _, f := range n.OneofFields {
+				number, ok := atoi(f.FieldNumber)
+				if !ok {
+					continue
+				}
+				typ, kind := b.typeOf(f.Type, f)
+				m.fields = append(m.fields, &field{
+					name:   f.FieldName,
+					number: number,
+					typ:    typ,
+					kind:   kind,
+					oneof:  n.OneofName,
+					pos:    f.Meta.Pos,
+				})
+			}
+		case *parser.Reserved:
+			m.reserved.add(n, maxFieldNumber)
+		}
+	}
+	b.body(fullName, body)
+	return m
+}
+
+func (b *builder) enum(fullName string, n *parser.Enum) {
+	e := &enum{
+		fullName: fullName,
+		pos:      n.Meta.Pos,
+		reserved: reserved{names: make(map[string]bool)},
+	}
+	for _, stmt := range n.EnumBody {
+		switch s := stmt.(type) {
+		case *parser.EnumField:
+			number, ok := atoi(s.Number)
+			if !ok {
+				continue
+			}
+			e.values = append(e.values, &enumValue{
+				name:   s.Ident,
+				number: number,
+				pos:    s.Meta.Pos,
+			})
+		case *parser.Reserved:
+			e.reserved.add(s, maxEnumNumber)
+		}
+	}
+	b.schema.enums[fullName] = e
+}
+
+func (b *builder) service(fullName string, n *parser.Service) {
+	s := &service{
+		fullName: fullName,
+		pos:      n.Meta.Pos,
+	}
+	for _, stmt := range n.ServiceBody {
+		r, ok := stmt.(*parser.RPC)
+		if !ok {
+			continue
+		}
+		request, _ := b.typeOf(r.RPCRequest.MessageType, r.RPCRequest)
+		response, _ := b.typeOf(r.RPCResponse.MessageType, r.RPCResponse)
+		s.rpcs = append(s.rpcs, &rpc{
+			name:            r.RPCName,
+			request:         request,
+			response:        response,
+			clientStreaming: r.RPCRequest.IsStream,
+			serverStreaming: r.RPCResponse.IsStream,
+			pos:             r.Meta.Pos,
+		})
+	}
+	b.schema.services[fullName] = s
+}
+
+// wireKinds are the categories of the scalar types whose values are compatible on the wire.
+var wireKinds = map[string]string{
+	"int32":    "varint",
+	"int64":    "varint",
+	"uint32":   "varint",
+	"uint64":   "varint",
+	"bool":     "varint",
+	"sint32":   "zigzag",
+	"sint64":   "zigzag",
+	"fixed32":  "fixed32",
+	"sfixed32": "fixed32",
+	"fixed64":  "fixed64",
+	"sfixed64": "fixed64",
+	"float":    "float",
+	"double":   "double",
+	"string":   "bytes",
+	"bytes":    "bytes",
+}
+
+// typeOf returns the resolved type of the node and the category of it.
+// An unresolved type is kept as it is written, without the leading dot.
+func (b *builder) typeOf(typ string, node interface{}) (string, string) {
+	if kind, ok := wireKinds[typ]; ok {
+		return typ, kind
+	}
+	if b.table != nil {
+		if symbol := b.table.Resolve(node); symbol != nil {
+			if symbol.Kind == linker.SymbolKindEnum {
+				// an enum is compatible with the integers on the wire.
+				return symbol.FullName, "varint"
+			}
+			return symbol.FullName, "message " + symbol.FullName
+		}
+	}
+	name := strings.TrimPrefix(typ, ".")
+	return name, "message " + name
+}
+
+func label(isRepeated, isRequired, isOptional bool) string {
+	switch {
+	case isRepeated:
+		return "repeated"
+	case isRequired:
+		return "required"
+	case isOptional:
+		return "optional"
+	}
+	return ""
+}
+
+// atoi parses the number written in decimal, octal or hexadecimal. It returns false if the s is not a number.
+func atoi(s string) (int, bool) {
+	n, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(n), true
+}
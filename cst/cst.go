@@ -0,0 +1,417 @@
+// Package cst provides a lossless concrete syntax tree, which keeps every token along with its whitespaces and comments.
+package cst
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Token is a token with its trivia, that is whitespaces and comments.
+type Token struct {
+	Token scanner.Token
+	// Text is the source text of the token.
+	Text string
+	// Pos is the position of the first character.
+	Pos meta.Position
+
+	// LeadingTrivia is the trivia placed before the token which is not the TrailingTrivia of the previous token.
+	LeadingTrivia string
+	// TrailingTrivia is the trivia placed behind the token up to and including the end of the line.
+	TrailingTrivia string
+
+	// Node is the innermost statement which owns the token.
+	// It's the Proto for the trailing EOF token.
+	Node parser.Visitee
+}
+
+// File is a lossless concrete syntax tree of a .proto file.
+//
+// Fprint reproduces the source exactly unless the Proto is modified.
+// When it is modified, only the statements which are changed, added or removed are printed anew.
+type File struct {
+	Proto *parser.Proto
+	// Tokens are all of the tokens in the source, followed by the TEOF token which holds the trivia at the end.
+	Tokens []*Token
+
+	src   string
+	root  *node
+	nodes map[parser.Visitee]*node
+	// indent is the unit of indentation guessed from the source.
+	indent string
+}
+
+// node is the original layout of a statement.
+type node struct {
+	parent *node
+	// start and end are the byte offsets of the statement including its comments.
+	start int
+	end   int
+	// gap is the source between the previous statement, or the beginning of the body, and the statement.
+	gap       string
+	signature string
+
+	// bodyStart and bodyEnd are the byte offsets of the body of a block.
+	bodyStart int
+	bodyEnd   int
+	// trailer is the source between the last statement and the end of the body.
+	trailer string
+}
+
+// Parse parses the input into a File.
+// The opts are passed to the parser. If the error recovery mode is enabled, Parse returns a File with the partial Proto and the error.
+func Parse(filename string, input io.Reader, opts ...parser.ConfigOption) (*File, error) {
+	src, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	lex := lexer.NewLexer(
+		bytes.NewReader(src),
+		lexer.WithFilename(filename),
+		lexer.WithRecording(true),
+	)
+	proto, err := parser.NewParser(lex, opts...).ParseProto()
+	if proto == nil {
+		return nil, err
+	}
+
+	f := &File{
+		Proto: proto,
+		src:   string(src),
+		nodes: make(map[parser.Visitee]*node),
+	}
+	f.Tokens = newTokens(f.src, lex.RecordedTokens(), filename)
+	f.root = &node{
+		end:     len(f.src),
+		bodyEnd: len(f.src),
+	}
+	f.indexBody(f.root, protoBody(proto))
+	if f.indent == "" {
+		f.indent = "  "
+	}
+	f.linkTokens(f.root, protoBody(proto))
+	f.Tokens[len(f.Tokens)-1].Node = proto
+	return f, err
+}
+
+// NodeTokens returns the tokens of the statement parsed from the source, or nil.
+func (f *File) NodeTokens(stmt parser.Visitee) []*Token {
+	n, ok := f.nodes[stmt]
+	if !ok {
+		return nil
+	}
+	first, last := f.tokenRange(n.start, n.end)
+	return f.Tokens[first:last]
+}
+
+// newTokens converts the recorded tokens to the ones with trivia. The comments are treated as trivia.
+func newTokens(src string, recorded []*lexer.RecordedToken, filename string) []*Token {
+	var tokens []*Token
+	end := 0
+	triviaStart := 0
+	for _, r := range recorded {
+		rawEnd := end + len(r.Raw)
+		if r.Token != scanner.TCOMMENT && r.Token != scanner.TEOF {
+			tokens = appendToken(tokens, &Token{
+				Token: r.Token,
+				Text:  src[r.Pos.Offset:rawEnd],
+				Pos:   r.Pos.Position,
+			}, src[triviaStart:r.Pos.Offset])
+			triviaStart = rawEnd
+		}
+		end = rawEnd
+	}
+
+	eofPos := meta.Position{Filename: filename, Offset: len(src), Line: 1, Column: 1}
+	for _, r := range src {
+		if r == '\n' {
+			eofPos.Line++
+			eofPos.Column = 1
+		} else {
+			eofPos.Column++
+		}
+	}
+	return appendToken(tokens, &Token{
+		Token: scanner.TEOF,
+		Pos:   eofPos,
+	}, src[triviaStart:])
+}
+
+func appendToken(tokens []*Token, token *Token, trivia string) []*Token {
+	if len(tokens) == 0 {
+		token.LeadingTrivia = trivia
+		return append(tokens, token)
+	}
+	tokens[len(tokens)-1].TrailingTrivia, token.LeadingTrivia = splitTrivia(trivia)
+	return append(tokens, token)
+}
+
+// splitTrivia splits the trivia into the trailing one of the previous token, which ends with the first newline
+// out of comments, and the leading one of the next token.
+func splitTrivia(trivia string) (string, string) {
+	i := 0
+	for i < len(trivia) {
+		switch {
+		case trivia[i] == ' ', trivia[i] == '\t', trivia[i] == '\r':
+			i++
+		case trivia[i] == '\n':
+			return trivia[:i+1], trivia[i+1:]
+		case strings.HasPrefix(trivia[i:], "//"):
+			j := strings.IndexByte(trivia[i:], '\n')
+			if j < 0 {
+				return trivia, ""
+			}
+			return trivia[:i+j+1], trivia[i+j+1:]
+		case strings.HasPrefix(trivia[i:], "/*"):
+			j := strings.Index(trivia[i+2:], "*/")
+			if j < 0 {
+				return trivia, ""
+			}
+			i += 2 + j + 2
+		default:
+			return trivia[:i], trivia[i:]
+		}
+	}
+	return trivia, ""
+}
+
+// tokenRange returns the range of the tokens which start within the byte offsets.
+func (f *File) tokenRange(start, end int) (int, int) {
+	first := sort.Search(len(f.Tokens), func(i int) bool {
+		return start <= f.Tokens[i].Pos.Offset
+	})
+	last := sort.Search(len(f.Tokens), func(i int) bool {
+		return end <= f.Tokens[i].Pos.Offset
+	})
+	return first, last
+}
+
+func (f *File) indexBody(parent *node, stmts []parser.Visitee) {
+	prevEnd := parent.bodyStart
+	for _, stmt := range stmts {
+		start, end, ok := f.span(stmt, prevEnd)
+		if !ok || start < prevEnd || parent.bodyEnd < end {
+			continue
+		}
+		n := &node{
+			parent:    parent,
+			start:     start,
+			end:       end,
+			gap:       f.src[prevEnd:start],
+			signature: signature(stmt),
+		}
+		f.nodes[stmt] = n
+
+		if body, inlineLeftCurly, ok := f.block(stmt); ok {
+			n.bodyStart, n.bodyEnd = f.curlies(n, inlineLeftCurly)
+			f.indexBody(n, body)
+			if 0 < len(body) && parent == f.root && f.indent == "" {
+				if child, ok := f.nodes[body[0]]; ok {
+					f.indent = f.indentation(child)
+				}
+			}
+		}
+		prevEnd = end
+	}
+	parent.trailer = f.src[prevEnd:parent.bodyEnd]
+}
+
+// curlies returns the byte offsets of the body enclosed by the curly braces.
+// The inline comment behind the left curly is a part of the header.
+func (f *File) curlies(n *node, inlineLeftCurly *parser.Comment) (int, int) {
+	first, last := f.tokenRange(n.start, n.end)
+	bodyStart, bodyEnd := n.end, n.end
+	for _, token := range f.Tokens[first:last] {
+		if token.Token == scanner.TLEFTCURLY {
+			bodyStart = token.Pos.Offset + 1
+			break
+		}
+	}
+	for i := last - 1; first <= i; i-- {
+		if f.Tokens[i].Token == scanner.TRIGHTCURLY {
+			bodyEnd = f.Tokens[i].Pos.Offset
+			break
+		}
+	}
+	if inlineLeftCurly != nil {
+		bodyStart = f.endOffset(inlineLeftCurly.Meta.LastPos)
+	}
+	return bodyStart, bodyEnd
+}
+
+// span returns the byte offsets of the statement including its comments.
+// The empty statement, which has no position, is the first semicolon after the offset.
+func (f *File) span(stmt parser.Visitee, after int) (int, int, bool) {
+	if _, ok := stmt.(*parser.EmptyStatement); ok {
+		first, last := f.tokenRange(after, len(f.src))
+		for _, token := range f.Tokens[first:last] {
+			if token.Token == scanner.TSEMICOLON {
+				start := token.Pos.Offset
+				end := start + 1
+				if comment := stmt.(*parser.EmptyStatement).InlineComment; comment != nil {
+					end = f.endOffset(comment.Meta.LastPos)
+				}
+				return start, end, true
+			}
+		}
+		return 0, 0, false
+	}
+
+	comments, inlineComment, m, ok := layout(stmt)
+	if !ok || m.Pos.Line == 0 {
+		return 0, 0, false
+	}
+	start := m.Pos.Offset
+	if 0 < len(comments) {
+		start = comments[0].Meta.Pos.Offset
+	}
+	end := f.endOffset(m.LastPos)
+	if inlineComment != nil {
+		end = f.endOffset(inlineComment.Meta.LastPos)
+	}
+	return start, end, true
+}
+
+// endOffset returns the byte offset next to the last character.
+func (f *File) endOffset(lastPos meta.Position) int {
+	if len(f.src) <= lastPos.Offset {
+		return len(f.src)
+	}
+	_, size := utf8.DecodeRuneInString(f.src[lastPos.Offset:])
+	return lastPos.Offset + size
+}
+
+// indentation returns the whitespaces placed before the statement in the same line.
+func (f *File) indentation(n *node) string {
+	lineStart := strings.LastIndexByte(f.src[:n.start], '\n') + 1
+	indent := f.src[lineStart:n.start]
+	if strings.TrimLeft(indent, " \t") != "" {
+		return ""
+	}
+	return indent
+}
+
+// linkTokens links each token to the innermost statement which owns it.
+func (f *File) linkTokens(parent *node, stmts []parser.Visitee) {
+	for _, stmt := range stmts {
+		n, ok := f.nodes[stmt]
+		if !ok || n.parent != parent {
+			continue
+		}
+		first, last := f.tokenRange(n.start, n.end)
+		for _, token := range f.Tokens[first:last] {
+			token.Node = stmt
+		}
+		if body, _, ok := f.block(stmt); ok {
+			f.linkTokens(n, body)
+		}
+	}
+}
+
+// block returns the body of the statement which has the one enclosed by curly braces.
+// The rpc is not regarded as a block because its options are a part of the statement.
+func (f *File) block(stmt parser.Visitee) ([]parser.Visitee, *parser.Comment, bool) {
+	switch n := stmt.(type) {
+	case *parser.Message:
+		return n.MessageBody, n.InlineCommentBehindLeftCurly, true
+	case *parser.Enum:
+		return n.EnumBody, n.InlineCommentBehindLeftCurly, true
+	case *parser.Service:
+		return n.ServiceBody, n.InlineCommentBehindLeftCurly, true
+	case *parser.Extend:
+		return n.ExtendBody, n.InlineCommentBehindLeftCurly, true
+	case *parser.GroupField:
+		return n.MessageBody, n.InlineCommentBehindLeftCurly, true
+	case *parser.Oneof:
+		var body []parser.Visitee
+		for _, option := range n.Options {
+			body = append(body, option)
+		}
+		for _, field := range n.OneofFields {
+			body = append(body, field)
+		}
+		// keeps the order of the source, and puts new ones at the end.
+		offset := func(stmt parser.Visitee) int {
+			if orig, ok := f.nodes[stmt]; ok {
+				return orig.start
+			}
+			_, _, m, _ := layout(stmt)
+			if m.Pos.Line == 0 {
+				return len(f.src)
+			}
+			return m.Pos.Offset
+		}
+		sort.SliceStable(body, func(i, j int) bool {
+			return offset(body[i]) < offset(body[j])
+		})
+		return body, n.InlineCommentBehindLeftCurly, true
+	default:
+		return nil, nil, false
+	}
+}
+
+func protoBody(proto *parser.Proto) []parser.Visitee {
+	var stmts []parser.Visitee
+	if proto.Syntax != nil {
+		stmts = append(stmts, proto.Syntax)
+	}
+	if proto.Edition != nil {
+		stmts = append(stmts, proto.Edition)
+	}
+	return append(stmts, proto.ProtoBody...)
+}
+
+// layout returns the fields of the statement which decide its range.
+func layout(stmt parser.Visitee) ([]*parser.Comment, *parser.Comment, meta.Meta, bool) {
+	switch n := stmt.(type) {
+	case *parser.Comment:
+		return nil, nil, n.Meta, true
+	case *parser.Syntax:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Edition:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Import:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Package:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Option:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Message:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Enum:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Service:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Extend:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Field:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.MapField:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.GroupField:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Oneof:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.OneofField:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.EnumField:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Reserved:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Extensions:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.RPC:
+		return n.Comments, n.InlineComment, n.Meta, true
+	default:
+		return nil, nil, meta.Meta{}, false
+	}
+}
@@ -0,0 +1,271 @@
+package cst_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/cst"
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+func TestParse_lossless(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "_testdata", "*.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no testdata")
+	}
+
+	for _, file := range files {
+		file := file
+		for _, bodyIncludingComments := range []bool{false, true} {
+			bodyIncludingComments := bodyIncludingComments
+			name := fmt.Sprintf("%s with bodyIncludingComments=%v", filepath.Base(file), bodyIncludingComments)
+			t.Run(name, func(t *testing.T) {
+				src, err := ioutil.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				f, err := cst.Parse(
+					file,
+					strings.NewReader(string(src)),
+					parser.WithPermissive(true),
+					parser.WithBodyIncludingComments(bodyIncludingComments),
+				)
+				if err != nil {
+					t.Fatalf("got err %v, but want nil", err)
+				}
+
+				if got := f.String(); got != string(src) {
+					t.Errorf("got %q, but want %q", got, string(src))
+				}
+
+				var tokens strings.Builder
+				for _, token := range f.Tokens {
+					tokens.WriteString(token.LeadingTrivia + token.Text + token.TrailingTrivia)
+					if token.Node == nil {
+						t.Errorf("got no node of %v", token)
+					}
+				}
+				if got := tokens.String(); got != string(src) {
+					t.Errorf("got %q, but want %q", got, string(src))
+				}
+			})
+		}
+	}
+}
+
+func TestFile_Tokens(t *testing.T) {
+	input := `syntax = "proto3"; // syntax
+/* leading */ message A { int32 x = 1; }
+`
+	f, err := cst.Parse("a.proto", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	type token struct {
+		Token    scanner.Token
+		Text     string
+		Leading  string
+		Trailing string
+	}
+	var got []token
+	for _, tok := range f.Tokens {
+		got = append(got, token{tok.Token, tok.Text, tok.LeadingTrivia, tok.TrailingTrivia})
+	}
+	want := []token{
+		{scanner.TSYNTAX, "syntax", "", " "},
+		{scanner.TEQUALS, "=", "", " "},
+		{scanner.TQUOTE, `"`, "", ""},
+		{scanner.TIDENT, "proto3", "", ""},
+		{scanner.TQUOTE, `"`, "", ""},
+		{scanner.TSEMICOLON, ";", "", " // syntax\n"},
+		{scanner.TMESSAGE, "message", "/* leading */ ", " "},
+		{scanner.TIDENT, "A", "", " "},
+		{scanner.TLEFTCURLY, "{", "", " "},
+		{scanner.TIDENT, "int32", "", " "},
+		{scanner.TIDENT, "x", "", " "},
+		{scanner.TEQUALS, "=", "", " "},
+		{scanner.TINTLIT, "1", "", ""},
+		{scanner.TSEMICOLON, ";", "", " "},
+		{scanner.TRIGHTCURLY, "}", "", "\n"},
+		{scanner.TEOF, "", "", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+
+	message := f.Proto.ProtoBody[0].(*parser.Message)
+	field := message.MessageBody[0].(*parser.Field)
+	if got := f.Tokens[6].Node; got != message {
+		t.Errorf("got %v, but want %v", got, message)
+	}
+	if got := f.Tokens[9].Node; got != field {
+		t.Errorf("got %v, but want %v", got, field)
+	}
+	if got := len(f.NodeTokens(field)); got != 5 {
+		t.Errorf("got %d tokens, but want 5", got)
+	}
+	if got := f.NodeTokens(&parser.Field{}); got != nil {
+		t.Errorf("got %v, but want nil", got)
+	}
+}
+
+func TestFile_Fprint(t *testing.T) {
+	input := `syntax   =   "proto3";
+
+// A is a message.
+message A {   // A
+    int32   x   =   1;  // x
+    /* y */
+    string  y   =   2;
+
+    message B {}
+}
+
+service S{
+    rpc Get ( A ) returns ( A );  // Get
+}
+`
+	tests := []struct {
+		name   string
+		modify func(proto *parser.Proto)
+		want   string
+	}{
+		{
+			name:   "no modification",
+			modify: func(*parser.Proto) {},
+			want:   input,
+		},
+		{
+			name: "changing a field",
+			modify: func(proto *parser.Proto) {
+				a := proto.ProtoBody[0].(*parser.Message)
+				a.MessageBody[0].(*parser.Field).FieldName = "renamed"
+			},
+			want: `syntax   =   "proto3";
+
+// A is a message.
+message A {   // A
+    int32 renamed = 1; // x
+    /* y */
+    string  y   =   2;
+
+    message B {}
+}
+
+service S{
+    rpc Get ( A ) returns ( A );  // Get
+}
+`,
+		},
+		{
+			name: "removing a field",
+			modify: func(proto *parser.Proto) {
+				a := proto.ProtoBody[0].(*parser.Message)
+				a.MessageBody = a.MessageBody[1:]
+			},
+			want: `syntax   =   "proto3";
+
+// A is a message.
+message A {   // A
+    /* y */
+    string  y   =   2;
+
+    message B {}
+}
+
+service S{
+    rpc Get ( A ) returns ( A );  // Get
+}
+`,
+		},
+		{
+			name: "adding statements",
+			modify: func(proto *parser.Proto) {
+				a := proto.ProtoBody[0].(*parser.Message)
+				b := a.MessageBody[2].(*parser.Message)
+				b.MessageBody = append(b.MessageBody, &parser.Field{
+					Type:        "int64",
+					FieldName:   "z",
+					FieldNumber: "1",
+				})
+				proto.ProtoBody = append(proto.ProtoBody, &parser.Enum{
+					EnumName: "E",
+					EnumBody: []parser.Visitee{
+						&parser.EnumField{Ident: "E0", Number: "0"},
+					},
+				})
+			},
+			want: `syntax   =   "proto3";
+
+// A is a message.
+message A {   // A
+    int32   x   =   1;  // x
+    /* y */
+    string  y   =   2;
+
+    message B {
+        int64 z = 1;
+    }
+}
+
+service S{
+    rpc Get ( A ) returns ( A );  // Get
+}
+enum E {
+    E0 = 0;
+}
+`,
+		},
+		{
+			name: "changing a block",
+			modify: func(proto *parser.Proto) {
+				s := proto.ProtoBody[1].(*parser.Service)
+				s.ServiceName = "T"
+			},
+			want: `syntax   =   "proto3";
+
+// A is a message.
+message A {   // A
+    int32   x   =   1;  // x
+    /* y */
+    string  y   =   2;
+
+    message B {}
+}
+
+service T {
+    rpc Get(A) returns (A); // Get
+}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			f, err := cst.Parse("a.proto", strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			test.modify(f.Proto)
+
+			var got strings.Builder
+			err = f.Fprint(&got)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			if got.String() != test.want {
+				t.Errorf("got\n%s\nbut want\n%s", got.String(), test.want)
+			}
+		})
+	}
+}
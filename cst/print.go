@@ -0,0 +1,158 @@
+package cst
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/printer"
+)
+
+// Fprint prints the Proto to w.
+// The statements which are unchanged since Parse are printed as they are in the source, including their trivia.
+func (f *File) Fprint(w io.Writer) error {
+	var buf bytes.Buffer
+	if err := f.printBody(&buf, f.root, protoBody(f.Proto), ""); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// String returns the printed Proto.
+func (f *File) String() string {
+	var b strings.Builder
+	if err := f.Fprint(&b); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+func (f *File) printBody(
+	buf *bytes.Buffer,
+	parent *node,
+	stmts []parser.Visitee,
+	indent string,
+) error {
+	// the indentation of the original statements is preferred for the new ones.
+	for _, stmt := range stmts {
+		if n, ok := f.nodes[stmt]; ok && n.parent == parent {
+			indent = f.indentation(n)
+			break
+		}
+	}
+
+	inserted := false
+	for _, stmt := range stmts {
+		n, ok := f.nodes[stmt]
+		if !ok || n.parent != parent {
+			if 0 < buf.Len() {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(indent)
+			if err := f.printNew(buf, stmt, indent); err != nil {
+				return err
+			}
+			inserted = true
+			continue
+		}
+
+		if inserted && !strings.Contains(n.gap, "\n") {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(n.gap)
+		if err := f.printStmt(buf, stmt, n); err != nil {
+			return err
+		}
+	}
+
+	if inserted && !strings.Contains(parent.trailer, "\n") && parent != f.root {
+		buf.WriteString("\n")
+		buf.WriteString(f.indentation(parent))
+	}
+	buf.WriteString(parent.trailer)
+	return nil
+}
+
+func (f *File) printStmt(buf *bytes.Buffer, stmt parser.Visitee, n *node) error {
+	if signature(stmt) != n.signature {
+		return f.printNew(buf, stmt, f.indentation(n))
+	}
+
+	body, _, ok := f.block(stmt)
+	if !ok {
+		buf.WriteString(f.src[n.start:n.end])
+		return nil
+	}
+	buf.WriteString(f.src[n.start:n.bodyStart])
+	if err := f.printBody(buf, n, body, f.indentation(n)+f.indent); err != nil {
+		return err
+	}
+	buf.WriteString(f.src[n.bodyEnd:n.end])
+	return nil
+}
+
+// printNew prints the statement with the printer from the current column.
+// The indent is put at the beginning of the following lines.
+func (f *File) printNew(buf *bytes.Buffer, stmt parser.Visitee, indent string) error {
+	var b bytes.Buffer
+	err := (&printer.Config{
+		Indent:          f.indent,
+		IgnorePositions: true,
+	}).Fprint(&b, stmt)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	for i, line := range lines {
+		if 0 < i {
+			buf.WriteString("\n")
+			if line != "" {
+				buf.WriteString(indent)
+			}
+		}
+		buf.WriteString(line)
+	}
+	return nil
+}
+
+// signature returns the text to decide whether the statement is changed.
+// The body of a block is excluded because the statements in it are compared one by one.
+func signature(stmt parser.Visitee) string {
+	switch n := stmt.(type) {
+	case *parser.Message:
+		c := *n
+		c.MessageBody = nil
+		stmt = &c
+	case *parser.Enum:
+		c := *n
+		c.EnumBody = nil
+		stmt = &c
+	case *parser.Service:
+		c := *n
+		c.ServiceBody = nil
+		stmt = &c
+	case *parser.Extend:
+		c := *n
+		c.ExtendBody = nil
+		stmt = &c
+	case *parser.GroupField:
+		c := *n
+		c.MessageBody = nil
+		stmt = &c
+	case *parser.Oneof:
+		c := *n
+		c.OneofFields = nil
+		c.Options = nil
+		stmt = &c
+	}
+
+	var b bytes.Buffer
+	err := (&printer.Config{IgnorePositions: true}).Fprint(&b, stmt)
+	if err != nil {
+		return ""
+	}
+	return b.String()
+}
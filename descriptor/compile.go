@@ -0,0 +1,653 @@
+package descriptor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/internal/strlit"
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+const (
+	// maxFieldNumber is the largest field number, which "max" means in the ranges of a message.
+	maxFieldNumber = 536870911
+	// maxEnumNumber is the largest enum number, which "max" means in the ranges of an enum.
+	maxEnumNumber = math.MaxInt32
+)
+
+// The field numbers in google/protobuf/descriptor.proto which the paths in the SourceCodeInfo consist of.
+const (
+	filePackageNumber     = 2
+	fileDependencyNumber  = 3
+	fileMessageTypeNumber = 4
+	fileEnumTypeNumber    = 5
+	fileServiceNumber     = 6
+	fileExtensionNumber   = 7
+	fileSyntaxNumber      = 12
+	fileEditionNumber     = 14
+
+	messageFieldNumber      = 2
+	messageNestedTypeNumber = 3
+	messageEnumTypeNumber   = 4
+	messageExtensionNumber  = 6
+	messageOneofDeclNumber  = 8
+
+	enumValueNumber      = 2
+	serviceMethodNumber  = 2
+	mapEntryOptionNumber = 7
+)
+
+// scalarTypes are the types of the fields which are not references.
+var scalarTypes = map[string]FieldType{
+	"double":   FieldTypeDouble,
+	"float":    FieldTypeFloat,
+	"int64":    FieldTypeInt64,
+	"uint64":   FieldTypeUint64,
+	"int32":    FieldTypeInt32,
+	"fixed64":  FieldTypeFixed64,
+	"fixed32":  FieldTypeFixed32,
+	"bool":     FieldTypeBool,
+	"string":   FieldTypeString,
+	"bytes":    FieldTypeBytes,
+	"uint32":   FieldTypeUint32,
+	"sfixed32": FieldTypeSfixed32,
+	"sfixed64": FieldTypeSfixed64,
+	"sint32":   FieldTypeSint32,
+	"sint64":   FieldTypeSint64,
+}
+
+// Config is a config for Compile.
+type Config struct {
+	sourceCodeInfo bool
+}
+
+// ConfigOption is an option for Config.
+type ConfigOption func(*Config)
+
+// WithSourceCodeInfo is an option to include the SourceCodeInfo, which holds the locations and the comments,
+// like protoc's --include_source_info.
+func WithSourceCodeInfo(sourceCodeInfo bool) ConfigOption {
+	return func(c *Config) {
+		c.sourceCodeInfo = sourceCodeInfo
+	}
+}
+
+// CompileFileSet links and compiles all of the files in the set.
+func CompileFileSet(set *fileset.FileSet, opts ...ConfigOption) (*FileDescriptorSet, error) {
+	table, err := linker.LinkFileSet(set)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(set.Sorted(), table, opts...)
+}
+
+// Compile compiles the files, which are linked into the table, into the descriptors.
+// The files must follow the files they import, like the ones returned by fileset.FileSet.Sorted.
+//
+// Compile returns the set along with an ErrorList when any options or references can't be compiled.
+func Compile(files []*fileset.File, table *linker.Table, opts ...ConfigOption) (*FileDescriptorSet, error) {
+	config := &Config{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	set := &FileDescriptorSet{}
+	var errs ErrorList
+	for _, file := range files {
+		c := &compiler{
+			table:  table,
+			file:   file,
+			config: config,
+		}
+		set.File = append(set.File, c.compile())
+		errs = append(errs, c.errors...)
+	}
+	if 0 < len(errs) {
+		return set, errs
+	}
+	return set, nil
+}
+
+type compiler struct {
+	table  *linker.Table
+	file   *fileset.File
+	config *Config
+	errors ErrorList
+
+	proto3    bool
+	locations []*Location
+}
+
+func (c *compiler) errorf(pos meta.Position, format string, args ...interface{}) {
+	c.errors = append(c.errors, &Error{
+		Pos:     pos,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *compiler) compile() *FileDescriptorProto {
+	proto := c.file.Proto
+	f := &FileDescriptorProto{
+		Name: c.file.Path,
+	}
+	switch {
+	case proto.Syntax != nil:
+		if proto.Syntax.ProtobufVersion == "proto3" {
+			c.proto3 = true
+			f.Syntax = "proto3"
+		}
+		c.locate([]int32{fileSyntaxNumber}, proto.Syntax.Meta, proto.Syntax.Comments, proto.Syntax.InlineComment)
+	case proto.Edition != nil:
+		f.Syntax = "editions"
+		f.Edition = edition(proto.Edition.Edition)
+		c.locate([]int32{fileEditionNumber}, proto.Edition.Meta, proto.Edition.Comments, proto.Edition.InlineComment)
+	}
+
+	var options []option
+	for _, stmt := range proto.ProtoBody {
+		switch n := stmt.(type) {
+		case *parser.Package:
+			f.Package = n.Name
+			c.locate([]int32{filePackageNumber}, n.Meta, n.Comments, n.InlineComment)
+		case *parser.Import:
+			index := int32(len(f.Dependency))
+			location, _ := strlit.Unquote(n.Location)
+			f.Dependency = append(f.Dependency, string(location))
+			switch n.Modifier {
+			case parser.ImportModifierPublic:
+				f.PublicDependency = append(f.PublicDependency, index)
+			case parser.ImportModifierWeak:
+				f.WeakDependency = append(f.WeakDependency, index)
+			}
+			c.locate([]int32{fileDependencyNumber, index}, n.Meta, n.Comments, n.InlineComment)
+		case *parser.Option:
+			options = append(options, option{name: n.OptionName, value: n.Value, pos: n.Meta.Pos})
+		case *parser.Message:
+			path := []int32{fileMessageTypeNumber, int32(len(f.MessageType))}
+			f.MessageType = append(f.MessageType, c.message(f.Package, n.MessageName, n.MessageBody, path))
+			c.locate(path, n.Meta, n.Comments, n.InlineComment)
+		case *parser.Enum:
+			path := []int32{fileEnumTypeNumber, int32(len(f.EnumType))}
+			f.EnumType = append(f.EnumType, c.enum(f.Package, n, path))
+			c.locate(path, n.Meta, n.Comments, n.InlineComment)
+		case *parser.Service:
+			path := []int32{fileServiceNumber, int32(len(f.Service))}
+			f.Service = append(f.Service, c.service(f.Package, n, path))
+			c.locate(path, n.Meta, n.Comments, n.InlineComment)
+		case *parser.Extend:
+			c.locate([]int32{fileExtensionNumber}, n.Meta, n.Comments, n.InlineComment)
+			c.extend(f.Package, n, &f.Extension, &f.MessageType, nil)
+		}
+	}
+	f.Options = c.encodeOptions(fileOptions, f.Package, options)
+
+	if c.config.sourceCodeInfo {
+		f.SourceCodeInfo = &SourceCodeInfo{Location: c.locations}
+	}
+	return f
+}
+
+// edition returns the edition of the text like "2023".
+func edition(text string) Edition {
+	switch strings.Trim(text, `"'`) {
+	case "2023":
+		return Edition2023
+	case "2024":
+		return Edition2024
+	}
+	return EditionUnknown
+}
+
+// join returns the full name of the name in the scope.
+func join(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// appendPath returns a new path which is the path followed by the elements.
+func appendPath(path []int32, elements ...int32) []int32 {
+	p := make([]int32, 0, len(path)+len(elements))
+	p = append(p, path...)
+	return append(p, elements...)
+}
+
+// message compiles the message, or the group, whose body is the body.
+func (c *compiler) message(scope, name string, body []parser.Visitee, path []int32) *DescriptorProto {
+	fullName := join(scope, name)
+	m := &DescriptorProto{
+		Name: name,
+	}
+
+	var options []option
+	var proto3Optionals []*FieldDescriptorProto
+	addField := func(field *FieldDescriptorProto, n meta.Meta, comments []*parser.Comment, inline *parser.Comment) {
+		c.locate(appendPath(path, messageFieldNumber, int32(len(m.Field))), n, comments, inline)
+		m.Field = append(m.Field, field)
+	}
+	addNested := func(nested *DescriptorProto) {
+		m.NestedType = append(m.NestedType, nested)
+	}
+
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *parser.Field:
+			field := c.field(fullName, n.FieldName, n.FieldNumber, n.Type, n, n.FieldOptions, n.Meta.Pos)
+			field.Label = c.label(n.IsRepeated, n.IsRequired)
+			if c.proto3 && n.IsOptional {
+				field.Proto3Optional = true
+				proto3Optionals = append(proto3Optionals, field)
+			}
+			addField(field, n.Meta, n.Comments, n.InlineComment)
+		case *parser.MapField:
+			entryName := mapEntryName(n.MapName)
+			key := c.field(join(fullName, entryName), "key", "1", n.KeyType, nil, nil, n.Meta.Pos)
+			value := c.field(join(fullName, entryName), "value", "2", n.Type, n, nil, n.Meta.Pos)
+			key.Label = FieldLabelOptional
+			value.Label = FieldLabelOptional
+			var mapEntry encoder
+			mapEntry.boolField(mapEntryOptionNumber, true)
+			addNested(&DescriptorProto{
+				Name:    entryName,
+				Field:   []*FieldDescriptorProto{key, value},
+				Options: Options(mapEntry.buf),
+			})
+
+			field := c.field(fullName, n.MapName, n.FieldNumber, "", nil, n.FieldOptions, n.Meta.Pos)
+			field.Label = FieldLabelRepeated
+			field.Type = FieldTypeMessage
+			field.TypeName = "." + join(fullName, entryName)
+			addField(field, n.Meta, n.Comments, n.InlineComment)
+		case *parser.GroupField:
+			nestedPath := appendPath(path, messageNestedTypeNumber, int32(len(m.NestedType)))
+			addNested(c.message(fullName, n.GroupName, n.MessageBody, nestedPath))
+			c.locate(nestedPath, n.Meta, nil, nil)
+			field := c.group(fullName, n)
+			addField(field, n.Meta, n.Comments, n.InlineComment)
+		case *parser.Oneof:
+			index := int32(len(m.OneofDecl))
+			var oneofOpts []option
+			for _, o := range n.Options {
+				oneofOpts = append(oneofOpts, option{name: o.OptionName, value: o.Value, pos: o.Meta.Pos})
+			}
+			m.OneofDecl = append(m.OneofDecl, &OneofDescriptorProto{
+				Name:    n.OneofName,
+				Options: c.encodeOptions(oneofOptions, fullName, oneofOpts),
+			})
+			c.locate(appendPath(path, messageOneofDeclNumber, index), n.Meta, n.Comments, n.InlineComment)
+			for _, f := range n.OneofFields {
+				field := c.field(fullName, f.FieldName, f.FieldNumber, f.Type, f, f.FieldOptions, f.Meta.Pos)
+				field.Label = FieldLabelOptional
+				i := index
+				field.OneofIndex = &i
+				addField(field, f.Meta, f.Comments, f.InlineComment)
+			}
+		case *parser.Message:
+			nestedPath := appendPath(path, messageNestedTypeNumber, int32(len(m.NestedType)))
+			addNested(c.message(fullName, n.MessageName, n.MessageBody, nestedPath))
+			c.locate(nestedPath, n.Meta, n.Comments, n.InlineComment)
+		case *parser.Enum:
+			enumPath := appendPath(path, messageEnumTypeNumber, int32(len(m.EnumType)))
+			m.EnumType = append(m.EnumType, c.enum(fullName, n, enumPath))
+			c.locate(enumPath, n.Meta, n.Comments, n.InlineComment)
+		case *parser.Extend:
+			c.locate(appendPath(path, messageExtensionNumber), n.Meta, n.Comments, n.InlineComment)
+			c.extend(fullName, n, &m.Extension, &m.NestedType, path)
+		case *parser.Extensions:
+			for _, r := range c.ranges(n.Ranges, maxFieldNumber, n.Meta.Pos) {
+				m.ExtensionRange = append(m.ExtensionRange, &ExtensionRange{Start: r.Start, End: r.End + 1})
+			}
+		case *parser.Reserved:
+			for _, r := range c.ranges(n.Ranges, maxFieldNumber, n.Meta.Pos) {
+				m.ReservedRange = append(m.ReservedRange, &ReservedRange{Start: r.Start, End: r.End + 1})
+			}
+			m.ReservedName = append(m.ReservedName, reservedNames(n.FieldNames)...)
+		case *parser.Option:
+			options = append(options, option{name: n.OptionName, value: n.Value, pos: n.Meta.Pos})
+		}
+	}
+
+	// the synthetic oneofs for the proto3 optional fields follow the other oneofs.
+	for _, field := range proto3Optionals {
+		index := int32(len(m.OneofDecl))
+		m.OneofDecl = append(m.OneofDecl, &OneofDescriptorProto{Name: "_" + field.Name})
+		field.OneofIndex = &index
+	}
+	m.Options = c.encodeOptions(messageOptions, fullName, options)
+	return m
+}
+
+// label returns the label of the field which is not in a oneof.
+func (c *compiler) label(isRepeated, isRequired bool) FieldLabel {
+	switch {
+	case isRepeated:
+		return FieldLabelRepeated
+	case isRequired:
+		return FieldLabelRequired
+	}
+	return FieldLabelOptional
+}
+
+// field compiles the field whose type is the typeName. The node is the one which the linker resolved the type for.
+func (c *compiler) field(
+	scope, name, number, typeName string,
+	node interface{},
+	fieldOpts []*parser.FieldOption,
+	pos meta.Position,
+) *FieldDescriptorProto {
+	n, err := parseInt32(number)
+	if err != nil {
+		c.errorf(pos, "invalid field number %s", number)
+	}
+	field := &FieldDescriptorProto{
+		Name:     name,
+		Number:   n,
+		JSONName: jsonName(name),
+	}
+	if typeName != "" {
+		c.fieldType(field, typeName, node, pos)
+	}
+
+	var options []option
+	for _, o := range fieldOpts {
+		switch o.OptionName {
+		case "default":
+			field.DefaultValue = c.defaultValue(field, o.Value, pos)
+		case "json_name":
+			if o.Value != nil && o.Value.Kind == parser.OptionValueKindString {
+				b, _ := strlit.Unquote(o.Value.Scalar)
+				field.JSONName = string(b)
+			}
+		default:
+			options = append(options, option{name: o.OptionName, value: o.Value, pos: pos})
+		}
+	}
+	field.Options = c.encodeOptions(fieldOptions, scope, options)
+	return field
+}
+
+// fieldType sets the type of the field.
+func (c *compiler) fieldType(field *FieldDescriptorProto, typeName string, node interface{}, pos meta.Position) {
+	if typ, ok := scalarTypes[typeName]; ok {
+		field.Type = typ
+		return
+	}
+	symbol := c.table.Resolve(node)
+	if symbol == nil {
+		c.errorf(pos, "unresolved type %s", typeName)
+		field.Type = FieldTypeMessage
+		field.TypeName = typeName
+		return
+	}
+	field.TypeName = "." + symbol.FullName
+	if symbol.Kind == linker.SymbolKindEnum {
+		field.Type = FieldTypeEnum
+	} else {
+		field.Type = FieldTypeMessage
+	}
+}
+
+// group compiles the field of the group.
+func (c *compiler) group(scope string, n *parser.GroupField) *FieldDescriptorProto {
+	field := c.field(scope, strings.ToLower(n.GroupName), n.FieldNumber, "", nil, nil, n.Meta.Pos)
+	field.Label = c.label(n.IsRepeated, n.IsRequired)
+	field.Type = FieldTypeGroup
+	field.TypeName = "." + join(scope, n.GroupName)
+	return field
+}
+
+// defaultValue returns the default value as text, like protoc.
+func (c *compiler) defaultValue(field *FieldDescriptorProto, v *parser.OptionValue, pos meta.Position) *string {
+	if v == nil {
+		return nil
+	}
+	text := v.Scalar
+	switch field.Type {
+	case FieldTypeString, FieldTypeBytes:
+		b, err := strlit.Unquote(v.Scalar)
+		if err != nil {
+			c.errorf(pos, "invalid default value %s", v.Scalar)
+			return nil
+		}
+		text = string(b)
+		if field.Type == FieldTypeBytes {
+			text = cEscape(b)
+		}
+	case FieldTypeFloat, FieldTypeDouble:
+		x, err := parseFloat(v)
+		if err != nil {
+			c.errorf(pos, "invalid default value %s", v.Scalar)
+			return nil
+		}
+		switch {
+		case math.IsInf(x, 1):
+			text = "inf"
+		case math.IsInf(x, -1):
+			text = "-inf"
+		case math.IsNaN(x):
+			text = "nan"
+		default:
+			text = strconv.FormatFloat(x, 'g', -1, 64)
+		}
+	case FieldTypeInt32, FieldTypeInt64, FieldTypeSint32, FieldTypeSint64, FieldTypeSfixed32, FieldTypeSfixed64:
+		x, err := strconv.ParseInt(strings.TrimPrefix(v.Scalar, "+"), 0, 64)
+		if err != nil {
+			c.errorf(pos, "invalid default value %s", v.Scalar)
+			return nil
+		}
+		text = strconv.FormatInt(x, 10)
+	case FieldTypeUint32, FieldTypeUint64, FieldTypeFixed32, FieldTypeFixed64:
+		x, err := strconv.ParseUint(strings.TrimPrefix(v.Scalar, "+"), 0, 64)
+		if err != nil {
+			c.errorf(pos, "invalid default value %s", v.Scalar)
+			return nil
+		}
+		text = strconv.FormatUint(x, 10)
+	}
+	return &text
+}
+
+// extend compiles the extensions in the extend. The groups in it are added to the nested types.
+// The path is the one of the message which has the extend, or nil for the file.
+func (c *compiler) extend(
+	scope string,
+	n *parser.Extend,
+	extensions *[]*FieldDescriptorProto,
+	nestedTypes *[]*DescriptorProto,
+	path []int32,
+) {
+	extendee := n.MessageType
+	if symbol := c.table.Resolve(n); symbol != nil {
+		extendee = "." + symbol.FullName
+	} else {
+		c.errorf(n.Meta.Pos, "unresolved extendee %s", n.MessageType)
+	}
+
+	extensionNumber, nestedNumber := int32(fileExtensionNumber), int32(fileMessageTypeNumber)
+	if path != nil {
+		extensionNumber, nestedNumber = messageExtensionNumber, messageNestedTypeNumber
+	}
+	for _, stmt := range n.ExtendBody {
+		var field *FieldDescriptorProto
+		var m meta.Meta
+		var comments []*parser.Comment
+		var inline *parser.Comment
+		switch f := stmt.(type) {
+		case *parser.Field:
+			field = c.field(scope, f.FieldName, f.FieldNumber, f.Type, f, f.FieldOptions, f.Meta.Pos)
+			field.Label = c.label(f.IsRepeated, f.IsRequired)
+			if c.proto3 && f.IsOptional {
+				field.Proto3Optional = true
+			}
+			m, comments, inline = f.Meta, f.Comments, f.InlineComment
+		case *parser.GroupField:
+			groupPath := appendPath(path, nestedNumber, int32(len(*nestedTypes)))
+			*nestedTypes = append(*nestedTypes, c.message(scope, f.GroupName, f.MessageBody, groupPath))
+			field = c.group(scope, f)
+			m, comments, inline = f.Meta, f.Comments, f.InlineComment
+		default:
+			continue
+		}
+		field.Extendee = extendee
+		c.locate(appendPath(path, extensionNumber, int32(len(*extensions))), m, comments, inline)
+		*extensions = append(*extensions, field)
+	}
+}
+
+// enum compiles the enum.
+func (c *compiler) enum(scope string, n *parser.Enum, path []int32) *EnumDescriptorProto {
+	fullName := join(scope, n.EnumName)
+	enum := &EnumDescriptorProto{
+		Name: n.EnumName,
+	}
+	var options []option
+	for _, stmt := range n.EnumBody {
+		switch s := stmt.(type) {
+		case *parser.EnumField:
+			number, err := parseInt32(s.Number)
+			if err != nil {
+				c.errorf(s.Meta.Pos, "invalid enum value number %s", s.Number)
+			}
+			var valueOptions []option
+			for _, o := range s.EnumValueOptions {
+				valueOptions = append(valueOptions, option{name: o.OptionName, value: o.Value, pos: s.Meta.Pos})
+			}
+			c.locate(appendPath(path, enumValueNumber, int32(len(enum.Value))), s.Meta, s.Comments, s.InlineComment)
+			enum.Value = append(enum.Value, &EnumValueDescriptorProto{
+				Name:    s.Ident,
+				Number:  number,
+				Options: c.encodeOptions(enumValueOptions, fullName, valueOptions),
+			})
+		case *parser.Reserved:
+			enum.ReservedRange = append(enum.ReservedRange, c.ranges(s.Ranges, maxEnumNumber, s.Meta.Pos)...)
+			enum.ReservedName = append(enum.ReservedName, reservedNames(s.FieldNames)...)
+		case *parser.Option:
+			options = append(options, option{name: s.OptionName, value: s.Value, pos: s.Meta.Pos})
+		}
+	}
+	enum.Options = c.encodeOptions(enumOptions, fullName, options)
+	return enum
+}
+
+// service compiles the service.
+func (c *compiler) service(scope string, n *parser.Service, path []int32) *ServiceDescriptorProto {
+	fullName := join(scope, n.ServiceName)
+	service := &ServiceDescriptorProto{
+		Name: n.ServiceName,
+	}
+	var options []option
+	for _, stmt := range n.ServiceBody {
+		switch s := stmt.(type) {
+		case *parser.RPC:
+			var methodOpts []option
+			for _, o := range s.Options {
+				methodOpts = append(methodOpts, option{name: o.OptionName, value: o.Value, pos: o.Meta.Pos})
+			}
+			c.locate(appendPath(path, serviceMethodNumber, int32(len(service.Method))), s.Meta, s.Comments, s.InlineComment)
+			service.Method = append(service.Method, &MethodDescriptorProto{
+				Name:            s.RPCName,
+				InputType:       c.messageType(s.RPCRequest.MessageType, s.RPCRequest, s.Meta.Pos),
+				OutputType:      c.messageType(s.RPCResponse.MessageType, s.RPCResponse, s.Meta.Pos),
+				Options:         c.encodeOptions(methodOptions, fullName, methodOpts),
+				ClientStreaming: s.RPCRequest.IsStream,
+				ServerStreaming: s.RPCResponse.IsStream,
+			})
+		case *parser.Option:
+			options = append(options, option{name: s.OptionName, value: s.Value, pos: s.Meta.Pos})
+		}
+	}
+	service.Options = c.encodeOptions(serviceOptions, fullName, options)
+	return service
+}
+
+// messageType returns the fully-qualified name of the message type with the leading dot.
+func (c *compiler) messageType(typeName string, node interface{}, pos meta.Position) string {
+	symbol := c.table.Resolve(node)
+	if symbol == nil {
+		c.errorf(pos, "unresolved type %s", typeName)
+		return typeName
+	}
+	return "." + symbol.FullName
+}
+
+// ranges converts the ranges to the inclusive ones. The max is used for the end "max".
+func (c *compiler) ranges(src []*parser.Range, max int32, pos meta.Position) []*EnumReservedRange {
+	var rs []*EnumReservedRange
+	for _, r := range src {
+		begin, err := parseInt32(r.Begin)
+		if err != nil {
+			c.errorf(pos, "invalid range %s", r.Begin)
+			continue
+		}
+		end := begin
+		switch r.End {
+		case "":
+		case "max":
+			end = max
+		default:
+			end, err = parseInt32(r.End)
+			if err != nil {
+				c.errorf(pos, "invalid range %s", r.End)
+				continue
+			}
+		}
+		rs = append(rs, &EnumReservedRange{Start: begin, End: end})
+	}
+	return rs
+}
+
+// reservedNames returns the names without the quotes.
+func reservedNames(names []string) []string {
+	var unquoted []string
+	for _, name := range names {
+		if b, err := strlit.Unquote(name); err == nil {
+			name = string(b)
+		}
+		unquoted = append(unquoted, name)
+	}
+	return unquoted
+}
+
+// jsonName returns the default JSON name of the field, like protoc.
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && 'a' <= r && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteRune(r)
+			upper = false
+		}
+	}
+	return b.String()
+}
+
+// mapEntryName returns the name of the synthetic message for the map field, like protoc.
+func mapEntryName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && 'a' <= r && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteRune(r)
+			upper = false
+		}
+	}
+	return b.String() + "Entry"
+}
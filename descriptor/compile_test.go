@@ -0,0 +1,432 @@
+package descriptor_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/yoheimuta/go-protoparser/v4/descriptor"
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+)
+
+func parse(t *testing.T, files map[string]string, roots ...string) *fileset.FileSet {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	set, err := fileset.Parse(roots, fileset.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return set
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+func TestCompileFileSet(t *testing.T) {
+	set := parse(t, map[string]string{
+		"a.proto": `syntax = "proto3";
+package foo;
+import public "b.proto";
+option go_package = "example.com/foo";
+
+message Outer {
+  string user_name = 1 [deprecated = true];
+  optional int32 count = 2;
+  map<string, bar.Shared> shared = 3;
+  oneof choice {
+    Kind kind = 4;
+  }
+  reserved 10 to 20, 100 to max;
+  reserved "old";
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+  }
+}
+
+service S {
+  rpc Get(Outer) returns (stream .foo.Outer);
+}
+`,
+		"b.proto": `syntax = "proto2";
+package bar;
+message Shared {
+  optional string s = 1 [default = "a\nb", json_name = "S"];
+  required group Result = 2 {}
+  extensions 100 to 200;
+}
+extend Shared {
+  repeated bytes ext = 100;
+}
+`,
+	}, "a.proto")
+
+	got, err := descriptor.CompileFileSet(set)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	want := &descriptor.FileDescriptorSet{
+		File: []*descriptor.FileDescriptorProto{
+			{
+				Name:    "b.proto",
+				Package: "bar",
+				MessageType: []*descriptor.DescriptorProto{
+					{
+						Name: "Shared",
+						Field: []*descriptor.FieldDescriptorProto{
+							{
+								Name:         "s",
+								Number:       1,
+								Label:        descriptor.FieldLabelOptional,
+								Type:         descriptor.FieldTypeString,
+								DefaultValue: stringPtr("a\nb"),
+								JSONName:     "S",
+							},
+							{
+								Name:     "result",
+								Number:   2,
+								Label:    descriptor.FieldLabelRequired,
+								Type:     descriptor.FieldTypeGroup,
+								TypeName: ".bar.Shared.Result",
+								JSONName: "result",
+							},
+						},
+						NestedType: []*descriptor.DescriptorProto{
+							{
+								Name: "Result",
+							},
+						},
+						ExtensionRange: []*descriptor.ExtensionRange{
+							{Start: 100, End: 201},
+						},
+					},
+				},
+				Extension: []*descriptor.FieldDescriptorProto{
+					{
+						Name:     "ext",
+						Number:   100,
+						Label:    descriptor.FieldLabelRepeated,
+						Type:     descriptor.FieldTypeBytes,
+						Extendee: ".bar.Shared",
+						JSONName: "ext",
+					},
+				},
+			},
+			{
+				Name:             "a.proto",
+				Package:          "foo",
+				Dependency:       []string{"b.proto"},
+				PublicDependency: []int32{0},
+				MessageType: []*descriptor.DescriptorProto{
+					{
+						Name: "Outer",
+						Field: []*descriptor.FieldDescriptorProto{
+							{
+								Name:     "user_name",
+								Number:   1,
+								Label:    descriptor.FieldLabelOptional,
+								Type:     descriptor.FieldTypeString,
+								JSONName: "userName",
+								// deprecated = true
+								Options: descriptor.Options{0x18, 0x01},
+							},
+							{
+								Name:           "count",
+								Number:         2,
+								Label:          descriptor.FieldLabelOptional,
+								Type:           descriptor.FieldTypeInt32,
+								OneofIndex:     int32Ptr(1),
+								JSONName:       "count",
+								Proto3Optional: true,
+							},
+							{
+								Name:     "shared",
+								Number:   3,
+								Label:    descriptor.FieldLabelRepeated,
+								Type:     descriptor.FieldTypeMessage,
+								TypeName: ".foo.Outer.SharedEntry",
+								JSONName: "shared",
+							},
+							{
+								Name:       "kind",
+								Number:     4,
+								Label:      descriptor.FieldLabelOptional,
+								Type:       descriptor.FieldTypeEnum,
+								TypeName:   ".foo.Outer.Kind",
+								OneofIndex: int32Ptr(0),
+								JSONName:   "kind",
+							},
+						},
+						NestedType: []*descriptor.DescriptorProto{
+							{
+								Name: "SharedEntry",
+								Field: []*descriptor.FieldDescriptorProto{
+									{
+										Name:     "key",
+										Number:   1,
+										Label:    descriptor.FieldLabelOptional,
+										Type:     descriptor.FieldTypeString,
+										JSONName: "key",
+									},
+									{
+										Name:     "value",
+										Number:   2,
+										Label:    descriptor.FieldLabelOptional,
+										Type:     descriptor.FieldTypeMessage,
+										TypeName: ".bar.Shared",
+										JSONName: "value",
+									},
+								},
+								// map_entry = true
+								Options: descriptor.Options{0x38, 0x01},
+							},
+						},
+						EnumType: []*descriptor.EnumDescriptorProto{
+							{
+								Name: "Kind",
+								Value: []*descriptor.EnumValueDescriptorProto{
+									{Name: "KIND_UNSPECIFIED", Number: 0},
+								},
+							},
+						},
+						OneofDecl: []*descriptor.OneofDescriptorProto{
+							{Name: "choice"},
+							{Name: "_count"},
+						},
+						ReservedRange: []*descriptor.ReservedRange{
+							{Start: 10, End: 21},
+							{Start: 100, End: 536870912},
+						},
+						ReservedName: []string{"old"},
+					},
+				},
+				Service: []*descriptor.ServiceDescriptorProto{
+					{
+						Name: "S",
+						Method: []*descriptor.MethodDescriptorProto{
+							{
+								Name:            "Get",
+								InputType:       ".foo.Outer",
+								OutputType:      ".foo.Outer",
+								ServerStreaming: true,
+							},
+						},
+					},
+				},
+				// go_package = "example.com/foo"
+				Options: append(descriptor.Options{0x5a, 0x0f}, "example.com/foo"...),
+				Syntax:  "proto3",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for i := range want.File {
+			if i < len(got.File) && !reflect.DeepEqual(got.File[i], want.File[i]) {
+				t.Errorf("got %+v, but want %+v", got.File[i], want.File[i])
+			}
+		}
+		t.Errorf("got %d files, but want %d files", len(got.File), len(want.File))
+	}
+}
+
+func TestCompileFileSet_options(t *testing.T) {
+	set := parse(t, map[string]string{
+		"google/protobuf/descriptor.proto": `syntax = "proto2";
+package google.protobuf;
+message MessageOptions {
+  extensions 1000 to max;
+}
+`,
+		"a.proto": `syntax = "proto2";
+package foo;
+import "google/protobuf/descriptor.proto";
+
+message Rule {
+  optional string name = 1;
+  repeated sint32 values = 2;
+}
+
+extend google.protobuf.MessageOptions {
+  optional Rule rule = 1000;
+  optional double weight = 1001;
+}
+
+message M {
+  option deprecated = true;
+  option (rule) = { name: "x" values: [-1, 1] };
+  option (weight) = 0.5;
+  option (foo.rule).name = "y";
+}
+`,
+	}, "a.proto")
+
+	got, err := descriptor.CompileFileSet(set)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	want := descriptor.Options{
+		// deprecated = true
+		0x18, 0x01,
+		// (rule) = { name: "x" values: [-1, 1] }
+		0xc2, 0x3e, 0x07, 0x0a, 0x01, 'x', 0x10, 0x01, 0x10, 0x02,
+		// (rule).name = "y"
+		0xc2, 0x3e, 0x03, 0x0a, 0x01, 'y',
+		// (weight) = 0.5
+		0xc9, 0x3e, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xe0, 0x3f,
+	}
+	m := got.File[1].MessageType[1]
+	if !reflect.DeepEqual(m.Options, want) {
+		t.Errorf("got %x, but want %x", m.Options, want)
+	}
+}
+
+func TestCompileFileSet_errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError string
+	}{
+		{
+			name: "unknown option",
+			input: `syntax = "proto3";
+message M {
+  option unknown = true;
+}
+`,
+			wantError: "a.proto:3:3: option unknown: unknown field unknown",
+		},
+		{
+			name: "unknown extension",
+			input: `syntax = "proto3";
+option (foo.bar) = true;
+`,
+			wantError: "a.proto:2:1: option (foo.bar): unknown extension foo.bar",
+		},
+		{
+			name: "invalid value",
+			input: `syntax = "proto3";
+option java_multiple_files = "yes";
+`,
+			wantError: "a.proto:2:1: option java_multiple_files: java_multiple_files must be a bool",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			set := parse(t, map[string]string{"a.proto": test.input}, "a.proto")
+			got, err := descriptor.CompileFileSet(set)
+			if got == nil {
+				t.Fatalf("got nil, but want the partial set")
+			}
+			var list descriptor.ErrorList
+			if !errors.As(err, &list) {
+				t.Fatalf("got err %v, but want ErrorList", err)
+			}
+			if len(list) != 1 {
+				t.Fatalf("got %d errors, but want 1", len(list))
+			}
+			if list[0].Error() != test.wantError {
+				t.Errorf("got %q, but want %q", list[0].Error(), test.wantError)
+			}
+		})
+	}
+}
+
+func TestCompileFileSet_sourceCodeInfo(t *testing.T) {
+	set := parse(t, map[string]string{
+		"a.proto": `syntax = "proto3";
+// M is a message.
+// It has a field.
+message M {
+  /* the id */
+  int32 id = 1; // trailing
+}
+`,
+	}, "a.proto")
+
+	got, err := descriptor.CompileFileSet(set, descriptor.WithSourceCodeInfo(true))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	want := &descriptor.SourceCodeInfo{
+		Location: []*descriptor.Location{
+			{
+				Path: []int32{12},
+				Span: []int32{0, 0, 18},
+			},
+			{
+				Path: []int32{4, 0, 2, 0},
+				Span: []int32{5, 2, 15},
+				// the comment is kept as it is between the markers.
+				LeadingComments:  stringPtr(" the id "),
+				TrailingComments: stringPtr(" trailing\n"),
+			},
+			{
+				Path:            []int32{4, 0},
+				Span:            []int32{3, 0, 6, 1},
+				LeadingComments: stringPtr(" M is a message.\n It has a field.\n"),
+			},
+		},
+	}
+	if !reflect.DeepEqual(got.File[0].SourceCodeInfo, want) {
+		t.Errorf("got %+v, but want %+v", got.File[0].SourceCodeInfo.Location, want.Location)
+	}
+}
+
+func TestFileDescriptorSet_Marshal(t *testing.T) {
+	set := &descriptor.FileDescriptorSet{
+		File: []*descriptor.FileDescriptorProto{
+			{
+				Name: "a.proto",
+				MessageType: []*descriptor.DescriptorProto{
+					{
+						Name: "M",
+						Field: []*descriptor.FieldDescriptorProto{
+							{
+								Name:     "n",
+								Number:   1,
+								Label:    descriptor.FieldLabelOptional,
+								Type:     descriptor.FieldTypeInt32,
+								JSONName: "n",
+							},
+						},
+					},
+				},
+				Syntax: "proto3",
+			},
+		},
+	}
+
+	want := []byte{
+		// file
+		0x0a, 0x24,
+		// name: "a.proto"
+		0x0a, 0x07, 'a', '.', 'p', 'r', 'o', 't', 'o',
+		// message_type
+		0x22, 0x11,
+		// name: "M"
+		0x0a, 0x01, 'M',
+		// field
+		0x12, 0x0c,
+		// name: "n", number: 1, label: LABEL_OPTIONAL, type: TYPE_INT32, json_name: "n"
+		0x0a, 0x01, 'n', 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 'n',
+		// syntax: "proto3"
+		0x62, 0x06, 'p', 'r', 'o', 't', 'o', '3',
+	}
+	got := set.Marshal()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %x, but want %x", got, want)
+	}
+}
@@ -0,0 +1,516 @@
+package descriptor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var errTruncated = errors.New("unexpected end of the input")
+
+// decoder decodes the fields of a message in the wire format.
+type decoder struct {
+	buf []byte
+}
+
+// decodeFields calls the function for each field in the message.
+// The function must consume the value of the field.
+func decodeFields(b []byte, field func(d *decoder, number int32, typ wireType) error) error {
+	d := &decoder{buf: b}
+	for 0 < len(d.buf) {
+		tag, err := d.varint()
+		if err != nil {
+			return err
+		}
+		number, typ := int32(tag>>3), wireType(tag&7)
+		if number <= 0 {
+			return fmt.Errorf("invalid field number %d", number)
+		}
+		if err := field(d, number, typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decoder) varint() (uint64, error) {
+	var v uint64
+	for i := 0; i < 10; i++ {
+		if len(d.buf) <= i {
+			return 0, errTruncated
+		}
+		b := d.buf[i]
+		v |= uint64(b&0x7f) << (7 * i)
+		if b < 0x80 {
+			d.buf = d.buf[i+1:]
+			return v, nil
+		}
+	}
+	return 0, errors.New("invalid varint")
+}
+
+func (d *decoder) fixed32() (uint32, error) {
+	if len(d.buf) < 4 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint32(d.buf)
+	d.buf = d.buf[4:]
+	return v, nil
+}
+
+func (d *decoder) fixed64() (uint64, error) {
+	if len(d.buf) < 8 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return v, nil
+}
+
+// bytes returns the length-delimited value.
+func (d *decoder) bytes(typ wireType) ([]byte, error) {
+	if typ != wireBytes {
+		return nil, fmt.Errorf("got wire type %d, but want %d", typ, wireBytes)
+	}
+	n, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(d.buf)) < n {
+		return nil, errTruncated
+	}
+	v := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+func (d *decoder) string(typ wireType) (string, error) {
+	b, err := d.bytes(typ)
+	return string(b), err
+}
+
+func (d *decoder) int32(typ wireType) (int32, error) {
+	if typ != wireVarint {
+		return 0, fmt.Errorf("got wire type %d, but want %d", typ, wireVarint)
+	}
+	v, err := d.varint()
+	return int32(v), err
+}
+
+func (d *decoder) bool(typ wireType) (bool, error) {
+	v, err := d.int32(typ)
+	return v != 0, err
+}
+
+// int32s appends the repeated numbers, which are either packed or not.
+func (d *decoder) int32s(typ wireType, vs *[]int32) error {
+	if typ != wireBytes {
+		v, err := d.int32(typ)
+		*vs = append(*vs, v)
+		return err
+	}
+	b, err := d.bytes(typ)
+	if err != nil {
+		return err
+	}
+	p := &decoder{buf: b}
+	for 0 < len(p.buf) {
+		v, err := p.varint()
+		if err != nil {
+			return err
+		}
+		*vs = append(*vs, int32(v))
+	}
+	return nil
+}
+
+// message decodes the message value by the function.
+func (d *decoder) message(typ wireType, decode func(b []byte) error) error {
+	b, err := d.bytes(typ)
+	if err != nil {
+		return err
+	}
+	return decode(b)
+}
+
+// skip consumes the value of the unknown field.
+func (d *decoder) skip(number int32, typ wireType) error {
+	var err error
+	switch typ {
+	case wireVarint:
+		_, err = d.varint()
+	case wireFixed64:
+		_, err = d.fixed64()
+	case wireFixed32:
+		_, err = d.fixed32()
+	case wireBytes:
+		_, err = d.bytes(typ)
+	case wireStartGroup:
+		_, err = d.group(number)
+	default:
+		err = fmt.Errorf("unexpected wire type %d", typ)
+	}
+	return err
+}
+
+// group returns the fields of the group until the end tag of the number.
+func (d *decoder) group(number int32) ([]byte, error) {
+	start := d.buf
+	for {
+		if len(d.buf) == 0 {
+			return nil, errTruncated
+		}
+		end := len(start) - len(d.buf)
+		tag, err := d.varint()
+		if err != nil {
+			return nil, err
+		}
+		n, typ := int32(tag>>3), wireType(tag&7)
+		if typ == wireEndGroup {
+			if n != number {
+				return nil, fmt.Errorf("got the end of the group %d, but want %d", n, number)
+			}
+			return start[:end:end], nil
+		}
+		if err := d.skip(n, typ); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Unmarshal decodes the set in the wire format. The unknown fields are ignored.
+func (s *FileDescriptorSet) Unmarshal(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		if number != 1 {
+			return d.skip(number, typ)
+		}
+		file := &FileDescriptorProto{}
+		s.File = append(s.File, file)
+		return d.message(typ, file.Unmarshal)
+	})
+}
+
+// Unmarshal decodes the file in the wire format. The unknown fields are ignored.
+func (f *FileDescriptorProto) Unmarshal(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			f.Name, err = d.string(typ)
+		case 2:
+			f.Package, err = d.string(typ)
+		case 3:
+			var dep string
+			dep, err = d.string(typ)
+			f.Dependency = append(f.Dependency, dep)
+		case 4:
+			m := &DescriptorProto{}
+			f.MessageType = append(f.MessageType, m)
+			err = d.message(typ, m.decode)
+		case 5:
+			enum := &EnumDescriptorProto{}
+			f.EnumType = append(f.EnumType, enum)
+			err = d.message(typ, enum.decode)
+		case 6:
+			s := &ServiceDescriptorProto{}
+			f.Service = append(f.Service, s)
+			err = d.message(typ, s.decode)
+		case 7:
+			ext := &FieldDescriptorProto{}
+			f.Extension = append(f.Extension, ext)
+			err = d.message(typ, ext.decode)
+		case 8:
+			f.Options, err = d.bytes(typ)
+		case 9:
+			f.SourceCodeInfo = &SourceCodeInfo{}
+			err = d.message(typ, f.SourceCodeInfo.decode)
+		case 10:
+			err = d.int32s(typ, &f.PublicDependency)
+		case 11:
+			err = d.int32s(typ, &f.WeakDependency)
+		case 12:
+			f.Syntax, err = d.string(typ)
+		case 14:
+			var edition int32
+			edition, err = d.int32(typ)
+			f.Edition = Edition(edition)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (m *DescriptorProto) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			m.Name, err = d.string(typ)
+		case 2:
+			field := &FieldDescriptorProto{}
+			m.Field = append(m.Field, field)
+			err = d.message(typ, field.decode)
+		case 3:
+			nested := &DescriptorProto{}
+			m.NestedType = append(m.NestedType, nested)
+			err = d.message(typ, nested.decode)
+		case 4:
+			enum := &EnumDescriptorProto{}
+			m.EnumType = append(m.EnumType, enum)
+			err = d.message(typ, enum.decode)
+		case 5:
+			r := &ExtensionRange{}
+			m.ExtensionRange = append(m.ExtensionRange, r)
+			err = d.message(typ, r.decode)
+		case 6:
+			ext := &FieldDescriptorProto{}
+			m.Extension = append(m.Extension, ext)
+			err = d.message(typ, ext.decode)
+		case 7:
+			m.Options, err = d.bytes(typ)
+		case 8:
+			oneof := &OneofDescriptorProto{}
+			m.OneofDecl = append(m.OneofDecl, oneof)
+			err = d.message(typ, oneof.decode)
+		case 9:
+			r := &ReservedRange{}
+			m.ReservedRange = append(m.ReservedRange, r)
+			err = d.message(typ, r.decode)
+		case 10:
+			var name string
+			name, err = d.string(typ)
+			m.ReservedName = append(m.ReservedName, name)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (r *ExtensionRange) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			r.Start, err = d.int32(typ)
+		case 2:
+			r.End, err = d.int32(typ)
+		case 3:
+			r.Options, err = d.bytes(typ)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (r *ReservedRange) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			r.Start, err = d.int32(typ)
+		case 2:
+			r.End, err = d.int32(typ)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (f *FieldDescriptorProto) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			f.Name, err = d.string(typ)
+		case 2:
+			f.Extendee, err = d.string(typ)
+		case 3:
+			f.Number, err = d.int32(typ)
+		case 4:
+			var label int32
+			label, err = d.int32(typ)
+			f.Label = FieldLabel(label)
+		case 5:
+			var t int32
+			t, err = d.int32(typ)
+			f.Type = FieldType(t)
+		case 6:
+			f.TypeName, err = d.string(typ)
+		case 7:
+			var v string
+			v, err = d.string(typ)
+			f.DefaultValue = &v
+		case 8:
+			f.Options, err = d.bytes(typ)
+		case 9:
+			var index int32
+			index, err = d.int32(typ)
+			f.OneofIndex = &index
+		case 10:
+			f.JSONName, err = d.string(typ)
+		case 17:
+			f.Proto3Optional, err = d.bool(typ)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (o *OneofDescriptorProto) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			o.Name, err = d.string(typ)
+		case 2:
+			o.Options, err = d.bytes(typ)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (enum *EnumDescriptorProto) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			enum.Name, err = d.string(typ)
+		case 2:
+			v := &EnumValueDescriptorProto{}
+			enum.Value = append(enum.Value, v)
+			err = d.message(typ, v.decode)
+		case 3:
+			enum.Options, err = d.bytes(typ)
+		case 4:
+			r := &EnumReservedRange{}
+			enum.ReservedRange = append(enum.ReservedRange, r)
+			err = d.message(typ, r.decode)
+		case 5:
+			var name string
+			name, err = d.string(typ)
+			enum.ReservedName = append(enum.ReservedName, name)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (r *EnumReservedRange) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			r.Start, err = d.int32(typ)
+		case 2:
+			r.End, err = d.int32(typ)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (v *EnumValueDescriptorProto) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			v.Name, err = d.string(typ)
+		case 2:
+			v.Number, err = d.int32(typ)
+		case 3:
+			v.Options, err = d.bytes(typ)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (s *ServiceDescriptorProto) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			s.Name, err = d.string(typ)
+		case 2:
+			m := &MethodDescriptorProto{}
+			s.Method = append(s.Method, m)
+			err = d.message(typ, m.decode)
+		case 3:
+			s.Options, err = d.bytes(typ)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (m *MethodDescriptorProto) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			m.Name, err = d.string(typ)
+		case 2:
+			m.InputType, err = d.string(typ)
+		case 3:
+			m.OutputType, err = d.string(typ)
+		case 4:
+			m.Options, err = d.bytes(typ)
+		case 5:
+			m.ClientStreaming, err = d.bool(typ)
+		case 6:
+			m.ServerStreaming, err = d.bool(typ)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
+
+func (s *SourceCodeInfo) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		if number != 1 {
+			return d.skip(number, typ)
+		}
+		l := &Location{}
+		s.Location = append(s.Location, l)
+		return d.message(typ, l.decode)
+	})
+}
+
+func (l *Location) decode(b []byte) error {
+	return decodeFields(b, func(d *decoder, number int32, typ wireType) error {
+		var err error
+		switch number {
+		case 1:
+			err = d.int32s(typ, &l.Path)
+		case 2:
+			err = d.int32s(typ, &l.Span)
+		case 3:
+			var c string
+			c, err = d.string(typ)
+			l.LeadingComments = &c
+		case 4:
+			var c string
+			c, err = d.string(typ)
+			l.TrailingComments = &c
+		case 6:
+			var c string
+			c, err = d.string(typ)
+			l.LeadingDetachedComments = append(l.LeadingDetachedComments, c)
+		default:
+			err = d.skip(number, typ)
+		}
+		return err
+	})
+}
@@ -0,0 +1,563 @@
+package descriptor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Decompile converts the files in the set back into the parsed files, which the same Visitor can walk.
+//
+// The types are referred to by their fully-qualified names with the leading dot, like ".foo.Bar".
+// The declarations are ordered by their kinds, since the set doesn't keep the order in the source.
+// When the file has the SourceCodeInfo, the comments and the positions of the declarations are restored from it.
+//
+// Decompile returns the files along with an ErrorList when any options can't be decoded,
+// like a custom option whose extension is not in the set.
+func Decompile(set *FileDescriptorSet) ([]*parser.Proto, error) {
+	index := newIndex(set)
+	var protos []*parser.Proto
+	var errs ErrorList
+	for _, file := range set.File {
+		d := &decompiler{
+			index:     index,
+			file:      file,
+			locations: make(map[string]*Location),
+		}
+		protos = append(protos, d.decompile())
+		errs = append(errs, d.errors...)
+	}
+	if 0 < len(errs) {
+		return protos, errs
+	}
+	return protos, nil
+}
+
+// extension is an extension field with its fully-qualified name.
+type extension struct {
+	fullName string
+	field    *FieldDescriptorProto
+}
+
+// index holds the definitions in the set by their fully-qualified names without the leading dot.
+type index struct {
+	messages map[string]*DescriptorProto
+	enums    map[string]*EnumDescriptorProto
+	// extensions are keyed by the extendee and the number.
+	extensions map[string]map[int32]*extension
+}
+
+func newIndex(set *FileDescriptorSet) *index {
+	x := &index{
+		messages:   make(map[string]*DescriptorProto),
+		enums:      make(map[string]*EnumDescriptorProto),
+		extensions: make(map[string]map[int32]*extension),
+	}
+	for _, file := range set.File {
+		x.add(file.Package, file.MessageType, file.EnumType, file.Extension)
+	}
+	return x
+}
+
+func (x *index) add(scope string, messages []*DescriptorProto, enums []*EnumDescriptorProto, extensions []*FieldDescriptorProto) {
+	for _, m := range messages {
+		name := join(scope, m.Name)
+		x.messages[name] = m
+		x.add(name, m.NestedType, m.EnumType, m.Extension)
+	}
+	for _, enum := range enums {
+		x.enums[join(scope, enum.Name)] = enum
+	}
+	for _, ext := range extensions {
+		extendee := strings.TrimPrefix(ext.Extendee, ".")
+		if x.extensions[extendee] == nil {
+			x.extensions[extendee] = make(map[int32]*extension)
+		}
+		x.extensions[extendee][ext.Number] = &extension{fullName: join(scope, ext.Name), field: ext}
+	}
+}
+
+type decompiler struct {
+	index  *index
+	file   *FileDescriptorProto
+	errors ErrorList
+
+	// locations are keyed by pathKey.
+	locations map[string]*Location
+}
+
+func (d *decompiler) errorf(format string, args ...interface{}) {
+	d.errors = append(d.errors, &Error{
+		Pos:     meta.Position{Filename: d.file.Name},
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *decompiler) decompile() *parser.Proto {
+	f := d.file
+	if f.SourceCodeInfo != nil {
+		for _, l := range f.SourceCodeInfo.Location {
+			// the first one is used, like protoc.
+			if _, ok := d.locations[pathKey(l.Path)]; !ok {
+				d.locations[pathKey(l.Path)] = l
+			}
+		}
+	}
+
+	proto := &parser.Proto{
+		Meta: &parser.ProtoMeta{Filename: f.Name},
+	}
+	switch f.Syntax {
+	case "editions":
+		m, comments, inline := d.locate([]int32{fileEditionNumber})
+		text := editionText(f.Edition)
+		proto.Edition = &parser.Edition{
+			Edition:       text,
+			EditionQuote:  strconv.Quote(text),
+			Comments:      comments,
+			InlineComment: inline,
+			Meta:          m,
+		}
+	default:
+		version := f.Syntax
+		if version == "" {
+			version = "proto2"
+		}
+		m, comments, inline := d.locate([]int32{fileSyntaxNumber})
+		proto.Syntax = &parser.Syntax{
+			ProtobufVersion:      version,
+			ProtobufVersionQuote: strconv.Quote(version),
+			Comments:             comments,
+			InlineComment:        inline,
+			Meta:                 m,
+		}
+	}
+
+	var body []parser.Visitee
+	if f.Package != "" {
+		m, comments, inline := d.locate([]int32{filePackageNumber})
+		body = append(body, &parser.Package{
+			Name:          f.Package,
+			Comments:      comments,
+			InlineComment: inline,
+			Meta:          m,
+		})
+	}
+	for i, dep := range f.Dependency {
+		modifier := parser.ImportModifierNone
+		if containsInt32(f.PublicDependency, int32(i)) {
+			modifier = parser.ImportModifierPublic
+		} else if containsInt32(f.WeakDependency, int32(i)) {
+			modifier = parser.ImportModifierWeak
+		}
+		m, comments, inline := d.locate([]int32{fileDependencyNumber, int32(i)})
+		body = append(body, &parser.Import{
+			Modifier:      modifier,
+			Location:      strconv.Quote(dep),
+			Comments:      comments,
+			InlineComment: inline,
+			Meta:          m,
+		})
+	}
+	body = append(body, statements(d.options(f.Options, "google.protobuf.FileOptions", fileOptions))...)
+	for i, m := range f.MessageType {
+		body = append(body, d.message(f.Package, m, []int32{fileMessageTypeNumber, int32(i)}))
+	}
+	for i, enum := range f.EnumType {
+		body = append(body, d.enum(enum, []int32{fileEnumTypeNumber, int32(i)}))
+	}
+	for i, s := range f.Service {
+		body = append(body, d.service(s, []int32{fileServiceNumber, int32(i)}))
+	}
+	body = append(body, d.extends(f.Package, f.Extension, f.MessageType, nil)...)
+	proto.ProtoBody = body
+	return proto
+}
+
+// editionText returns the text of the edition, like "2023".
+func editionText(e Edition) string {
+	switch e {
+	case Edition2023:
+		return "2023"
+	case Edition2024:
+		return "2024"
+	}
+	return strconv.Itoa(int(e))
+}
+
+func containsInt32(vs []int32, v int32) bool {
+	for _, x := range vs {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// proto2 reports whether the optional fields of the file have the "optional" label.
+func (d *decompiler) proto2() bool {
+	return d.file.Syntax == "" || d.file.Syntax == "proto2"
+}
+
+// message decompiles the message. The scope is the full name of the enclosing package or message.
+func (d *decompiler) message(scope string, m *DescriptorProto, path []int32) *parser.Message {
+	mt, comments, inline := d.locate(path)
+	return &parser.Message{
+		MessageName:   m.Name,
+		MessageBody:   d.messageBody(join(scope, m.Name), m, path),
+		Comments:      comments,
+		InlineComment: inline,
+		Meta:          mt,
+	}
+}
+
+func (d *decompiler) messageBody(fullName string, m *DescriptorProto, path []int32) []parser.Visitee {
+	body := statements(d.options(m.Options, "google.protobuf.MessageOptions", messageOptions))
+
+	// the map entries and the groups are declared by the fields.
+	nested := make(map[string]int)
+	for i, n := range m.NestedType {
+		nested["."+join(fullName, n.Name)] = i
+	}
+	declared := make(map[int]bool)
+
+	oneofs := make(map[int32]*parser.Oneof)
+	for i, field := range m.Field {
+		fieldPath := appendPath(path, messageFieldNumber, int32(i))
+		var stmt parser.Visitee
+		if j, ok := nested[field.TypeName]; ok && isMapEntry(m.NestedType[j]) {
+			declared[j] = true
+			stmt = d.mapField(field, m.NestedType[j], fieldPath)
+		} else if ok && field.Type == FieldTypeGroup {
+			declared[j] = true
+			stmt = d.group(fullName, field, m.NestedType[j], fieldPath, appendPath(path, messageNestedTypeNumber, int32(j)))
+		} else {
+			stmt = d.field(field, fieldPath)
+		}
+
+		if field.OneofIndex == nil || field.Proto3Optional || int(*field.OneofIndex) >= len(m.OneofDecl) {
+			body = append(body, stmt)
+			continue
+		}
+		index := *field.OneofIndex
+		oneof, ok := oneofs[index]
+		if !ok {
+			oneofPath := appendPath(path, messageOneofDeclNumber, index)
+			mt, comments, inline := d.locate(oneofPath)
+			oneof = &parser.Oneof{
+				OneofName:     m.OneofDecl[index].Name,
+				Options:       d.options(m.OneofDecl[index].Options, "google.protobuf.OneofOptions", oneofOptions),
+				Comments:      comments,
+				InlineComment: inline,
+				Meta:          mt,
+			}
+			oneofs[index] = oneof
+			body = append(body, oneof)
+		}
+		f, ok := stmt.(*parser.Field)
+		if !ok {
+			// the parser.Oneof can't have a group.
+			body = append(body, stmt)
+			continue
+		}
+		oneof.OneofFields = append(oneof.OneofFields, &parser.OneofField{
+			Type:          f.Type,
+			FieldName:     f.FieldName,
+			FieldNumber:   f.FieldNumber,
+			FieldOptions:  f.FieldOptions,
+			Comments:      f.Comments,
+			InlineComment: f.InlineComment,
+			Meta:          f.Meta,
+		})
+	}
+
+	for i, n := range m.NestedType {
+		if !declared[i] {
+			body = append(body, d.message(fullName, n, appendPath(path, messageNestedTypeNumber, int32(i))))
+		}
+	}
+	for i, enum := range m.EnumType {
+		body = append(body, d.enum(enum, appendPath(path, messageEnumTypeNumber, int32(i))))
+	}
+	body = append(body, d.extends(fullName, m.Extension, m.NestedType, path)...)
+
+	if 0 < len(m.ExtensionRange) {
+		extensions := &parser.Extensions{}
+		for _, r := range m.ExtensionRange {
+			extensions.Ranges = append(extensions.Ranges, decompileRange(r.Start, r.End-1, maxFieldNumber))
+		}
+		body = append(body, extensions)
+	}
+	if 0 < len(m.ReservedRange) {
+		reserved := &parser.Reserved{}
+		for _, r := range m.ReservedRange {
+			reserved.Ranges = append(reserved.Ranges, decompileRange(r.Start, r.End-1, maxFieldNumber))
+		}
+		body = append(body, reserved)
+	}
+	if 0 < len(m.ReservedName) {
+		body = append(body, &parser.Reserved{FieldNames: quoteAll(m.ReservedName)})
+	}
+	return body
+}
+
+// isMapEntry reports whether the message is the synthetic entry of a map field.
+func isMapEntry(m *DescriptorProto) bool {
+	mapEntry := false
+	_ = decodeFields(m.Options, func(d *decoder, number int32, typ wireType) error {
+		if number == mapEntryOptionNumber && typ == wireVarint {
+			v, err := d.bool(typ)
+			mapEntry = v
+			return err
+		}
+		return d.skip(number, typ)
+	})
+	return mapEntry && len(m.Field) == 2
+}
+
+// fieldType returns the type of the field in the source.
+func fieldType(field *FieldDescriptorProto) string {
+	if field.TypeName != "" {
+		return field.TypeName
+	}
+	for name, typ := range scalarTypes {
+		if typ == field.Type {
+			return name
+		}
+	}
+	return ""
+}
+
+func (d *decompiler) field(field *FieldDescriptorProto, path []int32) *parser.Field {
+	mt, comments, inline := d.locate(path)
+	return &parser.Field{
+		IsRepeated:    field.Label == FieldLabelRepeated,
+		IsRequired:    field.Label == FieldLabelRequired,
+		IsOptional:    d.isOptional(field),
+		Type:          fieldType(field),
+		FieldName:     field.Name,
+		FieldNumber:   strconv.Itoa(int(field.Number)),
+		FieldOptions:  d.fieldOptions(field),
+		Comments:      comments,
+		InlineComment: inline,
+		Meta:          mt,
+	}
+}
+
+// isOptional reports whether the field has the "optional" label in the source.
+func (d *decompiler) isOptional(field *FieldDescriptorProto) bool {
+	if field.Proto3Optional {
+		return true
+	}
+	return d.proto2() && field.Label == FieldLabelOptional && field.OneofIndex == nil
+}
+
+func (d *decompiler) mapField(field *FieldDescriptorProto, entry *DescriptorProto, path []int32) *parser.MapField {
+	mt, comments, inline := d.locate(path)
+	key, value := entry.Field[0], entry.Field[1]
+	if key.Number != 1 {
+		key, value = value, key
+	}
+	return &parser.MapField{
+		KeyType:       fieldType(key),
+		Type:          fieldType(value),
+		MapName:       field.Name,
+		FieldNumber:   strconv.Itoa(int(field.Number)),
+		FieldOptions:  d.fieldOptions(field),
+		Comments:      comments,
+		InlineComment: inline,
+		Meta:          mt,
+	}
+}
+
+// group decompiles the group field. The scope is the full name of the enclosing message or package.
+func (d *decompiler) group(scope string, field *FieldDescriptorProto, m *DescriptorProto, path, messagePath []int32) *parser.GroupField {
+	mt, comments, inline := d.locate(path)
+	return &parser.GroupField{
+		IsRepeated:    field.Label == FieldLabelRepeated,
+		IsRequired:    field.Label == FieldLabelRequired,
+		IsOptional:    d.isOptional(field),
+		GroupName:     m.Name,
+		MessageBody:   d.messageBody(join(scope, m.Name), m, messagePath),
+		FieldNumber:   strconv.Itoa(int(field.Number)),
+		Comments:      comments,
+		InlineComment: inline,
+		Meta:          mt,
+	}
+}
+
+// fieldOptions returns the default value, the json_name unless it's the default one, and the options of the field.
+func (d *decompiler) fieldOptions(field *FieldDescriptorProto) []*parser.FieldOption {
+	var options []*parser.FieldOption
+	if field.DefaultValue != nil {
+		value := &parser.OptionValue{Kind: parser.OptionValueKindIdent, Scalar: *field.DefaultValue}
+		switch field.Type {
+		case FieldTypeString:
+			value = &parser.OptionValue{Kind: parser.OptionValueKindString, Scalar: quote(*field.DefaultValue)}
+		case FieldTypeBytes:
+			// the default value of the bytes field is already escaped.
+			value = &parser.OptionValue{Kind: parser.OptionValueKindString, Scalar: `"` + *field.DefaultValue + `"`}
+		case FieldTypeBool:
+			value.Kind = parser.OptionValueKindBool
+		case FieldTypeFloat, FieldTypeDouble:
+			value.Kind = parser.OptionValueKindFloat
+		case FieldTypeEnum:
+		default:
+			value.Kind = parser.OptionValueKindInt
+		}
+		options = append(options, &parser.FieldOption{OptionName: "default", Constant: value.Scalar, Value: value})
+	}
+	if field.JSONName != "" && field.JSONName != jsonName(field.Name) {
+		value := &parser.OptionValue{Kind: parser.OptionValueKindString, Scalar: quote(field.JSONName)}
+		options = append(options, &parser.FieldOption{OptionName: "json_name", Constant: value.Scalar, Value: value})
+	}
+	for _, o := range d.decodeOptions(field.Options, "google.protobuf.FieldOptions", fieldOptions) {
+		options = append(options, &parser.FieldOption{OptionName: o.name, Constant: constant(o.value), Value: o.value})
+	}
+	return options
+}
+
+// extends decompiles the extensions, which are grouped by the consecutive same extendees.
+// The groups are declared in the nested types, or the message types of the file when the path is nil.
+func (d *decompiler) extends(scope string, extensions []*FieldDescriptorProto, nestedTypes []*DescriptorProto, path []int32) []parser.Visitee {
+	extensionNumber, nestedNumber := int32(fileExtensionNumber), int32(fileMessageTypeNumber)
+	if path != nil {
+		extensionNumber, nestedNumber = messageExtensionNumber, messageNestedTypeNumber
+	}
+
+	var stmts []parser.Visitee
+	var extend *parser.Extend
+	for i, ext := range extensions {
+		if extend == nil || extend.MessageType != ext.Extendee {
+			mt, comments, inline := d.locate(appendPath(path, extensionNumber))
+			extend = &parser.Extend{
+				MessageType: ext.Extendee,
+				Meta:        mt,
+			}
+			if len(stmts) == 0 {
+				// the location of the extend statements is recorded only once.
+				extend.Comments, extend.InlineComment = comments, inline
+			}
+			stmts = append(stmts, extend)
+		}
+		extPath := appendPath(path, extensionNumber, int32(i))
+		if ext.Type == FieldTypeGroup {
+			for j, n := range nestedTypes {
+				if ext.TypeName == "."+join(scope, n.Name) {
+					extend.ExtendBody = append(extend.ExtendBody, d.group(scope, ext, n, extPath, appendPath(path, nestedNumber, int32(j))))
+					break
+				}
+			}
+			continue
+		}
+		extend.ExtendBody = append(extend.ExtendBody, d.field(ext, extPath))
+	}
+	return stmts
+}
+
+func (d *decompiler) enum(enum *EnumDescriptorProto, path []int32) *parser.Enum {
+	body := statements(d.options(enum.Options, "google.protobuf.EnumOptions", enumOptions))
+	for i, v := range enum.Value {
+		mt, comments, inline := d.locate(appendPath(path, enumValueNumber, int32(i)))
+		field := &parser.EnumField{
+			Ident:         v.Name,
+			Number:        strconv.Itoa(int(v.Number)),
+			Comments:      comments,
+			InlineComment: inline,
+			Meta:          mt,
+		}
+		for _, o := range d.decodeOptions(v.Options, "google.protobuf.EnumValueOptions", enumValueOptions) {
+			field.EnumValueOptions = append(field.EnumValueOptions, &parser.EnumValueOption{
+				OptionName: o.name,
+				Constant:   constant(o.value),
+				Value:      o.value,
+			})
+		}
+		body = append(body, field)
+	}
+	if 0 < len(enum.ReservedRange) {
+		reserved := &parser.Reserved{}
+		for _, r := range enum.ReservedRange {
+			reserved.Ranges = append(reserved.Ranges, decompileRange(r.Start, r.End, maxEnumNumber))
+		}
+		body = append(body, reserved)
+	}
+	if 0 < len(enum.ReservedName) {
+		body = append(body, &parser.Reserved{FieldNames: quoteAll(enum.ReservedName)})
+	}
+
+	mt, comments, inline := d.locate(path)
+	return &parser.Enum{
+		EnumName:      enum.Name,
+		EnumBody:      body,
+		Comments:      comments,
+		InlineComment: inline,
+		Meta:          mt,
+	}
+}
+
+func (d *decompiler) service(s *ServiceDescriptorProto, path []int32) *parser.Service {
+	body := statements(d.options(s.Options, "google.protobuf.ServiceOptions", serviceOptions))
+	for i, m := range s.Method {
+		mt, comments, inline := d.locate(appendPath(path, serviceMethodNumber, int32(i)))
+		body = append(body, &parser.RPC{
+			RPCName: m.Name,
+			RPCRequest: &parser.RPCRequest{
+				IsStream:    m.ClientStreaming,
+				MessageType: m.InputType,
+			},
+			RPCResponse: &parser.RPCResponse{
+				IsStream:    m.ServerStreaming,
+				MessageType: m.OutputType,
+			},
+			Options:       d.options(m.Options, "google.protobuf.MethodOptions", methodOptions),
+			Comments:      comments,
+			InlineComment: inline,
+			Meta:          mt,
+		})
+	}
+
+	mt, comments, inline := d.locate(path)
+	return &parser.Service{
+		ServiceName:   s.Name,
+		ServiceBody:   body,
+		Comments:      comments,
+		InlineComment: inline,
+		Meta:          mt,
+	}
+}
+
+// decompileRange returns the range whose end is inclusive.
+func decompileRange(start, end, max int32) *parser.Range {
+	r := &parser.Range{Begin: strconv.Itoa(int(start))}
+	switch {
+	case end == max:
+		r.End = "max"
+	case end != start:
+		r.End = strconv.Itoa(int(end))
+	}
+	return r
+}
+
+func quoteAll(names []string) []string {
+	var quoted []string
+	for _, name := range names {
+		quoted = append(quoted, quote(name))
+	}
+	return quoted
+}
+
+// pathKey returns the key of the path for the locations.
+func pathKey(path []int32) string {
+	var b strings.Builder
+	for i, p := range path {
+		if 0 < i {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(int(p)))
+	}
+	return b.String()
+}
@@ -0,0 +1,330 @@
+package descriptor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// decodedOption is an option decoded from an options message.
+type decodedOption struct {
+	name  string
+	value *parser.OptionValue
+}
+
+// optionMessage describes a message which an option value is decoded as.
+type optionMessage struct {
+	// fullName is the name of the message without the leading dot, which the extensions are looked up by.
+	fullName string
+	// builtin is set for a message in google/protobuf/descriptor.proto. Its fields are decoded as separate options.
+	builtin builtinMessage
+	// user is set for a message in the set. Its fields are decoded as a message literal.
+	user *DescriptorProto
+}
+
+// optionField describes a field which an option value is decoded as.
+type optionField struct {
+	// name is the field name, or the extension name in parentheses.
+	name string
+	typ  FieldType
+	// builtin is set for a field of a builtin message.
+	builtin *fieldInfo
+	// enum is set for a field whose type is an enum in the set.
+	enum *EnumDescriptorProto
+	// message is set for a field whose type is a message.
+	message *optionMessage
+}
+
+// optionField returns the field of the number in the message, or nil if it's unknown.
+func (d *decompiler) optionField(m *optionMessage, number int32) *optionField {
+	for _, f := range m.builtin {
+		if f.number != number {
+			continue
+		}
+		field := &optionField{name: f.name, typ: f.typ, builtin: f}
+		if f.name == "features" {
+			field.message = &optionMessage{fullName: "google.protobuf.FeatureSet", builtin: featureSet}
+		}
+		return field
+	}
+	if m.user != nil {
+		for _, f := range m.user.Field {
+			if f.Number == number {
+				return d.userOptionField(f.Name, f)
+			}
+		}
+	}
+	if ext, ok := d.index.extensions[m.fullName][number]; ok {
+		return d.userOptionField("("+ext.fullName+")", ext.field)
+	}
+	return nil
+}
+
+func (d *decompiler) userOptionField(name string, f *FieldDescriptorProto) *optionField {
+	field := &optionField{name: name, typ: f.Type}
+	typeName := strings.TrimPrefix(f.TypeName, ".")
+	switch f.Type {
+	case FieldTypeEnum:
+		field.enum = d.index.enums[typeName]
+	case FieldTypeMessage, FieldTypeGroup:
+		field.message = &optionMessage{fullName: typeName, user: d.index.messages[typeName]}
+	}
+	return field
+}
+
+// options decodes the options message, whose type is the typeName, into the option statements.
+func (d *decompiler) options(b Options, typeName string, builtin builtinMessage) []*parser.Option {
+	var options []*parser.Option
+	for _, o := range d.decodeOptions(b, typeName, builtin) {
+		options = append(options, &parser.Option{
+			OptionName: o.name,
+			Constant:   constant(o.value),
+			Value:      o.value,
+		})
+	}
+	return options
+}
+
+// statements returns the options as the statements of a body.
+func statements(options []*parser.Option) []parser.Visitee {
+	var stmts []parser.Visitee
+	for _, o := range options {
+		stmts = append(stmts, o)
+	}
+	return stmts
+}
+
+// decodeOptions decodes the options message, whose type is the typeName.
+func (d *decompiler) decodeOptions(b Options, typeName string, builtin builtinMessage) []*decodedOption {
+	options, err := d.decodeFlatten(b, &optionMessage{fullName: typeName, builtin: builtin}, "")
+	if err != nil {
+		d.errorf("failed to decode %s: %v", typeName, err)
+	}
+	return options
+}
+
+// decodeFlatten decodes the fields of the builtin message as the separate options whose names have the prefix,
+// like "features.field_presence".
+func (d *decompiler) decodeFlatten(b []byte, m *optionMessage, prefix string) ([]*decodedOption, error) {
+	var options []*decodedOption
+	err := decodeFields(b, func(dec *decoder, number int32, typ wireType) error {
+		f := d.optionField(m, number)
+		if f == nil {
+			return fmt.Errorf("unknown field %d of %s", number, m.fullName)
+		}
+		if f.message != nil && f.message.builtin != nil {
+			nested, err := messageBytes(dec, number, typ)
+			if err != nil {
+				return err
+			}
+			flatten, err := d.decodeFlatten(nested, f.message, prefix+f.name+".")
+			options = append(options, flatten...)
+			return err
+		}
+		values, err := d.decodeValues(dec, f, number, typ)
+		for _, v := range values {
+			options = append(options, &decodedOption{name: prefix + f.name, value: v})
+		}
+		return err
+	})
+	return options, err
+}
+
+// messageBytes returns the message value, which is either length-delimited or a group.
+func messageBytes(dec *decoder, number int32, typ wireType) ([]byte, error) {
+	if typ == wireStartGroup {
+		return dec.group(number)
+	}
+	return dec.bytes(typ)
+}
+
+// decodeMessage decodes the message as a message literal.
+func (d *decompiler) decodeMessage(b []byte, m *optionMessage) (*parser.OptionValue, error) {
+	value := &parser.OptionValue{Kind: parser.OptionValueKindMessage}
+	err := decodeFields(b, func(dec *decoder, number int32, typ wireType) error {
+		f := d.optionField(m, number)
+		if f == nil {
+			return fmt.Errorf("unknown field %d of %s", number, m.fullName)
+		}
+		name := f.name
+		if strings.HasPrefix(name, "(") {
+			// the extensions are enclosed in the brackets in a message literal.
+			name = "[" + strings.Trim(name, "()") + "]"
+		}
+		values, err := d.decodeValues(dec, f, number, typ)
+		for _, v := range values {
+			value.Fields = append(value.Fields, &parser.OptionValueField{Name: name, Value: v})
+		}
+		return err
+	})
+	return value, err
+}
+
+// decodeValues decodes the value of the field. A packed value has multiple values.
+func (d *decompiler) decodeValues(dec *decoder, f *optionField, number int32, typ wireType) ([]*parser.OptionValue, error) {
+	switch f.typ {
+	case FieldTypeMessage, FieldTypeGroup:
+		b, err := messageBytes(dec, number, typ)
+		if err != nil {
+			return nil, err
+		}
+		if f.message.user == nil {
+			return nil, fmt.Errorf("unknown message %s", f.message.fullName)
+		}
+		v, err := d.decodeMessage(b, f.message)
+		if err != nil {
+			return nil, err
+		}
+		return []*parser.OptionValue{v}, nil
+	case FieldTypeString, FieldTypeBytes:
+	default:
+		if typ == wireBytes {
+			b, err := dec.bytes(typ)
+			if err != nil {
+				return nil, err
+			}
+			packed := &decoder{buf: b}
+			var values []*parser.OptionValue
+			for 0 < len(packed.buf) {
+				v, err := d.decodeScalar(packed, f, scalarWireType(f.typ))
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, v)
+			}
+			return values, nil
+		}
+	}
+	v, err := d.decodeScalar(dec, f, typ)
+	if err != nil {
+		return nil, err
+	}
+	return []*parser.OptionValue{v}, nil
+}
+
+// scalarWireType returns the wire type of the scalar type.
+func scalarWireType(typ FieldType) wireType {
+	switch typ {
+	case FieldTypeFloat, FieldTypeFixed32, FieldTypeSfixed32:
+		return wireFixed32
+	case FieldTypeDouble, FieldTypeFixed64, FieldTypeSfixed64:
+		return wireFixed64
+	}
+	return wireVarint
+}
+
+// decodeScalar decodes the scalar value of the field.
+func (d *decompiler) decodeScalar(dec *decoder, f *optionField, typ wireType) (*parser.OptionValue, error) {
+	if typ == wireBytes && (f.typ == FieldTypeString || f.typ == FieldTypeBytes) {
+		b, err := dec.bytes(typ)
+		if err != nil {
+			return nil, err
+		}
+		text := quote(string(b))
+		if f.typ == FieldTypeBytes {
+			text = `"` + cEscape(b) + `"`
+		}
+		return &parser.OptionValue{Kind: parser.OptionValueKindString, Scalar: text}, nil
+	}
+	if typ != scalarWireType(f.typ) || f.typ == FieldTypeString || f.typ == FieldTypeBytes {
+		return nil, fmt.Errorf("unexpected wire type %d of %s", typ, f.name)
+	}
+
+	var v uint64
+	var err error
+	switch typ {
+	case wireFixed32:
+		var x uint32
+		x, err = dec.fixed32()
+		v = uint64(x)
+	case wireFixed64:
+		v, err = dec.fixed64()
+	default:
+		v, err = dec.varint()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	integer := func(text string) (*parser.OptionValue, error) {
+		return &parser.OptionValue{Kind: parser.OptionValueKindInt, Scalar: text}, nil
+	}
+	switch f.typ {
+	case FieldTypeBool:
+		return &parser.OptionValue{Kind: parser.OptionValueKindBool, Scalar: strconv.FormatBool(v != 0)}, nil
+	case FieldTypeEnum:
+		if name := f.enumName(int32(v)); name != "" {
+			return &parser.OptionValue{Kind: parser.OptionValueKindIdent, Scalar: name}, nil
+		}
+		return integer(strconv.Itoa(int(int32(v))))
+	case FieldTypeFloat:
+		return &parser.OptionValue{Kind: parser.OptionValueKindFloat, Scalar: formatFloat(float64(math.Float32frombits(uint32(v))), 32)}, nil
+	case FieldTypeDouble:
+		return &parser.OptionValue{Kind: parser.OptionValueKindFloat, Scalar: formatFloat(math.Float64frombits(v), 64)}, nil
+	case FieldTypeInt32, FieldTypeSfixed32:
+		return integer(strconv.FormatInt(int64(int32(v)), 10))
+	case FieldTypeInt64, FieldTypeSfixed64:
+		return integer(strconv.FormatInt(int64(v), 10))
+	case FieldTypeUint32, FieldTypeFixed32:
+		return integer(strconv.FormatUint(uint64(uint32(v)), 10))
+	case FieldTypeSint32:
+		return integer(strconv.FormatInt(int64(int32(uint32(v)>>1)^-int32(v&1)), 10))
+	case FieldTypeSint64:
+		return integer(strconv.FormatInt(int64(v>>1)^-int64(v&1), 10))
+	}
+	return integer(strconv.FormatUint(v, 10))
+}
+
+// enumName returns the name of the enum value of the number, or "" if it's unknown.
+func (f *optionField) enumName(number int32) string {
+	if f.builtin != nil {
+		for name, n := range f.builtin.enum {
+			if n == number {
+				return name
+			}
+		}
+	}
+	if f.enum != nil {
+		for _, v := range f.enum.Value {
+			if v.Number == number {
+				return v.Name
+			}
+		}
+	}
+	return ""
+}
+
+// formatFloat formats the number, like protoc.
+func formatFloat(x float64, bits int) string {
+	switch {
+	case math.IsInf(x, 1):
+		return "inf"
+	case math.IsInf(x, -1):
+		return "-inf"
+	case math.IsNaN(x):
+		return "nan"
+	}
+	return strconv.FormatFloat(x, 'g', -1, bits)
+}
+
+// constant returns the text of the value, like parser.Option.Constant.
+func constant(v *parser.OptionValue) string {
+	switch v.Kind {
+	case parser.OptionValueKindMessage:
+		var fields []string
+		for _, f := range v.Fields {
+			fields = append(fields, f.Name+":"+constant(f.Value))
+		}
+		return "{" + strings.Join(fields, ",") + "}"
+	case parser.OptionValueKindList:
+		var elements []string
+		for _, e := range v.Elements {
+			elements = append(elements, constant(e))
+		}
+		return "[" + strings.Join(elements, ",") + "]"
+	}
+	return v.Scalar
+}
@@ -0,0 +1,190 @@
+package descriptor_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/descriptor"
+	"github.com/yoheimuta/go-protoparser/v4/printer"
+)
+
+const decompileInput = `syntax = "proto3";
+// foo is a package.
+package foo;
+import "google/protobuf/descriptor.proto";
+
+extend google.protobuf.FieldOptions {
+  optional Rule rule = 1000;
+}
+
+// Outer is a message.
+message Outer {
+  option deprecated = true;
+  string user_name = 1 [json_name = "name", (rule) = { min: -1 values: [1, 2] }]; // the name
+  optional int32 count = 2;
+  map<string, Rule> rules = 3;
+  oneof choice {
+    Kind kind = 4;
+    bytes raw = 5 [deprecated = true];
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+    KIND_A = 1 [deprecated = true];
+    reserved 10 to max;
+  }
+  reserved 10 to 20, 100;
+  reserved "old";
+}
+
+message Rule {
+  sint32 min = 1;
+  repeated int32 values = 2;
+}
+
+service S {
+  rpc Get(stream Outer) returns (Rule) {
+    option deprecated = true;
+  }
+}
+`
+
+func TestFileDescriptorSet_Unmarshal(t *testing.T) {
+	set := parse(t, map[string]string{
+		"google/protobuf/descriptor.proto": descriptorProto,
+		"a.proto":                          decompileInput,
+	}, "a.proto")
+	want, err := descriptor.CompileFileSet(set, descriptor.WithSourceCodeInfo(true))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	got := &descriptor.FileDescriptorSet{}
+	if err := got.Unmarshal(want.Marshal()); err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	if !reflect.DeepEqual(got.Marshal(), want.Marshal()) {
+		t.Errorf("got %x, but want %x", got.Marshal(), want.Marshal())
+	}
+	if got.File[1].Name != "a.proto" || got.File[1].MessageType[0].Field[0].JSONName != "name" {
+		t.Errorf("got %+v, but want a.proto", got.File[1])
+	}
+
+	if err := got.Unmarshal([]byte{0x0a, 0x05, 0x0a}); err == nil {
+		t.Errorf("got nil, but want the error of the truncated input")
+	}
+}
+
+const descriptorProto = `syntax = "proto2";
+package google.protobuf;
+message FieldOptions {
+  extensions 1000 to max;
+}
+`
+
+func TestDecompile(t *testing.T) {
+	set := parse(t, map[string]string{
+		"google/protobuf/descriptor.proto": descriptorProto,
+		"a.proto":                          decompileInput,
+	}, "a.proto")
+	compiled, err := descriptor.CompileFileSet(set, descriptor.WithSourceCodeInfo(true))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	protos, err := descriptor.Decompile(compiled)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	if len(protos) != 2 {
+		t.Fatalf("got %d files, but want 2", len(protos))
+	}
+
+	var got bytes.Buffer
+	config := &printer.Config{IgnorePositions: true}
+	if err := config.Fprint(&got, protos[1]); err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	want := `syntax = "proto3";
+// foo is a package.
+package foo;
+import "google/protobuf/descriptor.proto";
+// Outer is a message.
+message Outer {
+  option deprecated = true;
+  string user_name = 1 [json_name = "name", (foo.rule) = {
+    min: -1,
+    values: 1,
+    values: 2
+  }]; // the name
+  optional int32 count = 2;
+  map<string, .foo.Rule> rules = 3;
+  oneof choice {
+    .foo.Outer.Kind kind = 4;
+    bytes raw = 5 [deprecated = true];
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+    KIND_A = 1 [deprecated = true];
+    reserved 10 to max;
+  }
+  reserved 10 to 20, 100;
+  reserved "old";
+}
+message Rule {
+  sint32 min = 1;
+  repeated int32 values = 2;
+}
+service S {
+  rpc Get(stream .foo.Outer) returns (.foo.Rule) {
+    option deprecated = true;
+  }
+}
+extend .google.protobuf.FieldOptions {
+  optional .foo.Rule rule = 1000;
+}
+`
+	if got.String() != want {
+		t.Errorf("got %s, but want %s", got.String(), want)
+	}
+
+	// the decompiled file is compiled into the same descriptor.
+	recompiled, err := descriptor.CompileFileSet(parse(t, map[string]string{
+		"google/protobuf/descriptor.proto": descriptorProto,
+		"a.proto":                          got.String(),
+	}, "a.proto"))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	compiled.File[1].SourceCodeInfo = nil
+	if !reflect.DeepEqual(recompiled.File[1], compiled.File[1]) {
+		t.Errorf("got %+v, but want %+v", recompiled.File[1], compiled.File[1])
+	}
+}
+
+func TestDecompile_unknownOption(t *testing.T) {
+	set := &descriptor.FileDescriptorSet{
+		File: []*descriptor.FileDescriptorProto{
+			{
+				Name: "a.proto",
+				// the unknown field 1000 = 1
+				Options: descriptor.Options{0xc0, 0x3e, 0x01},
+				Syntax:  "proto3",
+			},
+		},
+	}
+
+	protos, err := descriptor.Decompile(set)
+	if len(protos) != 1 {
+		t.Fatalf("got %d files, but want 1", len(protos))
+	}
+	var list descriptor.ErrorList
+	if !errors.As(err, &list) || len(list) != 1 {
+		t.Fatalf("got err %v, but want an ErrorList with one error", err)
+	}
+	want := "a.proto:0:0: failed to decode google.protobuf.FileOptions: unknown field 1000 of google.protobuf.FileOptions"
+	if list[0].Error() != want {
+		t.Errorf("got %q, but want %q", list[0].Error(), want)
+	}
+}
@@ -0,0 +1,197 @@
+// Package descriptor compiles the parsed files into google.protobuf.FileDescriptorProto messages,
+// and encodes them in the protobuf wire format without depending on any protobuf runtime.
+//
+// The types mirror the messages in google/protobuf/descriptor.proto. The fields which this package doesn't
+// produce are omitted.
+package descriptor
+
+// FieldType is the type of a field, like google.protobuf.FieldDescriptorProto.Type.
+type FieldType int32
+
+// FieldType values.
+const (
+	FieldTypeDouble   FieldType = 1
+	FieldTypeFloat    FieldType = 2
+	FieldTypeInt64    FieldType = 3
+	FieldTypeUint64   FieldType = 4
+	FieldTypeInt32    FieldType = 5
+	FieldTypeFixed64  FieldType = 6
+	FieldTypeFixed32  FieldType = 7
+	FieldTypeBool     FieldType = 8
+	FieldTypeString   FieldType = 9
+	FieldTypeGroup    FieldType = 10
+	FieldTypeMessage  FieldType = 11
+	FieldTypeBytes    FieldType = 12
+	FieldTypeUint32   FieldType = 13
+	FieldTypeEnum     FieldType = 14
+	FieldTypeSfixed32 FieldType = 15
+	FieldTypeSfixed64 FieldType = 16
+	FieldTypeSint32   FieldType = 17
+	FieldTypeSint64   FieldType = 18
+)
+
+// FieldLabel is the label of a field, like google.protobuf.FieldDescriptorProto.Label.
+type FieldLabel int32
+
+// FieldLabel values.
+const (
+	FieldLabelOptional FieldLabel = 1
+	FieldLabelRequired FieldLabel = 2
+	FieldLabelRepeated FieldLabel = 3
+)
+
+// Edition is the edition of a file, like google.protobuf.Edition.
+type Edition int32
+
+// Edition values.
+const (
+	EditionUnknown Edition = 0
+	EditionProto2  Edition = 998
+	EditionProto3  Edition = 999
+	Edition2023    Edition = 1000
+	Edition2024    Edition = 1001
+)
+
+// Options is an options message, like google.protobuf.FieldOptions, encoded in the wire format.
+// It holds both of the standard options and the custom options, which are the extensions of the message.
+type Options []byte
+
+// FileDescriptorSet is a set of the files, like google.protobuf.FileDescriptorSet.
+// Every file follows the files it depends on.
+type FileDescriptorSet struct {
+	File []*FileDescriptorProto
+}
+
+// FileDescriptorProto describes a file, like google.protobuf.FileDescriptorProto.
+type FileDescriptorProto struct {
+	// Name is the import path of the file.
+	Name    string
+	Package string
+	// Dependency holds the import paths of the imported files.
+	Dependency []string
+	// PublicDependency holds the indexes of the public imports in Dependency.
+	PublicDependency []int32
+	// WeakDependency holds the indexes of the weak imports in Dependency.
+	WeakDependency []int32
+
+	MessageType []*DescriptorProto
+	EnumType    []*EnumDescriptorProto
+	Service     []*ServiceDescriptorProto
+	Extension   []*FieldDescriptorProto
+
+	Options        Options
+	SourceCodeInfo *SourceCodeInfo
+
+	// Syntax is "proto3" or "editions". It is empty for proto2.
+	Syntax  string
+	Edition Edition
+}
+
+// DescriptorProto describes a message, like google.protobuf.DescriptorProto.
+type DescriptorProto struct {
+	Name           string
+	Field          []*FieldDescriptorProto
+	Extension      []*FieldDescriptorProto
+	NestedType     []*DescriptorProto
+	EnumType       []*EnumDescriptorProto
+	ExtensionRange []*ExtensionRange
+	OneofDecl      []*OneofDescriptorProto
+	Options        Options
+	ReservedRange  []*ReservedRange
+	ReservedName   []string
+}
+
+// ExtensionRange is a range of the extension numbers. The End is exclusive.
+type ExtensionRange struct {
+	Start   int32
+	End     int32
+	Options Options
+}
+
+// ReservedRange is a range of the reserved field numbers. The End is exclusive.
+type ReservedRange struct {
+	Start int32
+	End   int32
+}
+
+// FieldDescriptorProto describes a field or an extension, like google.protobuf.FieldDescriptorProto.
+type FieldDescriptorProto struct {
+	Name   string
+	Number int32
+	Label  FieldLabel
+	Type   FieldType
+	// TypeName is the fully-qualified name of the message or the enum with the leading dot.
+	TypeName string
+	// Extendee is the fully-qualified name of the extended message with the leading dot.
+	Extendee string
+	// DefaultValue is the default value as text. It is nil when the default is not set.
+	DefaultValue *string
+	// OneofIndex is the index of the oneof in the message. It is nil when the field is not in a oneof.
+	OneofIndex     *int32
+	JSONName       string
+	Options        Options
+	Proto3Optional bool
+}
+
+// OneofDescriptorProto describes a oneof, like google.protobuf.OneofDescriptorProto.
+type OneofDescriptorProto struct {
+	Name    string
+	Options Options
+}
+
+// EnumDescriptorProto describes an enum, like google.protobuf.EnumDescriptorProto.
+type EnumDescriptorProto struct {
+	Name          string
+	Value         []*EnumValueDescriptorProto
+	Options       Options
+	ReservedRange []*EnumReservedRange
+	ReservedName  []string
+}
+
+// EnumReservedRange is a range of the reserved enum numbers. The End is inclusive.
+type EnumReservedRange struct {
+	Start int32
+	End   int32
+}
+
+// EnumValueDescriptorProto describes an enum value, like google.protobuf.EnumValueDescriptorProto.
+type EnumValueDescriptorProto struct {
+	Name    string
+	Number  int32
+	Options Options
+}
+
+// ServiceDescriptorProto describes a service, like google.protobuf.ServiceDescriptorProto.
+type ServiceDescriptorProto struct {
+	Name    string
+	Method  []*MethodDescriptorProto
+	Options Options
+}
+
+// MethodDescriptorProto describes a method of a service, like google.protobuf.MethodDescriptorProto.
+type MethodDescriptorProto struct {
+	Name string
+	// InputType and OutputType are the fully-qualified names of the messages with the leading dot.
+	InputType       string
+	OutputType      string
+	Options         Options
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// SourceCodeInfo holds the locations of the elements, like google.protobuf.SourceCodeInfo.
+type SourceCodeInfo struct {
+	Location []*Location
+}
+
+// Location is the location of an element, like google.protobuf.SourceCodeInfo.Location.
+type Location struct {
+	// Path identifies the element by the field numbers and the indexes from the FileDescriptorProto.
+	Path []int32
+	// Span is the zero-based start line, start column, end line and end column.
+	// The end line is omitted when it is the same as the start line.
+	Span                    []int32
+	LeadingComments         *string
+	TrailingComments        *string
+	LeadingDetachedComments []string
+}
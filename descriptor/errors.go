@@ -0,0 +1,30 @@
+package descriptor
+
+import (
+	"fmt"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Error is an error found by Compile, like an unknown option or an invalid value.
+type Error struct {
+	Pos     meta.Position
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// ErrorList is a list of the errors found by Compile.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
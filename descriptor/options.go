@@ -0,0 +1,535 @@
+package descriptor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/internal/strlit"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// fieldInfo describes a field which an option value is encoded to.
+type fieldInfo struct {
+	name     string
+	number   int32
+	typ      FieldType
+	repeated bool
+	// enum holds the numbers of the enum values for FieldTypeEnum.
+	enum map[string]int32
+	// message looks up the fields for FieldTypeMessage and FieldTypeGroup.
+	message messageInfo
+}
+
+// messageInfo looks up the fields of a message.
+type messageInfo interface {
+	field(name string) *fieldInfo
+}
+
+// builtinMessage is a message defined in google/protobuf/descriptor.proto.
+type builtinMessage map[string]*fieldInfo
+
+func (m builtinMessage) field(name string) *fieldInfo {
+	return m[name]
+}
+
+func newBuiltinMessage(fields ...*fieldInfo) builtinMessage {
+	m := make(builtinMessage)
+	for _, f := range fields {
+		m[f.name] = f
+	}
+	return m
+}
+
+func boolOption(name string, number int32) *fieldInfo {
+	return &fieldInfo{name: name, number: number, typ: FieldTypeBool}
+}
+
+func stringOption(name string, number int32) *fieldInfo {
+	return &fieldInfo{name: name, number: number, typ: FieldTypeString}
+}
+
+func enumOption(name string, number int32, values map[string]int32) *fieldInfo {
+	return &fieldInfo{name: name, number: number, typ: FieldTypeEnum, enum: values}
+}
+
+func featuresOption(number int32) *fieldInfo {
+	return &fieldInfo{name: "features", number: number, typ: FieldTypeMessage, message: featureSet}
+}
+
+// featureSet is google.protobuf.FeatureSet.
+var featureSet = newBuiltinMessage(
+	enumOption("field_presence", 1, map[string]int32{"EXPLICIT": 1, "IMPLICIT": 2, "LEGACY_REQUIRED": 3}),
+	enumOption("enum_type", 2, map[string]int32{"OPEN": 1, "CLOSED": 2}),
+	enumOption("repeated_field_encoding", 3, map[string]int32{"PACKED": 1, "EXPANDED": 2}),
+	enumOption("utf8_validation", 4, map[string]int32{"VERIFY": 2, "NONE": 3}),
+	enumOption("message_encoding", 5, map[string]int32{"LENGTH_PREFIXED": 1, "DELIMITED": 2}),
+	enumOption("json_format", 6, map[string]int32{"ALLOW": 1, "LEGACY_BEST_EFFORT": 2}),
+)
+
+// The options messages in google/protobuf/descriptor.proto.
+var (
+	fileOptions = newBuiltinMessage(
+		stringOption("java_package", 1),
+		stringOption("java_outer_classname", 8),
+		enumOption("optimize_for", 9, map[string]int32{"SPEED": 1, "CODE_SIZE": 2, "LITE_RUNTIME": 3}),
+		boolOption("java_multiple_files", 10),
+		stringOption("go_package", 11),
+		boolOption("cc_generic_services", 16),
+		boolOption("java_generic_services", 17),
+		boolOption("py_generic_services", 18),
+		boolOption("java_generate_equals_and_hash", 20),
+		boolOption("deprecated", 23),
+		boolOption("java_string_check_utf8", 27),
+		boolOption("cc_enable_arenas", 31),
+		stringOption("objc_class_prefix", 36),
+		stringOption("csharp_namespace", 37),
+		stringOption("swift_prefix", 39),
+		stringOption("php_class_prefix", 40),
+		stringOption("php_namespace", 41),
+		stringOption("php_metadata_namespace", 44),
+		stringOption("ruby_package", 45),
+		featuresOption(50),
+	)
+	messageOptions = newBuiltinMessage(
+		boolOption("message_set_wire_format", 1),
+		boolOption("no_standard_descriptor_accessor", 2),
+		boolOption("deprecated", 3),
+		boolOption("map_entry", 7),
+		boolOption("deprecated_legacy_json_field_conflicts", 11),
+		featuresOption(12),
+	)
+	fieldOptions = newBuiltinMessage(
+		enumOption("ctype", 1, map[string]int32{"STRING": 0, "CORD": 1, "STRING_PIECE": 2}),
+		boolOption("packed", 2),
+		boolOption("deprecated", 3),
+		boolOption("lazy", 5),
+		enumOption("jstype", 6, map[string]int32{"JS_NORMAL": 0, "JS_STRING": 1, "JS_NUMBER": 2}),
+		boolOption("weak", 10),
+		boolOption("unverified_lazy", 15),
+		boolOption("debug_redact", 16),
+		enumOption("retention", 17, map[string]int32{
+			"RETENTION_UNKNOWN": 0,
+			"RETENTION_RUNTIME": 1,
+			"RETENTION_SOURCE":  2,
+		}),
+		&fieldInfo{name: "targets", number: 19, typ: FieldTypeEnum, repeated: true, enum: map[string]int32{
+			"TARGET_TYPE_UNKNOWN":         0,
+			"TARGET_TYPE_FILE":            1,
+			"TARGET_TYPE_EXTENSION_RANGE": 2,
+			"TARGET_TYPE_MESSAGE":         3,
+			"TARGET_TYPE_FIELD":           4,
+			"TARGET_TYPE_ONEOF":           5,
+			"TARGET_TYPE_ENUM":            6,
+			"TARGET_TYPE_ENUM_ENTRY":      7,
+			"TARGET_TYPE_SERVICE":         8,
+			"TARGET_TYPE_METHOD":          9,
+		}},
+		featuresOption(21),
+	)
+	oneofOptions = newBuiltinMessage(
+		featuresOption(1),
+	)
+	enumOptions = newBuiltinMessage(
+		boolOption("allow_alias", 2),
+		boolOption("deprecated", 3),
+		boolOption("deprecated_legacy_json_field_conflicts", 6),
+		featuresOption(7),
+	)
+	enumValueOptions = newBuiltinMessage(
+		boolOption("deprecated", 1),
+		featuresOption(2),
+		boolOption("debug_redact", 3),
+	)
+	serviceOptions = newBuiltinMessage(
+		boolOption("deprecated", 33),
+		featuresOption(34),
+	)
+	methodOptions = newBuiltinMessage(
+		boolOption("deprecated", 33),
+		enumOption("idempotency_level", 34, map[string]int32{
+			"IDEMPOTENCY_UNKNOWN": 0,
+			"NO_SIDE_EFFECTS":     1,
+			"IDEMPOTENT":          2,
+		}),
+		featuresOption(35),
+	)
+)
+
+// option is an option statement.
+type option struct {
+	name  string
+	value *parser.OptionValue
+	pos   meta.Position
+}
+
+// optionNamePart is a part of an option name, like "(foo.bar)" or "baz" of "(foo.bar).baz".
+type optionNamePart struct {
+	name      string
+	extension bool
+}
+
+// splitOptionName splits the option name into the parts.
+func splitOptionName(name string) []optionNamePart {
+	var parts []optionNamePart
+	for name != "" {
+		if strings.HasPrefix(name, "(") {
+			end := strings.Index(name, ")")
+			if end < 0 {
+				end = len(name) - 1
+			}
+			parts = append(parts, optionNamePart{name: name[1:end], extension: true})
+			name = strings.TrimPrefix(name[end+1:], ".")
+			continue
+		}
+		end := strings.Index(name, ".")
+		if end < 0 {
+			end = len(name)
+		}
+		parts = append(parts, optionNamePart{name: name[:end]})
+		name = strings.TrimPrefix(name[end:], ".")
+	}
+	return parts
+}
+
+// encodeOptions encodes the options as the fields of the target options message.
+// The extensions in the option names are resolved in the scope.
+// The fields are ordered by their numbers, as protoc does.
+func (c *compiler) encodeOptions(target builtinMessage, scope string, options []option) Options {
+	type encoded struct {
+		number int32
+		buf    []byte
+	}
+	var fields []encoded
+	for _, o := range options {
+		var e encoder
+		number, err := c.encodeOption(&e, target, scope, o)
+		if err != nil {
+			c.errorf(o.pos, "option %s: %v", o.name, err)
+			continue
+		}
+		fields = append(fields, encoded{number: number, buf: e.buf})
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].number < fields[j].number
+	})
+	var buf []byte
+	for _, f := range fields {
+		buf = append(buf, f.buf...)
+	}
+	return Options(buf)
+}
+
+// encodeOption encodes the option and returns the number of the outermost field.
+func (c *compiler) encodeOption(e *encoder, target messageInfo, scope string, o option) (int32, error) {
+	if o.value == nil {
+		return 0, fmt.Errorf("no value")
+	}
+
+	var fields []*fieldInfo
+	var m = target
+	for _, part := range splitOptionName(o.name) {
+		if m == nil {
+			return 0, fmt.Errorf("%s is not a message", fields[len(fields)-1].name)
+		}
+		var f *fieldInfo
+		if part.extension {
+			symbol := c.table.ResolveExtension(scope, part.name)
+			if symbol == nil {
+				return 0, fmt.Errorf("unknown extension %s", part.name)
+			}
+			f = c.fieldInfo(symbol.Node)
+		} else {
+			f = m.field(part.name)
+		}
+		if f == nil {
+			return 0, fmt.Errorf("unknown field %s", part.name)
+		}
+		fields = append(fields, f)
+		m = f.message
+	}
+
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("no name")
+	}
+
+	var inner encoder
+	if err := c.encodeValue(&inner, fields[len(fields)-1], o.value); err != nil {
+		return 0, err
+	}
+	for i := len(fields) - 2; 0 <= i; i-- {
+		var outer encoder
+		outer.wrap(fields[i], inner.buf)
+		inner = outer
+	}
+	e.buf = append(e.buf, inner.buf...)
+	return fields[0].number, nil
+}
+
+// wrap encodes the encoded message as the field.
+func (e *encoder) wrap(f *fieldInfo, message []byte) {
+	if f.typ == FieldTypeGroup {
+		e.tag(f.number, wireStartGroup)
+		e.buf = append(e.buf, message...)
+		e.tag(f.number, wireEndGroup)
+		return
+	}
+	e.bytesField(f.number, message)
+}
+
+// encodeValue encodes the value as the field.
+func (c *compiler) encodeValue(e *encoder, f *fieldInfo, v *parser.OptionValue) error {
+	if v.Kind == parser.OptionValueKindList {
+		for _, element := range v.Elements {
+			if err := c.encodeValue(e, f, element); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch f.typ {
+	case FieldTypeMessage, FieldTypeGroup:
+		if v.Kind != parser.OptionValueKindMessage || f.message == nil {
+			return fmt.Errorf("%s must be a message", f.name)
+		}
+		var m encoder
+		for _, field := range v.Fields {
+			sub := f.message.field(field.Name)
+			if sub == nil {
+				return fmt.Errorf("unknown field %s", field.Name)
+			}
+			if err := c.encodeValue(&m, sub, field.Value); err != nil {
+				return err
+			}
+		}
+		e.wrap(f, m.buf)
+		return nil
+	case FieldTypeString, FieldTypeBytes:
+		if v.Kind != parser.OptionValueKindString {
+			return fmt.Errorf("%s must be a string", f.name)
+		}
+		b, err := strlit.Unquote(v.Scalar)
+		if err != nil {
+			return err
+		}
+		e.bytesField(f.number, b)
+		return nil
+	case FieldTypeEnum:
+		number, ok := f.enum[v.Scalar]
+		if !ok {
+			n, err := strconv.ParseInt(v.Scalar, 0, 32)
+			if err != nil || v.Kind != parser.OptionValueKindInt {
+				return fmt.Errorf("unknown enum value %s", v.Scalar)
+			}
+			number = int32(n)
+		}
+		e.int32Field(f.number, number)
+		return nil
+	case FieldTypeBool:
+		switch v.Scalar {
+		case "true":
+			e.boolField(f.number, true)
+		case "false":
+			e.boolField(f.number, false)
+		default:
+			return fmt.Errorf("%s must be a bool", f.name)
+		}
+		return nil
+	case FieldTypeFloat, FieldTypeDouble:
+		x, err := parseFloat(v)
+		if err != nil {
+			return err
+		}
+		if f.typ == FieldTypeFloat {
+			e.tag(f.number, wireFixed32)
+			e.fixed32(float32bits(x))
+		} else {
+			e.tag(f.number, wireFixed64)
+			e.fixed64(math.Float64bits(x))
+		}
+		return nil
+	}
+
+	if v.Kind != parser.OptionValueKindInt {
+		return fmt.Errorf("%s must be an integer", f.name)
+	}
+	switch f.typ {
+	case FieldTypeUint32, FieldTypeUint64, FieldTypeFixed32, FieldTypeFixed64:
+		bits := 64
+		if f.typ == FieldTypeUint32 || f.typ == FieldTypeFixed32 {
+			bits = 32
+		}
+		x, err := strconv.ParseUint(strings.TrimPrefix(v.Scalar, "+"), 0, bits)
+		if err != nil {
+			return err
+		}
+		switch f.typ {
+		case FieldTypeFixed32:
+			e.tag(f.number, wireFixed32)
+			e.fixed32(uint32(x))
+		case FieldTypeFixed64:
+			e.tag(f.number, wireFixed64)
+			e.fixed64(x)
+		default:
+			e.tag(f.number, wireVarint)
+			e.varint(x)
+		}
+		return nil
+	}
+
+	bits := 64
+	if f.typ == FieldTypeInt32 || f.typ == FieldTypeSint32 || f.typ == FieldTypeSfixed32 {
+		bits = 32
+	}
+	x, err := strconv.ParseInt(strings.TrimPrefix(v.Scalar, "+"), 0, bits)
+	if err != nil {
+		return err
+	}
+	switch f.typ {
+	case FieldTypeSint32:
+		e.tag(f.number, wireVarint)
+		e.varint(zigzag32(int32(x)))
+	case FieldTypeSint64:
+		e.tag(f.number, wireVarint)
+		e.varint(zigzag64(x))
+	case FieldTypeSfixed32:
+		e.tag(f.number, wireFixed32)
+		e.fixed32(uint32(int32(x)))
+	case FieldTypeSfixed64:
+		e.tag(f.number, wireFixed64)
+		e.fixed64(uint64(x))
+	default:
+		e.tag(f.number, wireVarint)
+		e.varint(uint64(x))
+	}
+	return nil
+}
+
+// parseFloat parses the number, which may be an integer, inf or nan.
+func parseFloat(v *parser.OptionValue) (float64, error) {
+	switch v.Kind {
+	case parser.OptionValueKindInt, parser.OptionValueKindFloat, parser.OptionValueKindIdent:
+		text := strings.TrimPrefix(v.Scalar, "+")
+		if v.Kind == parser.OptionValueKindInt {
+			x, err := strconv.ParseInt(text, 0, 64)
+			return float64(x), err
+		}
+		return strconv.ParseFloat(text, 64)
+	}
+	return 0, fmt.Errorf("%s is not a number", v.Scalar)
+}
+
+// userMessage is a message defined in the parsed files.
+type userMessage struct {
+	c    *compiler
+	body []parser.Visitee
+}
+
+func (m *userMessage) field(name string) *fieldInfo {
+	for _, stmt := range m.body {
+		switch n := stmt.(type) {
+		case *parser.Field:
+			if n.FieldName == name {
+				return m.c.fieldInfo(n)
+			}
+		case *parser.MapField:
+			if n.MapName == name {
+				return m.c.fieldInfo(n)
+			}
+		case *parser.GroupField:
+			if strings.ToLower(n.GroupName) == name || n.GroupName == name {
+				return m.c.fieldInfo(n)
+			}
+		case *parser.Oneof:
+			for _, f := range n.OneofFields {
+				if f.FieldName == name {
+					return m.c.fieldInfo(f)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fieldInfo returns the description of the field, or nil when the node is not a field.
+func (c *compiler) fieldInfo(node interface{}) *fieldInfo {
+	switch n := node.(type) {
+	case *parser.Field:
+		return c.typedFieldInfo(n.FieldName, n.FieldNumber, n.Type, n, n.IsRepeated)
+	case *parser.OneofField:
+		return c.typedFieldInfo(n.FieldName, n.FieldNumber, n.Type, n, false)
+	case *parser.GroupField:
+		number, _ := parseInt32(n.FieldNumber)
+		return &fieldInfo{
+			name:     strings.ToLower(n.GroupName),
+			number:   number,
+			typ:      FieldTypeGroup,
+			repeated: n.IsRepeated,
+			message:  &userMessage{c: c, body: n.MessageBody},
+		}
+	case *parser.MapField:
+		number, _ := parseInt32(n.FieldNumber)
+		return &fieldInfo{
+			name:     n.MapName,
+			number:   number,
+			typ:      FieldTypeMessage,
+			repeated: true,
+			message: newBuiltinMessage(
+				c.typedFieldInfo("key", "1", n.KeyType, nil, false),
+				c.typedFieldInfo("value", "2", n.Type, n, false),
+			),
+		}
+	}
+	return nil
+}
+
+// typedFieldInfo returns the description of the field whose type is the typeName.
+// The node is the one which the linker resolved the typeName for.
+func (c *compiler) typedFieldInfo(name, numberText, typeName string, node interface{}, repeated bool) *fieldInfo {
+	number, _ := parseInt32(numberText)
+	f := &fieldInfo{
+		name:     name,
+		number:   number,
+		repeated: repeated,
+	}
+	if typ, ok := scalarTypes[typeName]; ok {
+		f.typ = typ
+		return f
+	}
+
+	symbol := c.table.Resolve(node)
+	if symbol == nil {
+		f.typ = FieldTypeMessage
+		return f
+	}
+	switch n := symbol.Node.(type) {
+	case *parser.Enum:
+		f.typ = FieldTypeEnum
+		f.enum = make(map[string]int32)
+		for _, stmt := range n.EnumBody {
+			if v, ok := stmt.(*parser.EnumField); ok {
+				f.enum[v.Ident], _ = parseInt32(v.Number)
+			}
+		}
+	case *parser.Message:
+		f.typ = FieldTypeMessage
+		f.message = &userMessage{c: c, body: n.MessageBody}
+	case *parser.GroupField:
+		f.typ = FieldTypeMessage
+		f.message = &userMessage{c: c, body: n.MessageBody}
+	}
+	return f
+}
+
+// parseInt32 parses the number in the decimal, the hexadecimal or the octal notation.
+func parseInt32(text string) (int32, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(text, "+"), 0, 32)
+	return int32(n), err
+}
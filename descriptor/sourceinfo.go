@@ -0,0 +1,151 @@
+package descriptor
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// locate records the location of the declaration at the path when the SourceCodeInfo is enabled.
+func (c *compiler) locate(path []int32, m meta.Meta, comments []*parser.Comment, inline *parser.Comment) {
+	if !c.config.sourceCodeInfo {
+		return
+	}
+	c.locations = append(c.locations, &Location{
+		Path:             appendPath(path),
+		Span:             span(m),
+		LeadingComments:  commentText(comments),
+		TrailingComments: commentText([]*parser.Comment{inline}),
+	})
+}
+
+// span returns the zero-based span, which is [startLine, startColumn, endLine, endColumn].
+// The endLine is omitted when it is the same as the startLine. The endColumn is exclusive.
+func span(m meta.Meta) []int32 {
+	startLine, startColumn := int32(m.Pos.Line-1), int32(m.Pos.Column-1)
+	endLine, endColumn := int32(m.LastPos.Line-1), int32(m.LastPos.Column)
+	if startLine == endLine {
+		return []int32{startLine, startColumn, endColumn}
+	}
+	return []int32{startLine, startColumn, endLine, endColumn}
+}
+
+// commentText returns the text of the comments without the comment markers, like protoc.
+// It returns nil when there are no comments.
+func commentText(comments []*parser.Comment) *string {
+	var b strings.Builder
+	for _, comment := range comments {
+		if comment == nil {
+			continue
+		}
+		raw := comment.Raw
+		if strings.HasPrefix(raw, "//") {
+			b.WriteString(strings.TrimPrefix(raw, "//"))
+			b.WriteString("\n")
+			continue
+		}
+		raw = strings.TrimSuffix(strings.TrimPrefix(raw, "/*"), "*/")
+		for i, line := range strings.Split(raw, "\n") {
+			if 0 < i {
+				b.WriteString("\n")
+				line = strings.TrimLeft(line, " \t")
+				line = strings.TrimPrefix(line, "*")
+			}
+			b.WriteString(line)
+		}
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	text := b.String()
+	return &text
+}
+
+// locate returns the position and the comments of the declaration at the path, which are restored from its location.
+// They are empty when the file has no location for the path.
+func (d *decompiler) locate(path []int32) (meta.Meta, []*parser.Comment, *parser.Comment) {
+	l, ok := d.locations[pathKey(path)]
+	if !ok || len(l.Span) < 3 {
+		return meta.Meta{}, nil, nil
+	}
+	line, column := int(l.Span[0])+1, int(l.Span[1])+1
+	endLine, endColumn := line, int(l.Span[2])
+	if len(l.Span) == 4 {
+		endLine, endColumn = int(l.Span[2])+1, int(l.Span[3])
+	}
+	m := meta.Meta{
+		Pos:     meta.Position{Filename: d.file.Name, Line: line, Column: column},
+		LastPos: meta.Position{Filename: d.file.Name, Line: endLine, Column: endColumn},
+	}
+
+	// the comments are placed on the lines just above the declaration, and the detached ones are separated by
+	// a blank line, since their positions are not recorded.
+	var texts []string
+	var blankBefore []bool
+	for _, c := range l.LeadingDetachedComments {
+		texts = append(texts, c)
+		blankBefore = append(blankBefore, true)
+	}
+	if l.LeadingComments != nil {
+		texts = append(texts, *l.LeadingComments)
+		blankBefore = append(blankBefore, false)
+	}
+	var comments []*parser.Comment
+	next := line
+	for i := len(texts) - 1; 0 <= i; i-- {
+		cs := d.comments(texts[i], column, next)
+		next = cs[0].Meta.Pos.Line
+		if blankBefore[i] {
+			next--
+		}
+		comments = append(cs, comments...)
+	}
+
+	var inline *parser.Comment
+	if l.TrailingComments != nil {
+		text := *l.TrailingComments
+		if 1 < strings.Count(text, "\n") {
+			// the lines are restored as a block comment, since there is only one inline comment.
+			text = strings.TrimSuffix(text, "\n")
+		}
+		inline = d.comments(text, endColumn+2, endLine+1)[0]
+		lines := inline.Meta.LastPos.Line - inline.Meta.Pos.Line
+		inline.Meta.Pos.Line, inline.Meta.LastPos.Line = endLine, endLine+lines
+	}
+	return m, comments, inline
+}
+
+// comments returns the comments of the text, which end just above the line.
+// The text ending with a newline is restored as the line comments, and the other is restored as a block comment.
+func (d *decompiler) comments(text string, column, next int) []*parser.Comment {
+	var raws []string
+	if strings.HasSuffix(text, "\n") {
+		for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			raws = append(raws, "//"+line)
+		}
+	} else {
+		raws = append(raws, "/*"+text+"*/")
+	}
+
+	var comments []*parser.Comment
+	for i := len(raws) - 1; 0 <= i; i-- {
+		raw := raws[i]
+		lines := strings.Count(raw, "\n")
+		last := next - 1
+		first := last - lines
+		lastColumn := len(raw) - strings.LastIndex(raw, "\n") - 1
+		if lines == 0 {
+			lastColumn += column - 1
+		}
+		comments = append([]*parser.Comment{{
+			Raw: raw,
+			Meta: meta.Meta{
+				Pos:     meta.Position{Filename: d.file.Name, Line: first, Column: column},
+				LastPos: meta.Position{Filename: d.file.Name, Line: last, Column: lastColumn},
+			},
+		}}, comments...)
+		next = first
+	}
+	return comments
+}
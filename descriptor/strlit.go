@@ -0,0 +1,64 @@
+package descriptor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cEscape escapes the bytes as protoc does for the default values of the bytes fields.
+func cEscape(b []byte) string {
+	var s strings.Builder
+	for _, c := range b {
+		switch c {
+		case '\n':
+			s.WriteString(`\n`)
+		case '\r':
+			s.WriteString(`\r`)
+		case '\t':
+			s.WriteString(`\t`)
+		case '"':
+			s.WriteString(`\"`)
+		case '\'':
+			s.WriteString(`\'`)
+		case '\\':
+			s.WriteString(`\\`)
+		default:
+			if c < 0x20 || 0x7f <= c {
+				fmt.Fprintf(&s, `\%03o`, c)
+				continue
+			}
+			s.WriteByte(c)
+		}
+	}
+	return s.String()
+}
+
+// quote returns the string literal of the string with the double quotes.
+// Unlike strconv.Quote, it escapes only the bytes which protobuf requires, and keeps the other UTF-8 bytes as they are.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\%03o`, c)
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
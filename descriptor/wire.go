@@ -0,0 +1,304 @@
+package descriptor
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// wireType is the type of an encoded value.
+type wireType int
+
+const (
+	wireVarint     wireType = 0
+	wireFixed64    wireType = 1
+	wireBytes      wireType = 2
+	wireStartGroup wireType = 3
+	wireEndGroup   wireType = 4
+	wireFixed32    wireType = 5
+)
+
+// encoder encodes the fields of a message in the wire format.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) tag(number int32, typ wireType) {
+	e.varint(uint64(number)<<3 | uint64(typ))
+}
+
+func (e *encoder) varint(v uint64) {
+	for 0x80 <= v {
+		e.buf = append(e.buf, byte(v)|0x80)
+		v >>= 7
+	}
+	e.buf = append(e.buf, byte(v))
+}
+
+func (e *encoder) fixed32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) fixed64(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+// int32Field encodes the number. A negative number is sign-extended to ten bytes, as protobuf does.
+func (e *encoder) int32Field(number int32, v int32) {
+	e.tag(number, wireVarint)
+	e.varint(uint64(int64(v)))
+}
+
+func (e *encoder) boolField(number int32, v bool) {
+	e.tag(number, wireVarint)
+	if v {
+		e.varint(1)
+	} else {
+		e.varint(0)
+	}
+}
+
+func (e *encoder) bytesField(number int32, v []byte) {
+	e.tag(number, wireBytes)
+	e.varint(uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) stringField(number int32, v string) {
+	e.tag(number, wireBytes)
+	e.varint(uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// packedInt32Field encodes the numbers in the packed format.
+func (e *encoder) packedInt32Field(number int32, vs []int32) {
+	if len(vs) == 0 {
+		return
+	}
+	var p encoder
+	for _, v := range vs {
+		p.varint(uint64(int64(v)))
+	}
+	e.bytesField(number, p.buf)
+}
+
+// messageField encodes the message which is encoded by the function.
+func (e *encoder) messageField(number int32, encode func(e *encoder)) {
+	var m encoder
+	encode(&m)
+	e.bytesField(number, m.buf)
+}
+
+// optionsField encodes the options unless they are nil.
+func (e *encoder) optionsField(number int32, options Options) {
+	if options != nil {
+		e.bytesField(number, options)
+	}
+}
+
+// zigzag32 and zigzag64 encode the signed numbers for sint32 and sint64.
+func zigzag32(v int32) uint64 {
+	return uint64(uint32(v<<1) ^ uint32(v>>31))
+}
+
+func zigzag64(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func float32bits(v float64) uint32 {
+	return math.Float32bits(float32(v))
+}
+
+// Marshal encodes the set in the wire format.
+func (s *FileDescriptorSet) Marshal() []byte {
+	var e encoder
+	for _, file := range s.File {
+		e.messageField(1, file.encode)
+	}
+	return e.buf
+}
+
+// Marshal encodes the file in the wire format.
+func (f *FileDescriptorProto) Marshal() []byte {
+	var e encoder
+	f.encode(&e)
+	return e.buf
+}
+
+func (f *FileDescriptorProto) encode(e *encoder) {
+	e.stringField(1, f.Name)
+	if f.Package != "" {
+		e.stringField(2, f.Package)
+	}
+	for _, dep := range f.Dependency {
+		e.stringField(3, dep)
+	}
+	for _, m := range f.MessageType {
+		e.messageField(4, m.encode)
+	}
+	for _, enum := range f.EnumType {
+		e.messageField(5, enum.encode)
+	}
+	for _, s := range f.Service {
+		e.messageField(6, s.encode)
+	}
+	for _, ext := range f.Extension {
+		e.messageField(7, ext.encode)
+	}
+	e.optionsField(8, f.Options)
+	if f.SourceCodeInfo != nil {
+		e.messageField(9, f.SourceCodeInfo.encode)
+	}
+	for _, i := range f.PublicDependency {
+		e.int32Field(10, i)
+	}
+	for _, i := range f.WeakDependency {
+		e.int32Field(11, i)
+	}
+	if f.Syntax != "" {
+		e.stringField(12, f.Syntax)
+	}
+	if f.Edition != EditionUnknown {
+		e.int32Field(14, int32(f.Edition))
+	}
+}
+
+func (m *DescriptorProto) encode(e *encoder) {
+	e.stringField(1, m.Name)
+	for _, field := range m.Field {
+		e.messageField(2, field.encode)
+	}
+	for _, nested := range m.NestedType {
+		e.messageField(3, nested.encode)
+	}
+	for _, enum := range m.EnumType {
+		e.messageField(4, enum.encode)
+	}
+	for _, r := range m.ExtensionRange {
+		e.messageField(5, r.encode)
+	}
+	for _, ext := range m.Extension {
+		e.messageField(6, ext.encode)
+	}
+	e.optionsField(7, m.Options)
+	for _, oneof := range m.OneofDecl {
+		e.messageField(8, oneof.encode)
+	}
+	for _, r := range m.ReservedRange {
+		e.messageField(9, r.encode)
+	}
+	for _, name := range m.ReservedName {
+		e.stringField(10, name)
+	}
+}
+
+func (r *ExtensionRange) encode(e *encoder) {
+	e.int32Field(1, r.Start)
+	e.int32Field(2, r.End)
+	e.optionsField(3, r.Options)
+}
+
+func (r *ReservedRange) encode(e *encoder) {
+	e.int32Field(1, r.Start)
+	e.int32Field(2, r.End)
+}
+
+func (f *FieldDescriptorProto) encode(e *encoder) {
+	e.stringField(1, f.Name)
+	if f.Extendee != "" {
+		e.stringField(2, f.Extendee)
+	}
+	e.int32Field(3, f.Number)
+	e.int32Field(4, int32(f.Label))
+	e.int32Field(5, int32(f.Type))
+	if f.TypeName != "" {
+		e.stringField(6, f.TypeName)
+	}
+	if f.DefaultValue != nil {
+		e.stringField(7, *f.DefaultValue)
+	}
+	e.optionsField(8, f.Options)
+	if f.OneofIndex != nil {
+		e.int32Field(9, *f.OneofIndex)
+	}
+	if f.JSONName != "" {
+		e.stringField(10, f.JSONName)
+	}
+	if f.Proto3Optional {
+		e.boolField(17, true)
+	}
+}
+
+func (o *OneofDescriptorProto) encode(e *encoder) {
+	e.stringField(1, o.Name)
+	e.optionsField(2, o.Options)
+}
+
+func (enum *EnumDescriptorProto) encode(e *encoder) {
+	e.stringField(1, enum.Name)
+	for _, v := range enum.Value {
+		e.messageField(2, v.encode)
+	}
+	e.optionsField(3, enum.Options)
+	for _, r := range enum.ReservedRange {
+		e.messageField(4, r.encode)
+	}
+	for _, name := range enum.ReservedName {
+		e.stringField(5, name)
+	}
+}
+
+func (r *EnumReservedRange) encode(e *encoder) {
+	e.int32Field(1, r.Start)
+	e.int32Field(2, r.End)
+}
+
+func (v *EnumValueDescriptorProto) encode(e *encoder) {
+	e.stringField(1, v.Name)
+	e.int32Field(2, v.Number)
+	e.optionsField(3, v.Options)
+}
+
+func (s *ServiceDescriptorProto) encode(e *encoder) {
+	e.stringField(1, s.Name)
+	for _, m := range s.Method {
+		e.messageField(2, m.encode)
+	}
+	e.optionsField(3, s.Options)
+}
+
+func (m *MethodDescriptorProto) encode(e *encoder) {
+	e.stringField(1, m.Name)
+	e.stringField(2, m.InputType)
+	e.stringField(3, m.OutputType)
+	e.optionsField(4, m.Options)
+	if m.ClientStreaming {
+		e.boolField(5, true)
+	}
+	if m.ServerStreaming {
+		e.boolField(6, true)
+	}
+}
+
+func (s *SourceCodeInfo) encode(e *encoder) {
+	for _, l := range s.Location {
+		e.messageField(1, l.encode)
+	}
+}
+
+func (l *Location) encode(e *encoder) {
+	e.packedInt32Field(1, l.Path)
+	e.packedInt32Field(2, l.Span)
+	if l.LeadingComments != nil {
+		e.stringField(3, *l.LeadingComments)
+	}
+	if l.TrailingComments != nil {
+		e.stringField(4, *l.TrailingComments)
+	}
+	for _, c := range l.LeadingDetachedComments {
+		e.stringField(6, c)
+	}
+}
@@ -0,0 +1,62 @@
+package fileset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// FileError is the error returned when the file can't be opened or parsed.
+type FileError struct {
+	// Path is the import path of the file.
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// MissingImportError is the error returned when the imported file is not found in any import paths.
+type MissingImportError struct {
+	// Pos is the position of the import statement.
+	Pos meta.Position
+	// Path is the import path which is not found.
+	Path string
+}
+
+func (e *MissingImportError) Error() string {
+	return fmt.Sprintf("%s: import %q not found", e.Pos, e.Path)
+}
+
+// ImportCycleError is the error returned when the import closes a cycle.
+type ImportCycleError struct {
+	// Pos is the position of the import statement which closes the cycle.
+	Pos meta.Position
+	// Cycle holds the import paths in the cycle. The first and the last ones are the same.
+	Cycle []string
+}
+
+func (e *ImportCycleError) Error() string {
+	return fmt.Sprintf("%s: import cycle %s", e.Pos, strings.Join(e.Cycle, " -> "))
+}
+
+// ErrorList is a list of the errors found by Parse.
+type ErrorList []error
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
@@ -0,0 +1,311 @@
+// Package fileset parses a set of .proto files along with all of the files they import.
+package fileset
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// File is a parsed file in the FileSet.
+type File struct {
+	// Path is the import path of the file, which is relative to the import path it was found in.
+	Path string
+	// Filename is the name which the file was opened with. It is also set to the positions in the Proto.
+	Filename string
+	// Proto is the parsed file.
+	Proto *parser.Proto
+	// Imports holds the import paths of the files which are imported by the file, in the order of the import statements.
+	Imports []string
+}
+
+// PublicImports returns the import paths of the files which are imported by the file with the public modifier.
+func (f *File) PublicImports() []string {
+	var imports []string
+	for _, stmt := range f.Proto.ProtoBody {
+		if imp, ok := stmt.(*parser.Import); ok && imp.Modifier == parser.ImportModifierPublic {
+			imports = append(imports, unquote(imp.Location))
+		}
+	}
+	return imports
+}
+
+// FileSet is the transitive closure of the imports of the root files.
+type FileSet struct {
+	// Roots holds the import paths of the root files, in the given order.
+	Roots []string
+	// Files holds all of the parsed files keyed by their import paths.
+	Files map[string]*File
+
+	// sorted holds the import paths in which every file follows the files it imports.
+	sorted []string
+}
+
+// Lookup returns the file whose import path is the path, or nil.
+func (s *FileSet) Lookup(path string) *File {
+	return s.Files[path]
+}
+
+// Sorted returns the files in the order in which every file follows the files it imports.
+func (s *FileSet) Sorted() []*File {
+	var files []*File
+	for _, p := range s.sorted {
+		files = append(files, s.Files[p])
+	}
+	return files
+}
+
+// Opener opens the named file. The name is a slash-separated path which is joined with the import path.
+type Opener func(name string) (io.ReadCloser, error)
+
+// Config is a config for Parse.
+type Config struct {
+	importPaths  []string
+	opener       Opener
+	parseOptions []protoparser.Option
+}
+
+// Option is an option for Config.
+type Option func(*Config)
+
+// WithImportPaths is an option to set the directories in which the imports are searched, like protoc's -I.
+// The current directory is used when no directories are set.
+func WithImportPaths(paths ...string) Option {
+	return func(c *Config) {
+		c.importPaths = append(c.importPaths, paths...)
+	}
+}
+
+// WithFS is an option to read the files from fsys instead of the OS file system.
+func WithFS(fsys fs.FS) Option {
+	return func(c *Config) {
+		c.opener = func(name string) (io.ReadCloser, error) {
+			return fsys.Open(name)
+		}
+	}
+}
+
+// WithOpener is an option to read the files with the opener instead of the OS file system.
+func WithOpener(opener Opener) Option {
+	return func(c *Config) {
+		c.opener = opener
+	}
+}
+
+// WithParseOptions is an option to set the options passed to protoparser.Parse for each file.
+func WithParseOptions(options ...protoparser.Option) Option {
+	return func(c *Config) {
+		c.parseOptions = append(c.parseOptions, options...)
+	}
+}
+
+// Parse parses the root files and all of the files they import.
+// A root file is either a path relative to one of the import paths or a name under one of them.
+// An absolute root which is not under any import paths is opened as it is, and its import path is the absolute one.
+// Every file is parsed once even if it is imported many times.
+//
+// Parse keeps on parsing after an error and returns the partial FileSet along with an ErrorList.
+// The list holds a *FileError for a file which can't be opened or parsed,
+// a *MissingImportError for an import which is not found in any import paths,
+// and an *ImportCycleError for an import which closes a cycle.
+func Parse(roots []string, options ...Option) (*FileSet, error) {
+	config := &Config{
+		opener: func(name string) (io.ReadCloser, error) {
+			return os.Open(filepath.FromSlash(name))
+		},
+	}
+	for _, opt := range options {
+		opt(config)
+	}
+	if len(config.importPaths) == 0 {
+		config.importPaths = []string{"."}
+	}
+
+	r := &resolver{
+		config: config,
+		set: &FileSet{
+			Files: make(map[string]*File),
+		},
+		states: make(map[string]state),
+	}
+	for _, root := range roots {
+		p := r.rootPath(root)
+		r.set.Roots = append(r.set.Roots, p)
+		r.visit(p, nil)
+	}
+
+	if 0 < len(r.errors) {
+		return r.set, r.errors
+	}
+	return r.set, nil
+}
+
+// state is the state of the depth-first traversal over the imports.
+type state int
+
+const (
+	unvisited state = iota
+	visiting
+	visited
+)
+
+type resolver struct {
+	config *Config
+	set    *FileSet
+	errors ErrorList
+	states map[string]state
+	// stack holds the import paths of the files being visited.
+	stack []string
+}
+
+// rootPath returns the import path of the root file.
+func (r *resolver) rootPath(root string) string {
+	name := path.Clean(filepath.ToSlash(root))
+	for _, dir := range r.config.importPaths {
+		dir = path.Clean(filepath.ToSlash(dir))
+		if dir != "." && strings.HasPrefix(name, dir+"/") {
+			return strings.TrimPrefix(name, dir+"/")
+		}
+	}
+	return name
+}
+
+// visit parses the file and then the files it imports.
+// The importer is the import statement through which the file is visited, or nil for a root.
+func (r *resolver) visit(p string, importer *parser.Import) {
+	switch r.states[p] {
+	case visiting:
+		var cycle []string
+		for i := len(r.stack) - 1; 0 <= i; i-- {
+			if r.stack[i] == p {
+				cycle = append(cycle, r.stack[i:]...)
+				break
+			}
+		}
+		r.errors = append(r.errors, &ImportCycleError{
+			Pos:   importer.Meta.Pos,
+			Cycle: append(cycle, p),
+		})
+		return
+	case visited:
+		return
+	}
+
+	file, err := r.parse(p)
+	if err == errNotFound {
+		// the other importers don't report it again.
+		r.states[p] = visited
+		if importer == nil {
+			r.errors = append(r.errors, &FileError{
+				Path: p,
+				Err:  os.ErrNotExist,
+			})
+		} else {
+			r.errors = append(r.errors, &MissingImportError{
+				Pos:  importer.Meta.Pos,
+				Path: p,
+			})
+		}
+		return
+	}
+	if err != nil {
+		r.errors = append(r.errors, &FileError{
+			Path: p,
+			Err:  err,
+		})
+	}
+	if file == nil {
+		r.states[p] = visited
+		return
+	}
+
+	r.set.Files[p] = file
+	r.states[p] = visiting
+	r.stack = append(r.stack, p)
+	for _, stmt := range file.Proto.ProtoBody {
+		imp, ok := stmt.(*parser.Import)
+		if !ok {
+			continue
+		}
+		location := unquote(imp.Location)
+		file.Imports = append(file.Imports, location)
+		r.visit(location, imp)
+	}
+	r.stack = r.stack[:len(r.stack)-1]
+	r.states[p] = visited
+	r.set.sorted = append(r.set.sorted, p)
+}
+
+// errNotFound is returned when the file is not found in any import paths.
+var errNotFound = errors.New("not found")
+
+// parse opens the file whose import path is p from the first import path which has it, and parses it.
+// It returns the partial file along with the error when the parser recovers from the errors.
+func (r *resolver) parse(p string) (*File, error) {
+	dirs := r.config.importPaths
+	if filepath.IsAbs(filepath.FromSlash(p)) {
+		// an absolute root which is not under any import paths is opened as it is.
+		dirs = []string{""}
+	}
+
+	var name string
+	var reader io.ReadCloser
+	for _, dir := range dirs {
+		n := path.Join(filepath.ToSlash(dir), p)
+		rc, err := r.config.opener(n)
+		if err == nil {
+			name, reader = n, rc
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			// only the missing file is searched in the next import path.
+			return nil, err
+		}
+	}
+	if reader == nil {
+		return nil, errNotFound
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	options := append([]protoparser.Option{}, r.config.parseOptions...)
+	options = append(options, protoparser.WithFilename(name))
+	proto, err := protoparser.Parse(reader, options...)
+	if proto == nil {
+		return nil, err
+	}
+	return &File{
+		Path:     p,
+		Filename: name,
+		Proto:    proto,
+	}, err
+}
+
+// unquote returns the content of the string literal.
+func unquote(lit string) string {
+	if len(lit) < 2 {
+		return lit
+	}
+	content := lit[1 : len(lit)-1]
+	if !strings.Contains(content, `\`) {
+		return content
+	}
+	if lit[0] == '\'' {
+		content = strings.ReplaceAll(content, `\'`, `'`)
+		content = strings.ReplaceAll(content, `"`, `\"`)
+	}
+	s, err := strconv.Unquote(`"` + content + `"`)
+	if err != nil {
+		return content
+	}
+	return s
+}
@@ -0,0 +1,361 @@
+package fileset_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+func TestParse(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proto/a.proto": {Data: []byte(`syntax = "proto3";
+import "b/b.proto";
+import 'c.proto';
+`)},
+		"proto/b/b.proto": {Data: []byte(`syntax = "proto3";
+import "c.proto";
+`)},
+		"proto/c.proto": {Data: []byte(`syntax = "proto3";
+`)},
+		"vendor/d.proto": {Data: []byte(`syntax = "proto3";
+import "c.proto";
+`)},
+		"cycle/x.proto": {Data: []byte(`syntax = "proto3";
+import "y.proto";
+`)},
+		"cycle/y.proto": {Data: []byte(`syntax = "proto3";
+import "z.proto";
+`)},
+		"cycle/z.proto": {Data: []byte(`syntax = "proto3";
+
+import "x.proto";
+`)},
+		"missing/m.proto": {Data: []byte(`syntax = "proto3";
+import "c.proto";
+  import "none.proto";
+`)},
+		"missing/n.proto": {Data: []byte(`syntax = "proto3";
+import "none.proto";
+`)},
+	}
+
+	tests := []struct {
+		name          string
+		roots         []string
+		importPaths   []string
+		wantRoots     []string
+		wantSorted    []string
+		wantFilenames map[string]string
+		wantImports   map[string][]string
+		wantErrors    []error
+	}{
+		{
+			name:        "parsing the transitive imports once per file",
+			roots:       []string{"a.proto"},
+			importPaths: []string{"proto"},
+			wantRoots:   []string{"a.proto"},
+			wantSorted:  []string{"c.proto", "b/b.proto", "a.proto"},
+			wantFilenames: map[string]string{
+				"a.proto":   "proto/a.proto",
+				"b/b.proto": "proto/b/b.proto",
+				"c.proto":   "proto/c.proto",
+			},
+			wantImports: map[string][]string{
+				"a.proto":   {"b/b.proto", "c.proto"},
+				"b/b.proto": {"c.proto"},
+				"c.proto":   nil,
+			},
+		},
+		{
+			name:        "searching the import paths in order",
+			roots:       []string{"vendor/d.proto", "proto/b/b.proto"},
+			importPaths: []string{"vendor", "proto"},
+			wantRoots:   []string{"d.proto", "b/b.proto"},
+			wantSorted:  []string{"c.proto", "d.proto", "b/b.proto"},
+			wantFilenames: map[string]string{
+				"b/b.proto": "proto/b/b.proto",
+				"c.proto":   "proto/c.proto",
+				"d.proto":   "vendor/d.proto",
+			},
+			wantImports: map[string][]string{
+				"b/b.proto": {"c.proto"},
+				"c.proto":   nil,
+				"d.proto":   {"c.proto"},
+			},
+		},
+		{
+			name:        "reporting an import cycle",
+			roots:       []string{"x.proto"},
+			importPaths: []string{"cycle"},
+			wantRoots:   []string{"x.proto"},
+			wantSorted:  []string{"z.proto", "y.proto", "x.proto"},
+			wantFilenames: map[string]string{
+				"x.proto": "cycle/x.proto",
+				"y.proto": "cycle/y.proto",
+				"z.proto": "cycle/z.proto",
+			},
+			wantImports: map[string][]string{
+				"x.proto": {"y.proto"},
+				"y.proto": {"z.proto"},
+				"z.proto": {"x.proto"},
+			},
+			wantErrors: []error{
+				&fileset.ImportCycleError{
+					Pos: meta.Position{
+						Filename: "cycle/z.proto",
+						Offset:   20,
+						Line:     3,
+						Column:   1,
+					},
+					Cycle: []string{"x.proto", "y.proto", "z.proto", "x.proto"},
+				},
+			},
+		},
+		{
+			name:        "reporting a missing import",
+			roots:       []string{"m.proto"},
+			importPaths: []string{"missing", "proto"},
+			wantRoots:   []string{"m.proto"},
+			wantSorted:  []string{"c.proto", "m.proto"},
+			wantFilenames: map[string]string{
+				"c.proto": "proto/c.proto",
+				"m.proto": "missing/m.proto",
+			},
+			wantImports: map[string][]string{
+				"c.proto": nil,
+				"m.proto": {"c.proto", "none.proto"},
+			},
+			wantErrors: []error{
+				&fileset.MissingImportError{
+					Pos: meta.Position{
+						Filename: "missing/m.proto",
+						Offset:   39,
+						Line:     3,
+						Column:   3,
+					},
+					Path: "none.proto",
+				},
+			},
+		},
+		{
+			name:        "reporting a missing import once",
+			roots:       []string{"m.proto", "n.proto"},
+			importPaths: []string{"missing", "proto"},
+			wantRoots:   []string{"m.proto", "n.proto"},
+			wantSorted:  []string{"c.proto", "m.proto", "n.proto"},
+			wantFilenames: map[string]string{
+				"c.proto": "proto/c.proto",
+				"m.proto": "missing/m.proto",
+				"n.proto": "missing/n.proto",
+			},
+			wantImports: map[string][]string{
+				"c.proto": nil,
+				"m.proto": {"c.proto", "none.proto"},
+				"n.proto": {"none.proto"},
+			},
+			wantErrors: []error{
+				&fileset.MissingImportError{
+					Pos: meta.Position{
+						Filename: "missing/m.proto",
+						Offset:   39,
+						Line:     3,
+						Column:   3,
+					},
+					Path: "none.proto",
+				},
+			},
+		},
+		{
+			name:          "reporting a missing root",
+			roots:         []string{"none.proto"},
+			wantRoots:     []string{"none.proto"},
+			wantFilenames: map[string]string{},
+			wantImports:   map[string][]string{},
+			wantErrors: []error{
+				&fileset.FileError{
+					Path: "none.proto",
+					Err:  os.ErrNotExist,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := fileset.Parse(
+				test.roots,
+				fileset.WithImportPaths(test.importPaths...),
+				fileset.WithFS(fsys),
+			)
+			if len(test.wantErrors) == 0 && err != nil {
+				t.Errorf("got err %v, but want nil", err)
+			}
+			if 0 < len(test.wantErrors) {
+				var list fileset.ErrorList
+				if !errors.As(err, &list) {
+					t.Fatalf("got err %v, but want ErrorList", err)
+				}
+				if !reflect.DeepEqual([]error(list), test.wantErrors) {
+					t.Errorf("got %v, but want %v", list, test.wantErrors)
+				}
+			}
+
+			if !reflect.DeepEqual(got.Roots, test.wantRoots) {
+				t.Errorf("got %v, but want %v", got.Roots, test.wantRoots)
+			}
+			var sorted []string
+			for _, file := range got.Sorted() {
+				sorted = append(sorted, file.Path)
+			}
+			if !reflect.DeepEqual(sorted, test.wantSorted) {
+				t.Errorf("got %v, but want %v", sorted, test.wantSorted)
+			}
+			filenames := make(map[string]string)
+			imports := make(map[string][]string)
+			for path, file := range got.Files {
+				filenames[path] = file.Filename
+				imports[path] = file.Imports
+				if file.Proto.Meta.Filename != file.Filename {
+					t.Errorf("got %s, but want %s", file.Proto.Meta.Filename, file.Filename)
+				}
+			}
+			if !reflect.DeepEqual(filenames, test.wantFilenames) {
+				t.Errorf("got %v, but want %v", filenames, test.wantFilenames)
+			}
+			if !reflect.DeepEqual(imports, test.wantImports) {
+				t.Errorf("got %v, but want %v", imports, test.wantImports)
+			}
+		})
+	}
+}
+
+func TestParse_parseError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.proto": {Data: []byte(`syntax = "proto3";
+import "b.proto";
+message {}
+`)},
+		"b.proto": {Data: []byte(`syntax = "proto3";
+`)},
+	}
+
+	t.Run("failing to parse", func(t *testing.T) {
+		got, err := fileset.Parse([]string{"a.proto"}, fileset.WithFS(fsys))
+		var list fileset.ErrorList
+		if !errors.As(err, &list) || len(list) != 1 {
+			t.Fatalf("got err %v, but want an ErrorList", err)
+		}
+		var fileErr *fileset.FileError
+		if !errors.As(list[0], &fileErr) || fileErr.Path != "a.proto" {
+			t.Errorf("got err %v, but want a FileError", list[0])
+		}
+		if len(got.Files) != 0 {
+			t.Errorf("got %v, but want no files", got.Files)
+		}
+	})
+	t.Run("recovering from the error", func(t *testing.T) {
+		got, err := fileset.Parse(
+			[]string{"a.proto"},
+			fileset.WithFS(fsys),
+			fileset.WithParseOptions(protoparser.WithErrorRecovery(true)),
+		)
+		var list fileset.ErrorList
+		if !errors.As(err, &list) || len(list) != 1 {
+			t.Fatalf("got err %v, but want an ErrorList", err)
+		}
+		var parseErrs meta.ErrorList
+		if !errors.As(list[0], &parseErrs) {
+			t.Errorf("got err %v, but want meta.ErrorList", list[0])
+		}
+		if got.Lookup("a.proto") == nil || got.Lookup("b.proto") == nil {
+			t.Errorf("got %v, but want a.proto and b.proto", got.Files)
+		}
+	})
+}
+
+func TestWithOpener(t *testing.T) {
+	var opened []string
+	opener := func(name string) (io.ReadCloser, error) {
+		opened = append(opened, name)
+		switch name {
+		case "gen/a.proto":
+			return ioutil.NopCloser(strings.NewReader(`syntax = "proto3"; import "b.proto"; import "b.proto";`)), nil
+		case "src/b.proto":
+			return ioutil.NopCloser(strings.NewReader(`syntax = "proto3";`)), nil
+		}
+		return nil, os.ErrNotExist
+	}
+
+	got, err := fileset.Parse(
+		[]string{"a.proto"},
+		fileset.WithImportPaths("gen", "src"),
+		fileset.WithOpener(opener),
+	)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	if len(got.Files) != 2 {
+		t.Errorf("got %v, but want 2 files", got.Files)
+	}
+	want := []string{"gen/a.proto", "gen/b.proto", "src/b.proto"}
+	if !reflect.DeepEqual(opened, want) {
+		t.Errorf("got %v, but want %v", opened, want)
+	}
+}
+
+func TestWithOpener_error(t *testing.T) {
+	opener := func(name string) (io.ReadCloser, error) {
+		switch name {
+		case "gen/a.proto":
+			return ioutil.NopCloser(strings.NewReader(`syntax = "proto3"; import "b.proto";`)), nil
+		case "gen/b.proto":
+			return nil, os.ErrPermission
+		}
+		return nil, os.ErrNotExist
+	}
+
+	_, err := fileset.Parse(
+		[]string{"a.proto"},
+		fileset.WithImportPaths("gen", "src"),
+		fileset.WithOpener(opener),
+	)
+	var list fileset.ErrorList
+	if !errors.As(err, &list) || len(list) != 1 {
+		t.Fatalf("got err %v, but want an ErrorList", err)
+	}
+	var fileErr *fileset.FileError
+	if !errors.As(list[0], &fileErr) || fileErr.Path != "b.proto" || !errors.Is(fileErr, os.ErrPermission) {
+		t.Errorf("got err %v, but want a FileError of the permission error", list[0])
+	}
+}
+
+func TestParse_absoluteRoot(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "c.proto")
+	if err := ioutil.WriteFile(filename, []byte(`syntax = "proto3";`), 0o600); err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	got, err := fileset.Parse([]string{filename})
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	want := filepath.ToSlash(filename)
+	if !reflect.DeepEqual(got.Roots, []string{want}) {
+		t.Errorf("got %v, but want %v", got.Roots, []string{want})
+	}
+	if file := got.Lookup(want); file == nil || file.Filename != want {
+		t.Errorf("got %v, but want the file %s", file, want)
+	}
+}
@@ -0,0 +1,285 @@
+// Package format implements the standard formatting of .proto source.
+//
+// The formatting normalizes the indentation, the spacing, the alignment of the field numbers and the inline comments,
+// the blank lines between the top-level definitions, and the quotes of the string literals.
+// It keeps all of the comments attached by the parser, and the result is stable when formatted again.
+package format
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/printer"
+)
+
+// indent is the unit of the indentation.
+const indent = "  "
+
+// Source formats the src in the standard style.
+func Source(src []byte) ([]byte, error) {
+	p := parser.NewParser(
+		lexer.NewLexer(bytes.NewReader(src)),
+		parser.WithPermissive(true),
+		parser.WithBodyIncludingComments(true),
+	)
+	proto, err := p.ParseProto()
+	if err != nil {
+		return nil, err
+	}
+
+	proto.Accept(&normalizer{})
+
+	var buf bytes.Buffer
+	err = (&printer.Config{
+		Indent:              indent,
+		AlignFields:         true,
+		SeparateDefinitions: true,
+	}).Fprint(&buf, proto)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Fprint formats the proto in the standard style and writes the result to w.
+// The proto is not modified.
+func Fprint(w io.Writer, proto *parser.Proto) error {
+	var src bytes.Buffer
+	if err := printer.Fprint(&src, proto); err != nil {
+		return err
+	}
+	formatted, err := Source(src.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// normalizer normalizes the quotes and the option constants.
+type normalizer struct{}
+
+func (n *normalizer) VisitComment(*parser.Comment) {}
+
+func (n *normalizer) VisitEdition(e *parser.Edition) bool {
+	e.EditionQuote = doubleQuote(e.EditionQuote)
+	return true
+}
+
+func (n *normalizer) VisitEmptyStatement(*parser.EmptyStatement) bool {
+	return true
+}
+
+func (n *normalizer) VisitEnum(*parser.Enum) bool {
+	return true
+}
+
+func (n *normalizer) VisitEnumField(f *parser.EnumField) bool {
+	for _, option := range f.EnumValueOptions {
+		option.Value = normalizeValue(option.Value)
+		option.Constant = constant(option.Constant, option.Value)
+	}
+	return true
+}
+
+func (n *normalizer) VisitExtend(*parser.Extend) bool {
+	return true
+}
+
+func (n *normalizer) VisitExtensions(*parser.Extensions) bool {
+	return true
+}
+
+func (n *normalizer) VisitField(f *parser.Field) bool {
+	normalizeFieldOptions(f.FieldOptions)
+	return true
+}
+
+func (n *normalizer) VisitGroupField(*parser.GroupField) bool {
+	return true
+}
+
+func (n *normalizer) VisitImport(i *parser.Import) bool {
+	i.Location = doubleQuote(i.Location)
+	return true
+}
+
+func (n *normalizer) VisitMapField(f *parser.MapField) bool {
+	normalizeFieldOptions(f.FieldOptions)
+	return true
+}
+
+func (n *normalizer) VisitMessage(*parser.Message) bool {
+	return true
+}
+
+func (n *normalizer) VisitOneof(*parser.Oneof) bool {
+	return true
+}
+
+func (n *normalizer) VisitOneofField(f *parser.OneofField) bool {
+	normalizeFieldOptions(f.FieldOptions)
+	return true
+}
+
+func (n *normalizer) VisitOption(o *parser.Option) bool {
+	o.Value = normalizeValue(o.Value)
+	o.Constant = constant(o.Constant, o.Value)
+	return true
+}
+
+func (n *normalizer) VisitPackage(*parser.Package) bool {
+	return true
+}
+
+func (n *normalizer) VisitReserved(r *parser.Reserved) bool {
+	for i, name := range r.FieldNames {
+		r.FieldNames[i] = doubleQuote(name)
+	}
+	return true
+}
+
+func (n *normalizer) VisitRPC(r *parser.RPC) bool {
+	// RPC.Accept doesn't visit the options.
+	for _, option := range r.Options {
+		n.VisitOption(option)
+	}
+	return true
+}
+
+func (n *normalizer) VisitService(*parser.Service) bool {
+	return true
+}
+
+func (n *normalizer) VisitSyntax(s *parser.Syntax) bool {
+	s.ProtobufVersionQuote = doubleQuote(s.ProtobufVersionQuote)
+	return true
+}
+
+func normalizeFieldOptions(options []*parser.FieldOption) {
+	for _, option := range options {
+		option.Value = normalizeValue(option.Value)
+		option.Constant = constant(option.Constant, option.Value)
+	}
+}
+
+// normalizeValue returns a copy of the value whose strings are double-quoted. The value is not modified.
+func normalizeValue(value *parser.OptionValue) *parser.OptionValue {
+	if value == nil {
+		return nil
+	}
+	normalized := *value
+	switch value.Kind {
+	case parser.OptionValueKindMessage:
+		normalized.Fields = make([]*parser.OptionValueField, len(value.Fields))
+		for i, field := range value.Fields {
+			f := *field
+			f.Value = normalizeValue(field.Value)
+			normalized.Fields[i] = &f
+		}
+	case parser.OptionValueKindList:
+		normalized.Elements = make([]*parser.OptionValue, len(value.Elements))
+		for i, element := range value.Elements {
+			normalized.Elements[i] = normalizeValue(element)
+		}
+	case parser.OptionValueKindString:
+		normalized.Scalar = doubleQuote(value.Scalar)
+	}
+	return &normalized
+}
+
+// constant renders the normalized value. It falls back to the original constant when the value is missing.
+func constant(original string, value *parser.OptionValue) string {
+	if value == nil {
+		return doubleQuote(original)
+	}
+	return optionValue(value, 0)
+}
+
+// optionValue renders the value with putting each field of a message literal on its own line.
+// The lines are indented relative to the statement.
+func optionValue(value *parser.OptionValue, depth int) string {
+	switch value.Kind {
+	case parser.OptionValueKindMessage:
+		if len(value.Fields) == 0 {
+			return "{}"
+		}
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, field := range value.Fields {
+			b.WriteString(strings.Repeat(indent, depth+1))
+			b.WriteString(field.Name)
+			b.WriteString(": ")
+			b.WriteString(optionValue(field.Value, depth+1))
+			b.WriteString("\n")
+		}
+		b.WriteString(strings.Repeat(indent, depth))
+		b.WriteString("}")
+		return b.String()
+	case parser.OptionValueKindList:
+		var elements []string
+		for _, element := range value.Elements {
+			elements = append(elements, optionValue(element, depth))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	default:
+		return value.Scalar
+	}
+}
+
+// doubleQuote replaces the single quotes of the string literals in the text with the double quotes.
+func doubleQuote(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		q := text[i]
+		if q != '\'' {
+			if q == '"' {
+				// skips the double-quoted literal so that the single quotes in it are kept.
+				end := literalEnd(text, i)
+				b.WriteString(text[i:end])
+				i = end - 1
+				continue
+			}
+			b.WriteByte(q)
+			continue
+		}
+
+		end := literalEnd(text, i)
+		b.WriteByte('"')
+		for j := i + 1; j < end-1; j++ {
+			switch {
+			case text[j] == '\\' && j+1 < end-1:
+				if text[j+1] == '\'' {
+					b.WriteByte('\'')
+				} else {
+					b.WriteString(text[j : j+2])
+				}
+				j++
+			case text[j] == '"':
+				b.WriteString(`\"`)
+			default:
+				b.WriteByte(text[j])
+			}
+		}
+		b.WriteByte('"')
+		i = end - 1
+	}
+	return b.String()
+}
+
+// literalEnd returns the offset next to the closing quote of the literal which starts at the offset.
+func literalEnd(text string, start int) int {
+	q := text[start]
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case q:
+			return i + 1
+		}
+	}
+	return len(text)
+}
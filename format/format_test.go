@@ -0,0 +1,272 @@
+package format_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/format"
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "formatting an invalid source",
+			input:   `syntax = "proto3"; message {`,
+			wantErr: true,
+		},
+		{
+			name: "normalizing the indentation, the spacing and the blank lines",
+			input: `syntax='proto3';
+package foo;
+import "a.proto";
+
+
+import 'b.proto';
+option java_package="foo";
+message A{
+        int32 a=1;
+
+
+
+    message B {}
+}
+enum E {E0=0;}
+service S {rpc Get ( A )returns( stream A ) {}  }
+`,
+			want: `syntax = "proto3";
+
+package foo;
+
+import "a.proto";
+
+import "b.proto";
+
+option java_package = "foo";
+
+message A {
+  int32 a = 1;
+
+  message B {}
+}
+
+enum E {
+  E0 = 0;
+}
+
+service S {
+  rpc Get(A) returns (stream A);
+}
+`,
+		},
+		{
+			name: "aligning the field numbers and the inline comments",
+			input: `syntax = "proto3";
+message A { // A
+  // a
+  int32 a = 1; // a
+  repeated string long_name = 2;
+  map<string, int32> m = 3 [deprecated = true]; // m
+
+  oneof o {
+    string x = 4; // x
+    /* y */
+    bytes yy = 5;   /* yy */
+  }
+}
+enum E {
+  E0 = 0;
+  ELONG = 1; // ELONG
+}
+`,
+			want: `syntax = "proto3";
+
+message A { // A
+  // a
+  int32 a                   = 1;                     // a
+  repeated string long_name = 2;
+  map<string, int32> m      = 3 [deprecated = true]; // m
+
+  oneof o {
+    string x = 4; // x
+    /* y */
+    bytes yy = 5; /* yy */
+  }
+}
+
+enum E {
+  E0    = 0;
+  ELONG = 1; // ELONG
+}
+`,
+		},
+		{
+			name: "normalizing the option values and the quotes",
+			input: `syntax = "proto3";
+option (a) = {b:'it\'s' c:{d:"x'y"} e:[1,2]};
+message A {
+  reserved 'foo', "bar";
+  string s = 1 [(v) = {min:1, max:2}, default='"'];
+}
+`,
+			want: `syntax = "proto3";
+
+option (a) = {
+  b: "it's"
+  c: {
+    d: "x'y"
+  }
+  e: [1, 2]
+};
+
+message A {
+  reserved "foo", "bar";
+  string s = 1 [(v) = {
+    min: 1
+    max: 2
+  }, default = "\""];
+}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := format.Source([]byte(test.input))
+			switch {
+			case test.wantErr:
+				if err == nil {
+					t.Errorf("got err nil, but want err")
+				}
+				return
+			case !test.wantErr && err != nil:
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+
+			if string(got) != test.want {
+				t.Errorf("got\n%s\nbut want\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSource_testdata(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "_testdata", "*.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no testdata")
+	}
+
+	for _, file := range files {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			src, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			formatted, err := format.Source(src)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			again, err := format.Source(formatted)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			if !bytes.Equal(again, formatted) {
+				t.Errorf("got\n%s\nbut want\n%s", again, formatted)
+			}
+
+			want := comments(parse(t, src))
+			got := comments(parse(t, formatted))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, but want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFprint(t *testing.T) {
+	src := []byte(`syntax = 'proto3';
+message A {int32 a=1 [(x) = {s: 'y', l: ['z']}];}
+`)
+	proto := parse(t, src)
+
+	var got bytes.Buffer
+	if err := format.Fprint(&got, proto); err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	want := `syntax = "proto3";
+
+message A {
+  int32 a = 1 [(x) = {
+    s: "y"
+    l: ["z"]
+  }];
+}
+`
+	if got.String() != want {
+		t.Errorf("got\n%s\nbut want\n%s", got.String(), want)
+	}
+
+	if !reflect.DeepEqual(proto, parse(t, src)) {
+		t.Errorf("got the modified proto")
+	}
+}
+
+func parse(t *testing.T, src []byte) *parser.Proto {
+	t.Helper()
+	p := parser.NewParser(
+		lexer.NewLexer(bytes.NewReader(src)),
+		parser.WithPermissive(true),
+		parser.WithBodyIncludingComments(true),
+	)
+	proto, err := p.ParseProto()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return proto
+}
+
+// comments collects the raw comments in the proto.
+func comments(proto *parser.Proto) []string {
+	var raws []string
+	var collect func(v reflect.Value)
+	collect = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if v.IsNil() {
+				return
+			}
+			if comment, ok := v.Interface().(*parser.Comment); ok {
+				raws = append(raws, strings.TrimSpace(comment.Raw))
+				return
+			}
+			collect(v.Elem())
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				collect(v.Index(i))
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				collect(v.Field(i))
+			}
+		}
+	}
+	collect(reflect.ValueOf(proto))
+	return raws
+}
@@ -1,3 +1,3 @@
 module github.com/yoheimuta/go-protoparser/v4
 
-go 1.13
+go 1.16
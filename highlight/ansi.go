@@ -0,0 +1,84 @@
+package highlight
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/yoheimuta/go-protoparser/v4/token"
+)
+
+// The SGR escape sequences of the ANSI terminals.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiUnderline = "\x1b[4m"
+	ansiRed       = "\x1b[31m"
+	ansiGreen     = "\x1b[32m"
+	ansiYellow    = "\x1b[33m"
+	ansiMagenta   = "\x1b[35m"
+	ansiCyan      = "\x1b[36m"
+	ansiGray      = "\x1b[90m"
+)
+
+// ANSI writes the src colored with the escape sequences of the ANSI terminals.
+//
+// The keywords are magenta, the literals are yellow and green, the comments are gray and the illegal tokens are red.
+// The names of the definitions are bold, and the type names which refer to the definitions are cyan and underlined.
+func ANSI(w io.Writer, src []byte, options ...Option) error {
+	config := newConfig(options)
+
+	var b bytes.Buffer
+	offset := 0
+	inRef := false
+	for _, s := range annotate(src, config) {
+		b.Write(src[offset:s.Pos.Offset])
+		offset = s.End.Offset
+		if s.ref != nil {
+			inRef = true
+			b.WriteString(ansiCyan + ansiUnderline)
+		}
+		if inRef {
+			// the whole type name is styled at once.
+			b.WriteString(s.Raw)
+			if s.refEnd {
+				inRef = false
+				b.WriteString(ansiReset)
+			}
+			continue
+		}
+
+		style := ansiColor(s.Kind)
+		if s.def != nil {
+			style += ansiBold
+		}
+		if style == "" {
+			b.WriteString(s.Raw)
+			continue
+		}
+		b.WriteString(style + s.Raw + ansiReset)
+	}
+	if inRef {
+		b.WriteString(ansiReset)
+	}
+	b.Write(src[offset:])
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func ansiColor(kind token.Kind) string {
+	switch kind {
+	case token.KindKeyword:
+		return ansiMagenta
+	case token.KindIntLit, token.KindFloatLit, token.KindBoolLit:
+		return ansiYellow
+	case token.KindStrLit:
+		return ansiGreen
+	case token.KindComment:
+		return ansiGray
+	case token.KindIllegal:
+		return ansiRed
+	default:
+		return ""
+	}
+}
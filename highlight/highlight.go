@@ -0,0 +1,150 @@
+// Package highlight renders .proto source with the syntax highlighting, in HTML or for the ANSI terminals.
+//
+// The tokens are colored by their kinds, the names of the messages, the enums and the services are marked
+// as their definitions, and the type names are linked to the definitions which they refer to.
+// The source is rendered even if it has syntax errors, in which case the type names may not be linked.
+package highlight
+
+import (
+	"bytes"
+
+	"github.com/yoheimuta/go-protoparser/v4/astutil"
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/token"
+)
+
+// Config is a config for the renderers.
+type Config struct {
+	filename string
+	table    *linker.Table
+	href     func(symbol *linker.Symbol) string
+}
+
+// Option is an option for the renderers.
+type Option func(*Config)
+
+// WithFilename is an option to set the filename of the source, by which the file is found in the table of WithTable.
+func WithFilename(filename string) Option {
+	return func(c *Config) {
+		c.filename = filename
+	}
+}
+
+// WithTable is an option to link the type names to the definitions in the other files.
+// The table must have the file of the filename which is parsed from the same source with
+// protoparser.WithDetailedPositions. Without it, the source is linked by itself.
+func WithTable(table *linker.Table) Option {
+	return func(c *Config) {
+		c.table = table
+	}
+}
+
+// WithHref is an option to set the function which returns the URL of the definition to which a type name is linked
+// in HTML. The type name is not linked when it returns "".
+// The default one returns "#" followed by the full name for the definitions in the file, and "" for the others.
+func WithHref(href func(symbol *linker.Symbol) string) Option {
+	return func(c *Config) {
+		c.href = href
+	}
+}
+
+func newConfig(options []Option) *Config {
+	c := &Config{}
+	for _, opt := range options {
+		opt(c)
+	}
+	if c.href == nil {
+		c.href = func(symbol *linker.Symbol) string {
+			if symbol.File == nil || symbol.File.Filename != c.filename {
+				return ""
+			}
+			return "#" + symbol.FullName
+		}
+	}
+	return c
+}
+
+// span is a token annotated with the definition which it names or refers to.
+type span struct {
+	token.Token
+	// def is the definition whose name is the token.
+	def *linker.Symbol
+	// ref is the definition to which the type name starting at the token refers.
+	ref *linker.Symbol
+	// refEnd is whether the token ends a type name which refers to a definition.
+	refEnd bool
+}
+
+// annotate splits the src into the tokens and annotates them.
+func annotate(src []byte, config *Config) []span {
+	// the illegal tokens are rendered as they are.
+	tokens, _ := token.Tokenize(bytes.NewReader(src), token.WithFilename(config.filename))
+
+	table := config.table
+	if table == nil {
+		table = link(src, config.filename)
+	}
+	defs := make(map[int]*linker.Symbol)
+	for _, symbol := range table.Symbols {
+		if symbol.File == nil || symbol.File.Filename != config.filename {
+			continue
+		}
+		if !symbol.IsType() && symbol.Kind != linker.SymbolKindService {
+			continue
+		}
+		if mt := astutil.NameMeta(symbol.Node); mt.Pos.Line != 0 {
+			defs[mt.Pos.Offset] = symbol
+		}
+	}
+	refs := make(map[int]*linker.Reference)
+	for _, ref := range table.References {
+		if ref.File.Filename != config.filename || ref.Symbol == nil {
+			continue
+		}
+		if mt := astutil.TypeNameMeta(ref.Node); mt.Pos.Line != 0 {
+			refs[mt.Pos.Offset] = ref
+		}
+	}
+
+	spans := make([]span, len(tokens))
+	// refLast is the offset of the last character of the type name which the current token is in, or -1.
+	refLast := -1
+	for i, tok := range tokens {
+		s := span{Token: tok, def: defs[tok.Pos.Offset]}
+		if ref, ok := refs[tok.Pos.Offset]; ok && refLast < 0 {
+			s.ref = ref.Symbol
+			refLast = astutil.TypeNameMeta(ref.Node).LastPos.Offset
+		}
+		if 0 <= refLast && refLast < tok.End.Offset {
+			s.refEnd = true
+			refLast = -1
+		}
+		spans[i] = s
+	}
+	return spans
+}
+
+// link parses the src and links it by itself. The errors are ignored, because the partial table is enough to render.
+func link(src []byte, filename string) *linker.Table {
+	p := parser.NewParser(
+		lexer.NewLexer(bytes.NewReader(src), lexer.WithFilename(filename)),
+		parser.WithPermissive(true),
+		parser.WithErrorRecovery(true),
+		parser.WithDetailedPositions(true),
+	)
+	proto, _ := p.ParseProto()
+	if proto == nil {
+		proto = &parser.Proto{}
+	}
+	table, _ := linker.Link([]*fileset.File{
+		{
+			Path:     filename,
+			Filename: filename,
+			Proto:    proto,
+		},
+	})
+	return table
+}
@@ -0,0 +1,103 @@
+package highlight_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/highlight"
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+)
+
+const src = `package pkg;
+// A is "a".
+message A {
+  message B {}
+  .pkg.A.B b = 1;
+  map<string, B> m = 2;
+  C c = 3;
+}
+`
+
+func TestHTML(t *testing.T) {
+	var got bytes.Buffer
+	err := highlight.HTML(&got, []byte(src), highlight.WithFilename("a.proto"))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	want := `<pre class="proto"><span class="keyword">package</span> <span class="ident">pkg</span><span class="punct">;</span>
+<span class="comment">// A is &#34;a&#34;.</span>
+<span class="keyword">message</span> <span class="ident" id="pkg.A">A</span> <span class="punct">{</span>
+  <span class="keyword">message</span> <span class="ident" id="pkg.A.B">B</span> <span class="punct">{</span><span class="punct">}</span>
+  <a href="#pkg.A.B"><span class="punct">.</span><span class="ident">pkg</span><span class="punct">.</span><span class="ident">A</span><span class="punct">.</span><span class="ident">B</span></a> <span class="ident">b</span> <span class="punct">=</span> <span class="int">1</span><span class="punct">;</span>
+  <span class="keyword">map</span><span class="punct">&lt;</span><span class="ident">string</span><span class="punct">,</span> <a href="#pkg.A.B"><span class="ident">B</span></a><span class="punct">&gt;</span> <span class="ident">m</span> <span class="punct">=</span> <span class="int">2</span><span class="punct">;</span>
+  <span class="ident">C</span> <span class="ident">c</span> <span class="punct">=</span> <span class="int">3</span><span class="punct">;</span>
+<span class="punct">}</span>
+</pre>
+`
+	if got.String() != want {
+		t.Errorf("got %s, but want %s", got.String(), want)
+	}
+}
+
+func TestHTML_withTable(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.proto": &fstest.MapFile{Data: []byte(`syntax = "proto3"; import "b.proto"; service S { rpc Get(B) returns (B); }`)},
+		"b.proto": &fstest.MapFile{Data: []byte(`syntax = "proto3"; message B {}`)},
+	}
+	set, err := fileset.Parse(
+		[]string{"a.proto"},
+		fileset.WithFS(fsys),
+		fileset.WithParseOptions(protoparser.WithDetailedPositions(true)),
+	)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	table, err := linker.LinkFileSet(set)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	var got bytes.Buffer
+	err = highlight.HTML(
+		&got,
+		fsys["a.proto"].Data,
+		highlight.WithFilename("a.proto"),
+		highlight.WithTable(table),
+		highlight.WithHref(func(symbol *linker.Symbol) string {
+			return symbol.File.Filename + ".html#" + symbol.FullName
+		}),
+	)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	want := `<pre class="proto"><span class="keyword">syntax</span> <span class="punct">=</span> <span class="string">&#34;proto3&#34;</span><span class="punct">;</span> <span class="keyword">import</span> <span class="string">&#34;b.proto&#34;</span><span class="punct">;</span> <span class="keyword">service</span> <span class="ident" id="S">S</span> <span class="punct">{</span> <span class="keyword">rpc</span> <span class="ident">Get</span><span class="punct">(</span><a href="b.proto.html#B"><span class="ident">B</span></a><span class="punct">)</span> <span class="keyword">returns</span> <span class="punct">(</span><a href="b.proto.html#B"><span class="ident">B</span></a><span class="punct">)</span><span class="punct">;</span> <span class="punct">}</span></pre>
+`
+	if got.String() != want {
+		t.Errorf("got %s, but want %s", got.String(), want)
+	}
+}
+
+func TestANSI(t *testing.T) {
+	var got bytes.Buffer
+	err := highlight.ANSI(&got, []byte(src), highlight.WithFilename("a.proto"))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	want := "\x1b[35mpackage\x1b[0m pkg;\n" +
+		"\x1b[90m// A is \"a\".\x1b[0m\n" +
+		"\x1b[35mmessage\x1b[0m \x1b[1mA\x1b[0m {\n" +
+		"  \x1b[35mmessage\x1b[0m \x1b[1mB\x1b[0m {}\n" +
+		"  \x1b[36m\x1b[4m.pkg.A.B\x1b[0m b = \x1b[33m1\x1b[0m;\n" +
+		"  \x1b[35mmap\x1b[0m<string, \x1b[36m\x1b[4mB\x1b[0m> m = \x1b[33m2\x1b[0m;\n" +
+		"  C c = \x1b[33m3\x1b[0m;\n" +
+		"}\n"
+	if got.String() != want {
+		t.Errorf("got %q, but want %q", got.String(), want)
+	}
+}
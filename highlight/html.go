@@ -0,0 +1,75 @@
+package highlight
+
+import (
+	"bytes"
+	"html"
+	"io"
+
+	"github.com/yoheimuta/go-protoparser/v4/token"
+)
+
+// HTML writes the src in a <pre class="proto"> element, in which each token is a <span> with the class of its kind.
+//
+// The classes are keyword, ident, int, float, bool, string, comment, punct and illegal.
+// The name of a definition has the full name as its id, like <span class="ident" id="pkg.Outer.Inner">,
+// and a type name is wrapped in an <a> element which links to its definition.
+func HTML(w io.Writer, src []byte, options ...Option) error {
+	config := newConfig(options)
+
+	var b bytes.Buffer
+	b.WriteString(`<pre class="proto">`)
+	offset := 0
+	linked := false
+	for _, s := range annotate(src, config) {
+		b.WriteString(html.EscapeString(string(src[offset:s.Pos.Offset])))
+		if s.ref != nil {
+			if href := config.href(s.ref); href != "" {
+				b.WriteString(`<a href="` + html.EscapeString(href) + `">`)
+				linked = true
+			}
+		}
+
+		b.WriteString(`<span class="` + htmlClass(s.Kind) + `"`)
+		if s.def != nil {
+			b.WriteString(` id="` + html.EscapeString(s.def.FullName) + `"`)
+		}
+		b.WriteString(`>` + html.EscapeString(s.Raw) + `</span>`)
+
+		if s.refEnd && linked {
+			b.WriteString(`</a>`)
+			linked = false
+		}
+		offset = s.End.Offset
+	}
+	if linked {
+		b.WriteString(`</a>`)
+	}
+	b.WriteString(html.EscapeString(string(src[offset:])))
+	b.WriteString("</pre>\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func htmlClass(kind token.Kind) string {
+	switch kind {
+	case token.KindKeyword:
+		return "keyword"
+	case token.KindIdent:
+		return "ident"
+	case token.KindIntLit:
+		return "int"
+	case token.KindFloatLit:
+		return "float"
+	case token.KindBoolLit:
+		return "bool"
+	case token.KindStrLit:
+		return "string"
+	case token.KindComment:
+		return "comment"
+	case token.KindPunctuation:
+		return "punct"
+	default:
+		return "illegal"
+	}
+}
@@ -0,0 +1,166 @@
+// Package index records the parent and the fully-qualified name of each definition in a parsed proto,
+// so that a consumer doesn't have to rebuild the context in its visitor.
+package index
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// Entry is an indexed node.
+type Entry struct {
+	// Node is the node, which is a *parser.Message, a *parser.GroupField, a *parser.Enum, a *parser.EnumField,
+	// a *parser.Field, a *parser.MapField, a *parser.Oneof, a *parser.OneofField, a *parser.Service or a *parser.RPC.
+	Node parser.Visitee
+	// FullName is the fully-qualified name without the leading dot, like "pkg.Outer.Inner".
+	// As protoc names them, an enum value is a sibling of its enum, like "pkg.Outer.VALUE",
+	// and an extension is named in the scope which encloses the Extend, like "pkg.ext".
+	FullName string
+	// Parent is the node which has the node, which is a *parser.Message, a *parser.GroupField, a *parser.Enum,
+	// a *parser.Oneof, a *parser.Extend, a *parser.Service, or the *parser.Proto for a top-level definition.
+	Parent parser.Visitee
+}
+
+// Index holds the entries of the nodes in a proto.
+type Index struct {
+	// Proto is the indexed proto.
+	Proto *parser.Proto
+	// Package is the package statement of the proto, or nil.
+	Package *parser.Package
+	// Entries holds all of the entries in the order of the source.
+	Entries []*Entry
+
+	byNode map[parser.Visitee]*Entry
+	byName map[string]*Entry
+	// scopes holds the scopes in which the nodes in the node are named.
+	scopes map[parser.Visitee]string
+}
+
+// New indexes the proto. The proto must not be modified after indexing, or the index gets stale.
+func New(proto *parser.Proto) *Index {
+	x := &Index{
+		Proto:  proto,
+		byNode: make(map[parser.Visitee]*Entry),
+		byName: make(map[string]*Entry),
+		scopes: make(map[parser.Visitee]string),
+	}
+	for _, stmt := range proto.ProtoBody {
+		if pkg, ok := stmt.(*parser.Package); ok {
+			x.Package = pkg
+			x.scopes[proto] = pkg.Name
+			break
+		}
+	}
+
+	parser.Walk(proto, func(c *parser.Cursor) bool {
+		if c.Leaving() || c.Parent() == nil {
+			return true
+		}
+		return x.add(c.Node(), c.Parent())
+	})
+	return x
+}
+
+// add adds the node in the parent. It returns whether the nodes in the node can be indexed.
+func (x *Index) add(node, parent parser.Visitee) bool {
+	scope := x.scopes[parent]
+	switch n := node.(type) {
+	case *parser.Message:
+		name := join(scope, n.MessageName)
+		x.scopes[n] = name
+		x.entry(n, name, parent)
+	case *parser.GroupField:
+		name := join(scope, n.GroupName)
+		x.scopes[n] = name
+		x.entry(n, name, parent)
+	case *parser.Enum:
+		// the enum values are named in the scope which encloses the enum.
+		x.scopes[n] = scope
+		x.entry(n, join(scope, n.EnumName), parent)
+	case *parser.EnumField:
+		x.entry(n, join(scope, n.Ident), parent)
+	case *parser.Field:
+		x.entry(n, join(scope, n.FieldName), parent)
+	case *parser.MapField:
+		x.entry(n, join(scope, n.MapName), parent)
+	case *parser.Oneof:
+		// the fields in the oneof are named in the message.
+		x.scopes[n] = scope
+		x.entry(n, join(scope, n.OneofName), parent)
+	case *parser.OneofField:
+		x.entry(n, join(scope, n.FieldName), parent)
+	case *parser.Extend:
+		// the extensions are named in the scope which encloses the extend.
+		x.scopes[n] = scope
+	case *parser.Service:
+		name := join(scope, n.ServiceName)
+		x.scopes[n] = name
+		x.entry(n, name, parent)
+	case *parser.RPC:
+		x.entry(n, join(scope, n.RPCName), parent)
+	default:
+		return false
+	}
+	return true
+}
+
+func (x *Index) entry(node parser.Visitee, fullName string, parent parser.Visitee) {
+	e := &Entry{Node: node, FullName: fullName, Parent: parent}
+	x.Entries = append(x.Entries, e)
+	x.byNode[node] = e
+	if _, ok := x.byName[fullName]; !ok {
+		x.byName[fullName] = e
+	}
+}
+
+// Lookup returns the node whose fully-qualified name is the name, or nil.
+// A leading dot of the name is ignored. When some nodes have the same name, the first one in the source is returned.
+func (x *Index) Lookup(name string) parser.Visitee {
+	if e, ok := x.byName[strings.TrimPrefix(name, ".")]; ok {
+		return e.Node
+	}
+	return nil
+}
+
+// Entry returns the entry of the node, or nil if the node is not indexed.
+func (x *Index) Entry(node parser.Visitee) *Entry {
+	return x.byNode[node]
+}
+
+// Parent returns the parent of the node, or nil if the node is not indexed.
+func (x *Index) Parent(node parser.Visitee) parser.Visitee {
+	if e, ok := x.byNode[node]; ok {
+		return e.Parent
+	}
+	return nil
+}
+
+// FullName returns the fully-qualified name of the node, or "" if the node is not indexed.
+func (x *Index) FullName(node parser.Visitee) string {
+	if e, ok := x.byNode[node]; ok {
+		return e.FullName
+	}
+	return ""
+}
+
+// Message returns the message which has the node, like the one of a field in a oneof, or nil.
+// A group is returned as the message which has the fields in it. It's nil for an extension.
+func (x *Index) Message(node parser.Visitee) parser.Visitee {
+	for p := x.Parent(node); p != nil; p = x.Parent(p) {
+		switch p.(type) {
+		case *parser.Message, *parser.GroupField:
+			return p
+		case *parser.Extend:
+			return nil
+		}
+	}
+	return nil
+}
+
+func join(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
@@ -0,0 +1,161 @@
+package index_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/index"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+const input = `syntax = "proto2";
+package pkg;
+
+message Outer {
+  message Inner {
+    optional string name = 1;
+  }
+  oneof choice {
+    int32 a = 2;
+  }
+  map<string, Inner> inners = 3;
+  optional group Result = 4 {
+    optional string url = 1;
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+  }
+  extend Outer {
+    optional int32 nested_ext = 100;
+  }
+  extensions 100 to 200;
+}
+
+extend Outer {
+  optional int32 ext = 101;
+}
+
+service S {
+  rpc Get(Outer) returns (Outer);
+}
+`
+
+func parse(t *testing.T, input string) *parser.Proto {
+	t.Helper()
+	proto, err := protoparser.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return proto
+}
+
+func TestNew(t *testing.T) {
+	x := index.New(parse(t, input))
+
+	if x.Package == nil || x.Package.Name != "pkg" {
+		t.Errorf("got %v, but want the package pkg", x.Package)
+	}
+
+	var got []string
+	for _, e := range x.Entries {
+		got = append(got, e.FullName+" in "+x.FullName(e.Parent))
+	}
+	want := []string{
+		"pkg.Outer in ",
+		"pkg.Outer.Inner in pkg.Outer",
+		"pkg.Outer.Inner.name in pkg.Outer.Inner",
+		"pkg.Outer.choice in pkg.Outer",
+		"pkg.Outer.a in pkg.Outer.choice",
+		"pkg.Outer.inners in pkg.Outer",
+		"pkg.Outer.Result in pkg.Outer",
+		"pkg.Outer.Result.url in pkg.Outer.Result",
+		"pkg.Outer.Kind in pkg.Outer",
+		"pkg.Outer.KIND_UNSPECIFIED in pkg.Outer.Kind",
+		"pkg.Outer.nested_ext in ",
+		"pkg.ext in ",
+		"pkg.S in ",
+		"pkg.S.Get in pkg.S",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+}
+
+func TestIndex_Lookup(t *testing.T) {
+	proto := parse(t, input)
+	x := index.New(proto)
+	outer := proto.ProtoBody[1].(*parser.Message)
+	inner := outer.MessageBody[0].(*parser.Message)
+	oneof := outer.MessageBody[1].(*parser.Oneof)
+	nestedExtend := outer.MessageBody[5].(*parser.Extend)
+	extend := proto.ProtoBody[2].(*parser.Extend)
+
+	tests := []struct {
+		name        string
+		want        parser.Visitee
+		wantParent  parser.Visitee
+		wantMessage parser.Visitee
+	}{
+		{
+			name:       "pkg.Outer",
+			want:       outer,
+			wantParent: proto,
+		},
+		{
+			name:        ".pkg.Outer.Inner",
+			want:        inner,
+			wantParent:  outer,
+			wantMessage: outer,
+		},
+		{
+			name:        "pkg.Outer.Inner.name",
+			want:        inner.MessageBody[0],
+			wantParent:  inner,
+			wantMessage: inner,
+		},
+		{
+			name:        "pkg.Outer.a",
+			want:        oneof.OneofFields[0],
+			wantParent:  oneof,
+			wantMessage: outer,
+		},
+		{
+			name:       "pkg.Outer.nested_ext",
+			want:       nestedExtend.ExtendBody[0],
+			wantParent: nestedExtend,
+		},
+		{
+			name:       "pkg.ext",
+			want:       extend.ExtendBody[0],
+			wantParent: extend,
+		},
+		{
+			name: "pkg.Unknown",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := x.Lookup(test.name)
+			if got != test.want {
+				t.Errorf("got %v, but want %v", got, test.want)
+			}
+			if got == nil {
+				return
+			}
+			if parent := x.Parent(got); parent != test.wantParent {
+				t.Errorf("got %v, but want %v", parent, test.wantParent)
+			}
+			if message := x.Message(got); message != test.wantMessage {
+				t.Errorf("got %v, but want %v", message, test.wantMessage)
+			}
+		})
+	}
+
+	if x.Entry(extend) != nil || x.FullName(extend) != "" || x.Parent(extend) != nil {
+		t.Errorf("got the entry of the extend, but want nil")
+	}
+}
@@ -0,0 +1,98 @@
+// Package strlit decodes the string literals of the protocol buffers.
+package strlit
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Unquote decodes the string literal, which is quoted with either the single or the double quotes.
+//
+//	strLit = ( "'" { charValue } "'" ) | ( '"' { charValue } '"' )
+//	charValue = hexEscape | octEscape | charEscape | unicodeEscape | /[^\0\n\\]/
+func Unquote(lit string) ([]byte, error) {
+	if len(lit) < 2 || (lit[0] != '"' && lit[0] != '\'') || lit[len(lit)-1] != lit[0] {
+		return nil, fmt.Errorf("invalid string literal %s", lit)
+	}
+	s := lit[1 : len(lit)-1]
+
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b = append(b, c)
+			continue
+		}
+		i++
+		if len(s) <= i {
+			return nil, fmt.Errorf("invalid escape at the end of %s", lit)
+		}
+		switch c = s[i]; c {
+		case 'a':
+			b = append(b, '\a')
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'v':
+			b = append(b, '\v')
+		case '\\', '\'', '"', '?':
+			b = append(b, c)
+		case 'x', 'X':
+			n := digits(s[i+1:], 2, isHex)
+			if n == 0 {
+				return nil, fmt.Errorf("invalid hex escape in %s", lit)
+			}
+			v, _ := strconv.ParseUint(s[i+1:i+1+n], 16, 8)
+			b = append(b, byte(v))
+			i += n
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+			if digits(s[i+1:], size, isHex) != size {
+				return nil, fmt.Errorf("invalid unicode escape in %s", lit)
+			}
+			v, _ := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if !utf8.ValidRune(rune(v)) {
+				return nil, fmt.Errorf("invalid unicode escape in %s", lit)
+			}
+			b = append(b, string(rune(v))...)
+			i += size
+		default:
+			n := digits(s[i:], 3, isOct)
+			if n == 0 {
+				return nil, fmt.Errorf("invalid escape \\%c in %s", c, lit)
+			}
+			v, _ := strconv.ParseUint(s[i:i+n], 8, 16)
+			b = append(b, byte(v))
+			i += n - 1
+		}
+	}
+	return b, nil
+}
+
+// digits returns the number of the leading digits in s up to max.
+func digits(s string, max int, isDigit func(byte) bool) int {
+	n := 0
+	for n < max && n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	return n
+}
+
+func isHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+func isOct(c byte) bool {
+	return '0' <= c && c <= '7'
+}
@@ -20,7 +20,9 @@ type ProtoBody struct {
 
 // Proto represents a protocol buffer definition.
 type Proto struct {
-	Syntax    *parser.Syntax
+	Syntax *parser.Syntax
+	// Edition is set instead of Syntax when the file is editions-based.
+	Edition   *parser.Edition
 	ProtoBody *ProtoBody
 }
 
@@ -36,6 +38,7 @@ func InterpretProto(src *parser.Proto) (*Proto, error) {
 	}
 	return &Proto{
 		Syntax:    src.Syntax,
+		Edition:   src.Edition,
 		ProtoBody: enumBody,
 	}, nil
 }
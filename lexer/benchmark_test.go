@@ -0,0 +1,63 @@
+package lexer_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+)
+
+// readCorpus reads the files in _testdata.
+func readCorpus(b *testing.B) [][]byte {
+	b.Helper()
+	files, err := filepath.Glob(filepath.Join("..", "_testdata", "*.proto"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var corpus [][]byte
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			b.Fatal(err)
+		}
+		corpus = append(corpus, src)
+	}
+	return corpus
+}
+
+func benchmarkLexer(b *testing.B, scan func(lex *lexer.Lexer)) {
+	corpus := readCorpus(b)
+	var size int64
+	for _, src := range corpus {
+		size += int64(len(src))
+	}
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, src := range corpus {
+			lex := lexer.NewLexer(bytes.NewReader(src))
+			lex.Error = func(*lexer.Lexer, error) {}
+			for !lex.IsEOF() {
+				scan(lex)
+			}
+		}
+	}
+}
+
+func BenchmarkLexer_Next(b *testing.B) {
+	benchmarkLexer(b, func(lex *lexer.Lexer) {
+		lex.NextKeywordOrStrLit()
+	})
+}
+
+func BenchmarkLexer_PeekN(b *testing.B) {
+	benchmarkLexer(b, func(lex *lexer.Lexer) {
+		lex.Peek()
+		lex.PeekN(3)
+		lex.NextKeywordOrStrLit()
+	})
+}
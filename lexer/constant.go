@@ -6,9 +6,30 @@ import (
 	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
 )
 
+// ConstantLit is a constant read by ReadConstantLit.
+type ConstantLit struct {
+	// Text is the same value as the one returned by ReadConstant.
+	Text string
+	// Token is one of TINTLIT, TFLOATLIT, TBOOLLIT, TSTRLIT, and TIDENT which represents a fullIdent.
+	Token scanner.Token
+	// Pos is the position of the first character.
+	Pos scanner.Position
+	// LastPos is the position of the last character.
+	LastPos scanner.Position
+}
+
 // ReadConstant reads a constant. If permissive is true, accepts multiline string literals.
 // constant = fullIdent | ( [ "-" | "+" ] intLit ) | ( [ "-" | "+" ] floatLit ) | strLit | boolLit
 func (lex *Lexer) ReadConstant(permissive bool) (string, scanner.Position, error) {
+	lit, err := lex.ReadConstantLit(permissive)
+	if err != nil {
+		return "", scanner.Position{}, err
+	}
+	return lit.Text, lit.Pos, nil
+}
+
+// ReadConstantLit reads a constant like ReadConstant, along with its token type and last position.
+func (lex *Lexer) ReadConstantLit(permissive bool) (*ConstantLit, error) {
 	lex.NextLit()
 
 	startPos := lex.Pos
@@ -17,49 +38,72 @@ func (lex *Lexer) ReadConstant(permissive bool) (string, scanner.Position, error
 	switch {
 	case lex.Token == scanner.TSTRLIT:
 		if permissive {
-			return lex.mergeMultilineStrLit(), startPos, nil
+			merged, lastPos := lex.mergeMultilineStrLit()
+			return &ConstantLit{
+				Text:    merged,
+				Token:   scanner.TSTRLIT,
+				Pos:     startPos,
+				LastPos: lastPos,
+			}, nil
 		}
-		return cons, startPos, nil
+		return lex.newConstantLit(cons, startPos), nil
 	case lex.Token == scanner.TBOOLLIT:
-		return cons, startPos, nil
+		return lex.newConstantLit(cons, startPos), nil
 	case lex.Token == scanner.TIDENT:
 		lex.UnNext()
-		fullIdent, pos, err := lex.ReadFullIdent()
+		fullIdent, pos, lastPos, err := lex.readFullIdent()
 		if err != nil {
-			return "", scanner.Position{}, err
+			return nil, err
 		}
-		return fullIdent, pos, nil
+		return &ConstantLit{
+			Text:    fullIdent,
+			Token:   scanner.TIDENT,
+			Pos:     pos,
+			LastPos: lastPos,
+		}, nil
 	case lex.Token == scanner.TINTLIT, lex.Token == scanner.TFLOATLIT:
-		return cons, startPos, nil
+		return lex.newConstantLit(cons, startPos), nil
 	case lex.Text == "-" || lex.Text == "+":
 		lex.NextLit()
 
 		switch lex.Token {
 		case scanner.TINTLIT, scanner.TFLOATLIT:
 			cons += lex.Text
-			return cons, startPos, nil
+			return lex.newConstantLit(cons, startPos), nil
 		default:
-			return "", scanner.Position{}, lex.unexpected(lex.Text, "TINTLIT or TFLOATLIT")
+			return nil, lex.unexpected(lex.Text, "TINTLIT or TFLOATLIT")
 		}
 	default:
-		return "", scanner.Position{}, lex.unexpected(lex.Text, "constant")
+		return nil, lex.unexpected(lex.Text, "constant")
+	}
+}
+
+// newConstantLit creates a ConstantLit which ends with the current token.
+func (lex *Lexer) newConstantLit(text string, startPos scanner.Position) *ConstantLit {
+	return &ConstantLit{
+		Text:    text,
+		Token:   lex.Token,
+		Pos:     startPos,
+		LastPos: lex.Pos.AdvancedBulk(lex.Text),
 	}
 }
 
 // Merges a multiline string literal into a single string.
-func (lex *Lexer) mergeMultilineStrLit() string {
+func (lex *Lexer) mergeMultilineStrLit() (string, scanner.Position) {
 	q := "'"
 	if strings.HasPrefix(lex.Text, "\"") {
 		q = "\""
 	}
 	var b strings.Builder
+	var lastPos scanner.Position
 	b.WriteString(q)
 	for lex.Token == scanner.TSTRLIT {
-		strippedString := strings.Trim(lex.Text, q)
-		b.WriteString(strippedString)
+		// strips only the enclosing quotes, since the content may end with an escaped quote.
+		b.WriteString(lex.Text[1 : len(lex.Text)-1])
+		lastPos = lex.Pos.AdvancedBulk(lex.Text)
 		lex.NextLit()
 	}
 	lex.UnNext()
 	b.WriteString(q)
-	return b.String()
+	return b.String(), lastPos
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
 )
 
 func TestLexer2_ReadConstant(t *testing.T) {
@@ -135,3 +136,113 @@ func TestLexer2_ReadConstant(t *testing.T) {
 		})
 	}
 }
+
+func TestLexer2_ReadConstantLit(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantText       string
+		wantToken      scanner.Token
+		wantLastOffset int
+		wantLastLine   int
+		wantLastColumn int
+		wantErr        bool
+	}{
+		{
+			name:           "fullIdent",
+			input:          "foo . bar;",
+			wantText:       "foo.bar",
+			wantToken:      scanner.TIDENT,
+			wantLastOffset: 8,
+			wantLastLine:   1,
+			wantLastColumn: 9,
+		},
+		{
+			name:           "-intLit",
+			input:          "- 1928",
+			wantText:       "-1928",
+			wantToken:      scanner.TINTLIT,
+			wantLastOffset: 5,
+			wantLastLine:   1,
+			wantLastColumn: 6,
+		},
+		{
+			name:           "floatLit",
+			input:          "inf",
+			wantText:       "inf",
+			wantToken:      scanner.TFLOATLIT,
+			wantLastOffset: 2,
+			wantLastLine:   1,
+			wantLastColumn: 3,
+		},
+		{
+			name:           "boolLit",
+			input:          "true",
+			wantText:       "true",
+			wantToken:      scanner.TBOOLLIT,
+			wantLastOffset: 3,
+			wantLastLine:   1,
+			wantLastColumn: 4,
+		},
+		{
+			name:           "multiline strLit",
+			input:          "\"line1 \"\n\"line2\" ;",
+			wantText:       `"line1 line2"`,
+			wantToken:      scanner.TSTRLIT,
+			wantLastOffset: 15,
+			wantLastLine:   2,
+			wantLastColumn: 7,
+		},
+		{
+			name:           "strLit ending with an escaped quote",
+			input:          `"a\"" ;`,
+			wantText:       `"a\""`,
+			wantToken:      scanner.TSTRLIT,
+			wantLastOffset: 4,
+			wantLastLine:   1,
+			wantLastColumn: 5,
+		},
+		{
+			name:    "ident.",
+			input:   "rpc.",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			lex := lexer.NewLexer(strings.NewReader(test.input))
+			got, err := lex.ReadConstantLit(true)
+
+			switch {
+			case test.wantErr:
+				if err == nil {
+					t.Errorf("got err nil, but want err")
+				}
+				return
+			case !test.wantErr && err != nil:
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+
+			if got.Text != test.wantText {
+				t.Errorf("got %s, but want %s", got.Text, test.wantText)
+			}
+			if got.Token != test.wantToken {
+				t.Errorf("got %v, but want %v", got.Token, test.wantToken)
+			}
+			if got.Pos.Offset != 0 {
+				t.Errorf("got %d, but want 0", got.Pos.Offset)
+			}
+			if got.LastPos.Offset != test.wantLastOffset {
+				t.Errorf("got %d, but want %d", got.LastPos.Offset, test.wantLastOffset)
+			}
+			if got.LastPos.Line != test.wantLastLine {
+				t.Errorf("got %d, but want %d", got.LastPos.Line, test.wantLastLine)
+			}
+			if got.LastPos.Column != test.wantLastColumn {
+				t.Errorf("got %d, but want %d", got.LastPos.Column, test.wantLastColumn)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ func (lex *Lexer) ReadEmptyStatement() error {
 	if lex.Token == scanner.TSEMICOLON {
 		return nil
 	}
+	err := lex.unexpected(lex.Text, ";")
 	lex.UnNext()
-	return lex.unexpected(lex.Text, ";")
+	return err
 }
@@ -5,11 +5,18 @@ import "github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
 // ReadFullIdent reads a fullIdent.
 // fullIdent = ident { "." ident }
 func (lex *Lexer) ReadFullIdent() (string, scanner.Position, error) {
+	fullIdent, startPos, _, err := lex.readFullIdent()
+	return fullIdent, startPos, err
+}
+
+// readFullIdent reads a fullIdent and returns it with the positions of the first and last characters.
+func (lex *Lexer) readFullIdent() (string, scanner.Position, scanner.Position, error) {
 	lex.Next()
 	if lex.Token != scanner.TIDENT {
-		return "", scanner.Position{}, lex.unexpected(lex.Text, "TIDENT")
+		return "", scanner.Position{}, scanner.Position{}, lex.unexpected(lex.Text, "TIDENT")
 	}
 	startPos := lex.Pos
+	lastPos := lex.Pos.AdvancedBulk(lex.Text)
 
 	fullIdent := lex.Text
 	lex.Next()
@@ -22,10 +29,11 @@ func (lex *Lexer) ReadFullIdent() (string, scanner.Position, error) {
 
 		lex.Next()
 		if lex.Token != scanner.TIDENT {
-			return "", scanner.Position{}, lex.unexpected(lex.Text, "TIDENT")
+			return "", scanner.Position{}, scanner.Position{}, lex.unexpected(lex.Text, "TIDENT")
 		}
 		fullIdent += "." + lex.Text
+		lastPos = lex.Pos.AdvancedBulk(lex.Text)
 		lex.Next()
 	}
-	return fullIdent, startPos, nil
+	return fullIdent, startPos, lastPos, nil
 }
@@ -1,12 +1,15 @@
 package lexer
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"log"
 	"path/filepath"
 	"runtime"
 
 	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
 )
 
 // Lexer is a lexer.
@@ -31,6 +34,52 @@ type Lexer struct {
 	scannerOpts []scanner.Option
 	scanErr     error
 	debug       bool
+
+	// curlyDepth is the number of unclosed left curlies.
+	curlyDepth int
+
+	// tokens is a ring buffer of the tokens read lately, which UnNext puts back and Next reads again.
+	// It holds the tokens from first to last in the order of the input, and cur is the current one.
+	tokens []token
+	first  int
+	cur    int
+	last   int
+
+	recording bool
+	recorded  []*RecordedToken
+
+	ctx           context.Context
+	maxInputBytes int
+	maxTokens     int
+	// counter scans the input in its own mode to count each token once, whatever mode the token is read in.
+	counter    *scanner.Scanner
+	tokenCount int
+	abortErr   error
+}
+
+// token is a scanned token.
+type token struct {
+	// mode is the mode in which the token is scanned.
+	mode  scanner.Mode
+	token scanner.Token
+	text  string
+	raw   []rune
+	pos   scanner.Position
+	err   error
+	// start is the mark before the whitespaces and the comments skipped before the token.
+	start scanner.Mark
+}
+
+// minTokens is the initial size of the ring buffer of the tokens.
+const minTokens = 16
+
+// RecordedToken is a token read by the Lexer in the recording mode.
+type RecordedToken struct {
+	Token scanner.Token
+	Text  string
+	// Raw is the scanned raw text, which includes the whitespaces and comments skipped before the token.
+	Raw string
+	Pos scanner.Position
 }
 
 // Option is an option for lexer.NewLexer.
@@ -43,6 +92,14 @@ func WithDebug(debug bool) Option {
 	}
 }
 
+// WithRecording is an option to record all of the tokens read by the Lexer.
+// The tokens put back to the read buffer are removed from the records.
+func WithRecording(recording bool) Option {
+	return func(l *Lexer) {
+		l.recording = recording
+	}
+}
+
 // WithFilename is an option for scanner.Option.
 func WithFilename(filename string) Option {
 	return func(l *Lexer) {
@@ -50,6 +107,33 @@ func WithFilename(filename string) Option {
 	}
 }
 
+// WithContext is an option to stop reading the input when the ctx is done.
+// The ctx is checked each time the Lexer scans a token, and AbortErr returns its error.
+func WithContext(ctx context.Context) Option {
+	return func(l *Lexer) {
+		l.ctx = ctx
+	}
+}
+
+// WithMaxInputBytes is an option to limit the size of the input.
+// The Lexer reads at most one more byte than the max, and stops with a *meta.LimitError if the input is larger.
+// The max of 0 means no limit.
+func WithMaxInputBytes(max int) Option {
+	return func(l *Lexer) {
+		l.maxInputBytes = max
+	}
+}
+
+// WithMaxTokens is an option to limit the number of the tokens in the input.
+// The tokens are counted up to the one read last, in the same way whatever mode they are read in:
+// a string or a number literal is one token, and the comments are not counted.
+// The Lexer stops with a *meta.LimitError at the token exceeding the max. The max of 0 means no limit.
+func WithMaxTokens(max int) Option {
+	return func(l *Lexer) {
+		l.maxTokens = max
+	}
+}
+
 // NewLexer creates a new lexer.
 func NewLexer(input io.Reader, opts ...Option) *Lexer {
 	lex := new(Lexer)
@@ -60,7 +144,24 @@ func NewLexer(input io.Reader, opts ...Option) *Lexer {
 	lex.Error = func(_ *Lexer, err error) {
 		log.Printf(`Lexer encountered the error "%v"`, err)
 	}
+	if 0 < lex.maxInputBytes {
+		input = io.LimitReader(input, int64(lex.maxInputBytes)+1)
+	}
 	lex.scanner = scanner.NewScanner(input, lex.scannerOpts...)
+	if 0 < lex.maxTokens {
+		lex.counter = scanner.NewScanner(bytes.NewReader(lex.scanner.Source()), lex.scannerOpts...)
+		lex.counter.Mode = scanner.ScanLit
+	}
+	lex.tokens = make([]token, minTokens)
+	lex.cur = -1
+	lex.last = -1
+	if 0 < lex.maxInputBytes && lex.maxInputBytes < lex.scanner.Size() {
+		lex.Abort(&meta.LimitError{
+			Pos:   lex.scanner.Mark().Position().Position,
+			Limit: meta.LimitInputBytes,
+			Max:   lex.maxInputBytes,
+		})
+	}
 	return lex
 }
 
@@ -82,13 +183,144 @@ func (lex *Lexer) Next() {
 		}
 	}()
 
-	var err error
-	lex.Token, lex.Text, lex.Pos, err = lex.scanner.Scan()
-	lex.RawText = lex.scanner.LastScanRaw()
-	if err != nil {
-		lex.scanErr = err
-		lex.Error(lex, err)
+	tok := lex.nextToken()
+	lex.Token, lex.Text, lex.RawText, lex.Pos = tok.token, tok.text, tok.raw, tok.pos
+	switch lex.Token {
+	case scanner.TLEFTCURLY:
+		lex.curlyDepth++
+	case scanner.TRIGHTCURLY:
+		lex.curlyDepth--
+	}
+	if lex.recording {
+		lex.recorded = append(lex.recorded, &RecordedToken{
+			Token: lex.Token,
+			Text:  lex.Text,
+			Raw:   string(lex.RawText),
+			Pos:   lex.Pos,
+		})
+	}
+	if tok.err != nil {
+		lex.scanErr = tok.err
+		lex.Error(lex, tok.err)
+	}
+}
+
+// nextToken returns the token next to the current one.
+// The token put back by UnNext is returned again if it's scanned in the current mode.
+func (lex *Lexer) nextToken() *token {
+	mode := lex.scanner.Mode
+	if lex.cur < lex.last {
+		next := lex.token(lex.cur + 1)
+		if next.mode == mode {
+			lex.cur++
+			return next
+		}
+		// the tokens after it are scanned again in the mode.
+		lex.scanner.Rewind(next.start)
+		lex.last = lex.cur
+	}
+
+	if lex.last-lex.first+1 == len(lex.tokens) {
+		lex.first++
 	}
+	lex.cur++
+	lex.last = lex.cur
+	tok := lex.token(lex.cur)
+	start := lex.scanner.Mark()
+	if lex.abortErr == nil && lex.ctx != nil && lex.ctx.Err() != nil {
+		lex.Abort(lex.ctx.Err())
+	}
+	if lex.abortErr != nil {
+		*tok = token{mode: mode, token: scanner.TEOF, pos: start.Position(), start: start}
+		return tok
+	}
+
+	t, text, pos, err := lex.scanner.Scan()
+	if err != nil && t == scanner.TEOF {
+		// the input can't be read.
+		lex.Abort(err)
+	}
+	*tok = token{
+		mode:  mode,
+		token: t,
+		text:  text,
+		raw:   lex.scanner.LastScanRaw(),
+		pos:   pos,
+		err:   err,
+		start: start,
+	}
+	lex.countToken(tok)
+	return tok
+}
+
+// countToken counts the tokens which start before the end of the token and are not counted yet,
+// and aborts at the one exceeding maxTokens. The token is replaced with TEOF then.
+func (lex *Lexer) countToken(tok *token) {
+	if lex.counter == nil || tok.token == scanner.TEOF {
+		return
+	}
+	end := lex.scanner.Mark().Position().Offset
+	for {
+		mark := lex.counter.Mark()
+		t, _, pos, _ := lex.counter.Scan()
+		if t == scanner.TEOF || end <= pos.Offset {
+			lex.counter.Rewind(mark)
+			return
+		}
+		lex.tokenCount++
+		if lex.maxTokens < lex.tokenCount {
+			lex.Abort(&meta.LimitError{
+				Pos:   pos.Position,
+				Limit: meta.LimitTokens,
+				Max:   lex.maxTokens,
+			})
+			*tok = token{mode: tok.mode, token: scanner.TEOF, pos: tok.start.Position(), start: tok.start}
+			return
+		}
+	}
+}
+
+// Abort stops reading the input with the err. The Lexer reads TEOF after that, so that the parser stops.
+// The err of the first call is kept.
+func (lex *Lexer) Abort(err error) {
+	if lex.abortErr != nil {
+		return
+	}
+	lex.abortErr = err
+	// the tokens put back are dropped to stop as soon as possible.
+	lex.last = lex.cur
+}
+
+// AbortErr returns the error with which the Lexer is aborted,
+// like a *meta.LimitError, the error of the context or the one of reading the input.
+func (lex *Lexer) AbortErr() error {
+	return lex.abortErr
+}
+
+// Source returns the whole input, which must not be modified.
+func (lex *Lexer) Source() []byte {
+	return lex.scanner.Source()
+}
+
+// token returns the ith token of the input in the ring buffer.
+func (lex *Lexer) token(i int) *token {
+	return &lex.tokens[i&(len(lex.tokens)-1)]
+}
+
+// reserve grows the ring buffer to hold the n tokens after the current one as well as the ones before it.
+func (lex *Lexer) reserve(n int) {
+	size := len(lex.tokens)
+	for size < (lex.cur-lex.first+1)+n+1 {
+		size *= 2
+	}
+	if size == len(lex.tokens) {
+		return
+	}
+	tokens := make([]token, size)
+	for i := lex.first; i <= lex.last; i++ {
+		tokens[i&(size-1)] = *lex.token(i)
+	}
+	lex.tokens = tokens
 }
 
 // NextN scans the read buffer nth times.
@@ -144,6 +376,17 @@ func (lex *Lexer) IsEOF() bool {
 	return lex.Token == scanner.TEOF
 }
 
+// CurlyDepth returns the number of left curlies which are not closed yet at the current position.
+func (lex *Lexer) CurlyDepth() int {
+	return lex.curlyDepth
+}
+
+// RecordedTokens returns the tokens read so far in the recording mode.
+// Concatenating their Raw reproduces the input read so far.
+func (lex *Lexer) RecordedTokens() []*RecordedToken {
+	return lex.recorded
+}
+
 // LatestErr returns the latest non-EOF error that was encountered by the Lexer.Next().
 func (lex *Lexer) LatestErr() error {
 	return lex.scanErr
@@ -158,28 +401,45 @@ func (lex *Lexer) Peek() scanner.Token {
 
 // PeekN returns the nth next token with keeping the read buffer unchanged.
 func (lex *Lexer) PeekN(n int) scanner.Token {
-	var lasts [][]rune
-	for 0 < n {
+	lex.reserve(n)
+	for i := 0; i < n; i++ {
 		lex.Next()
-		lasts = append(lasts, lex.RawText)
-		n--
 	}
 	token := lex.Token
-	for i := len(lasts) - 1; 0 <= i; i-- {
-		lex.UnNextTo(lasts[i])
+	for i := 0; i < n; i++ {
+		lex.UnNext()
 	}
 	return token
 }
 
 // UnNext put the latest text back to the read buffer.
+// The tokens read lately are put back in the reverse order by calling UnNext repeatedly.
 func (lex *Lexer) UnNext() {
-	lex.Pos = lex.scanner.UnScan()
+	if lex.cur < lex.first {
+		lex.Token = scanner.TILLEGAL
+		return
+	}
+
+	tok := lex.token(lex.cur)
+	switch tok.token {
+	case scanner.TLEFTCURLY:
+		lex.curlyDepth--
+	case scanner.TRIGHTCURLY:
+		lex.curlyDepth++
+	}
+	if lex.recording && 0 < len(lex.recorded) {
+		lex.recorded = lex.recorded[:len(lex.recorded)-1]
+	}
+	lex.Pos = tok.start.Position()
 	lex.Token = scanner.TILLEGAL
+	lex.cur--
 }
 
 // UnNextTo put the given latest text back to the read buffer.
+// The Lexer remembers the texts of the tokens read lately, so the lastScan is ignored and it's the same as UnNext.
+//
+// Deprecated: Use UnNext instead.
 func (lex *Lexer) UnNextTo(lastScan []rune) {
-	lex.scanner.SetLastScanRaw(lastScan)
 	lex.UnNext()
 }
 
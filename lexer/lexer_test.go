@@ -0,0 +1,127 @@
+package lexer_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+)
+
+func TestLexer_UnNext(t *testing.T) {
+	lex := lexer.NewLexer(strings.NewReader("message A { int32 a = 1; }"))
+	lex.NextN(4)
+	if lex.CurlyDepth() != 1 {
+		t.Errorf("got %d, but want 1", lex.CurlyDepth())
+	}
+
+	lex.UnNext()
+	lex.UnNext()
+	lex.UnNext()
+	if lex.Pos.Offset != 7 {
+		t.Errorf("got %d, but want 7", lex.Pos.Offset)
+	}
+	if lex.CurlyDepth() != 0 {
+		t.Errorf("got %d, but want 0", lex.CurlyDepth())
+	}
+
+	lex.UnNext()
+	lex.NextKeyword()
+	if lex.Token != scanner.TMESSAGE {
+		t.Errorf("got %v, but want %v", lex.Token, scanner.TMESSAGE)
+	}
+
+	lex.Next()
+	if lex.Token != scanner.TIDENT || lex.Text != "A" || lex.Pos.Offset != 8 {
+		t.Errorf("got %v(%s) at %d, but want A at 8", lex.Token, lex.Text, lex.Pos.Offset)
+	}
+
+	lex.NextN(4)
+	lex.NextNumberLit()
+	if lex.Token != scanner.TINTLIT || lex.Text != "1" {
+		t.Errorf("got %v(%s), but want 1", lex.Token, lex.Text)
+	}
+}
+
+func TestLexer_readError(t *testing.T) {
+	readErr := errors.New("read error")
+	lex := lexer.NewLexer(iotest.ErrReader(readErr))
+	lex.Next()
+	if !lex.IsEOF() {
+		t.Errorf("got %v, but want TEOF", lex.Token)
+	}
+	if lex.AbortErr() != readErr {
+		t.Errorf("got %v, but want %v", lex.AbortErr(), readErr)
+	}
+}
+
+func TestLexer_PeekN(t *testing.T) {
+	var idents []string
+	for i := 0; i < 50; i++ {
+		idents = append(idents, fmt.Sprintf("a%d", i))
+	}
+	input := "{ " + strings.Join(idents, " ")
+
+	tests := []struct {
+		name      string
+		inputN    int
+		wantToken scanner.Token
+		wantText  string
+	}{
+		{
+			name:      "the next token",
+			inputN:    1,
+			wantToken: scanner.TIDENT,
+			wantText:  "a1",
+		},
+		{
+			name:      "the 2nd next token",
+			inputN:    2,
+			wantToken: scanner.TIDENT,
+			wantText:  "a2",
+		},
+		{
+			name:      "more tokens than the buffer holds at first",
+			inputN:    40,
+			wantToken: scanner.TIDENT,
+			wantText:  "a40",
+		},
+		{
+			name:      "beyond the end of the input",
+			inputN:    60,
+			wantToken: scanner.TEOF,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			lex := lexer.NewLexer(strings.NewReader(input))
+			lex.Next()
+			lex.Next()
+
+			got := lex.PeekN(test.inputN)
+			if got != test.wantToken {
+				t.Errorf("got %v, but want %v", got, test.wantToken)
+			}
+			if lex.Text != test.wantText {
+				t.Errorf("got %s, but want %s", lex.Text, test.wantText)
+			}
+
+			lex.Next()
+			if lex.Text != "a1" {
+				t.Errorf("got %s, but want a1", lex.Text)
+			}
+			lex.UnNext()
+			lex.UnNext()
+			lex.UnNext()
+			lex.Next()
+			if lex.Text != "{" || lex.CurlyDepth() != 1 {
+				t.Errorf("got %s with the depth %d, but want { with the depth 1", lex.Text, lex.CurlyDepth())
+			}
+		})
+	}
+}
@@ -6,6 +6,12 @@ import "github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
 // messageType = [ "." ] { ident "." } messageName
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#identifiers
 func (lex *Lexer) ReadMessageType() (string, scanner.Position, error) {
+	messageType, startPos, _, err := lex.ReadMessageTypeWithLastPos()
+	return messageType, startPos, err
+}
+
+// ReadMessageTypeWithLastPos reads a messageType like ReadMessageType, along with the position of the last character.
+func (lex *Lexer) ReadMessageTypeWithLastPos() (string, scanner.Position, scanner.Position, error) {
 	lex.Next()
 	startPos := lex.Pos
 
@@ -16,12 +22,14 @@ func (lex *Lexer) ReadMessageType() (string, scanner.Position, error) {
 		lex.UnNext()
 	}
 
+	var lastPos scanner.Position
 	lex.Next()
 	for !lex.IsEOF() {
 		if lex.Token != scanner.TIDENT {
-			return "", scanner.Position{}, lex.unexpected(lex.Text, "ident")
+			return "", scanner.Position{}, scanner.Position{}, lex.unexpected(lex.Text, "ident")
 		}
 		messageType += lex.Text
+		lastPos = lex.Pos.AdvancedBulk(lex.Text)
 
 		lex.Next()
 		if lex.Token != scanner.TDOT {
@@ -33,5 +41,5 @@ func (lex *Lexer) ReadMessageType() (string, scanner.Position, error) {
 		lex.Next()
 	}
 
-	return messageType, startPos, nil
+	return messageType, startPos, lastPos, nil
 }
@@ -2,37 +2,32 @@ package scanner
 
 // comment = ( "//" { [^\n] } "\n" ) |  ( "/*" { any } "*/" )
 func (s *Scanner) scanComment() (string, error) {
-	lit := string(s.read())
+	start := s.pos.Offset
+	s.read()
 
 	ch := s.read()
 	switch ch {
 	case '/':
-		for ch != '\n' {
-			lit += string(ch)
-
+		for {
 			if s.isEOF() {
-				return lit, nil
+				return s.text(start), nil
+			}
+			end := s.pos.Offset
+			if s.read() == '\n' {
+				return string(s.src[start:end]), nil
 			}
-			ch = s.read()
 		}
 	case '*':
 		for {
 			if s.isEOF() {
-				return lit, s.unexpected(eof, "\n")
+				return "", s.unexpected(eof, "\n")
 			}
-			lit += string(ch)
-
-			ch = s.read()
-			chn := s.peek()
-			if ch == '*' && chn == '/' {
-				lit += string(ch)
-				lit += string(s.read())
-				break
+			if s.read() == '*' && s.peek() == '/' {
+				s.read()
+				return s.text(start), nil
 			}
 		}
 	default:
 		return "", s.unexpected(ch, "/ or *")
 	}
-
-	return lit, nil
 }
@@ -7,166 +7,131 @@ package scanner
 //
 // floatLit = ( decimals "." [ decimals ] [ exponent ] | decimals exponent | "."decimals [ exponent ] ) | "inf" | "nan"
 func (s *Scanner) scanNumberLit() (Token, string, error) {
-	lit := string(s.read())
+	start := s.pos.Offset
+	first := s.read()
 	ch := s.peek()
 
 	switch {
-	case lit == "0" && (ch == 'x' || ch == 'X'):
+	case first == '0' && (ch == 'x' || ch == 'X'):
 		// hexLit
-		lit += string(s.read())
+		s.read()
 		if !isHexDigit(s.peek()) {
 			return TILLEGAL, "", s.unexpected(s.peek(), "hexDigit")
 		}
-		lit += string(s.read())
+		s.read()
 
-		for !s.isEOF() {
-			if !isHexDigit(s.peek()) {
-				break
-			}
-			lit += string(s.read())
+		for isHexDigit(s.peek()) {
+			s.read()
 		}
-		return TINTLIT, lit, nil
-	case lit == ".":
+		return TINTLIT, s.text(start), nil
+	case first == '.':
 		// floatLit
-		fractional, err := s.scanFractionPartNoOmit()
-		if err != nil {
+		if err := s.scanFractionPartNoOmit(); err != nil {
 			return TILLEGAL, "", err
 		}
-		return TFLOATLIT, lit + fractional, nil
+		return TFLOATLIT, s.text(start), nil
 	case ch == '.':
 		// floatLit
-		lit += string(s.read())
-		fractional, err := s.scanFractionPart()
-		if err != nil {
+		s.read()
+		if err := s.scanFractionPart(); err != nil {
 			return TILLEGAL, "", err
 		}
-		return TFLOATLIT, lit + fractional, nil
+		return TFLOATLIT, s.text(start), nil
 	case ch == 'e' || ch == 'E':
 		// floatLit
-		exp, err := s.scanExponent()
-		if err != nil {
+		if err := s.scanExponent(); err != nil {
 			return TILLEGAL, "", err
 		}
-		return TFLOATLIT, lit + exp, nil
-	case lit == "0":
+		return TFLOATLIT, s.text(start), nil
+	case first == '0':
 		// octalLit
-		for !s.isEOF() {
-			if !isOctalDigit(s.peek()) {
-				break
-			}
-			lit += string(s.read())
+		for isOctalDigit(s.peek()) {
+			s.read()
 		}
-		return TINTLIT, lit, nil
+		return TINTLIT, s.text(start), nil
 	default:
 		// decimalLit or floatLit
-		for !s.isEOF() {
-			if !isDecimalDigit(s.peek()) {
-				break
-			}
-			lit += string(s.read())
+		for isDecimalDigit(s.peek()) {
+			s.read()
 		}
 
 		switch s.peek() {
 		case '.':
 			// floatLit
-			lit += string(s.read())
-			fractional, err := s.scanFractionPart()
-			if err != nil {
+			s.read()
+			if err := s.scanFractionPart(); err != nil {
 				return TILLEGAL, "", err
 			}
-			return TFLOATLIT, lit + fractional, nil
+			return TFLOATLIT, s.text(start), nil
 		case 'e', 'E':
 			// floatLit
-			exp, err := s.scanExponent()
-			if err != nil {
+			if err := s.scanExponent(); err != nil {
 				return TILLEGAL, "", err
 			}
-			return TFLOATLIT, lit + exp, nil
+			return TFLOATLIT, s.text(start), nil
 		default:
 			// decimalLit
-			return TINTLIT, lit, nil
+			return TINTLIT, s.text(start), nil
 		}
 	}
 }
 
 // [ decimals ] [ exponent ]
-func (s *Scanner) scanFractionPart() (string, error) {
-	lit := ""
-
-	ch := s.peek()
-	switch {
-	case isDecimalDigit(ch):
-		decimals, err := s.scanDecimals()
-		if err != nil {
-			return "", err
+func (s *Scanner) scanFractionPart() error {
+	if isDecimalDigit(s.peek()) {
+		if err := s.scanDecimals(); err != nil {
+			return err
 		}
-		lit += decimals
 	}
 
 	switch s.peek() {
 	case 'e', 'E':
-		exp, err := s.scanExponent()
-		if err != nil {
-			return "", err
-		}
-		lit += exp
+		return s.scanExponent()
 	}
-	return lit, nil
+	return nil
 }
 
 // decimals [ exponent ]
-func (s *Scanner) scanFractionPartNoOmit() (string, error) {
-	decimals, err := s.scanDecimals()
-	if err != nil {
-		return "", err
+func (s *Scanner) scanFractionPartNoOmit() error {
+	if err := s.scanDecimals(); err != nil {
+		return err
 	}
 
 	switch s.peek() {
 	case 'e', 'E':
-		exp, err := s.scanExponent()
-		if err != nil {
-			return "", err
-		}
-		return decimals + exp, nil
+		return s.scanExponent()
 	default:
-		return decimals, nil
+		return nil
 	}
 }
 
 // exponent  = ( "e" | "E" ) [ "+" | "-" ] decimals
-func (s *Scanner) scanExponent() (string, error) {
+func (s *Scanner) scanExponent() error {
 	ch := s.peek()
 	switch ch {
 	case 'e', 'E':
-		lit := string(s.read())
+		s.read()
 
 		switch s.peek() {
 		case '+', '-':
-			lit += string(s.read())
+			s.read()
 		}
-		decimals, err := s.scanDecimals()
-		if err != nil {
-			return "", err
-		}
-		return lit + decimals, nil
+		return s.scanDecimals()
 	default:
-		return "", s.unexpected(ch, "e or E")
+		return s.unexpected(ch, "e or E")
 	}
 }
 
 // decimals  = decimalDigit { decimalDigit }
-func (s *Scanner) scanDecimals() (string, error) {
+func (s *Scanner) scanDecimals() error {
 	ch := s.peek()
 	if !isDecimalDigit(ch) {
-		return "", s.unexpected(ch, "decimalDigit")
+		return s.unexpected(ch, "decimalDigit")
 	}
-	lit := string(s.read())
+	s.read()
 
-	for !s.isEOF() {
-		if !isDecimalDigit(s.peek()) {
-			break
-		}
-		lit += string(s.read())
+	for isDecimalDigit(s.peek()) {
+		s.read()
 	}
-	return lit, nil
+	return nil
 }
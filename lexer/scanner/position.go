@@ -11,6 +11,7 @@ type Position struct {
 	meta.Position
 
 	// columns is a map which the key is a line number and the value is a column number.
+	// It's made by Advance when it's nil.
 	columns map[int]int
 }
 
@@ -37,6 +38,9 @@ func (pos *Position) Advance(r rune) {
 	pos.Offset += length
 
 	if r == '\n' {
+		if pos.columns == nil {
+			pos.columns = make(map[int]int)
+		}
 		pos.columns[pos.Line] = pos.Column
 
 		pos.Line++
@@ -1,26 +1,47 @@
 package scanner
 
 import (
-	"bufio"
+	"bytes"
 	"io"
+	"io/ioutil"
 	"unicode"
+	"unicode/utf8"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
 )
 
 var eof = rune(0)
 
 // Scanner represents a lexical scanner.
+//
+// The Scanner reads the whole input at first, and scans it by the offset,
+// so that it backtracks to any point of the input without copying the text.
 type Scanner struct {
-	r              *bufio.Reader
-	lastReadBuffer []rune
-	lastScanRaw    []rune
+	src []byte
 
-	// pos is a current source position.
-	pos *Position
+	// pos is a current source position, whose Offset is the one of the next character.
+	pos Position
+	// lastScan is the mark at which the last Scan started, before the skipped whitespaces and comments.
+	lastScan Mark
+	// lastScanEnd is the offset at which the last Scan ended.
+	lastScanEnd int
+	// err is the error of reading the input, which the first Scan returns.
+	err error
 
 	// The Mode field controls which tokens are recognized.
 	Mode Mode
 }
 
+// Mark is a point of the input, to which Rewind backtracks.
+type Mark struct {
+	pos meta.Position
+}
+
+// Position returns the source position of the mark.
+func (m Mark) Position() Position {
+	return Position{Position: m.pos}
+}
+
 // Option is an option for scanner.NewScanner.
 type Option func(*Scanner)
 
@@ -32,129 +53,187 @@ func WithFilename(filename string) Option {
 }
 
 // NewScanner returns a new instance of Scanner.
+// The first Scan returns the error of reading the input, if any.
 func NewScanner(r io.Reader, opts ...Option) *Scanner {
+	src, err := ioutil.ReadAll(r)
 	s := &Scanner{
-		r:   bufio.NewReader(r),
-		pos: NewPosition(),
+		src: src,
+		err: err,
+		pos: Position{
+			Position: meta.Position{
+				Offset: 0,
+				Line:   1,
+				Column: 1,
+			},
+		},
 	}
 	for _, opt := range opts {
 		opt(s)
 	}
+	s.lastScan = s.Mark()
 	return s
 }
 
-func (s *Scanner) read() (r rune) {
-	defer func() {
-		if r == eof {
-			return
-		}
-		s.lastScanRaw = append(s.lastScanRaw, r)
-
-		s.pos.Advance(r)
-	}()
+// Size returns the size of the input in bytes.
+func (s *Scanner) Size() int {
+	return len(s.src)
+}
 
-	if 0 < len(s.lastReadBuffer) {
-		var ch rune
-		ch, s.lastReadBuffer = s.lastReadBuffer[len(s.lastReadBuffer)-1], s.lastReadBuffer[:len(s.lastReadBuffer)-1]
-		return ch
+// decode returns the next character and its size without reading it.
+func (s *Scanner) decode() (rune, int) {
+	if len(s.src) <= s.pos.Offset {
+		return eof, 0
 	}
-	ch, _, err := s.r.ReadRune()
-	if err != nil {
-		return eof
+	if c := s.src[s.pos.Offset]; c < utf8.RuneSelf {
+		return rune(c), 1
 	}
-	return ch
+	return utf8.DecodeRune(s.src[s.pos.Offset:])
 }
 
-func (s *Scanner) unread(ch rune) {
-	s.lastReadBuffer = append(s.lastReadBuffer, ch)
+func (s *Scanner) read() rune {
+	ch, size := s.decode()
+	if ch == eof {
+		return eof
+	}
 
-	s.pos.Revert(ch)
+	s.pos.Offset += size
+	if ch == '\n' {
+		s.pos.Line++
+		s.pos.Column = 1
+	} else {
+		s.pos.Column++
+	}
+	return ch
 }
 
 func (s *Scanner) peek() rune {
-	ch := s.read()
-	if ch != eof {
-		s.lastScanRaw = s.lastScanRaw[0 : len(s.lastScanRaw)-1]
-		s.unread(ch)
-	}
+	ch, _ := s.decode()
 	return ch
 }
 
+// text returns the text read from the offset.
+func (s *Scanner) text(start int) string {
+	return string(s.src[start:s.pos.Offset])
+}
+
+// Source returns the whole input, which must not be modified.
+func (s *Scanner) Source() []byte {
+	return s.src
+}
+
+// Mark returns the current point of the input.
+func (s *Scanner) Mark() Mark {
+	return Mark{pos: s.pos.Position}
+}
+
+// Rewind backtracks to the mark. The mark must be the one which the Scanner returned.
+func (s *Scanner) Rewind(m Mark) {
+	s.pos.Position = m.pos
+}
+
 // UnScan put the last scanned text back to the read buffer.
 func (s *Scanner) UnScan() Position {
-	var reversedRunes []rune
-	for _, ch := range s.lastScanRaw {
-		reversedRunes = append([]rune{ch}, reversedRunes...)
-	}
-	for _, ch := range reversedRunes {
-		s.unread(ch)
-	}
-	return *s.pos
+	s.Rewind(s.lastScan)
+	return s.pos
 }
 
 // Scan returns the next token and text value.
+// It returns TEOF and the error of reading the input at first, if any, and the next call scans the input read so far.
 func (s *Scanner) Scan() (Token, string, Position, error) {
-	s.lastScanRaw = s.lastScanRaw[:0]
-	return s.scan()
+	s.lastScan = s.Mark()
+	if err := s.err; err != nil {
+		s.err = nil
+		s.lastScanEnd = s.pos.Offset
+		return TEOF, "", s.pos, err
+	}
+	tok, text, pos, err := s.scan()
+	s.lastScanEnd = s.pos.Offset
+	return tok, text, pos, err
 }
 
 // LastScanRaw returns the deep-copied lastScanRaw.
 func (s *Scanner) LastScanRaw() []rune {
-	r := make([]rune, len(s.lastScanRaw))
-	copy(r, s.lastScanRaw)
+	raw := s.src[s.lastScan.pos.Offset:s.lastScanEnd]
+	r := make([]rune, 0, utf8.RuneCount(raw))
+	for 0 < len(raw) {
+		ch, size := utf8.DecodeRune(raw)
+		r = append(r, ch)
+		raw = raw[size:]
+	}
 	return r
 }
 
-// SetLastScanRaw sets lastScanRaw to the given raw.
+// SetLastScanRaw sets lastScanRaw to the given raw, which ends at the current position.
 func (s *Scanner) SetLastScanRaw(raw []rune) {
-	s.lastScanRaw = raw
+	s.lastScan = s.markAt(s.pos.Offset - len(string(raw)))
+	s.lastScanEnd = s.pos.Offset
+}
+
+// markAt returns the mark at the offset before the current position.
+func (s *Scanner) markAt(offset int) Mark {
+	if offset < 0 {
+		offset = 0
+	}
+	pos := s.pos.Position
+	pos.Offset = offset
+	pos.Line -= bytes.Count(s.src[offset:s.pos.Offset], []byte{'\n'})
+	lineStart := bytes.LastIndexByte(s.src[:offset], '\n') + 1
+	pos.Column = utf8.RuneCount(s.src[lineStart:offset]) + 1
+	return Mark{pos: pos}
 }
 
 func (s *Scanner) scan() (Token, string, Position, error) {
-	ch := s.peek()
-
-	startPos := *s.pos
-
-	switch {
-	case unicode.IsSpace(ch):
-		s.read()
-		return s.scan()
-	case s.isEOF():
-		return TEOF, "", startPos, nil
-	case isLetter(ch), ch == '_':
-		ident := s.scanIdent()
-		if s.Mode&ScanBoolLit != 0 && isBoolLit(ident) {
-			return TBOOLLIT, ident, startPos, nil
-		}
-		if s.Mode&ScanNumberLit != 0 && isFloatLitKeyword(ident) {
-			return TFLOATLIT, ident, startPos, nil
-		}
-		if s.Mode&ScanKeyword != 0 && asKeywordToken(ident) != TILLEGAL {
-			return asKeywordToken(ident), ident, startPos, nil
-		}
-		return TIDENT, ident, startPos, nil
-	case ch == '/':
-		lit, err := s.scanComment()
-		if err != nil {
-			return TILLEGAL, "", startPos, err
-		}
-		if s.Mode&ScanComment != 0 {
-			return TCOMMENT, lit, startPos, nil
-		}
-		return s.scan()
-	case isQuote(ch) && s.Mode&ScanStrLit != 0:
-		lit, err := s.scanStrLit()
-		if err != nil {
-			return TILLEGAL, "", startPos, err
-		}
-		return TSTRLIT, lit, startPos, nil
-	case (isDecimalDigit(ch) || ch == '.') && s.Mode&ScanNumberLit != 0:
-		tok, lit, err := s.scanNumberLit()
-		if err != nil {
-			return TILLEGAL, "", startPos, err
+	for {
+		ch := s.peek()
+
+		startPos := s.pos
+		start := s.pos.Offset
+
+		switch {
+		case unicode.IsSpace(ch):
+			s.read()
+			continue
+		case ch == eof:
+			return TEOF, "", startPos, nil
+		case isLetter(ch), ch == '_':
+			s.scanIdent()
+			ident := s.text(start)
+			if s.Mode&ScanBoolLit != 0 && isBoolLit(ident) {
+				return TBOOLLIT, ident, startPos, nil
+			}
+			if s.Mode&ScanNumberLit != 0 && isFloatLitKeyword(ident) {
+				return TFLOATLIT, ident, startPos, nil
+			}
+			if s.Mode&ScanKeyword != 0 {
+				if t := asKeywordToken(ident); t != TILLEGAL {
+					return t, ident, startPos, nil
+				}
+			}
+			return TIDENT, ident, startPos, nil
+		case ch == '/':
+			lit, err := s.scanComment()
+			if err != nil {
+				return TILLEGAL, "", startPos, err
+			}
+			if s.Mode&ScanComment != 0 {
+				return TCOMMENT, lit, startPos, nil
+			}
+			continue
+		case isQuote(ch) && s.Mode&ScanStrLit != 0:
+			lit, err := s.scanStrLit()
+			if err != nil {
+				return TILLEGAL, "", startPos, err
+			}
+			return TSTRLIT, lit, startPos, nil
+		case (isDecimalDigit(ch) || ch == '.') && s.Mode&ScanNumberLit != 0:
+			tok, lit, err := s.scanNumberLit()
+			if err != nil {
+				return TILLEGAL, "", startPos, err
+			}
+			return tok, lit, startPos, nil
+		default:
+			s.read()
+			return asMiscToken(ch), s.text(start), startPos, nil
 		}
-		return tok, lit, startPos, nil
-	default:
-		return asMiscToken(ch), string(s.read()), startPos, nil
 	}
 }
@@ -1,8 +1,11 @@
 package scanner_test
 
 import (
+	"errors"
+	"io"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
 	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
@@ -762,3 +765,48 @@ func TestScanner_UnScan(t *testing.T) {
 		})
 	}
 }
+
+func TestScanner_Rewind(t *testing.T) {
+	s := scanner.NewScanner(strings.NewReader("service\n  s1928 // c\n s_a"))
+	s.Scan()
+	mark := s.Mark()
+	_, _, pos, _ := s.Scan()
+	s.Scan()
+
+	s.Rewind(mark)
+	if got := mark.Position(); got.Offset != 7 || got.Line != 1 || got.Column != 8 {
+		t.Errorf("got %v, but want the offset 7 at 1:8", got)
+	}
+	token, text, pos2, err := s.Scan()
+	if err != nil {
+		t.Errorf("got err %v, but want nil", err)
+	}
+	if token != scanner.TIDENT || text != "s1928" {
+		t.Errorf("got %v(%s), but want s1928", token, text)
+	}
+	if pos.Position != pos2.Position {
+		t.Errorf("got %v, but want %v", pos2, pos)
+	}
+
+	s.Scan()
+	s.SetLastScanRaw([]rune(" // c\n s_a"))
+	got := s.UnScan()
+	if got.Offset != 15 || got.Line != 2 || got.Column != 8 {
+		t.Errorf("got %v, but want the offset 15 at 2:8", got)
+	}
+}
+
+func TestScanner_Scan_readError(t *testing.T) {
+	readErr := errors.New("read error")
+	s := scanner.NewScanner(io.MultiReader(strings.NewReader("message"), iotest.ErrReader(readErr)))
+
+	token, _, _, err := s.Scan()
+	if token != scanner.TEOF || err != readErr {
+		t.Errorf("got %v and err %v, but want TEOF and %v", token, err, readErr)
+	}
+	// the next call scans the input read so far.
+	token, text, _, err := s.Scan()
+	if token != scanner.TIDENT || text != "message" || err != nil {
+		t.Errorf("got %v(%s) and err %v, but want message and nil", token, text, err)
+	}
+}
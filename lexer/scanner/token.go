@@ -60,59 +60,73 @@ const (
 	TENUM
 	TSTREAM
 	TGROUP
+	TEDITION
 )
 
 func asMiscToken(ch rune) Token {
-	m := map[rune]Token{
-		';':  TSEMICOLON,
-		':':  TCOLON,
-		'=':  TEQUALS,
-		'"':  TQUOTE,
-		'\'': TQUOTE,
-		'(':  TLEFTPAREN,
-		')':  TRIGHTPAREN,
-		'{':  TLEFTCURLY,
-		'}':  TRIGHTCURLY,
-		'[':  TLEFTSQUARE,
-		']':  TRIGHTSQUARE,
-		'<':  TLESS,
-		'>':  TGREATER,
-		',':  TCOMMA,
-		'.':  TDOT,
-		'-':  TMINUS,
+	switch ch {
+	case ';':
+		return TSEMICOLON
+	case ':':
+		return TCOLON
+	case '=':
+		return TEQUALS
+	case '"', '\'':
+		return TQUOTE
+	case '(':
+		return TLEFTPAREN
+	case ')':
+		return TRIGHTPAREN
+	case '{':
+		return TLEFTCURLY
+	case '}':
+		return TRIGHTCURLY
+	case '[':
+		return TLEFTSQUARE
+	case ']':
+		return TRIGHTSQUARE
+	case '<':
+		return TLESS
+	case '>':
+		return TGREATER
+	case ',':
+		return TCOMMA
+	case '.':
+		return TDOT
+	case '-':
+		return TMINUS
+	default:
+		return TILLEGAL
 	}
-	if t, ok := m[ch]; ok {
-		return t
-	}
-	return TILLEGAL
 }
 
-func asKeywordToken(st string) Token {
-	m := map[string]Token{
-		"syntax":     TSYNTAX,
-		"service":    TSERVICE,
-		"rpc":        TRPC,
-		"returns":    TRETURNS,
-		"message":    TMESSAGE,
-		"extend":     TEXTEND,
-		"import":     TIMPORT,
-		"package":    TPACKAGE,
-		"option":     TOPTION,
-		"repeated":   TREPEATED,
-		"required":   TREQUIRED,
-		"optional":   TOPTIONAL,
-		"weak":       TWEAK,
-		"public":     TPUBLIC,
-		"oneof":      TONEOF,
-		"map":        TMAP,
-		"reserved":   TRESERVED,
-		"extensions": TEXTENSIONS,
-		"enum":       TENUM,
-		"stream":     TSTREAM,
-		"group":      TGROUP,
-	}
+var keywordTokens = map[string]Token{
+	"syntax":     TSYNTAX,
+	"edition":    TEDITION,
+	"service":    TSERVICE,
+	"rpc":        TRPC,
+	"returns":    TRETURNS,
+	"message":    TMESSAGE,
+	"extend":     TEXTEND,
+	"import":     TIMPORT,
+	"package":    TPACKAGE,
+	"option":     TOPTION,
+	"repeated":   TREPEATED,
+	"required":   TREQUIRED,
+	"optional":   TOPTIONAL,
+	"weak":       TWEAK,
+	"public":     TPUBLIC,
+	"oneof":      TONEOF,
+	"map":        TMAP,
+	"reserved":   TRESERVED,
+	"extensions": TEXTENSIONS,
+	"enum":       TENUM,
+	"stream":     TSTREAM,
+	"group":      TGROUP,
+}
 
-	if t, ok := m[st]; ok {
+func asKeywordToken(st string) Token {
+	if t, ok := keywordTokens[st]; ok {
 		return t
 	}
 	return TILLEGAL
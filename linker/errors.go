@@ -0,0 +1,80 @@
+package linker
+
+import (
+	"fmt"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// ErrorKind is a kind of the Error.
+type ErrorKind int
+
+// ErrorKind values.
+const (
+	// ErrorKindUnresolved means the name is not defined.
+	ErrorKindUnresolved ErrorKind = iota
+	// ErrorKindAmbiguous means the first part of the name is found in an inner scope which doesn't have the rest,
+	// while the name is defined in an outer scope.
+	ErrorKindAmbiguous
+	// ErrorKindNotType means the name refers to a package or a service.
+	ErrorKindNotType
+	// ErrorKindNotMessage means the name refers to an enum where a message is required.
+	ErrorKindNotMessage
+	// ErrorKindNotImported means the name is defined in a file which is not imported.
+	ErrorKindNotImported
+	// ErrorKindDuplicate means the name is already defined.
+	ErrorKindDuplicate
+)
+
+// Error is the error about a name.
+type Error struct {
+	// Pos is the position of the reference, or of the definition for ErrorKindDuplicate.
+	Pos  meta.Position
+	Kind ErrorKind
+	// Name is the name as it is written, or the fully-qualified name for ErrorKindDuplicate.
+	Name string
+	// Scope is the fully-qualified name of the scope in which the name is written.
+	Scope string
+	// Resolved is the fully-qualified name which the name is resolved to in the innermost scope.
+	// It is set when the name is not defined in the scope which has its first part.
+	Resolved string
+	// Candidates holds the related definitions.
+	// For ErrorKindAmbiguous, it is the definition in the outer scope. For ErrorKindDuplicate, it is the former definition.
+	// For the other kinds except ErrorKindUnresolved, it is the found definition.
+	Candidates []*Symbol
+}
+
+func (e *Error) Error() string {
+	switch e.Kind {
+	case ErrorKindAmbiguous:
+		return fmt.Sprintf(
+			"%s: %q is resolved to %q, which is not defined. Consider using %q to refer to %q",
+			e.Pos, e.Name, e.Resolved, "."+e.Candidates[0].FullName, e.Candidates[0].FullName,
+		)
+	case ErrorKindNotType:
+		return fmt.Sprintf("%s: %q is not a type but a %s", e.Pos, e.Name, e.Candidates[0].Kind)
+	case ErrorKindNotMessage:
+		return fmt.Sprintf("%s: %q is not a message type", e.Pos, e.Name)
+	case ErrorKindNotImported:
+		return fmt.Sprintf("%s: %q is defined in %q, which is not imported", e.Pos, e.Name, e.Candidates[0].File.Path)
+	case ErrorKindDuplicate:
+		return fmt.Sprintf("%s: %q is already defined", e.Pos, e.Name)
+	}
+	if e.Resolved != "" {
+		return fmt.Sprintf("%s: %q is resolved to %q, which is not defined", e.Pos, e.Name, e.Resolved)
+	}
+	return fmt.Sprintf("%s: %q is not defined", e.Pos, e.Name)
+}
+
+// ErrorList is a list of the errors found by Link.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
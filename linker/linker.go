@@ -0,0 +1,314 @@
+// Package linker builds the symbol table of a set of files and resolves the type references in them.
+//
+// The references are resolved with the protobuf scoping rules:
+// a name starting with a dot is fully-qualified, and other names are searched from the innermost scope outwards.
+package linker
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Reference is a type name written in a file.
+type Reference struct {
+	// Name is the name as it is written, like "Foo", ".pkg.Foo" or "Outer.Inner".
+	Name string
+	// Scope is the fully-qualified name of the scope in which the name is written.
+	Scope string
+	// Node is the node which has the name, which is a *parser.Field, a *parser.MapField, a *parser.OneofField,
+	// a *parser.RPCRequest, a *parser.RPCResponse or a *parser.Extend.
+	Node interface{}
+	// File is the file which has the name.
+	File *fileset.File
+	// Pos is the position of the node.
+	Pos meta.Position
+	// Symbol is the resolved definition. It is nil when the name is not resolved.
+	Symbol *Symbol
+}
+
+// Table is the symbol table of the linked files.
+type Table struct {
+	// Symbols holds the definitions keyed by their fully-qualified names.
+	Symbols map[string]*Symbol
+	// References holds the type references in the order of the files and the statements.
+	References []*Reference
+
+	resolved map[interface{}]*Reference
+	errors   ErrorList
+}
+
+// Lookup returns the symbol whose fully-qualified name is the name, or nil.
+// The leading dot of the name is optional.
+func (t *Table) Lookup(name string) *Symbol {
+	return t.Symbols[strings.TrimPrefix(name, ".")]
+}
+
+// Resolve returns the definition which the type of the node refers to, or nil.
+// The node is one of the nodes which Reference.Node can hold.
+func (t *Table) Resolve(node interface{}) *Symbol {
+	if ref, ok := t.resolved[node]; ok {
+		return ref.Symbol
+	}
+	return nil
+}
+
+// LinkFileSet links all of the files in the set.
+func LinkFileSet(set *fileset.FileSet) (*Table, error) {
+	return Link(set.Sorted())
+}
+
+// Link builds the symbol table of the files and resolves all of the type references in them.
+// A file can refer to the definitions in itself, in the files it imports,
+// and in the files which are publicly imported by them.
+//
+// Link returns the table along with an ErrorList when any names are duplicated or not resolved.
+func Link(files []*fileset.File) (*Table, error) {
+	t := &Table{
+		Symbols:  make(map[string]*Symbol),
+		resolved: make(map[interface{}]*Reference),
+	}
+	for _, file := range files {
+		t.define(file)
+	}
+
+	byPath := make(map[string]*fileset.File)
+	for _, file := range files {
+		byPath[file.Path] = file
+	}
+	for _, file := range files {
+		l := &linker{
+			table:   t,
+			file:    file,
+			visible: visibleFiles(file, byPath),
+		}
+		l.body(packageName(file.Proto), file.Proto.ProtoBody)
+	}
+
+	if 0 < len(t.errors) {
+		return t, t.errors
+	}
+	return t, nil
+}
+
+// visibleFiles returns the files whose definitions the file can refer to.
+func visibleFiles(file *fileset.File, byPath map[string]*fileset.File) map[*fileset.File]bool {
+	visible := map[*fileset.File]bool{file: true}
+	var addPublic func(f *fileset.File)
+	addPublic = func(f *fileset.File) {
+		for _, p := range f.PublicImports() {
+			dep, ok := byPath[p]
+			if !ok || visible[dep] {
+				continue
+			}
+			visible[dep] = true
+			addPublic(dep)
+		}
+	}
+	for _, p := range file.Imports {
+		dep, ok := byPath[p]
+		if !ok || visible[dep] {
+			continue
+		}
+		visible[dep] = true
+		addPublic(dep)
+	}
+	return visible
+}
+
+// scalarTypes are the types which are not references.
+var scalarTypes = map[string]bool{
+	"double":   true,
+	"float":    true,
+	"int32":    true,
+	"int64":    true,
+	"uint32":   true,
+	"uint64":   true,
+	"sint32":   true,
+	"sint64":   true,
+	"fixed32":  true,
+	"fixed64":  true,
+	"sfixed32": true,
+	"sfixed64": true,
+	"bool":     true,
+	"string":   true,
+	"bytes":    true,
+}
+
+type linker struct {
+	table   *Table
+	file    *fileset.File
+	visible map[*fileset.File]bool
+}
+
+func (l *linker) body(scope string, body []parser.Visitee) {
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *parser.Message:
+			l.body(join(scope, n.MessageName), n.MessageBody)
+		case *parser.GroupField:
+			l.body(join(scope, n.GroupName), n.MessageBody)
+		case *parser.Field:
+			l.link(scope, n.Type, n, n.Meta.Pos, false)
+		case *parser.MapField:
+			l.link(scope, n.Type, n, n.Meta.Pos, false)
+		case *parser.Oneof:
+			for _, field := range n.OneofFields {
+				l.link(scope, field.Type, field, field.Meta.Pos, false)
+			}
+		case *parser.Extend:
+			l.link(scope, n.MessageType, n, n.Meta.Pos, true)
+			l.body(scope, n.ExtendBody)
+		case *parser.Service:
+			for _, stmt := range n.ServiceBody {
+				rpc, ok := stmt.(*parser.RPC)
+				if !ok {
+					continue
+				}
+				if rpc.RPCRequest != nil {
+					l.link(scope, rpc.RPCRequest.MessageType, rpc.RPCRequest, rpc.RPCRequest.Meta.Pos, true)
+				}
+				if rpc.RPCResponse != nil {
+					l.link(scope, rpc.RPCResponse.MessageType, rpc.RPCResponse, rpc.RPCResponse.Meta.Pos, true)
+				}
+			}
+		}
+	}
+}
+
+// link resolves the name and records the reference.
+// The name must refer to a message when messageOnly is true, and to a message or an enum otherwise.
+func (l *linker) link(scope, name string, node interface{}, pos meta.Position, messageOnly bool) {
+	if name == "" || (!messageOnly && scalarTypes[name]) {
+		return
+	}
+	ref := &Reference{
+		Name:  name,
+		Scope: scope,
+		Node:  node,
+		File:  l.file,
+		Pos:   pos,
+	}
+	l.table.References = append(l.table.References, ref)
+	l.table.resolved[node] = ref
+
+	symbol, err := l.resolve(scope, name, (*Symbol).IsType)
+	if err == nil && messageOnly && symbol.Kind != SymbolKindMessage {
+		err = &Error{Kind: ErrorKindNotMessage, Candidates: []*Symbol{symbol}}
+	}
+	if err != nil {
+		err.Pos = pos
+		err.Name = name
+		err.Scope = scope
+		l.table.errors = append(l.table.errors, err)
+		return
+	}
+	ref.Symbol = symbol
+}
+
+// ResolveExtension returns the extension which the name in the scope refers to, or nil.
+// The name is the one in the parentheses of an option name, like "foo.bar" of "(foo.bar)".
+func (t *Table) ResolveExtension(scope, name string) *Symbol {
+	l := &linker{table: t}
+	symbol, err := l.resolve(scope, name, func(s *Symbol) bool {
+		return s.Kind == SymbolKindExtension
+	})
+	if err != nil {
+		return nil
+	}
+	return symbol
+}
+
+// resolve finds the symbol which the name in the scope refers to. The symbol must be accepted by the function.
+// The symbols in the files which are not visible are skipped, like protoc does,
+// and reported as ErrorKindNotImported only when nothing else is found.
+func (l *linker) resolve(scope, name string, accept func(*Symbol) bool) (*Symbol, *Error) {
+	symbols := l.table.Symbols
+	if strings.HasPrefix(name, ".") {
+		symbol, ok := symbols[name[1:]]
+		if !ok {
+			return nil, &Error{Kind: ErrorKindUnresolved}
+		}
+		if !l.isVisible(symbol) {
+			return nil, &Error{Kind: ErrorKindNotImported, Candidates: []*Symbol{symbol}}
+		}
+		if !accept(symbol) {
+			return nil, &Error{Kind: ErrorKindNotType, Candidates: []*Symbol{symbol}}
+		}
+		return symbol, nil
+	}
+
+	first := name
+	if i := strings.Index(name, "."); 0 <= i {
+		first = name[:i]
+	}
+	var notType, notImported *Symbol
+	for s := scope; ; s = parentScope(s) {
+		symbol, ok := symbols[join(s, first)]
+		switch {
+		case !ok:
+		case !l.isVisible(symbol):
+			// keeps searching the outer scopes for the visible one, like protoc does.
+			if notImported == nil {
+				notImported = symbol
+			}
+		case first == name && accept(symbol):
+			return symbol, nil
+		case first == name:
+			// keeps searching the outer scopes, like protoc does.
+			if notType == nil {
+				notType = symbol
+			}
+		case !symbol.isAggregate():
+			// keeps searching the outer scopes for the one which can have the rest, like protoc does.
+		default:
+			// the first part is found, so the rest must be in it.
+			full, ok := symbols[join(s, name)]
+			if !ok {
+				err := &Error{Kind: ErrorKindUnresolved}
+				if outer := l.outer(s, name, accept); outer != nil {
+					err.Kind = ErrorKindAmbiguous
+					err.Candidates = []*Symbol{outer}
+				}
+				err.Resolved = join(s, name)
+				return nil, err
+			}
+			if !l.isVisible(full) {
+				return nil, &Error{Kind: ErrorKindNotImported, Candidates: []*Symbol{full}}
+			}
+			if !accept(full) {
+				return nil, &Error{Kind: ErrorKindNotType, Candidates: []*Symbol{full}}
+			}
+			return full, nil
+		}
+		if s == "" {
+			break
+		}
+	}
+	if notType != nil {
+		return nil, &Error{Kind: ErrorKindNotType, Candidates: []*Symbol{notType}}
+	}
+	if notImported != nil {
+		return nil, &Error{Kind: ErrorKindNotImported, Candidates: []*Symbol{notImported}}
+	}
+	return nil, &Error{Kind: ErrorKindUnresolved}
+}
+
+// isVisible reports whether the file which is linked can refer to the symbol.
+// All of the symbols are visible when no file is linked.
+func (l *linker) isVisible(s *Symbol) bool {
+	return l.visible == nil || s.File == nil || l.visible[s.File]
+}
+
+// outer returns the symbol which the name would refer to in the scopes enclosing the scope, or nil.
+func (l *linker) outer(scope, name string, accept func(*Symbol) bool) *Symbol {
+	for scope != "" {
+		scope = parentScope(scope)
+		if symbol, ok := l.table.Symbols[join(scope, name)]; ok && l.isVisible(symbol) && accept(symbol) {
+			return symbol
+		}
+	}
+	return nil
+}
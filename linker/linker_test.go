@@ -0,0 +1,338 @@
+package linker_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+)
+
+func parse(t *testing.T, files map[string]string, roots ...string) *fileset.FileSet {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	set, err := fileset.Parse(roots, fileset.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return set
+}
+
+func TestLinkFileSet(t *testing.T) {
+	set := parse(t, map[string]string{
+		"a.proto": `syntax = "proto2";
+package foo.bar;
+import "b.proto";
+
+message Outer {
+  message Inner {
+    optional Outer outer = 1;
+    optional Kind kind = 2;
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+  }
+  optional Inner inner = 1;
+  optional Outer.Inner qualified = 2;
+  optional .foo.bar.Outer.Inner full = 3;
+  map<string, baz.Shared> shared = 4;
+  oneof o {
+    Other other = 5;
+  }
+  optional group Result = 6 {
+    optional Inner inner = 7;
+  }
+  optional Result result = 8;
+  extend baz.Shared {
+    optional Inner ext = 100;
+  }
+  optional int32 scalar = 9;
+}
+
+message Other {}
+
+service S {
+  rpc Get(Outer) returns (stream bar.Other);
+}
+`,
+		"b.proto": `syntax = "proto2";
+package foo.baz;
+message Shared {
+  extensions 100 to 200;
+}
+`,
+	}, "a.proto")
+
+	table, err := linker.LinkFileSet(set)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	type resolved struct {
+		Name  string
+		Scope string
+		Full  string
+		Kind  linker.SymbolKind
+	}
+	var got []resolved
+	for _, ref := range table.References {
+		got = append(got, resolved{
+			Name:  ref.Name,
+			Scope: ref.Scope,
+			Full:  ref.Symbol.FullName,
+			Kind:  ref.Symbol.Kind,
+		})
+	}
+	want := []resolved{
+		{Name: "Outer", Scope: "foo.bar.Outer.Inner", Full: "foo.bar.Outer", Kind: linker.SymbolKindMessage},
+		{Name: "Kind", Scope: "foo.bar.Outer.Inner", Full: "foo.bar.Outer.Kind", Kind: linker.SymbolKindEnum},
+		{Name: "Inner", Scope: "foo.bar.Outer", Full: "foo.bar.Outer.Inner", Kind: linker.SymbolKindMessage},
+		{Name: "Outer.Inner", Scope: "foo.bar.Outer", Full: "foo.bar.Outer.Inner", Kind: linker.SymbolKindMessage},
+		{Name: ".foo.bar.Outer.Inner", Scope: "foo.bar.Outer", Full: "foo.bar.Outer.Inner", Kind: linker.SymbolKindMessage},
+		{Name: "baz.Shared", Scope: "foo.bar.Outer", Full: "foo.baz.Shared", Kind: linker.SymbolKindMessage},
+		{Name: "Other", Scope: "foo.bar.Outer", Full: "foo.bar.Other", Kind: linker.SymbolKindMessage},
+		{Name: "Inner", Scope: "foo.bar.Outer.Result", Full: "foo.bar.Outer.Inner", Kind: linker.SymbolKindMessage},
+		{Name: "Result", Scope: "foo.bar.Outer", Full: "foo.bar.Outer.Result", Kind: linker.SymbolKindMessage},
+		{Name: "baz.Shared", Scope: "foo.bar.Outer", Full: "foo.baz.Shared", Kind: linker.SymbolKindMessage},
+		{Name: "Inner", Scope: "foo.bar.Outer", Full: "foo.bar.Outer.Inner", Kind: linker.SymbolKindMessage},
+		{Name: "Outer", Scope: "foo.bar", Full: "foo.bar.Outer", Kind: linker.SymbolKindMessage},
+		{Name: "bar.Other", Scope: "foo.bar", Full: "foo.bar.Other", Kind: linker.SymbolKindMessage},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+
+	for _, ref := range table.References {
+		if table.Resolve(ref.Node) != ref.Symbol {
+			t.Errorf("got %v, but want %v", table.Resolve(ref.Node), ref.Symbol)
+		}
+	}
+	for _, name := range []string{"foo", "foo.bar", ".foo.baz.Shared", "foo.bar.Outer.Result", "foo.bar.S"} {
+		if table.Lookup(name) == nil {
+			t.Errorf("got nil, but want the symbol %s", name)
+		}
+	}
+}
+
+func TestLinkFileSet_nonAggregate(t *testing.T) {
+	set := parse(t, map[string]string{
+		"a.proto": `syntax = "proto2";
+package p;
+message ext {
+  message X {}
+  extensions 100 to 200;
+}
+message M {
+  extend ext {
+    optional int32 ext = 100;
+  }
+  optional ext.X x = 1;
+}
+`,
+	}, "a.proto")
+
+	table, err := linker.LinkFileSet(set)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	// the extension p.M.ext is skipped, because it can't have X.
+	ref := table.References[len(table.References)-1]
+	if ref.Name != "ext.X" || ref.Symbol.FullName != "p.ext.X" {
+		t.Errorf("got %s resolved to %v, but want ext.X resolved to p.ext.X", ref.Name, ref.Symbol)
+	}
+}
+
+func TestLinkFileSet_notImported(t *testing.T) {
+	set := parse(t, map[string]string{
+		"a.proto": `syntax = "proto3";
+package foo.bar;
+import "c.proto";
+message A {
+  M m = 1;
+}
+`,
+		"b.proto": `syntax = "proto3";
+package foo.bar;
+message M {}
+`,
+		"c.proto": `syntax = "proto3";
+package foo;
+message M {}
+`,
+	}, "a.proto", "b.proto")
+
+	table, err := linker.LinkFileSet(set)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	// foo.bar.M is skipped, because a.proto doesn't import b.proto.
+	ref := table.References[0]
+	if ref.Name != "M" || ref.Symbol.FullName != "foo.M" {
+		t.Errorf("got %s resolved to %v, but want M resolved to foo.M", ref.Name, ref.Symbol)
+	}
+}
+
+func TestLinkFileSet_errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantKinds []linker.ErrorKind
+		wantNames []string
+		wantLines []int
+	}{
+		{
+			name: "reporting an unresolved name",
+			files: map[string]string{
+				"a.proto": `syntax = "proto3";
+message A {
+  B b = 1;
+  A.B ab = 2;
+}
+`,
+			},
+			wantKinds: []linker.ErrorKind{linker.ErrorKindUnresolved, linker.ErrorKindUnresolved},
+			wantNames: []string{"B", "A.B"},
+			wantLines: []int{3, 4},
+		},
+		{
+			name: "reporting a name shadowed by an inner scope",
+			files: map[string]string{
+				"a.proto": `syntax = "proto3";
+package foo;
+message Bar {}
+message Outer {
+  message foo {}
+  foo.Bar bar = 1;
+}
+`,
+			},
+			wantKinds: []linker.ErrorKind{linker.ErrorKindAmbiguous},
+			wantNames: []string{"foo.Bar"},
+			wantLines: []int{6},
+		},
+		{
+			name: "reporting a name which is not a type",
+			files: map[string]string{
+				"a.proto": `syntax = "proto3";
+package foo;
+service S {
+  rpc Get(E) returns (foo);
+}
+enum E {
+  E_UNSPECIFIED = 0;
+}
+message M {
+  S s = 1;
+}
+`,
+			},
+			wantKinds: []linker.ErrorKind{linker.ErrorKindNotMessage, linker.ErrorKindNotType, linker.ErrorKindNotType},
+			wantNames: []string{"E", "foo", "S"},
+			wantLines: []int{4, 4, 10},
+		},
+		{
+			name: "reporting a name defined in a file which is not imported",
+			files: map[string]string{
+				"a.proto": `syntax = "proto3";
+import "b.proto";
+message A {
+  B b = 1;
+  C c = 2;
+  D d = 3;
+}
+`,
+				"b.proto": `syntax = "proto3";
+import public "c.proto";
+import "d.proto";
+message B {}
+`,
+				"c.proto": `syntax = "proto3";
+message C {}
+`,
+				"d.proto": `syntax = "proto3";
+message D {}
+`,
+			},
+			wantKinds: []linker.ErrorKind{linker.ErrorKindNotImported},
+			wantNames: []string{"D"},
+			wantLines: []int{6},
+		},
+		{
+			name: "reporting a name in a service",
+			files: map[string]string{
+				"a.proto": `syntax = "proto3";
+package p;
+import "b.proto";
+service S {}
+message M {
+  S.X x = 1;
+}
+`,
+				"b.proto": `syntax = "proto3";
+package S;
+message X {}
+`,
+			},
+			wantKinds: []linker.ErrorKind{linker.ErrorKindAmbiguous},
+			wantNames: []string{"S.X"},
+			wantLines: []int{6},
+		},
+		{
+			name: "reporting a duplicated name",
+			files: map[string]string{
+				"a.proto": `syntax = "proto3";
+package foo;
+import "b.proto";
+message A {}
+`,
+				"b.proto": `syntax = "proto3";
+package foo;
+enum A {
+  A_UNSPECIFIED = 0;
+}
+`,
+			},
+			wantKinds: []linker.ErrorKind{linker.ErrorKindDuplicate},
+			wantNames: []string{"foo.A"},
+			wantLines: []int{4},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			set := parse(t, test.files, "a.proto")
+
+			_, err := linker.LinkFileSet(set)
+			var list linker.ErrorList
+			if !errors.As(err, &list) {
+				t.Fatalf("got err %v, but want ErrorList", err)
+			}
+			var kinds []linker.ErrorKind
+			var names []string
+			var lines []int
+			for _, e := range list {
+				kinds = append(kinds, e.Kind)
+				names = append(names, e.Name)
+				lines = append(lines, e.Pos.Line)
+				if e.Error() == "" {
+					t.Errorf("got an empty message")
+				}
+			}
+			if !reflect.DeepEqual(kinds, test.wantKinds) {
+				t.Errorf("got %v, but want %v", kinds, test.wantKinds)
+			}
+			if !reflect.DeepEqual(names, test.wantNames) {
+				t.Errorf("got %v, but want %v", names, test.wantNames)
+			}
+			if !reflect.DeepEqual(lines, test.wantLines) {
+				t.Errorf("got %v, but want %v", lines, test.wantLines)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package linker
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// SymbolKind is a kind of the Symbol.
+type SymbolKind int
+
+// SymbolKind values.
+const (
+	SymbolKindPackage SymbolKind = iota
+	SymbolKindMessage
+	SymbolKindEnum
+	SymbolKindService
+	SymbolKindExtension
+)
+
+func (k SymbolKind) String() string {
+	switch k {
+	case SymbolKindPackage:
+		return "package"
+	case SymbolKindMessage:
+		return "message"
+	case SymbolKindEnum:
+		return "enum"
+	case SymbolKindService:
+		return "service"
+	case SymbolKindExtension:
+		return "extension"
+	}
+	return "unknown"
+}
+
+// Symbol is a named definition.
+type Symbol struct {
+	// FullName is the fully-qualified name without the leading dot, like "pkg.Outer.Inner".
+	FullName string
+	Kind     SymbolKind
+	// Node is the definition, which is a *parser.Message, a *parser.GroupField, a *parser.Enum or a *parser.Service.
+	// For an extension, it is a *parser.Field or a *parser.GroupField in the Extend. It is nil for a package.
+	Node parser.Visitee
+	// File is the file which has the definition. It is nil for a package.
+	File *fileset.File
+	// Pos is the position of the definition.
+	Pos meta.Position
+}
+
+// IsType reports whether the symbol can be the type of a field.
+func (s *Symbol) IsType() bool {
+	return s.Kind == SymbolKindMessage || s.Kind == SymbolKindEnum
+}
+
+// isAggregate reports whether the symbol can have the other symbols in it.
+func (s *Symbol) isAggregate() bool {
+	switch s.Kind {
+	case SymbolKindPackage, SymbolKindMessage, SymbolKindEnum, SymbolKindService:
+		return true
+	}
+	return false
+}
+
+// join returns the full name of the name in the scope.
+func join(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// parentScope returns the scope which encloses the scope.
+func parentScope(scope string) string {
+	i := strings.LastIndex(scope, ".")
+	if i < 0 {
+		return ""
+	}
+	return scope[:i]
+}
+
+// packageName returns the name of the package statement in the proto.
+func packageName(proto *parser.Proto) string {
+	for _, stmt := range proto.ProtoBody {
+		if pkg, ok := stmt.(*parser.Package); ok {
+			return pkg.Name
+		}
+	}
+	return ""
+}
+
+// define adds the definitions in the file to the table.
+func (t *Table) define(file *fileset.File) {
+	pkg := packageName(file.Proto)
+	if pkg != "" {
+		parts := strings.Split(pkg, ".")
+		for i := range parts {
+			name := strings.Join(parts[:i+1], ".")
+			if _, ok := t.Symbols[name]; !ok {
+				t.Symbols[name] = &Symbol{
+					FullName: name,
+					Kind:     SymbolKindPackage,
+				}
+			}
+		}
+	}
+	t.defineBody(file, pkg, file.Proto.ProtoBody)
+}
+
+func (t *Table) defineBody(file *fileset.File, scope string, body []parser.Visitee) {
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *parser.Message:
+			name := join(scope, n.MessageName)
+			t.add(&Symbol{FullName: name, Kind: SymbolKindMessage, Node: n, File: file, Pos: n.Meta.Pos})
+			t.defineBody(file, name, n.MessageBody)
+		case *parser.GroupField:
+			name := join(scope, n.GroupName)
+			t.add(&Symbol{FullName: name, Kind: SymbolKindMessage, Node: n, File: file, Pos: n.Meta.Pos})
+			t.defineBody(file, name, n.MessageBody)
+		case *parser.Enum:
+			t.add(&Symbol{FullName: join(scope, n.EnumName), Kind: SymbolKindEnum, Node: n, File: file, Pos: n.Meta.Pos})
+		case *parser.Service:
+			t.add(&Symbol{FullName: join(scope, n.ServiceName), Kind: SymbolKindService, Node: n, File: file, Pos: n.Meta.Pos})
+		case *parser.Extend:
+			// the extensions and the groups in the extend are defined in the scope which encloses the extend.
+			for _, ext := range n.ExtendBody {
+				switch f := ext.(type) {
+				case *parser.Field:
+					t.add(&Symbol{FullName: join(scope, f.FieldName), Kind: SymbolKindExtension, Node: f, File: file, Pos: f.Meta.Pos})
+				case *parser.GroupField:
+					t.add(&Symbol{FullName: join(scope, strings.ToLower(f.GroupName)), Kind: SymbolKindExtension, Node: f, File: file, Pos: f.Meta.Pos})
+				}
+			}
+			t.defineBody(file, scope, n.ExtendBody)
+		}
+	}
+}
+
+// add adds the symbol. It reports the duplicate when the name is already defined.
+func (t *Table) add(symbol *Symbol) {
+	if defined, ok := t.Symbols[symbol.FullName]; ok {
+		t.errors = append(t.errors, &Error{
+			Pos:        symbol.Pos,
+			Kind:       ErrorKindDuplicate,
+			Name:       symbol.FullName,
+			Candidates: []*Symbol{defined},
+		})
+		return
+	}
+	t.Symbols[symbol.FullName] = symbol
+}
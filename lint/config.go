@@ -0,0 +1,62 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Config enables and disables the rules. It's usually read from a JSON file like:
+//
+//	{
+//	  "disable": ["ENUM_ZERO_VALUE_UNSPECIFIED"]
+//	}
+type Config struct {
+	// Enable holds the IDs of the rules to run. All of the rules run when it's empty.
+	Enable []string `json:"enable,omitempty"`
+	// Disable holds the IDs of the rules not to run. It takes precedence over Enable.
+	Disable []string `json:"disable,omitempty"`
+}
+
+func (c *Config) enabled(id string) bool {
+	for _, disabled := range c.Disable {
+		if disabled == id {
+			return false
+		}
+	}
+	if len(c.Enable) == 0 {
+		return true
+	}
+	for _, enabled := range c.Enable {
+		if enabled == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseConfig parses the JSON config. It returns an error when the config has an unknown key.
+func ParseConfig(data []byte) (*Config, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("failed to parse the config, err %v", err)
+	}
+	return config, nil
+}
+
+// LoadConfig reads the JSON config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,116 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// directivePrefix starts a comment line which disables the rules.
+const directivePrefix = "protoparser:disable"
+
+// allRules is the key of a ruleSet which means all of the rules.
+const allRules = "*"
+
+// ruleSet is a set of the rule IDs.
+type ruleSet map[string]bool
+
+func (s ruleSet) has(id string) bool {
+	return s[allRules] || s[id]
+}
+
+// tree holds the parents of the elements and the rules disabled for them.
+type tree struct {
+	parents map[parser.Visitee]parser.Visitee
+	// disabled holds the rules disabled by the directives of the elements. It doesn't include the ones of their parents.
+	disabled map[parser.Visitee]ruleSet
+	// fileDisabled holds the rules disabled for the whole file.
+	fileDisabled ruleSet
+}
+
+func newTree(proto *parser.Proto) *tree {
+	t := &tree{
+		parents:      make(map[parser.Visitee]parser.Visitee),
+		disabled:     make(map[parser.Visitee]ruleSet),
+		fileDisabled: make(ruleSet),
+	}
+	parser.Walk(proto, func(c *parser.Cursor) bool {
+		parent := c.Parent()
+		if c.Leaving() || parent == nil {
+			return true
+		}
+		if _, ok := parent.(*parser.Proto); !ok {
+			t.parents[c.Node()] = parent
+		}
+
+		comment, ok := c.Node().(*parser.Comment)
+		if !ok {
+			return true
+		}
+		disabled := t.disabled[parent]
+		switch parent.(type) {
+		case *parser.Syntax, *parser.Edition:
+			disabled = t.fileDisabled
+		}
+		for _, id := range directive(comment) {
+			if disabled == nil {
+				disabled = make(ruleSet)
+				t.disabled[parent] = disabled
+			}
+			disabled[id] = true
+		}
+		return true
+	})
+	return t
+}
+
+// isDisabled reports whether the rule is disabled for the node. The node is nil outside of the traversal.
+func (t *tree) isDisabled(node parser.Visitee, id string) bool {
+	if t.fileDisabled.has(id) {
+		return true
+	}
+	for n := node; n != nil; n = t.parents[n] {
+		if t.disabled[n].has(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPruned reports whether the node is in one of the pruned elements.
+func (t *tree) isPruned(node parser.Visitee, pruned map[parser.Visitee]bool) bool {
+	if len(pruned) == 0 {
+		return false
+	}
+	for n := t.parents[node]; n != nil; n = t.parents[n] {
+		if pruned[n] {
+			return true
+		}
+	}
+	return false
+}
+
+// directive returns the rule IDs which the comment disables.
+func directive(comment *parser.Comment) []string {
+	var ids []string
+	for _, line := range comment.Lines() {
+		line = strings.TrimLeft(strings.TrimSpace(line), "*")
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, directivePrefix) {
+			continue
+		}
+		args := strings.TrimPrefix(line, directivePrefix)
+		if args != "" && !strings.HasPrefix(args, " ") && !strings.HasPrefix(args, "\t") {
+			// like "protoparser:disable-next-line", which is not a directive.
+			continue
+		}
+		fields := strings.FieldsFunc(args, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		})
+		if len(fields) == 0 {
+			return []string{allRules}
+		}
+		ids = append(ids, fields...)
+	}
+	return ids
+}
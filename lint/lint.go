@@ -0,0 +1,282 @@
+// Package lint runs the rules which check the style of a proto, like the naming of the definitions.
+//
+// A rule is a parser.Visitor which reports the findings. All of the rules run in one traversal of the proto.
+// The rules can be enabled and disabled with a Config, and disabled for a part of the proto
+// with a "protoparser:disable" directive in the comments of the element:
+//
+//	// protoparser:disable FIELD_NAMES_LOWER_SNAKE_CASE
+//	string Name = 1;
+//	string Kind = 2; // protoparser:disable
+//
+// A directive disables the rules for the element and the elements in it. A directive without the IDs disables all of the rules.
+// A directive of the syntax or the edition statement disables the rules for the whole file.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Severity is how important the Finding is.
+type Severity int
+
+// Severity values.
+const (
+	// SeverityWarning means the finding should be fixed, but doesn't have to be.
+	SeverityWarning Severity = iota
+	// SeverityError means the finding must be fixed.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// Rule is a lint rule.
+type Rule struct {
+	// ID identifies the rule in a Config and a directive, like "FIELD_NAMES_LOWER_SNAKE_CASE".
+	ID       string
+	Severity Severity
+	// Description describes what the rule checks.
+	Description string
+	// Visitor returns the visitor which checks a proto and reports the findings to the reporter.
+	// It's called once for each proto, so the visitor can keep the state of the traversal.
+	// When the visitor returns false, the elements in the visited one are not visited by it.
+	Visitor func(r *Reporter) parser.Visitor
+}
+
+// Finding is a violation of a rule.
+type Finding struct {
+	Pos      meta.Position
+	RuleID   string
+	Severity Severity
+	Message  string
+}
+
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", f.Pos, f.Severity, f.Message, f.RuleID)
+}
+
+// Reporter receives the findings of a rule.
+type Reporter struct {
+	rule *Rule
+	run  *run
+}
+
+// Proto returns the proto which is checked.
+func (r *Reporter) Proto() *parser.Proto {
+	return r.run.proto
+}
+
+// Reportf reports a finding at the pos. It's ignored when the rule is disabled for the element which is visited.
+func (r *Reporter) Reportf(pos meta.Position, format string, args ...interface{}) {
+	if r.run.tree.isDisabled(r.run.current, r.rule.ID) {
+		return
+	}
+	r.run.findings = append(r.run.findings, &Finding{
+		Pos:      pos,
+		RuleID:   r.rule.ID,
+		Severity: r.rule.Severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Linter runs the rules.
+type Linter struct {
+	rules []*Rule
+}
+
+// New returns a Linter which runs the rules enabled by the config. All of the rules are enabled when the config is nil.
+// It returns an error when the config refers to a rule which is not in the rules.
+func New(rules []*Rule, config *Config) (*Linter, error) {
+	if config == nil {
+		return &Linter{rules: rules}, nil
+	}
+
+	known := make(map[string]bool)
+	for _, rule := range rules {
+		known[rule.ID] = true
+	}
+	for _, id := range append(append([]string{}, config.Enable...), config.Disable...) {
+		if !known[id] {
+			return nil, fmt.Errorf("unknown rule %q", id)
+		}
+	}
+
+	l := &Linter{}
+	for _, rule := range rules {
+		if config.enabled(rule.ID) {
+			l.rules = append(l.rules, rule)
+		}
+	}
+	return l, nil
+}
+
+// Rules returns the rules which the linter runs.
+func (l *Linter) Rules() []*Rule {
+	return l.rules
+}
+
+// Lint runs the rules against the proto and returns the findings in the order of the positions.
+func (l *Linter) Lint(proto *parser.Proto) []*Finding {
+	r := &run{
+		proto: proto,
+		tree:  newTree(proto),
+	}
+	for _, rule := range l.rules {
+		r.visitors = append(r.visitors, &ruleVisitor{
+			visitor: rule.Visitor(&Reporter{rule: rule, run: r}),
+			pruned:  make(map[parser.Visitee]bool),
+		})
+	}
+	proto.Accept(r)
+
+	sort.SliceStable(r.findings, func(i, j int) bool {
+		return r.findings[i].Pos.Offset < r.findings[j].Pos.Offset
+	})
+	return r.findings
+}
+
+// ruleVisitor is the visitor of a rule.
+type ruleVisitor struct {
+	visitor parser.Visitor
+	// pruned holds the elements for which the visitor returned false.
+	pruned map[parser.Visitee]bool
+}
+
+// run dispatches the elements of a proto to the visitors of the rules.
+type run struct {
+	proto    *parser.Proto
+	tree     *tree
+	visitors []*ruleVisitor
+	// current is the element which is visited.
+	current  parser.Visitee
+	findings []*Finding
+}
+
+// dispatch calls the visit for each visitor which visits the node. It always returns true,
+// because the other visitors may visit the elements in the node.
+func (r *run) dispatch(node parser.Visitee, visit func(v parser.Visitor) bool) bool {
+	r.current = node
+	for _, v := range r.visitors {
+		if r.tree.isPruned(node, v.pruned) {
+			continue
+		}
+		if !visit(v.visitor) {
+			v.pruned[node] = true
+		}
+	}
+	return true
+}
+
+// VisitComment implements parser.Visitor.
+func (r *run) VisitComment(n *parser.Comment) {
+	r.dispatch(n, func(v parser.Visitor) bool {
+		v.VisitComment(n)
+		return true
+	})
+}
+
+// VisitEdition implements parser.Visitor.
+func (r *run) VisitEdition(n *parser.Edition) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitEdition(n) })
+}
+
+// VisitEmptyStatement implements parser.Visitor.
+func (r *run) VisitEmptyStatement(n *parser.EmptyStatement) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitEmptyStatement(n) })
+}
+
+// VisitEnum implements parser.Visitor.
+func (r *run) VisitEnum(n *parser.Enum) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitEnum(n) })
+}
+
+// VisitEnumField implements parser.Visitor.
+func (r *run) VisitEnumField(n *parser.EnumField) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitEnumField(n) })
+}
+
+// VisitExtend implements parser.Visitor.
+func (r *run) VisitExtend(n *parser.Extend) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitExtend(n) })
+}
+
+// VisitExtensions implements parser.Visitor.
+func (r *run) VisitExtensions(n *parser.Extensions) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitExtensions(n) })
+}
+
+// VisitField implements parser.Visitor.
+func (r *run) VisitField(n *parser.Field) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitField(n) })
+}
+
+// VisitGroupField implements parser.Visitor.
+func (r *run) VisitGroupField(n *parser.GroupField) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitGroupField(n) })
+}
+
+// VisitImport implements parser.Visitor.
+func (r *run) VisitImport(n *parser.Import) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitImport(n) })
+}
+
+// VisitMapField implements parser.Visitor.
+func (r *run) VisitMapField(n *parser.MapField) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitMapField(n) })
+}
+
+// VisitMessage implements parser.Visitor.
+func (r *run) VisitMessage(n *parser.Message) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitMessage(n) })
+}
+
+// VisitOneof implements parser.Visitor.
+func (r *run) VisitOneof(n *parser.Oneof) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitOneof(n) })
+}
+
+// VisitOneofField implements parser.Visitor.
+func (r *run) VisitOneofField(n *parser.OneofField) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitOneofField(n) })
+}
+
+// VisitOption implements parser.Visitor.
+func (r *run) VisitOption(n *parser.Option) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitOption(n) })
+}
+
+// VisitPackage implements parser.Visitor.
+func (r *run) VisitPackage(n *parser.Package) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitPackage(n) })
+}
+
+// VisitReserved implements parser.Visitor.
+func (r *run) VisitReserved(n *parser.Reserved) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitReserved(n) })
+}
+
+// VisitRPC implements parser.Visitor.
+func (r *run) VisitRPC(n *parser.RPC) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitRPC(n) })
+}
+
+// VisitService implements parser.Visitor.
+func (r *run) VisitService(n *parser.Service) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitService(n) })
+}
+
+// VisitSyntax implements parser.Visitor.
+func (r *run) VisitSyntax(n *parser.Syntax) bool {
+	return r.dispatch(n, func(v parser.Visitor) bool { return v.VisitSyntax(n) })
+}
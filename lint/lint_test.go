@@ -0,0 +1,189 @@
+package lint_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/lint"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+func parse(t *testing.T, filename, input string) *parser.Proto {
+	t.Helper()
+	proto, err := protoparser.Parse(strings.NewReader(input), protoparser.WithFilename(filename))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return proto
+}
+
+func findingStrings(findings []*lint.Finding) []string {
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	return got
+}
+
+// fieldVisitor reports every field. It doesn't visit the fields in the messages named "Skipped".
+type fieldVisitor struct {
+	parser.BaseVisitor
+	reporter *lint.Reporter
+}
+
+func (v *fieldVisitor) VisitMessage(n *parser.Message) bool {
+	return n.MessageName != "Skipped"
+}
+
+func (v *fieldVisitor) VisitField(n *parser.Field) bool {
+	v.reporter.Reportf(n.Meta.Pos, "field %s", n.FieldName)
+	return true
+}
+
+var fieldRule = &lint.Rule{
+	ID:       "FIELD",
+	Severity: lint.SeverityError,
+	Visitor: func(r *lint.Reporter) parser.Visitor {
+		return &fieldVisitor{reporter: r}
+	},
+}
+
+func TestLinter_Lint(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "reporting the findings and pruning the elements",
+			input: `syntax = "proto3";
+message A {
+  string a = 1;
+  message Skipped {
+    string b = 1;
+  }
+  message B {
+    string c = 1;
+  }
+}
+`,
+			want: []string{
+				"a.proto:3:3: error: field a [FIELD]",
+				"a.proto:8:5: error: field c [FIELD]",
+			},
+		},
+		{
+			name: "disabling the rules with the directives",
+			input: `syntax = "proto3";
+message A {
+  // protoparser:disable FIELD
+  string a = 1;
+  string b = 2; // protoparser:disable OTHER, FIELD
+  string c = 3; // protoparser:disable OTHER
+  /*
+   * protoparser:disable
+   */
+  message B {
+    string d = 1;
+  }
+  string e = 4; // protoparser:disable-next-line
+}
+`,
+			want: []string{
+				"a.proto:6:3: error: field c [FIELD]",
+				"a.proto:13:3: error: field e [FIELD]",
+			},
+		},
+		{
+			name: "disabling the rules for the file",
+			input: `// protoparser:disable FIELD
+syntax = "proto3";
+message A {
+  string a = 1;
+}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			linter, err := lint.New([]*lint.Rule{fieldRule}, nil)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			got := findingStrings(linter.Lint(parse(t, "a.proto", test.input)))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, but want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	rules := lint.StyleGuide()
+
+	tests := []struct {
+		name      string
+		config    string
+		wantRules []string
+		wantErr   bool
+	}{
+		{
+			name:   "enabling the rules",
+			config: `{"enable": ["IMPORTS_SORTED", "ENUM_NAMES_UPPER_CAMEL_CASE", "RPC_NAMES_UPPER_CAMEL_CASE"]}`,
+			wantRules: []string{
+				"IMPORTS_SORTED",
+				"ENUM_NAMES_UPPER_CAMEL_CASE",
+				"RPC_NAMES_UPPER_CAMEL_CASE",
+			},
+		},
+		{
+			name:   "disabling the rules",
+			config: `{"enable": ["IMPORTS_SORTED", "ENUM_NAMES_UPPER_CAMEL_CASE"], "disable": ["IMPORTS_SORTED"]}`,
+			wantRules: []string{
+				"ENUM_NAMES_UPPER_CAMEL_CASE",
+			},
+		},
+		{
+			name:    "an unknown rule",
+			config:  `{"disable": ["UNKNOWN"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "an unknown key",
+			config:  `{"disabled": ["IMPORTS_SORTED"]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			config, err := lint.ParseConfig([]byte(test.config))
+			var linter *lint.Linter
+			if err == nil {
+				linter, err = lint.New(rules, config)
+			}
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("got nil, but want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+
+			var got []string
+			for _, rule := range linter.Rules() {
+				got = append(got, rule.ID)
+			}
+			if !reflect.DeepEqual(got, test.wantRules) {
+				t.Errorf("got %v, but want %v", got, test.wantRules)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package lint
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// The IDs of the rules in StyleGuide.
+const (
+	RuleFileNamesLowerSnakeCase      = "FILE_NAMES_LOWER_SNAKE_CASE"
+	RulePackageNameLowerCase         = "PACKAGE_NAME_LOWER_CASE"
+	RuleImportsSorted                = "IMPORTS_SORTED"
+	RuleMessageNamesUpperCamelCase   = "MESSAGE_NAMES_UPPER_CAMEL_CASE"
+	RuleFieldNamesLowerSnakeCase     = "FIELD_NAMES_LOWER_SNAKE_CASE"
+	RuleOneofNamesLowerSnakeCase     = "ONEOF_NAMES_LOWER_SNAKE_CASE"
+	RuleEnumNamesUpperCamelCase      = "ENUM_NAMES_UPPER_CAMEL_CASE"
+	RuleEnumValueNamesUpperSnakeCase = "ENUM_VALUE_NAMES_UPPER_SNAKE_CASE"
+	RuleEnumValueNamesPrefixed       = "ENUM_VALUE_NAMES_PREFIXED"
+	RuleEnumZeroValueUnspecified     = "ENUM_ZERO_VALUE_UNSPECIFIED"
+	RuleServiceNamesUpperCamelCase   = "SERVICE_NAMES_UPPER_CAMEL_CASE"
+	RuleRPCNamesUpperCamelCase       = "RPC_NAMES_UPPER_CAMEL_CASE"
+)
+
+var (
+	upperCamelCase = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	// lowerSnakeCase doesn't allow a digit after an underscore, like "song_name_1".
+	lowerSnakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z][a-z0-9]*)*$`)
+	upperSnakeCase = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+)
+
+// StyleGuide returns the rules of the official style guide.
+//
+// See https://protobuf.dev/programming-guides/style/
+func StyleGuide() []*Rule {
+	return []*Rule{
+		{
+			ID:          RuleFileNamesLowerSnakeCase,
+			Severity:    SeverityWarning,
+			Description: "The file names are lower_snake_case.proto.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &fileNamesVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RulePackageNameLowerCase,
+			Severity:    SeverityWarning,
+			Description: "The package name is in lowercase.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &packageNameVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleImportsSorted,
+			Severity:    SeverityWarning,
+			Description: "The imports are sorted.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &importsVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleMessageNamesUpperCamelCase,
+			Severity:    SeverityWarning,
+			Description: "The message names are UpperCamelCase.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &messageNamesVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleFieldNamesLowerSnakeCase,
+			Severity:    SeverityWarning,
+			Description: "The field names are lower_snake_case, and a number follows a letter instead of an underscore.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &fieldNamesVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleOneofNamesLowerSnakeCase,
+			Severity:    SeverityWarning,
+			Description: "The oneof names are lower_snake_case.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &oneofNamesVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleEnumNamesUpperCamelCase,
+			Severity:    SeverityWarning,
+			Description: "The enum names are UpperCamelCase.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &enumNamesVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleEnumValueNamesUpperSnakeCase,
+			Severity:    SeverityWarning,
+			Description: "The enum value names are UPPER_SNAKE_CASE.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &enumValueNamesVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleEnumValueNamesPrefixed,
+			Severity:    SeverityWarning,
+			Description: "The enum value names are prefixed with the enum name in UPPER_SNAKE_CASE.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &enumValuePrefixVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleEnumZeroValueUnspecified,
+			Severity:    SeverityWarning,
+			Description: "The zero value of an enum has the suffix _UNSPECIFIED.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &enumZeroValueVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleServiceNamesUpperCamelCase,
+			Severity:    SeverityWarning,
+			Description: "The service names are UpperCamelCase.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &serviceNamesVisitor{reporter: r}
+			},
+		},
+		{
+			ID:          RuleRPCNamesUpperCamelCase,
+			Severity:    SeverityWarning,
+			Description: "The RPC names are UpperCamelCase.",
+			Visitor: func(r *Reporter) parser.Visitor {
+				return &rpcNamesVisitor{reporter: r}
+			},
+		},
+	}
+}
+
+// upperSnake converts the UpperCamelCase name into UPPER_SNAKE_CASE, like "HTTPStatus" into "HTTP_STATUS".
+func upperSnake(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if 0 < i && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+type fileNamesVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+// check checks the name of the file which has the syntax or the edition statement at the pos.
+func (v *fileNamesVisitor) check(pos meta.Position) {
+	base := strings.TrimSuffix(path.Base(pos.Filename), ".proto")
+	if pos.Filename == "" || lowerSnakeCase.MatchString(base) {
+		return
+	}
+	v.reporter.Reportf(pos, "file name %q should be lower_snake_case.proto", path.Base(pos.Filename))
+}
+
+func (v *fileNamesVisitor) VisitSyntax(n *parser.Syntax) bool {
+	v.check(n.Meta.Pos)
+	return false
+}
+
+func (v *fileNamesVisitor) VisitEdition(n *parser.Edition) bool {
+	v.check(n.Meta.Pos)
+	return false
+}
+
+type packageNameVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *packageNameVisitor) VisitPackage(n *parser.Package) bool {
+	if strings.ToLower(n.Name) != n.Name {
+		v.reporter.Reportf(n.Meta.Pos, "package name %q should be in lowercase, like %q", n.Name, strings.ToLower(n.Name))
+	}
+	return false
+}
+
+type importsVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+	previous string
+}
+
+func (v *importsVisitor) VisitImport(n *parser.Import) bool {
+	location := strings.Trim(n.Location, `"'`)
+	if location < v.previous {
+		v.reporter.Reportf(n.Meta.Pos, "import %q should be placed before %q", location, v.previous)
+	}
+	v.previous = location
+	return false
+}
+
+type messageNamesVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *messageNamesVisitor) VisitMessage(n *parser.Message) bool {
+	if !upperCamelCase.MatchString(n.MessageName) {
+		v.reporter.Reportf(n.Meta.Pos, "message name %q should be UpperCamelCase", n.MessageName)
+	}
+	return true
+}
+
+type fieldNamesVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *fieldNamesVisitor) VisitField(n *parser.Field) bool {
+	if !lowerSnakeCase.MatchString(n.FieldName) {
+		v.reporter.Reportf(n.Meta.Pos, "field name %q should be lower_snake_case", n.FieldName)
+	}
+	return true
+}
+
+func (v *fieldNamesVisitor) VisitMapField(n *parser.MapField) bool {
+	if !lowerSnakeCase.MatchString(n.MapName) {
+		v.reporter.Reportf(n.Meta.Pos, "field name %q should be lower_snake_case", n.MapName)
+	}
+	return true
+}
+
+func (v *fieldNamesVisitor) VisitOneofField(n *parser.OneofField) bool {
+	if !lowerSnakeCase.MatchString(n.FieldName) {
+		v.reporter.Reportf(n.Meta.Pos, "field name %q should be lower_snake_case", n.FieldName)
+	}
+	return true
+}
+
+type oneofNamesVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *oneofNamesVisitor) VisitOneof(n *parser.Oneof) bool {
+	if !lowerSnakeCase.MatchString(n.OneofName) {
+		v.reporter.Reportf(n.Meta.Pos, "oneof name %q should be lower_snake_case", n.OneofName)
+	}
+	return false
+}
+
+type enumNamesVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *enumNamesVisitor) VisitEnum(n *parser.Enum) bool {
+	if !upperCamelCase.MatchString(n.EnumName) {
+		v.reporter.Reportf(n.Meta.Pos, "enum name %q should be UpperCamelCase", n.EnumName)
+	}
+	return false
+}
+
+type enumValueNamesVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *enumValueNamesVisitor) VisitEnumField(n *parser.EnumField) bool {
+	if !upperSnakeCase.MatchString(n.Ident) {
+		v.reporter.Reportf(n.Meta.Pos, "enum value name %q should be UPPER_SNAKE_CASE", n.Ident)
+	}
+	return false
+}
+
+type enumValuePrefixVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+	// prefix is the one of the enum which is visited.
+	prefix string
+}
+
+func (v *enumValuePrefixVisitor) VisitEnum(n *parser.Enum) bool {
+	v.prefix = upperSnake(n.EnumName) + "_"
+	return true
+}
+
+func (v *enumValuePrefixVisitor) VisitEnumField(n *parser.EnumField) bool {
+	if !strings.HasPrefix(n.Ident, v.prefix) {
+		v.reporter.Reportf(n.Meta.Pos, "enum value name %q should be prefixed with %q", n.Ident, v.prefix)
+	}
+	return false
+}
+
+type enumZeroValueVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *enumZeroValueVisitor) VisitEnumField(n *parser.EnumField) bool {
+	// the number may be written like 0x0 or 00.
+	number, err := strconv.ParseInt(n.Number, 0, 64)
+	if err == nil && number == 0 && !strings.HasSuffix(n.Ident, "_UNSPECIFIED") {
+		v.reporter.Reportf(n.Meta.Pos, "enum zero value name %q should have the suffix \"_UNSPECIFIED\"", n.Ident)
+	}
+	return false
+}
+
+type serviceNamesVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *serviceNamesVisitor) VisitService(n *parser.Service) bool {
+	if !upperCamelCase.MatchString(n.ServiceName) {
+		v.reporter.Reportf(n.Meta.Pos, "service name %q should be UpperCamelCase", n.ServiceName)
+	}
+	return false
+}
+
+type rpcNamesVisitor struct {
+	parser.BaseVisitor
+	reporter *Reporter
+}
+
+func (v *rpcNamesVisitor) VisitRPC(n *parser.RPC) bool {
+	if !upperCamelCase.MatchString(n.RPCName) {
+		v.reporter.Reportf(n.Meta.Pos, "rpc name %q should be UpperCamelCase", n.RPCName)
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package lint_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lint"
+)
+
+func TestStyleGuide(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		input    string
+		want     []string
+	}{
+		{
+			name:     "a file following the style guide",
+			filename: "foo/bar_baz.proto",
+			input: `syntax = "proto3";
+package foo.bar_baz.v1;
+
+import "a.proto";
+import "b.proto";
+
+message SongServerRequest {
+  string song_name1 = 1;
+  map<string, int32> play_counts = 2;
+  oneof choice {
+    int32 by_id = 3;
+  }
+  enum HTTPStatus {
+    HTTP_STATUS_UNSPECIFIED = 0;
+    HTTP_STATUS_OK = 1;
+  }
+}
+
+service FooService {
+  rpc GetSong(SongServerRequest) returns (SongServerRequest);
+}
+`,
+		},
+		{
+			name:     "an enum zero value written in hexadecimal",
+			filename: "foo/bar_baz.proto",
+			input: `syntax = "proto3";
+package foo.bar_baz.v1;
+
+enum Status {
+  STATUS_OK = 0x0;
+  STATUS_NG = 1;
+}
+`,
+			want: []string{
+				`foo/bar_baz.proto:5:3: warning: enum zero value name "STATUS_OK" should have the suffix "_UNSPECIFIED" [ENUM_ZERO_VALUE_UNSPECIFIED]`,
+			},
+		},
+		{
+			name:     "a file violating the style guide",
+			filename: "fooBar.proto",
+			input: `syntax = "proto3";
+package Foo.Bar;
+
+import "b.proto";
+import "a.proto";
+
+message song_request {
+  string SongName = 1;
+  string song_name_1 = 2;
+  map<string, int32> playCounts = 3;
+  oneof Choice {
+    int32 byId = 4;
+  }
+  enum status {
+    OK = 0;
+    status_ng = 1;
+  }
+}
+
+service foo_service {
+  rpc get_song(song_request) returns (song_request);
+}
+`,
+			want: []string{
+				`fooBar.proto:1:1: warning: file name "fooBar.proto" should be lower_snake_case.proto [FILE_NAMES_LOWER_SNAKE_CASE]`,
+				`fooBar.proto:2:1: warning: package name "Foo.Bar" should be in lowercase, like "foo.bar" [PACKAGE_NAME_LOWER_CASE]`,
+				`fooBar.proto:5:1: warning: import "a.proto" should be placed before "b.proto" [IMPORTS_SORTED]`,
+				`fooBar.proto:7:1: warning: message name "song_request" should be UpperCamelCase [MESSAGE_NAMES_UPPER_CAMEL_CASE]`,
+				`fooBar.proto:8:3: warning: field name "SongName" should be lower_snake_case [FIELD_NAMES_LOWER_SNAKE_CASE]`,
+				`fooBar.proto:9:3: warning: field name "song_name_1" should be lower_snake_case [FIELD_NAMES_LOWER_SNAKE_CASE]`,
+				`fooBar.proto:10:3: warning: field name "playCounts" should be lower_snake_case [FIELD_NAMES_LOWER_SNAKE_CASE]`,
+				`fooBar.proto:11:3: warning: oneof name "Choice" should be lower_snake_case [ONEOF_NAMES_LOWER_SNAKE_CASE]`,
+				`fooBar.proto:12:5: warning: field name "byId" should be lower_snake_case [FIELD_NAMES_LOWER_SNAKE_CASE]`,
+				`fooBar.proto:14:3: warning: enum name "status" should be UpperCamelCase [ENUM_NAMES_UPPER_CAMEL_CASE]`,
+				`fooBar.proto:15:5: warning: enum value name "OK" should be prefixed with "STATUS_" [ENUM_VALUE_NAMES_PREFIXED]`,
+				`fooBar.proto:15:5: warning: enum zero value name "OK" should have the suffix "_UNSPECIFIED" [ENUM_ZERO_VALUE_UNSPECIFIED]`,
+				`fooBar.proto:16:5: warning: enum value name "status_ng" should be UPPER_SNAKE_CASE [ENUM_VALUE_NAMES_UPPER_SNAKE_CASE]`,
+				`fooBar.proto:16:5: warning: enum value name "status_ng" should be prefixed with "STATUS_" [ENUM_VALUE_NAMES_PREFIXED]`,
+				`fooBar.proto:20:1: warning: service name "foo_service" should be UpperCamelCase [SERVICE_NAMES_UPPER_CAMEL_CASE]`,
+				`fooBar.proto:21:3: warning: rpc name "get_song" should be UpperCamelCase [RPC_NAMES_UPPER_CAMEL_CASE]`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			linter, err := lint.New(lint.StyleGuide(), nil)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			got := findingStrings(linter.Lint(parse(t, test.filename, test.input)))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, but want %v", got, test.want)
+			}
+		})
+	}
+}
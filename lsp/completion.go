@@ -0,0 +1,138 @@
+package lsp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+)
+
+// scalarTypes are the candidates of the type names which are not defined in the files.
+var scalarTypes = []string{
+	"bool",
+	"bytes",
+	"double",
+	"fixed32",
+	"fixed64",
+	"float",
+	"int32",
+	"int64",
+	"sfixed32",
+	"sfixed64",
+	"sint32",
+	"sint64",
+	"string",
+	"uint32",
+	"uint64",
+}
+
+// builtinOptions are the candidates of the option names defined in google/protobuf/descriptor.proto.
+var builtinOptions = []string{
+	"allow_alias",
+	"cc_enable_arenas",
+	"csharp_namespace",
+	"ctype",
+	"debug_redact",
+	"default",
+	"deprecated",
+	"go_package",
+	"idempotency_level",
+	"java_multiple_files",
+	"java_outer_classname",
+	"java_package",
+	"jstype",
+	"json_name",
+	"lazy",
+	"map_entry",
+	"objc_class_prefix",
+	"optimize_for",
+	"packed",
+	"php_namespace",
+	"ruby_package",
+	"swift_prefix",
+}
+
+var (
+	// optionNamePattern matches the text before the cursor in an option name,
+	// like "option java_" or "int32 a = 1 [deprecated = true, (my.".
+	optionNamePattern = regexp.MustCompile(`(?:^|[\s;{])option\s+\(?[\w.]*$|\[(?:[^\]]*,)?\s*\(?[\w.]*$`)
+	// extensionNamePattern matches the text before the cursor in the parenthesized name of an extension.
+	extensionNamePattern = regexp.MustCompile(`\([\w.]*$`)
+)
+
+func (s *Server) completion(params *TextDocumentPositionParams) ([]CompletionItem, error) {
+	snap, err := s.snapshot(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	m := snap.mappers[snap.doc.filename]
+	offset := snap.offset(params.Position)
+	line := m.text[m.offset(Position{Line: params.Position.Line}):offset]
+
+	if optionNamePattern.MatchString(line) {
+		return snap.optionNames(extensionNamePattern.MatchString(line)), nil
+	}
+	return snap.typeNames(), nil
+}
+
+// optionNames returns the candidates of the option names.
+// The extensions are only returned when the cursor is in the parentheses.
+func (s *snapshot) optionNames(inParens bool) []CompletionItem {
+	items := []CompletionItem{}
+	if !inParens {
+		for _, name := range builtinOptions {
+			items = append(items, CompletionItem{Label: name, Kind: completionItemKindProperty, Detail: "option"})
+		}
+	}
+	for _, symbol := range s.sortedSymbols() {
+		if symbol.Kind == linker.SymbolKindExtension {
+			items = append(items, CompletionItem{Label: symbol.FullName, Kind: completionItemKindProperty, Detail: "extension"})
+		}
+	}
+	return items
+}
+
+// typeNames returns the candidates of the type names, which are the scalar types and the defined messages and enums.
+// The names in the package of the document are relative to the package.
+func (s *snapshot) typeNames() []CompletionItem {
+	items := []CompletionItem{}
+	for _, name := range scalarTypes {
+		items = append(items, CompletionItem{Label: name, Kind: completionItemKindKeyword})
+	}
+
+	var prefix string
+	if s.file != nil {
+		if pkg := packageName(s.file); pkg != "" {
+			prefix = pkg + "."
+		}
+	}
+	for _, symbol := range s.sortedSymbols() {
+		kind := completionItemKindClass
+		switch symbol.Kind {
+		case linker.SymbolKindMessage:
+		case linker.SymbolKindEnum:
+			kind = completionItemKindEnum
+		default:
+			continue
+		}
+		items = append(items, CompletionItem{
+			Label:  strings.TrimPrefix(symbol.FullName, prefix),
+			Kind:   kind,
+			Detail: symbol.Kind.String() + " " + symbol.FullName,
+		})
+	}
+	return items
+}
+
+// sortedSymbols returns the symbols in the order of their full names.
+func (s *snapshot) sortedSymbols() []*linker.Symbol {
+	var symbols []*linker.Symbol
+	for _, symbol := range s.table.Symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].FullName < symbols[j].FullName
+	})
+	return symbols
+}
@@ -0,0 +1,60 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// diagnosticSource is the source of the diagnostics shown by the client.
+const diagnosticSource = "protoparser"
+
+// publishDiagnostics sends the diagnostics of the parse errors in the document.
+func (s *Server) publishDiagnostics(doc *document) error {
+	snap := s.workspace.analyze(doc)
+	return s.conn.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     doc.version,
+		Diagnostics: snap.diagnostics(),
+	})
+}
+
+// diagnostics returns the diagnostics of the parse errors in the document.
+func (s *snapshot) diagnostics() []Diagnostic {
+	diagnostics := []Diagnostic{}
+	if s.parseErr == nil {
+		return diagnostics
+	}
+
+	var errs meta.ErrorList
+	var single *meta.Error
+	switch {
+	case errors.As(s.parseErr, &errs):
+	case errors.As(s.parseErr, &single):
+		errs = meta.ErrorList{single}
+	default:
+		return append(diagnostics, Diagnostic{
+			Severity: diagnosticSeverityError,
+			Source:   diagnosticSource,
+			Message:  s.parseErr.Error(),
+		})
+	}
+
+	m := s.mappers[s.doc.filename]
+	for _, err := range errs {
+		// the error is reported on the token found there.
+		start, end := err.Pos.Offset, m.tokenEnd(err.Pos.Offset)
+		message := fmt.Sprintf("found %q but expected [%s]", m.text[start:end], err.Expected)
+		if start == end {
+			message = fmt.Sprintf("found the end of the file but expected [%s]", err.Expected)
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    Range{Start: m.position(start), End: m.position(end)},
+			Severity: diagnosticSeverityError,
+			Source:   diagnosticSource,
+			Message:  message,
+		})
+	}
+	return diagnostics
+}
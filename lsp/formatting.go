@@ -0,0 +1,25 @@
+package lsp
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/format"
+)
+
+// formatting returns the edit which replaces the whole document with the formatted one.
+// It returns no edits when the document can't be parsed or is already formatted.
+func (s *Server) formatting(params *DocumentFormattingParams) ([]TextEdit, error) {
+	doc, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	edits := []TextEdit{}
+	formatted, err := format.Source([]byte(doc.text))
+	if err != nil || string(formatted) == doc.text {
+		return edits, nil
+	}
+
+	m := newMapper(doc.text)
+	return append(edits, TextEdit{
+		Range:   Range{Start: m.position(0), End: m.position(len(doc.text))},
+		NewText: string(formatted),
+	}), nil
+}
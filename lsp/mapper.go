@@ -0,0 +1,66 @@
+package lsp
+
+import (
+	"unicode/utf8"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// mapper converts the byte offsets in a text into the LSP positions, which count UTF-16 code units, and vice versa.
+type mapper struct {
+	text  string
+	lines *meta.LineIndex
+}
+
+func newMapper(text string) *mapper {
+	return &mapper{text: text, lines: meta.NewLineIndex([]byte(text))}
+}
+
+// position returns the LSP position of the offset.
+func (m *mapper) position(offset int) Position {
+	pos := m.lines.Position(offset)
+	return Position{Line: pos.Line - 1, Character: m.lines.UTF16Column(pos.Line, pos.Column) - 1}
+}
+
+// offset returns the byte offset of the LSP position. A position beyond the end of a line is the end of the line.
+func (m *mapper) offset(pos Position) int {
+	line := pos.Line + 1
+	return m.lines.Offset(line, m.lines.Column(line, pos.Character+1))
+}
+
+// rangeOf returns the LSP range from the first to the last character of the meta.
+func (m *mapper) rangeOf(mt meta.Meta) Range {
+	return Range{
+		Start: m.position(mt.Pos.Offset),
+		End:   m.position(m.end(mt.LastPos.Offset)),
+	}
+}
+
+// end returns the offset next to the character at the offset.
+func (m *mapper) end(offset int) int {
+	if len(m.text) <= offset {
+		return len(m.text)
+	}
+	_, size := utf8.DecodeRuneInString(m.text[offset:])
+	return offset + size
+}
+
+// tokenEnd returns the offset next to the token at the offset,
+// which is the run of the letters, the digits and the underscores, or a single character.
+func (m *mapper) tokenEnd(offset int) int {
+	if len(m.text) <= offset {
+		return len(m.text)
+	}
+	end := offset
+	for end < len(m.text) && isWordChar(m.text[end]) {
+		end++
+	}
+	if end == offset {
+		return m.end(offset)
+	}
+	return end
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,148 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/astutil"
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// leadingComments returns the comments placed at the beginning of the node which Symbol.Node holds.
+func leadingComments(node parser.Visitee) []*parser.Comment {
+	switch n := node.(type) {
+	case *parser.Message:
+		return n.Comments
+	case *parser.GroupField:
+		return n.Comments
+	case *parser.Enum:
+		return n.Comments
+	case *parser.Service:
+		return n.Comments
+	case *parser.Field:
+		return n.Comments
+	}
+	return nil
+}
+
+func covers(mt meta.Meta, offset int) bool {
+	return mt.Pos.Line != 0 && mt.Pos.Offset <= offset && offset <= mt.LastPos.Offset
+}
+
+// symbolAt returns the symbol whose name or the reference to which is at the offset in the document,
+// along with the meta of the name there.
+func (s *snapshot) symbolAt(offset int) (*linker.Symbol, meta.Meta) {
+	filename := s.doc.filename
+	for _, ref := range s.table.References {
+		if ref.File.Filename != filename || ref.Symbol == nil {
+			continue
+		}
+		if mt := astutil.TypeNameMeta(ref.Node); covers(mt, offset) {
+			return ref.Symbol, mt
+		}
+	}
+	for _, symbol := range s.table.Symbols {
+		if symbol.File == nil || symbol.File.Filename != filename {
+			continue
+		}
+		if mt := astutil.NameMeta(symbol.Node); covers(mt, offset) {
+			return symbol, mt
+		}
+	}
+	return nil, meta.Meta{}
+}
+
+// location returns the location of the meta in the file.
+func (s *snapshot) location(filename string, mt meta.Meta) (Location, bool) {
+	m := s.mapper(filename)
+	if m == nil {
+		return Location{}, false
+	}
+	return Location{URI: filenameToURI(filename), Range: m.rangeOf(mt)}, true
+}
+
+// definitionOf returns the location of the name of the symbol.
+func (s *snapshot) definitionOf(symbol *linker.Symbol) (Location, bool) {
+	return s.location(symbol.File.Filename, astutil.NameMeta(symbol.Node))
+}
+
+// offset returns the offset of the position in the document.
+func (s *snapshot) offset(pos Position) int {
+	return s.mappers[s.doc.filename].offset(pos)
+}
+
+func (s *Server) definition(params *TextDocumentPositionParams) ([]Location, error) {
+	snap, err := s.snapshot(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	locations := []Location{}
+	symbol, _ := snap.symbolAt(snap.offset(params.Position))
+	if symbol == nil {
+		return locations, nil
+	}
+	if loc, ok := snap.definitionOf(symbol); ok {
+		locations = append(locations, loc)
+	}
+	return locations, nil
+}
+
+func (s *Server) references(params *ReferenceParams) ([]Location, error) {
+	snap, err := s.snapshot(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	locations := []Location{}
+	symbol, _ := snap.symbolAt(snap.offset(params.Position))
+	if symbol == nil {
+		return locations, nil
+	}
+	if params.Context.IncludeDeclaration {
+		if loc, ok := snap.definitionOf(symbol); ok {
+			locations = append(locations, loc)
+		}
+	}
+	for _, ref := range snap.table.References {
+		if ref.Symbol != symbol {
+			continue
+		}
+		if loc, ok := snap.location(ref.File.Filename, astutil.TypeNameMeta(ref.Node)); ok {
+			locations = append(locations, loc)
+		}
+	}
+	return locations, nil
+}
+
+func (s *Server) hover(params *TextDocumentPositionParams) (*Hover, error) {
+	snap, err := s.snapshot(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	symbol, mt := snap.symbolAt(snap.offset(params.Position))
+	if symbol == nil {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("```proto\n" + symbol.Kind.String() + " " + symbol.FullName + "\n```")
+	var lines []string
+	for _, comment := range leadingComments(symbol.Node) {
+		for _, line := range comment.Lines() {
+			line = strings.TrimSpace(line)
+			if comment.IsCStyle() {
+				line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+			}
+			lines = append(lines, line)
+		}
+	}
+	if text := strings.TrimSpace(strings.Join(lines, "\n")); text != "" {
+		b.WriteString("\n\n" + text)
+	}
+
+	r := snap.mappers[snap.doc.filename].rangeOf(mt)
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: b.String()},
+		Range:    &r,
+	}, nil
+}
@@ -0,0 +1,193 @@
+package lsp
+
+// The types of the Language Server Protocol, which are only the parts used by the Server.
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/
+
+// Position is a zero-based position in a document. Character counts UTF-16 code units.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a range in a document. The End is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range in a document.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// TextDocumentIdentifier identifies a document.
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// TextDocumentItem is an opened document.
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+// VersionedTextDocumentIdentifier identifies a version of a document.
+type VersionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+}
+
+// TextDocumentContentChangeEvent is a change of a document. Only the full content is supported.
+type TextDocumentContentChangeEvent struct {
+	Text string `json:"text"`
+}
+
+// InitializeParams is the params of "initialize".
+type InitializeParams struct {
+	RootURI string `json:"rootUri,omitempty"`
+}
+
+// InitializeResult is the result of "initialize".
+type InitializeResult struct {
+	Capabilities ServerCapabilities `json:"capabilities"`
+	ServerInfo   *ServerInfo        `json:"serverInfo,omitempty"`
+}
+
+// ServerInfo is the information of the server.
+type ServerInfo struct {
+	Name string `json:"name"`
+}
+
+// ServerCapabilities holds the features which the server provides.
+type ServerCapabilities struct {
+	// TextDocumentSync is the kind of the synchronization, which is textDocumentSyncKindFull.
+	TextDocumentSync           int                `json:"textDocumentSync"`
+	DocumentSymbolProvider     bool               `json:"documentSymbolProvider"`
+	DefinitionProvider         bool               `json:"definitionProvider"`
+	ReferencesProvider         bool               `json:"referencesProvider"`
+	HoverProvider              bool               `json:"hoverProvider"`
+	CompletionProvider         *CompletionOptions `json:"completionProvider,omitempty"`
+	DocumentFormattingProvider bool               `json:"documentFormattingProvider"`
+}
+
+// CompletionOptions is the options of the completion.
+type CompletionOptions struct {
+	TriggerCharacters []string `json:"triggerCharacters,omitempty"`
+}
+
+// textDocumentSyncKindFull means that the documents are synced by sending the full content.
+const textDocumentSyncKindFull = 1
+
+// DidOpenTextDocumentParams is the params of "textDocument/didOpen".
+type DidOpenTextDocumentParams struct {
+	TextDocument TextDocumentItem `json:"textDocument"`
+}
+
+// DidChangeTextDocumentParams is the params of "textDocument/didChange".
+type DidChangeTextDocumentParams struct {
+	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+// DidCloseTextDocumentParams is the params of "textDocument/didClose".
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// TextDocumentPositionParams is the params of the requests at a position in a document.
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// ReferenceParams is the params of "textDocument/references".
+type ReferenceParams struct {
+	TextDocumentPositionParams
+	Context ReferenceContext `json:"context"`
+}
+
+// ReferenceContext is the context of "textDocument/references".
+type ReferenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
+
+// DocumentSymbolParams is the params of "textDocument/documentSymbol".
+type DocumentSymbolParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// DocumentFormattingParams is the params of "textDocument/formatting".
+type DocumentFormattingParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// PublishDiagnosticsParams is the params of "textDocument/publishDiagnostics".
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     int          `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// Diagnostic is a problem in a document.
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+// diagnosticSeverityError is the severity of an error.
+const diagnosticSeverityError = 1
+
+// DocumentSymbol is a symbol in the outline of a document.
+type DocumentSymbol struct {
+	Name           string           `json:"name"`
+	Detail         string           `json:"detail,omitempty"`
+	Kind           int              `json:"kind"`
+	Range          Range            `json:"range"`
+	SelectionRange Range            `json:"selectionRange"`
+	Children       []DocumentSymbol `json:"children,omitempty"`
+}
+
+// The SymbolKind values used by the outline.
+const (
+	symbolKindEnum      = 10
+	symbolKindInterface = 11
+	symbolKindStruct    = 23
+)
+
+// Hover is the result of "textDocument/hover".
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+// MarkupContent is a text in Markdown.
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// CompletionItem is a candidate of the completion.
+type CompletionItem struct {
+	Label  string `json:"label"`
+	Kind   int    `json:"kind,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// The CompletionItemKind values used by the completion.
+const (
+	completionItemKindProperty = 10
+	completionItemKindClass    = 7
+	completionItemKindEnum     = 13
+	completionItemKindKeyword  = 14
+)
+
+// TextEdit is an edit of a document.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
@@ -0,0 +1,259 @@
+// Package lsp implements a language server for .proto files, which speaks the Language Server Protocol over a stream.
+//
+// The server provides the diagnostics of the parse errors, the outline of the messages, the enums and the services,
+// the navigation between the type names and their definitions, the hover of their comments,
+// the completion of the type names and the option names, and the formatting.
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+)
+
+// Server is a language server. A Server serves one client.
+type Server struct {
+	conn      *conn
+	workspace *workspace
+
+	initialized bool
+	shutdown    bool
+}
+
+// Config is a config for NewServer.
+type Config struct {
+	importPaths []string
+}
+
+// Option is an option for Config.
+type Option func(*Config)
+
+// WithImportPaths is an option to set the directories in which the imports are searched, like protoc's -I.
+// The root of the workspace given by the client and the directory of each document are searched after them.
+func WithImportPaths(paths ...string) Option {
+	return func(c *Config) {
+		c.importPaths = append(c.importPaths, paths...)
+	}
+}
+
+// NewServer creates a new Server.
+func NewServer(options ...Option) *Server {
+	config := &Config{}
+	for _, opt := range options {
+		opt(config)
+	}
+
+	w := &workspace{docs: make(map[string]*document)}
+	for _, p := range config.importPaths {
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		w.importPaths = append(w.importPaths, filepath.ToSlash(p))
+	}
+	return &Server{workspace: w}
+}
+
+// Serve reads the messages from r and writes the responses and the notifications to w, until the client sends "exit".
+// It returns nil when the stream is closed after "shutdown", and an error when the stream is broken.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+	for {
+		msg, err := s.conn.read()
+		if err == io.EOF && s.shutdown {
+			return nil
+		}
+		if err != nil {
+			if _, ok := err.(*ResponseError); ok {
+				// the content is broken, but the stream is still framed.
+				null := json.RawMessage("null")
+				if err := s.conn.reply(&null, nil, err); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		result, err := s.handle(msg)
+		if msg.ID == nil {
+			// a notification has no response.
+			continue
+		}
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+// handle handles the request or the notification, and returns the result of the request.
+func (s *Server) handle(msg *message) (interface{}, error) {
+	if !s.initialized && msg.Method != "initialize" {
+		return nil, errorf(codeServerNotInitialized, "the server is not initialized")
+	}
+	if s.shutdown {
+		return nil, errorf(codeInvalidRequest, "the server is shut down")
+	}
+
+	switch msg.Method {
+	case "initialize":
+		var params InitializeParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(&params), nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didOpen(&params)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didChange(&params)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didClose(&params)
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.documentSymbol(&params)
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(&params)
+	case "textDocument/references":
+		var params ReferenceParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.references(&params)
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(&params)
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.completion(&params)
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.formatting(&params)
+	}
+	return nil, errorf(codeMethodNotFound, "method %q is not supported", msg.Method)
+}
+
+func unmarshalParams(msg *message, params interface{}) error {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return errorf(codeInvalidParams, "%v", err)
+	}
+	return nil
+}
+
+func (s *Server) initialize(params *InitializeParams) *InitializeResult {
+	s.initialized = true
+	if root, err := uriToFilename(params.RootURI); err == nil {
+		s.workspace.importPaths = append(s.workspace.importPaths, root)
+	}
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync:       textDocumentSyncKindFull,
+			DocumentSymbolProvider: true,
+			DefinitionProvider:     true,
+			ReferencesProvider:     true,
+			HoverProvider:          true,
+			CompletionProvider: &CompletionOptions{
+				TriggerCharacters: []string{".", "("},
+			},
+			DocumentFormattingProvider: true,
+		},
+		ServerInfo: &ServerInfo{Name: "protoparser-lsp"},
+	}
+}
+
+func (s *Server) didOpen(params *DidOpenTextDocumentParams) error {
+	filename, err := uriToFilename(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	doc := &document{
+		uri:      params.TextDocument.URI,
+		filename: filename,
+		version:  params.TextDocument.Version,
+		text:     params.TextDocument.Text,
+	}
+	s.workspace.docs[filename] = doc
+	return s.publishDiagnostics(doc)
+}
+
+func (s *Server) didChange(params *DidChangeTextDocumentParams) error {
+	doc, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	if len(params.ContentChanges) == 0 {
+		return nil
+	}
+	// the full content is sent, so the last change is the current one.
+	doc.text = params.ContentChanges[len(params.ContentChanges)-1].Text
+	doc.version = params.TextDocument.Version
+	return s.publishDiagnostics(doc)
+}
+
+func (s *Server) didClose(params *DidCloseTextDocumentParams) error {
+	doc, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	delete(s.workspace.docs, doc.filename)
+	return s.conn.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Diagnostics: []Diagnostic{},
+	})
+}
+
+// document returns the opened document of the URI.
+func (s *Server) document(uri string) (*document, error) {
+	filename, err := uriToFilename(uri)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := s.workspace.docs[filename]
+	if !ok {
+		return nil, errorf(codeInvalidParams, "document %q is not opened", uri)
+	}
+	return doc, nil
+}
+
+// snapshot returns the analysis of the opened document of the URI.
+func (s *Server) snapshot(uri string) (*snapshot, error) {
+	doc, err := s.document(uri)
+	if err != nil {
+		return nil, err
+	}
+	return s.workspace.analyze(doc), nil
+}
@@ -0,0 +1,511 @@
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/yoheimuta/go-protoparser/v4/lsp"
+)
+
+// client is an in-process JSON-RPC client of the Server.
+type client struct {
+	t      *testing.T
+	w      io.WriteCloser
+	nextID int
+	// responses receives the responses, and notifications receives the notifications.
+	responses     chan *rpcMessage
+	notifications chan *rpcMessage
+	done          chan error
+}
+
+type rpcMessage struct {
+	ID     *int               `json:"id,omitempty"`
+	Method string             `json:"method,omitempty"`
+	Params json.RawMessage    `json:"params,omitempty"`
+	Result json.RawMessage    `json:"result,omitempty"`
+	Error  *lsp.ResponseError `json:"error,omitempty"`
+}
+
+// newClient starts the server and initializes it with the root directory.
+func newClient(t *testing.T, root string, options ...lsp.Option) *client {
+	t.Helper()
+	serverR, clientW := io.Pipe()
+	clientR, serverW := io.Pipe()
+	c := &client{
+		t:             t,
+		w:             clientW,
+		responses:     make(chan *rpcMessage, 16),
+		notifications: make(chan *rpcMessage, 16),
+		done:          make(chan error, 1),
+	}
+	go func() {
+		c.done <- lsp.NewServer(options...).Serve(serverR, serverW)
+		_ = serverW.Close()
+	}()
+	go c.readLoop(clientR)
+	t.Cleanup(func() {
+		_ = clientW.Close()
+	})
+
+	var result lsp.InitializeResult
+	c.call("initialize", &lsp.InitializeParams{RootURI: uri(root)}, &result)
+	c.notify("initialized", struct{}{})
+	return c
+}
+
+func (c *client) readLoop(r io.Reader) {
+	tr := textproto.NewReader(bufio.NewReader(r))
+	for {
+		header, err := tr.ReadMIMEHeader()
+		if err != nil {
+			return
+		}
+		length, _ := strconv.Atoi(header.Get("Content-Length"))
+		body := make([]byte, length)
+		if _, err := io.ReadFull(tr.R, body); err != nil {
+			return
+		}
+		var msg rpcMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			c.t.Errorf("got the invalid message %s", body)
+			return
+		}
+		if msg.Method != "" {
+			c.notifications <- &msg
+		} else {
+			c.responses <- &msg
+		}
+	}
+}
+
+func (c *client) send(id *int, method string, params interface{}) {
+	c.t.Helper()
+	raw, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatalf("failed to marshal: %v", err)
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+		"params":  json.RawMessage(raw),
+	})
+	if id == nil {
+		body, _ = json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  method,
+			"params":  json.RawMessage(raw),
+		})
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatalf("failed to write: %v", err)
+	}
+}
+
+// call sends the request and decodes the result of the response into result.
+// It returns the error of the response.
+func (c *client) call(method string, params, result interface{}) *lsp.ResponseError {
+	c.t.Helper()
+	c.nextID++
+	id := c.nextID
+	c.send(&id, method, params)
+
+	select {
+	case msg := <-c.responses:
+		if msg.ID == nil || *msg.ID != id {
+			c.t.Fatalf("got the response %v, but want the one to %d", msg.ID, id)
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if err := json.Unmarshal(msg.Result, result); err != nil {
+			c.t.Fatalf("failed to unmarshal %s: %v", msg.Result, err)
+		}
+	case <-time.After(10 * time.Second):
+		c.t.Fatalf("timed out waiting for the response to %s", method)
+	}
+	return nil
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+	c.send(nil, method, params)
+}
+
+// diagnostics waits for the next diagnostics.
+func (c *client) diagnostics() *lsp.PublishDiagnosticsParams {
+	c.t.Helper()
+	select {
+	case msg := <-c.notifications:
+		if msg.Method != "textDocument/publishDiagnostics" {
+			c.t.Fatalf("got %s, but want textDocument/publishDiagnostics", msg.Method)
+		}
+		var params lsp.PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatalf("failed to unmarshal: %v", err)
+		}
+		return &params
+	case <-time.After(10 * time.Second):
+		c.t.Fatalf("timed out waiting for the diagnostics")
+	}
+	return nil
+}
+
+// open opens the document and returns its diagnostics.
+func (c *client) open(filename, text string) *lsp.PublishDiagnosticsParams {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri(filename), LanguageID: "proto", Version: 1, Text: text},
+	})
+	return c.diagnostics()
+}
+
+func uri(filename string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filename)}).String()
+}
+
+func writeFile(t *testing.T, filename, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(filename, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+}
+
+func at(uri string, line, character int) lsp.TextDocumentPositionParams {
+	return lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Position:     lsp.Position{Line: line, Character: character},
+	}
+}
+
+func span(line, start, end int) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: line, Character: start},
+		End:   lsp.Position{Line: line, Character: end},
+	}
+}
+
+const typesProto = `syntax = "proto3";
+package pkg;
+
+// User is a user.
+// It has a name.
+message User {
+  string name = 1;
+}
+
+enum Role {
+  ROLE_UNSPECIFIED = 0;
+}
+`
+
+const mainProto = `syntax = "proto3";
+package pkg;
+
+import "types.proto";
+
+message Request {
+  User user = 1;
+  repeated User friends = 2;
+  message Nested {
+    Role role = 1;
+  }
+}
+
+service Users {
+  rpc Get(Request) returns (User);
+}
+`
+
+func TestServer(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "types.proto"), typesProto)
+	mainFile := filepath.Join(root, "main.proto")
+	mainURI := uri(mainFile)
+	typesURI := uri(filepath.Join(root, "types.proto"))
+
+	c := newClient(t, root)
+	if diagnostics := c.open(mainFile, mainProto); len(diagnostics.Diagnostics) != 0 {
+		t.Errorf("got %v, but want no diagnostics", diagnostics.Diagnostics)
+	}
+
+	t.Run("documentSymbol", func(t *testing.T) {
+		var got []lsp.DocumentSymbol
+		c.call("textDocument/documentSymbol", &lsp.DocumentSymbolParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: mainURI},
+		}, &got)
+
+		want := []lsp.DocumentSymbol{
+			{
+				Name:   "Request",
+				Detail: "message",
+				Kind:   23,
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 5, Character: 0},
+					End:   lsp.Position{Line: 11, Character: 1},
+				},
+				SelectionRange: span(5, 8, 15),
+				Children: []lsp.DocumentSymbol{
+					{
+						Name:   "Nested",
+						Detail: "message",
+						Kind:   23,
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 8, Character: 2},
+							End:   lsp.Position{Line: 10, Character: 3},
+						},
+						SelectionRange: span(8, 10, 16),
+					},
+				},
+			},
+			{
+				Name:   "Users",
+				Detail: "service",
+				Kind:   11,
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 13, Character: 0},
+					End:   lsp.Position{Line: 15, Character: 1},
+				},
+				SelectionRange: span(13, 8, 13),
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, but want %+v", got, want)
+		}
+	})
+
+	t.Run("definition", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			position lsp.TextDocumentPositionParams
+			want     []lsp.Location
+		}{
+			{
+				name:     "a type in the imported file",
+				position: at(mainURI, 6, 4),
+				want:     []lsp.Location{{URI: typesURI, Range: span(5, 8, 12)}},
+			},
+			{
+				name:     "a type of the rpc",
+				position: at(mainURI, 14, 12),
+				want:     []lsp.Location{{URI: mainURI, Range: span(5, 8, 15)}},
+			},
+			{
+				name:     "a definition",
+				position: at(mainURI, 8, 12),
+				want:     []lsp.Location{{URI: mainURI, Range: span(8, 10, 16)}},
+			},
+			{
+				name:     "a field name",
+				position: at(mainURI, 6, 8),
+				want:     []lsp.Location{},
+			},
+		}
+		for _, test := range tests {
+			test := test
+			t.Run(test.name, func(t *testing.T) {
+				var got []lsp.Location
+				c.call("textDocument/definition", &test.position, &got)
+				if !reflect.DeepEqual(got, test.want) {
+					t.Errorf("got %v, but want %v", got, test.want)
+				}
+			})
+		}
+	})
+
+	t.Run("references", func(t *testing.T) {
+		var got []lsp.Location
+		c.call("textDocument/references", &lsp.ReferenceParams{
+			TextDocumentPositionParams: at(mainURI, 7, 12),
+			Context:                    lsp.ReferenceContext{IncludeDeclaration: true},
+		}, &got)
+
+		want := []lsp.Location{
+			{URI: typesURI, Range: span(5, 8, 12)},
+			{URI: mainURI, Range: span(6, 2, 6)},
+			{URI: mainURI, Range: span(7, 11, 15)},
+			{URI: mainURI, Range: span(14, 28, 32)},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, but want %v", got, want)
+		}
+	})
+
+	t.Run("hover", func(t *testing.T) {
+		var got lsp.Hover
+		c.call("textDocument/hover", at(mainURI, 6, 3), &got)
+
+		r := span(6, 2, 6)
+		want := lsp.Hover{
+			Contents: lsp.MarkupContent{
+				Kind:  "markdown",
+				Value: "```proto\nmessage pkg.User\n```\n\nUser is a user.\nIt has a name.",
+			},
+			Range: &r,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, but want %v", got, want)
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			text     string
+			position lsp.Position
+			want     []string
+		}{
+			{
+				name:     "type names",
+				text:     "syntax = \"proto3\";\npackage pkg;\nimport \"types.proto\";\nmessage M {\n  \n}\n",
+				position: lsp.Position{Line: 4, Character: 2},
+				want: []string{
+					"bool", "bytes", "double", "fixed32", "fixed64", "float", "int32", "int64",
+					"sfixed32", "sfixed64", "sint32", "sint64", "string", "uint32", "uint64",
+					"M", "Role", "User",
+				},
+			},
+			{
+				name:     "option names of a field",
+				text:     "syntax = \"proto3\";\nmessage M {\n  int32 a = 1 [deprecated = true, js\n}\n",
+				position: lsp.Position{Line: 2, Character: 36},
+				want: []string{
+					"allow_alias", "cc_enable_arenas", "csharp_namespace", "ctype", "debug_redact", "default",
+					"deprecated", "go_package", "idempotency_level", "java_multiple_files", "java_outer_classname",
+					"java_package", "jstype", "json_name", "lazy", "map_entry", "objc_class_prefix", "optimize_for",
+					"packed", "php_namespace", "ruby_package", "swift_prefix",
+				},
+			},
+			{
+				name:     "extension names",
+				text:     "syntax = \"proto2\";\nextend M { optional int32 ext = 100; }\nmessage M { extensions 100; }\noption (",
+				position: lsp.Position{Line: 3, Character: 8},
+				want:     []string{"ext"},
+			},
+		}
+		for i, test := range tests {
+			test := test
+			filename := filepath.Join(root, fmt.Sprintf("completion%d.proto", i))
+			t.Run(test.name, func(t *testing.T) {
+				c.open(filename, test.text)
+
+				var items []lsp.CompletionItem
+				c.call("textDocument/completion", at(uri(filename), test.position.Line, test.position.Character), &items)
+				var got []string
+				for _, item := range items {
+					got = append(got, item.Label)
+				}
+				if !reflect.DeepEqual(got, test.want) {
+					t.Errorf("got %v, but want %v", got, test.want)
+				}
+			})
+		}
+	})
+
+	t.Run("formatting", func(t *testing.T) {
+		filename := filepath.Join(root, "unformatted.proto")
+		c.open(filename, "syntax='proto3';\nmessage  A{int32 a=1;}")
+
+		var got []lsp.TextEdit
+		c.call("textDocument/formatting", &lsp.DocumentFormattingParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri(filename)},
+		}, &got)
+		want := []lsp.TextEdit{
+			{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 0, Character: 0},
+					End:   lsp.Position{Line: 1, Character: 22},
+				},
+				NewText: "syntax = \"proto3\";\n\nmessage A {\n  int32 a = 1;\n}\n",
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, but want %v", got, want)
+		}
+	})
+
+	if err := c.call("shutdown", nil, new(interface{})); err != nil {
+		t.Errorf("got err %v", err)
+	}
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Errorf("got err %v, but want nil", err)
+	}
+}
+
+func TestServer_diagnostics(t *testing.T) {
+	root := t.TempDir()
+	filename := filepath.Join(root, "a.proto")
+	c := newClient(t, root)
+
+	got := c.open(filename, "syntax = \"proto3\";\nmessage A {\n  int32 a = ;\n  string é = 2;\n}\n")
+	want := []lsp.Diagnostic{
+		{
+			Range:    span(2, 12, 13),
+			Severity: 1,
+			Source:   "protoparser",
+			Message:  `found ";" but expected [fieldNumber]`,
+		},
+		{
+			Range:    span(3, 9, 10),
+			Severity: 1,
+			Source:   "protoparser",
+			Message:  `found "é" but expected [fieldName]`,
+		},
+	}
+	if !reflect.DeepEqual(got.Diagnostics, want) {
+		t.Errorf("got %v, but want %v", got.Diagnostics, want)
+	}
+
+	c.notify("textDocument/didChange", &lsp.DidChangeTextDocumentParams{
+		TextDocument:   lsp.VersionedTextDocumentIdentifier{URI: uri(filename), Version: 2},
+		ContentChanges: []lsp.TextDocumentContentChangeEvent{{Text: "syntax = \"proto3\";\nmessage A {}\n"}},
+	})
+	if got := c.diagnostics(); got.Version != 2 || len(got.Diagnostics) != 0 {
+		t.Errorf("got %v, but want no diagnostics of the version 2", got)
+	}
+}
+
+func TestServer_errors(t *testing.T) {
+	root := t.TempDir()
+	c := newClient(t, root)
+
+	tests := []struct {
+		name     string
+		method   string
+		params   interface{}
+		wantCode int
+	}{
+		{
+			name:     "an unsupported method",
+			method:   "workspace/symbol",
+			params:   struct{}{},
+			wantCode: -32601,
+		},
+		{
+			name:     "a document which is not opened",
+			method:   "textDocument/hover",
+			params:   at(uri(filepath.Join(root, "unknown.proto")), 0, 0),
+			wantCode: -32602,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := c.call(test.method, test.params, new(interface{}))
+			if err == nil || err.Code != test.wantCode {
+				t.Errorf("got %v, but want the code %d", err, test.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package lsp
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// documentSymbol returns the outline of the messages, the enums and the services in the document.
+func (s *Server) documentSymbol(params *DocumentSymbolParams) ([]DocumentSymbol, error) {
+	snap, err := s.snapshot(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	symbols := []DocumentSymbol{}
+	if snap.file == nil {
+		return symbols, nil
+	}
+	return append(symbols, snap.outline(snap.file.Proto.ProtoBody)...), nil
+}
+
+// outline returns the symbols of the messages, the enums and the services in the body.
+func (s *snapshot) outline(body []parser.Visitee) []DocumentSymbol {
+	m := s.mappers[s.doc.filename]
+	var symbols []DocumentSymbol
+	for _, stmt := range body {
+		var symbol DocumentSymbol
+		switch n := stmt.(type) {
+		case *parser.Message:
+			symbol = newDocumentSymbol(m, n.MessageName, "message", symbolKindStruct, n.Meta, n.MessageNameMeta)
+			symbol.Children = s.outline(n.MessageBody)
+		case *parser.Enum:
+			symbol = newDocumentSymbol(m, n.EnumName, "enum", symbolKindEnum, n.Meta, n.EnumNameMeta)
+		case *parser.Service:
+			symbol = newDocumentSymbol(m, n.ServiceName, "service", symbolKindInterface, n.Meta, n.ServiceNameMeta)
+		default:
+			continue
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+func newDocumentSymbol(m *mapper, name, detail string, kind int, node, nameMeta meta.Meta) DocumentSymbol {
+	return DocumentSymbol{
+		Name:           name,
+		Detail:         detail,
+		Kind:           kind,
+		Range:          m.rangeOf(node),
+		SelectionRange: m.rangeOf(nameMeta),
+	}
+}
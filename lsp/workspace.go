@@ -0,0 +1,142 @@
+package lsp
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/fileset"
+	"github.com/yoheimuta/go-protoparser/v4/linker"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// document is an opened document.
+type document struct {
+	uri      string
+	filename string
+	version  int
+	text     string
+}
+
+// workspace holds the opened documents, which take precedence over the files on the disk.
+type workspace struct {
+	importPaths []string
+	// docs holds the opened documents keyed by their filenames.
+	docs map[string]*document
+}
+
+// uriToFilename returns the slash-separated absolute filename of the file URI.
+func uriToFilename(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", errorf(codeInvalidParams, "unsupported URI %q", uri)
+	}
+	return path.Clean(u.Path), nil
+}
+
+// filenameToURI returns the file URI of the slash-separated absolute filename.
+func filenameToURI(filename string) string {
+	return (&url.URL{Scheme: "file", Path: filename}).String()
+}
+
+// open opens the file with the name, which is a slash-separated absolute filename.
+func (w *workspace) open(name string) (io.ReadCloser, error) {
+	if doc, ok := w.docs[name]; ok {
+		return ioutil.NopCloser(strings.NewReader(doc.text)), nil
+	}
+	return os.Open(filepath.FromSlash(name))
+}
+
+// readFile returns the content of the file with the name.
+func (w *workspace) readFile(name string) (string, error) {
+	rc, err := w.open(name)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	b, err := ioutil.ReadAll(rc)
+	return string(b), err
+}
+
+// snapshot is the result of the analysis of a document.
+type snapshot struct {
+	doc *document
+	// file is the parsed document. It's nil when the document can't be parsed at all.
+	file *fileset.File
+	// parseErr is the error in the document.
+	parseErr error
+	// table is the symbol table of the document and the files it imports.
+	table *linker.Table
+
+	workspace *workspace
+	mappers   map[string]*mapper
+}
+
+// analyze parses the document along with the files it imports, and links them.
+// The directory of the document is searched after the import paths.
+func (w *workspace) analyze(doc *document) *snapshot {
+	importPaths := append(append([]string{}, w.importPaths...), path.Dir(doc.filename))
+	set, err := fileset.Parse(
+		[]string{doc.filename},
+		fileset.WithImportPaths(importPaths...),
+		fileset.WithOpener(w.open),
+		fileset.WithParseOptions(
+			protoparser.WithErrorRecovery(true),
+			protoparser.WithDetailedPositions(true),
+		),
+	)
+
+	s := &snapshot{
+		doc:       doc,
+		workspace: w,
+		mappers:   map[string]*mapper{doc.filename: newMapper(doc.text)},
+	}
+	root := set.Roots[0]
+	s.file = set.Lookup(root)
+	var errs fileset.ErrorList
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			var ferr *fileset.FileError
+			if errors.As(e, &ferr) && ferr.Path == root {
+				s.parseErr = ferr.Err
+			}
+		}
+	}
+	// the errors are ignored, because the unresolved references are just not navigated.
+	s.table, _ = linker.LinkFileSet(set)
+	return s
+}
+
+// mapper returns the mapper of the file with the filename, or nil if the file can't be read.
+func (s *snapshot) mapper(filename string) *mapper {
+	if m, ok := s.mappers[filename]; ok {
+		return m
+	}
+	var m *mapper
+	if text, err := s.workspace.readFile(filename); err == nil {
+		m = newMapper(text)
+	}
+	s.mappers[filename] = m
+	return m
+}
+
+// packageName returns the name of the package statement in the file.
+func packageName(file *fileset.File) string {
+	for _, stmt := range file.Proto.ProtoBody {
+		if pkg, ok := stmt.(*parser.Package); ok {
+			return pkg.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package protoparser
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// Source is a file which ParseFiles parses.
+type Source struct {
+	// Filename is the name of the file, which is set to the positions.
+	Filename string
+	// Reader is the content of the file. When it's nil, the file of the Filename is opened.
+	Reader io.Reader
+}
+
+// FileError is the error returned when a file can't be opened or parsed.
+type FileError struct {
+	// Filename is the name of the file.
+	Filename string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Filename, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList is a list of the errors found by ParseFiles, in the order of the sources.
+type ErrorList []*FileError
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// ParseFiles parses the files concurrently, at most as many at once as WithConcurrency sets.
+// The options are applied to each file, except that WithFilename and WithContext are replaced with the Filename
+// of the Source and the ctx.
+//
+// It returns the Protos in the order of the sources. It keeps on parsing after an error,
+// and returns the Protos of the files parsed successfully along with an ErrorList, whose Protos are nil
+// unless WithErrorRecovery returns the partial ones.
+// When the ctx is done, the files being parsed are stopped, the ones which are not started yet are skipped,
+// and ParseFiles returns the ctx.Err().
+func ParseFiles(ctx context.Context, sources []Source, options ...Option) ([]*parser.Proto, error) {
+	config := newParseConfig(options)
+	config.ctx = ctx
+	concurrency := config.concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	protos := make([]*parser.Proto, len(sources))
+	errs := make([]error, len(sources))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				protos[i], errs[i] = parseSource(sources[i], *config)
+			}
+		}()
+	}
+feed:
+	for i := range sources {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return protos, err
+	}
+	var list ErrorList
+	for i, err := range errs {
+		if err != nil {
+			list = append(list, &FileError{Filename: sources[i].Filename, Err: err})
+		}
+	}
+	if 0 < len(list) {
+		return protos, list
+	}
+	return protos, nil
+}
+
+// parseSource parses the source. The config is copied to set the filename.
+func parseSource(source Source, config ParseConfig) (*parser.Proto, error) {
+	config.filename = source.Filename
+	if source.Reader != nil {
+		return parse(source.Reader, &config)
+	}
+
+	f, err := os.Open(source.Filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return parse(f, &config)
+}
@@ -0,0 +1,147 @@
+package protoparser_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+func TestParseFiles(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("_testdata", "*.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sources []protoparser.Source
+	var wants []*parser.Proto
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := protoparser.Parse(f, protoparser.WithFilename(file))
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, protoparser.Source{Filename: file})
+		wants = append(wants, want)
+	}
+	want, err := protoparser.Parse(strings.NewReader(`syntax = "proto3";`), protoparser.WithFilename("reader.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wants = append(wants, want)
+
+	for _, concurrency := range []int{0, 1, 3} {
+		reader := protoparser.Source{
+			Filename: "reader.proto",
+			Reader:   strings.NewReader(`syntax = "proto3";`),
+		}
+		got, err := protoparser.ParseFiles(
+			context.Background(),
+			append(sources[:len(sources):len(sources)], reader),
+			protoparser.WithConcurrency(concurrency),
+		)
+		if err != nil {
+			t.Errorf("got err %v, but want nil", err)
+		}
+		if !reflect.DeepEqual(got, wants) {
+			t.Errorf("got the different Protos with the concurrency %d", concurrency)
+		}
+	}
+}
+
+func TestParseFiles_errors(t *testing.T) {
+	sources := []protoparser.Source{
+		{
+			Filename: "broken.proto",
+			Reader:   strings.NewReader(`syntax = "proto3"; message A {`),
+		},
+		{
+			Filename: "valid.proto",
+			Reader:   strings.NewReader(`syntax = "proto3"; message A {}`),
+		},
+		{
+			Filename: filepath.Join("_testdata", "missing.proto"),
+		},
+	}
+
+	protos, err := protoparser.ParseFiles(context.Background(), sources, protoparser.WithConcurrency(2))
+	var list protoparser.ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("got err %v, but want ErrorList", err)
+	}
+	var gotFilenames []string
+	for _, fileErr := range list {
+		gotFilenames = append(gotFilenames, fileErr.Filename)
+	}
+	wantFilenames := []string{"broken.proto", filepath.Join("_testdata", "missing.proto")}
+	if !reflect.DeepEqual(gotFilenames, wantFilenames) {
+		t.Errorf("got %v, but want %v", gotFilenames, wantFilenames)
+	}
+	if !errors.Is(list[1], os.ErrNotExist) {
+		t.Errorf("got %v, but want os.ErrNotExist", list[1])
+	}
+	if protos[0] != nil || protos[1] == nil || protos[2] != nil {
+		t.Errorf("got %v, but want only the Proto of valid.proto", protos)
+	}
+}
+
+func TestParseFiles_limits(t *testing.T) {
+	sources := []protoparser.Source{
+		{
+			Filename: "deep.proto",
+			Reader:   strings.NewReader(`syntax = "proto3"; message A { message B { message C {} } }`),
+		},
+		{
+			Filename: "large.proto",
+			Reader:   strings.NewReader(`syntax = "proto3"; message A { int32 a = 1; int32 b = 2; int32 c = 3; }`),
+		},
+	}
+
+	_, err := protoparser.ParseFiles(
+		context.Background(),
+		sources,
+		protoparser.WithMaxNestingDepth(2),
+		protoparser.WithMaxInputBytes(60),
+		protoparser.WithErrorRecovery(true),
+	)
+	var list protoparser.ErrorList
+	if !errors.As(err, &list) || len(list) != 2 {
+		t.Fatalf("got err %v, but want ErrorList of the 2 files", err)
+	}
+	wantLimits := []meta.Limit{meta.LimitNestingDepth, meta.LimitInputBytes}
+	for i, fileErr := range list {
+		var limitErr *meta.LimitError
+		if !errors.As(fileErr, &limitErr) || limitErr.Limit != wantLimits[i] {
+			t.Errorf("got %v, but want the limit of the %v", fileErr, wantLimits[i])
+		}
+	}
+}
+
+func TestParseFiles_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sources := []protoparser.Source{
+		{
+			Filename: "a.proto",
+			Reader:   strings.NewReader(`syntax = "proto3";`),
+		},
+	}
+	protos, err := protoparser.ParseFiles(ctx, sources)
+	if err != context.Canceled {
+		t.Errorf("got err %v, but want %v", err, context.Canceled)
+	}
+	if protos[0] != nil {
+		t.Errorf("got %v, but want nil", protos[0])
+	}
+}
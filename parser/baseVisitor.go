@@ -0,0 +1,75 @@
+package parser
+
+// BaseVisitor is a Visitor which visits all of the elements and does nothing.
+// It's embedded in a visitor which implements only the methods it needs:
+//
+//	type fieldCounter struct {
+//		parser.BaseVisitor
+//		count int
+//	}
+//
+//	func (c *fieldCounter) VisitField(*parser.Field) bool {
+//		c.count++
+//		return true
+//	}
+type BaseVisitor struct{}
+
+// VisitComment implements Visitor.
+func (BaseVisitor) VisitComment(*Comment) {}
+
+// VisitEdition implements Visitor.
+func (BaseVisitor) VisitEdition(*Edition) bool { return true }
+
+// VisitEmptyStatement implements Visitor.
+func (BaseVisitor) VisitEmptyStatement(*EmptyStatement) bool { return true }
+
+// VisitEnum implements Visitor.
+func (BaseVisitor) VisitEnum(*Enum) bool { return true }
+
+// VisitEnumField implements Visitor.
+func (BaseVisitor) VisitEnumField(*EnumField) bool { return true }
+
+// VisitExtend implements Visitor.
+func (BaseVisitor) VisitExtend(*Extend) bool { return true }
+
+// VisitExtensions implements Visitor.
+func (BaseVisitor) VisitExtensions(*Extensions) bool { return true }
+
+// VisitField implements Visitor.
+func (BaseVisitor) VisitField(*Field) bool { return true }
+
+// VisitGroupField implements Visitor.
+func (BaseVisitor) VisitGroupField(*GroupField) bool { return true }
+
+// VisitImport implements Visitor.
+func (BaseVisitor) VisitImport(*Import) bool { return true }
+
+// VisitMapField implements Visitor.
+func (BaseVisitor) VisitMapField(*MapField) bool { return true }
+
+// VisitMessage implements Visitor.
+func (BaseVisitor) VisitMessage(*Message) bool { return true }
+
+// VisitOneof implements Visitor.
+func (BaseVisitor) VisitOneof(*Oneof) bool { return true }
+
+// VisitOneofField implements Visitor.
+func (BaseVisitor) VisitOneofField(*OneofField) bool { return true }
+
+// VisitOption implements Visitor.
+func (BaseVisitor) VisitOption(*Option) bool { return true }
+
+// VisitPackage implements Visitor.
+func (BaseVisitor) VisitPackage(*Package) bool { return true }
+
+// VisitReserved implements Visitor.
+func (BaseVisitor) VisitReserved(*Reserved) bool { return true }
+
+// VisitRPC implements Visitor.
+func (BaseVisitor) VisitRPC(*RPC) bool { return true }
+
+// VisitService implements Visitor.
+func (BaseVisitor) VisitService(*Service) bool { return true }
+
+// VisitSyntax implements Visitor.
+func (BaseVisitor) VisitSyntax(*Syntax) bool { return true }
@@ -0,0 +1,40 @@
+package parser_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+func BenchmarkParser_ParseProto(b *testing.B) {
+	files, err := filepath.Glob(filepath.Join("..", "_testdata", "*.proto"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var corpus [][]byte
+	var size int64
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			b.Fatal(err)
+		}
+		corpus = append(corpus, src)
+		size += int64(len(src))
+	}
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, src := range corpus {
+			p := parser.NewParser(lexer.NewLexer(bytes.NewReader(src)), parser.WithPermissive(true))
+			if _, err := p.ParseProto(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
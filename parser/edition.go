@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Edition is used to define the protobuf edition, which replaces the syntax in editions-based files.
+type Edition struct {
+	Edition string
+
+	// EditionQuote includes quotes
+	EditionQuote string
+
+	// Comments are the optional ones placed at the beginning.
+	Comments []*Comment
+	// InlineComment is the optional one placed at the ending.
+	InlineComment *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
+	// Meta is the meta information.
+	Meta meta.Meta
+}
+
+// SetInlineComment implements the HasInlineCommentSetter interface.
+func (e *Edition) SetInlineComment(comment *Comment) {
+	e.InlineComment = comment
+}
+
+// Accept dispatches the call to the visitor.
+func (e *Edition) Accept(v Visitor) {
+	if !v.VisitEdition(e) {
+		return
+	}
+
+	for _, comment := range e.Comments {
+		comment.Accept(v)
+	}
+	if e.InlineComment != nil {
+		e.InlineComment.Accept(v)
+	}
+}
+
+// ParseEdition parses the edition.
+//
+//	edition = "edition" "=" quote editionLit quote ";"
+//
+// See https://protobuf.dev/reference/protobuf/edition-2023-spec/#edition
+func (p *Parser) ParseEdition() (*Edition, error) {
+	p.lex.NextKeyword()
+	if p.lex.Token != scanner.TEDITION {
+		return nil, p.unexpected("edition")
+	}
+	startPos := p.lex.Pos
+
+	p.lex.Next()
+	if p.lex.Token != scanner.TEQUALS {
+		return nil, p.unexpected("=")
+	}
+
+	p.lex.NextStrLit()
+	if p.lex.Token != scanner.TSTRLIT {
+		return nil, p.unexpected("quoted edition")
+	}
+	quoted := p.lex.Text
+	edition := quoted[1 : len(quoted)-1]
+	if edition == "" {
+		return nil, p.unexpected("edition")
+	}
+
+	p.lex.Next()
+	if p.lex.Token != scanner.TSEMICOLON {
+		return nil, p.unexpected(";")
+	}
+
+	return &Edition{
+		Edition:      edition,
+		EditionQuote: quoted,
+		Meta: meta.Meta{
+			Pos:     startPos.Position,
+			LastPos: p.lex.Pos.Position,
+		},
+	}, nil
+}
@@ -1,9 +1,13 @@
 package parser
 
+import "github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
 // EmptyStatement represents ";".
 type EmptyStatement struct {
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
+	// Meta is the meta information.
+	Meta meta.Meta
 }
 
 // SetInlineComment implements the HasInlineCommentSetter interface.
@@ -21,3 +25,10 @@ func (e *EmptyStatement) Accept(v Visitor) {
 		e.InlineComment.Accept(v)
 	}
 }
+
+// newEmptyStatement creates the EmptyStatement of the current ";".
+func (p *Parser) newEmptyStatement() *EmptyStatement {
+	return &EmptyStatement{
+		Meta: meta.Meta{Pos: p.lex.Pos.Position, LastPos: p.lex.Pos.Position},
+	}
+}
@@ -20,10 +20,19 @@ func (e *parseEnumBodyStatementErr) Error() string {
 	)
 }
 
+// Unwrap returns the first error.
+func (e *parseEnumBodyStatementErr) Unwrap() error {
+	return e.parseEnumFieldErr
+}
+
 // EnumValueOption is an option of a enumField.
 type EnumValueOption struct {
 	OptionName string
 	Constant   string
+	// Value is the typed tree of the Constant.
+	Value *OptionValue
+	// Meta is the meta information.
+	Meta meta.Meta
 }
 
 // EnumField is a field of enum.
@@ -32,10 +41,17 @@ type EnumField struct {
 	Number           string
 	EnumValueOptions []*EnumValueOption
 
+	// IdentMeta is the meta information of the Ident. It's set with WithDetailedPositions.
+	IdentMeta meta.Meta
+	// NumberMeta is the meta information of the Number. It's set with WithDetailedPositions.
+	NumberMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -66,12 +82,17 @@ type Enum struct {
 	// The element of this is the union of an option, enumField, reserved, and emptyStatement.
 	EnumBody []Visitee
 
+	// EnumNameMeta is the meta information of the EnumName. It's set with WithDetailedPositions.
+	EnumNameMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
 	// InlineCommentBehindLeftCurly is the optional one placed behind a left curly.
 	InlineCommentBehindLeftCurly *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -107,6 +128,10 @@ func (e *Enum) Accept(v Visitor) {
 //
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#enum_definition
 func (p *Parser) ParseEnum() (*Enum, error) {
+	defer p.leave()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
 	p.lex.NextKeyword()
 	if p.lex.Token != scanner.TENUM {
 		return nil, p.unexpected("enum")
@@ -118,6 +143,7 @@ func (p *Parser) ParseEnum() (*Enum, error) {
 		return nil, p.unexpected("enumName")
 	}
 	enumName := p.lex.Text
+	enumNameMeta := p.tokenMeta()
 
 	enumBody, inlineLeftCurly, lastPos, err := p.parseEnumBody()
 	if err != nil {
@@ -127,6 +153,7 @@ func (p *Parser) ParseEnum() (*Enum, error) {
 	return &Enum{
 		EnumName:                     enumName,
 		EnumBody:                     enumBody,
+		EnumNameMeta:                 p.partMeta(enumNameMeta),
 		InlineCommentBehindLeftCurly: inlineLeftCurly,
 		Meta: meta.Meta{
 			Pos:     startPos.Position,
@@ -154,18 +181,13 @@ func (p *Parser) parseEnumBody() (
 
 	for {
 		comments := p.ParseComments()
+		depth := p.lex.CurlyDepth()
 
 		p.lex.NextKeyword()
 		token := p.lex.Token
 		p.lex.UnNext()
 
-		var stmt interface {
-			HasInlineCommentSetter
-			Visitee
-		}
-
-		switch token {
-		case scanner.TRIGHTCURLY:
+		if token == scanner.TRIGHTCURLY {
 			if p.bodyIncludingComments {
 				for _, comment := range comments {
 					stmts = append(stmts, Visitee(comment))
@@ -182,40 +204,17 @@ func (p *Parser) parseEnumBody() (
 				}
 			}
 			return stmts, inlineLeftCurly, lastPos, nil
-		case scanner.TOPTION:
-			option, err := p.ParseOption()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			option.Comments = comments
-			stmt = option
-		case scanner.TRESERVED:
-			// See https://developers.google.com/protocol-buffers/docs/proto3#enum_reserved
-			reserved, err := p.ParseReserved()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			reserved.Comments = comments
-			stmt = reserved
-		default:
-			enumField, enumFieldErr := p.parseEnumField()
-			if enumFieldErr == nil {
-				enumField.Comments = comments
-				stmt = enumField
-				break
-			}
-			p.lex.UnNext()
+		}
 
-			emptyErr := p.lex.ReadEmptyStatement()
-			if emptyErr == nil {
-				stmt = &EmptyStatement{}
-				break
+		stmt, err := p.parseEnumBodyStatement(token, comments)
+		if err != nil {
+			if !p.recoverFrom(err, depth, true) {
+				return nil, nil, scanner.Position{}, err
 			}
-
-			return nil, nil, scanner.Position{}, &parseEnumBodyStatementErr{
-				parseEnumFieldErr:      enumFieldErr,
-				parseEmptyStatementErr: emptyErr,
+			if p.IsEOF() {
+				return stmts, inlineLeftCurly, p.lex.Pos, nil
 			}
+			continue
 		}
 
 		p.MaybeScanInlineComment(stmt)
@@ -223,6 +222,48 @@ func (p *Parser) parseEnumBody() (
 	}
 }
 
+func (p *Parser) parseEnumBodyStatement(
+	token scanner.Token,
+	comments []*Comment,
+) (interface {
+	HasInlineCommentSetter
+	Visitee
+}, error) {
+	switch token {
+	case scanner.TOPTION:
+		option, err := p.ParseOption()
+		if err != nil {
+			return nil, err
+		}
+		option.Comments = comments
+		return option, nil
+	case scanner.TRESERVED:
+		// See https://developers.google.com/protocol-buffers/docs/proto3#enum_reserved
+		reserved, err := p.ParseReserved()
+		if err != nil {
+			return nil, err
+		}
+		reserved.Comments = comments
+		return reserved, nil
+	default:
+		emptyErr := p.lex.ReadEmptyStatement()
+		if emptyErr == nil {
+			return p.newEmptyStatement(), nil
+		}
+
+		enumField, enumFieldErr := p.parseEnumField()
+		if enumFieldErr == nil {
+			enumField.Comments = comments
+			return enumField, nil
+		}
+
+		return nil, &parseEnumBodyStatementErr{
+			parseEnumFieldErr:      enumFieldErr,
+			parseEmptyStatementErr: emptyErr,
+		}
+	}
+}
+
 // enumField = [ "-" ] ident "=" intLit [ "[" enumValueOption { ","  enumValueOption } "]" ]";"
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#enum_definition
 func (p *Parser) parseEnumField() (*EnumField, error) {
@@ -232,6 +273,7 @@ func (p *Parser) parseEnumField() (*EnumField, error) {
 	}
 	startPos := p.lex.Pos
 	ident := p.lex.Text
+	identMeta := p.tokenMeta()
 
 	p.lex.Next()
 	if p.lex.Token != scanner.TEQUALS {
@@ -239,9 +281,12 @@ func (p *Parser) parseEnumField() (*EnumField, error) {
 	}
 
 	var intLit string
+	var minusPos *scanner.Position
 	p.lex.ConsumeToken(scanner.TMINUS)
 	if p.lex.Token == scanner.TMINUS {
 		intLit = "-"
+		pos := p.lex.Pos
+		minusPos = &pos
 	}
 
 	p.lex.NextNumberLit()
@@ -249,6 +294,10 @@ func (p *Parser) parseEnumField() (*EnumField, error) {
 		return nil, p.unexpected("intLit")
 	}
 	intLit += p.lex.Text
+	numberMeta := p.tokenMeta()
+	if minusPos != nil {
+		numberMeta.Pos = minusPos.Position
+	}
 
 	enumValueOptions, err := p.parseEnumValueOptions()
 	if err != nil {
@@ -264,6 +313,8 @@ func (p *Parser) parseEnumField() (*EnumField, error) {
 		Ident:            ident,
 		Number:           intLit,
 		EnumValueOptions: enumValueOptions,
+		IdentMeta:        p.partMeta(identMeta),
+		NumberMeta:       p.partMeta(numberMeta),
 		Meta:             meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
 	}, nil
 }
@@ -308,7 +359,7 @@ func (p *Parser) parseEnumValueOptions() ([]*EnumValueOption, error) {
 // enumValueOption = optionName "=" constant
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#enum_definition
 func (p *Parser) parseEnumValueOption() (*EnumValueOption, error) {
-	optionName, err := p.parseOptionName()
+	optionName, optionNameMeta, err := p.parseOptionName()
 	if err != nil {
 		return nil, err
 	}
@@ -318,7 +369,7 @@ func (p *Parser) parseEnumValueOption() (*EnumValueOption, error) {
 		return nil, p.unexpected("=")
 	}
 
-	constant, err := p.parseOptionConstant()
+	constant, value, err := p.parseOptionConstant()
 	if err != nil {
 		return nil, err
 	}
@@ -326,5 +377,7 @@ func (p *Parser) parseEnumValueOption() (*EnumValueOption, error) {
 	return &EnumValueOption{
 		OptionName: optionName,
 		Constant:   constant,
+		Value:      value,
+		Meta:       meta.Meta{Pos: optionNameMeta.Pos, LastPos: value.Meta.LastPos},
 	}, nil
 }
@@ -50,6 +50,22 @@ func TestParser_ParseEnum(t *testing.T) {
 					&parser.Option{
 						OptionName: "allow_alias",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 48,
+									Line:   2,
+									Column: 24,
+								},
+								LastPos: meta.Position{
+									Offset: 51,
+									Line:   2,
+									Column: 27,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Offset: 27,
@@ -102,6 +118,34 @@ func TestParser_ParseEnum(t *testing.T) {
 							{
 								OptionName: "(custom_option)",
 								Constant:   `"hello world"`,
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindString,
+									Scalar: `"hello world"`,
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 117,
+											Line:   5,
+											Column: 34,
+										},
+										LastPos: meta.Position{
+											Offset: 129,
+											Line:   5,
+											Column: 46,
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 99,
+										Line:   5,
+										Column: 16,
+									},
+									LastPos: meta.Position{
+										Offset: 129,
+										Line:   5,
+										Column: 46,
+									},
+								},
 							},
 						},
 						Meta: meta.Meta{
@@ -148,10 +192,66 @@ func TestParser_ParseEnum(t *testing.T) {
 							{
 								OptionName: "(custom_option)",
 								Constant:   `"hello world"`,
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindString,
+									Scalar: `"hello world"`,
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 58,
+											Line:   2,
+											Column: 34,
+										},
+										LastPos: meta.Position{
+											Offset: 70,
+											Line:   2,
+											Column: 46,
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 40,
+										Line:   2,
+										Column: 16,
+									},
+									LastPos: meta.Position{
+										Offset: 70,
+										Line:   2,
+										Column: 46,
+									},
+								},
 							},
 							{
 								OptionName: "(custom_option2)",
 								Constant:   `"hello world2"`,
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindString,
+									Scalar: `"hello world2"`,
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 92,
+											Line:   2,
+											Column: 68,
+										},
+										LastPos: meta.Position{
+											Offset: 105,
+											Line:   2,
+											Column: 81,
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 73,
+										Line:   2,
+										Column: 49,
+									},
+									LastPos: meta.Position{
+										Offset: 105,
+										Line:   2,
+										Column: 81,
+									},
+								},
 							},
 						},
 						Meta: meta.Meta{
@@ -197,6 +297,22 @@ func TestParser_ParseEnum(t *testing.T) {
 					&parser.Option{
 						OptionName: "allow_alias",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 60,
+									Line:   3,
+									Column: 24,
+								},
+								LastPos: meta.Position{
+									Offset: 63,
+									Line:   3,
+									Column: 27,
+								},
+							},
+						},
 						Comments: []*parser.Comment{
 							{
 								Raw: `// option`,
@@ -288,6 +404,22 @@ func TestParser_ParseEnum(t *testing.T) {
 					&parser.Option{
 						OptionName: "allow_alias",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 72,
+									Line:   2,
+									Column: 24,
+								},
+								LastPos: meta.Position{
+									Offset: 75,
+									Line:   2,
+									Column: 27,
+								},
+							},
+						},
 						InlineComment: &parser.Comment{
 							Raw: `// option`,
 							Meta: meta.Meta{
@@ -390,6 +522,22 @@ func TestParser_ParseEnum(t *testing.T) {
 					&parser.Option{
 						OptionName: "allow_alias",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 48,
+									Line:   2,
+									Column: 24,
+								},
+								LastPos: meta.Position{
+									Offset: 51,
+									Line:   2,
+									Column: 27,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Offset: 27,
@@ -433,6 +581,22 @@ func TestParser_ParseEnum(t *testing.T) {
 					&parser.Option{
 						OptionName: "allow_alias",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 48,
+									Line:   2,
+									Column: 24,
+								},
+								LastPos: meta.Position{
+									Offset: 51,
+									Line:   2,
+									Column: 27,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Offset: 27,
@@ -505,17 +669,65 @@ func TestParser_ParseEnum(t *testing.T) {
 						Ranges: []*parser.Range{
 							{
 								Begin: "2",
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 22,
+										Line:   2,
+										Column: 12,
+									},
+									LastPos: meta.Position{
+										Offset: 22,
+										Line:   2,
+										Column: 12,
+									},
+								},
 							},
 							{
 								Begin: "15",
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 25,
+										Line:   2,
+										Column: 15,
+									},
+									LastPos: meta.Position{
+										Offset: 26,
+										Line:   2,
+										Column: 16,
+									},
+								},
 							},
 							{
 								Begin: "9",
 								End:   "11",
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 29,
+										Line:   2,
+										Column: 19,
+									},
+									LastPos: meta.Position{
+										Offset: 35,
+										Line:   2,
+										Column: 25,
+									},
+								},
 							},
 							{
 								Begin: "40",
 								End:   "max",
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 38,
+										Line:   2,
+										Column: 28,
+									},
+									LastPos: meta.Position{
+										Offset: 46,
+										Line:   2,
+										Column: 36,
+									},
+								},
 							},
 						},
 						Meta: meta.Meta{
@@ -627,6 +839,34 @@ func TestParser_ParseEnum(t *testing.T) {
 							{
 								OptionName: "(custom_option)",
 								Constant:   `"this is a string on two lines"`,
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindString,
+									Scalar: `"this is a string on two lines"`,
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 58,
+											Line:   2,
+											Column: 34,
+										},
+										LastPos: meta.Position{
+											Offset: 124,
+											Line:   3,
+											Column: 54,
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 40,
+										Line:   2,
+										Column: 16,
+									},
+									LastPos: meta.Position{
+										Offset: 124,
+										Line:   3,
+										Column: 54,
+									},
+								},
 							},
 						},
 						Meta: meta.Meta{
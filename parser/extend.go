@@ -20,18 +20,28 @@ func (e *parseExtendBodyStatementErr) Error() string {
 	)
 }
 
+// Unwrap returns the first error.
+func (e *parseExtendBodyStatementErr) Unwrap() error {
+	return e.parseFieldErr
+}
+
 // Extend consists of a messageType and an extend body.
 type Extend struct {
 	MessageType string
 	// ExtendBody can have fields and emptyStatements
 	ExtendBody []Visitee
 
+	// MessageTypeMeta is the meta information of the MessageType. It's set with WithDetailedPositions.
+	MessageTypeMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
 	// InlineCommentBehindLeftCurly is the optional one placed behind a left curly.
 	InlineCommentBehindLeftCurly *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -67,13 +77,17 @@ func (m *Extend) Accept(v Visitor) {
 //
 // See https://developers.google.com/protocol-buffers/docs/reference/proto2-spec#extend
 func (p *Parser) ParseExtend() (*Extend, error) {
+	defer p.leave()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
 	p.lex.NextKeyword()
 	if p.lex.Token != scanner.TEXTEND {
 		return nil, p.unexpected("extend")
 	}
 	startPos := p.lex.Pos
 
-	messageType, _, err := p.lex.ReadMessageType()
+	messageType, messageTypePos, messageTypeLastPos, err := p.lex.ReadMessageTypeWithLastPos()
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +100,7 @@ func (p *Parser) ParseExtend() (*Extend, error) {
 	return &Extend{
 		MessageType:                  messageType,
 		ExtendBody:                   extendBody,
+		MessageTypeMeta:              p.partMeta(spanMeta(messageTypePos, messageTypeLastPos)),
 		InlineCommentBehindLeftCurly: inlineLeftCurly,
 		Meta: meta.Meta{
 			Pos:     startPos.Position,
@@ -129,18 +144,13 @@ func (p *Parser) parseExtendBody() (
 
 	for {
 		comments := p.ParseComments()
+		depth := p.lex.CurlyDepth()
 
 		p.lex.NextKeyword()
 		token := p.lex.Token
 		p.lex.UnNext()
 
-		var stmt interface {
-			HasInlineCommentSetter
-			Visitee
-		}
-
-		switch token {
-		case scanner.TRIGHTCURLY:
+		if token == scanner.TRIGHTCURLY {
 			if p.bodyIncludingComments {
 				for _, comment := range comments {
 					stmts = append(stmts, Visitee(comment))
@@ -157,28 +167,43 @@ func (p *Parser) parseExtendBody() (
 				}
 			}
 			return stmts, inlineLeftCurly, lastPos, nil
-		default:
-			field, fieldErr := p.ParseField()
-			if fieldErr == nil {
-				field.Comments = comments
-				stmt = field
-				break
-			}
-			p.lex.UnNext()
+		}
 
-			emptyErr := p.lex.ReadEmptyStatement()
-			if emptyErr == nil {
-				stmt = &EmptyStatement{}
-				break
+		stmt, err := p.parseExtendBodyStatement(comments)
+		if err != nil {
+			if !p.recoverFrom(err, depth, true) {
+				return nil, nil, scanner.Position{}, err
 			}
-
-			return nil, nil, scanner.Position{}, &parseExtendBodyStatementErr{
-				parseFieldErr:          fieldErr,
-				parseEmptyStatementErr: emptyErr,
+			if p.IsEOF() {
+				return stmts, inlineLeftCurly, p.lex.Pos, nil
 			}
+			continue
 		}
 
 		p.MaybeScanInlineComment(stmt)
 		stmts = append(stmts, stmt)
 	}
 }
+
+func (p *Parser) parseExtendBodyStatement(
+	comments []*Comment,
+) (interface {
+	HasInlineCommentSetter
+	Visitee
+}, error) {
+	emptyErr := p.lex.ReadEmptyStatement()
+	if emptyErr == nil {
+		return p.newEmptyStatement(), nil
+	}
+
+	field, fieldErr := p.ParseField()
+	if fieldErr == nil {
+		field.Comments = comments
+		return field, nil
+	}
+
+	return nil, &parseExtendBodyStatementErr{
+		parseFieldErr:          fieldErr,
+		parseEmptyStatementErr: emptyErr,
+	}
+}
@@ -13,6 +13,8 @@ type Extensions struct {
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
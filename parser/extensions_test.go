@@ -40,6 +40,18 @@ func TestParser_ParseExtensions(t *testing.T) {
 					{
 						Begin: "100",
 						End:   "199",
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 11,
+								Line:   1,
+								Column: 12,
+							},
+							LastPos: meta.Position{
+								Offset: 20,
+								Line:   1,
+								Column: 21,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -63,10 +75,34 @@ func TestParser_ParseExtensions(t *testing.T) {
 				Ranges: []*parser.Range{
 					{
 						Begin: "4",
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 11,
+								Line:   1,
+								Column: 12,
+							},
+							LastPos: meta.Position{
+								Offset: 11,
+								Line:   1,
+								Column: 12,
+							},
+						},
 					},
 					{
 						Begin: "20",
 						End:   "max",
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 14,
+								Line:   1,
+								Column: 15,
+							},
+							LastPos: meta.Position{
+								Offset: 22,
+								Line:   1,
+								Column: 23,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -9,6 +9,10 @@ import (
 type FieldOption struct {
 	OptionName string
 	Constant   string
+	// Value is the typed tree of the Constant.
+	Value *OptionValue
+	// Meta is the meta information.
+	Meta meta.Meta
 }
 
 // Field is a normal field that is the basic element of a protocol buffer message.
@@ -21,10 +25,19 @@ type Field struct {
 	FieldNumber  string
 	FieldOptions []*FieldOption
 
+	// TypeMeta is the meta information of the Type. It's set with WithDetailedPositions.
+	TypeMeta meta.Meta
+	// FieldNameMeta is the meta information of the FieldName. It's set with WithDetailedPositions.
+	FieldNameMeta meta.Meta
+	// FieldNumberMeta is the meta information of the FieldNumber. It's set with WithDetailedPositions.
+	FieldNumberMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -74,7 +87,7 @@ func (p *Parser) ParseField() (*Field, error) {
 		p.lex.UnNext()
 	}
 
-	typeValue, _, err := p.parseType()
+	typeValue, typeMeta, err := p.parseType()
 	if err != nil {
 		return nil, p.unexpected("type")
 	}
@@ -84,13 +97,14 @@ func (p *Parser) ParseField() (*Field, error) {
 		return nil, p.unexpected("fieldName")
 	}
 	fieldName := p.lex.Text
+	fieldNameMeta := p.tokenMeta()
 
 	p.lex.Next()
 	if p.lex.Token != scanner.TEQUALS {
 		return nil, p.unexpected("=")
 	}
 
-	fieldNumber, err := p.parseFieldNumber()
+	fieldNumber, fieldNumberMeta, err := p.parseFieldNumber()
 	if err != nil {
 		return nil, p.unexpected("fieldNumber")
 	}
@@ -106,14 +120,17 @@ func (p *Parser) ParseField() (*Field, error) {
 	}
 
 	return &Field{
-		IsRepeated:   isRepeated,
-		IsRequired:   isRequired,
-		IsOptional:   isOptional,
-		Type:         typeValue,
-		FieldName:    fieldName,
-		FieldNumber:  fieldNumber,
-		FieldOptions: fieldOptions,
-		Meta:         meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
+		IsRepeated:      isRepeated,
+		IsRequired:      isRequired,
+		IsOptional:      isOptional,
+		Type:            typeValue,
+		FieldName:       fieldName,
+		FieldNumber:     fieldNumber,
+		FieldOptions:    fieldOptions,
+		TypeMeta:        p.partMeta(typeMeta),
+		FieldNameMeta:   p.partMeta(fieldNameMeta),
+		FieldNumberMeta: p.partMeta(fieldNumberMeta),
+		Meta:            meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
 	}, nil
 }
 
@@ -166,7 +183,7 @@ func (p *Parser) parseFieldOptions() ([]*FieldOption, error) {
 // fieldOption = optionName "=" constant
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#field
 func (p *Parser) parseFieldOption() (*FieldOption, error) {
-	optionName, err := p.parseOptionName()
+	optionName, optionNameMeta, err := p.parseOptionName()
 	if err != nil {
 		return nil, err
 	}
@@ -176,7 +193,7 @@ func (p *Parser) parseFieldOption() (*FieldOption, error) {
 		return nil, p.unexpected("=")
 	}
 
-	constant, err := p.parseOptionConstant()
+	constant, value, err := p.parseOptionConstant()
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +201,8 @@ func (p *Parser) parseFieldOption() (*FieldOption, error) {
 	return &FieldOption{
 		OptionName: optionName,
 		Constant:   constant,
+		Value:      value,
+		Meta:       meta.Meta{Pos: optionNameMeta.Pos, LastPos: value.Meta.LastPos},
 	}, nil
 }
 
@@ -211,26 +230,26 @@ var typeConstants = map[string]struct{}{
 //	| "bool" | "string" | "bytes" | messageType | enumType
 //
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#fields
-func (p *Parser) parseType() (string, scanner.Position, error) {
+func (p *Parser) parseType() (string, meta.Meta, error) {
 	p.lex.Next()
 	if _, ok := typeConstants[p.lex.Text]; ok {
-		return p.lex.Text, p.lex.Pos, nil
+		return p.lex.Text, p.tokenMeta(), nil
 	}
 	p.lex.UnNext()
 
-	messageOrEnumType, startPos, err := p.lex.ReadMessageType()
+	messageOrEnumType, startPos, lastPos, err := p.lex.ReadMessageTypeWithLastPos()
 	if err != nil {
-		return "", scanner.Position{}, err
+		return "", meta.Meta{}, err
 	}
-	return messageOrEnumType, startPos, nil
+	return messageOrEnumType, spanMeta(startPos, lastPos), nil
 }
 
 // fieldNumber = intLit;
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#fields
-func (p *Parser) parseFieldNumber() (string, error) {
+func (p *Parser) parseFieldNumber() (string, meta.Meta, error) {
 	p.lex.NextNumberLit()
 	if p.lex.Token != scanner.TINTLIT {
-		return "", p.unexpected("intLit")
+		return "", meta.Meta{}, p.unexpected("intLit")
 	}
-	return p.lex.Text, nil
+	return p.lex.Text, p.tokenMeta(), nil
 }
@@ -66,6 +66,34 @@ func TestParser_ParseField(t *testing.T) {
 					{
 						OptionName: "packed",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 35,
+									Line:   1,
+									Column: 36,
+								},
+								LastPos: meta.Position{
+									Offset: 38,
+									Line:   1,
+									Column: 39,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 28,
+								Line:   1,
+								Column: 29,
+							},
+							LastPos: meta.Position{
+								Offset: 38,
+								Line:   1,
+								Column: 39,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -94,10 +122,66 @@ func TestParser_ParseField(t *testing.T) {
 					{
 						OptionName: "packed",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 35,
+									Line:   1,
+									Column: 36,
+								},
+								LastPos: meta.Position{
+									Offset: 38,
+									Line:   1,
+									Column: 39,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 28,
+								Line:   1,
+								Column: 29,
+							},
+							LastPos: meta.Position{
+								Offset: 38,
+								Line:   1,
+								Column: 39,
+							},
+						},
 					},
 					{
 						OptionName: "required",
 						Constant:   "false",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "false",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 50,
+									Line:   1,
+									Column: 51,
+								},
+								LastPos: meta.Position{
+									Offset: 54,
+									Line:   1,
+									Column: 55,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 41,
+								Line:   1,
+								Column: 42,
+							},
+							LastPos: meta.Position{
+								Offset: 54,
+								Line:   1,
+								Column: 55,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -131,6 +215,66 @@ func TestParser_ParseField(t *testing.T) {
 					{
 						OptionName: "(validator.field)",
 						Constant:   "{int_gt:0}",
+						Value: &parser.OptionValue{
+							Kind: parser.OptionValueKindMessage,
+							Fields: []*parser.OptionValueField{
+								{
+									Name: "int_gt",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindInt,
+										Scalar: "0",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 54,
+												Line:   1,
+												Column: 55,
+											},
+											LastPos: meta.Position{
+												Offset: 54,
+												Line:   1,
+												Column: 55,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 46,
+											Line:   1,
+											Column: 47,
+										},
+										LastPos: meta.Position{
+											Offset: 54,
+											Line:   1,
+											Column: 55,
+										},
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 45,
+									Line:   1,
+									Column: 46,
+								},
+								LastPos: meta.Position{
+									Offset: 55,
+									Line:   1,
+									Column: 56,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 25,
+								Line:   1,
+								Column: 26,
+							},
+							LastPos: meta.Position{
+								Offset: 55,
+								Line:   1,
+								Column: 56,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -159,6 +303,66 @@ func TestParser_ParseField(t *testing.T) {
 					{
 						OptionName: "(validator.field)",
 						Constant:   "{int_gt:0,}",
+						Value: &parser.OptionValue{
+							Kind: parser.OptionValueKindMessage,
+							Fields: []*parser.OptionValueField{
+								{
+									Name: "int_gt",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindInt,
+										Scalar: "0",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 54,
+												Line:   1,
+												Column: 55,
+											},
+											LastPos: meta.Position{
+												Offset: 54,
+												Line:   1,
+												Column: 55,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 46,
+											Line:   1,
+											Column: 47,
+										},
+										LastPos: meta.Position{
+											Offset: 54,
+											Line:   1,
+											Column: 55,
+										},
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 45,
+									Line:   1,
+									Column: 46,
+								},
+								LastPos: meta.Position{
+									Offset: 56,
+									Line:   1,
+									Column: 57,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 25,
+								Line:   1,
+								Column: 26,
+							},
+							LastPos: meta.Position{
+								Offset: 56,
+								Line:   1,
+								Column: 57,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -187,10 +391,161 @@ func TestParser_ParseField(t *testing.T) {
 					{
 						OptionName: "(validator.field)",
 						Constant:   "{length_gt:0,length_lt:1025}",
+						Value: &parser.OptionValue{
+							Kind: parser.OptionValueKindMessage,
+							Fields: []*parser.OptionValueField{
+								{
+									Name: "length_gt",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindInt,
+										Scalar: "0",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 50,
+												Line:   1,
+												Column: 51,
+											},
+											LastPos: meta.Position{
+												Offset: 50,
+												Line:   1,
+												Column: 51,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 39,
+											Line:   1,
+											Column: 40,
+										},
+										LastPos: meta.Position{
+											Offset: 50,
+											Line:   1,
+											Column: 51,
+										},
+									},
+								},
+								{
+									Name: "length_lt",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindInt,
+										Scalar: "1025",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 64,
+												Line:   1,
+												Column: 65,
+											},
+											LastPos: meta.Position{
+												Offset: 67,
+												Line:   1,
+												Column: 68,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 53,
+											Line:   1,
+											Column: 54,
+										},
+										LastPos: meta.Position{
+											Offset: 67,
+											Line:   1,
+											Column: 68,
+										},
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 38,
+									Line:   1,
+									Column: 39,
+								},
+								LastPos: meta.Position{
+									Offset: 68,
+									Line:   1,
+									Column: 69,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 18,
+								Line:   1,
+								Column: 19,
+							},
+							LastPos: meta.Position{
+								Offset: 68,
+								Line:   1,
+								Column: 69,
+							},
+						},
 					},
 					{
 						OptionName: "(validator.field)",
 						Constant:   `{regex:"[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}"}`,
+						Value: &parser.OptionValue{
+							Kind: parser.OptionValueKindMessage,
+							Fields: []*parser.OptionValueField{
+								{
+									Name: "regex",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindString,
+										Scalar: `"[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}"`,
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 98,
+												Line:   1,
+												Column: 99,
+											},
+											LastPos: meta.Position{
+												Offset: 174,
+												Line:   1,
+												Column: 175,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 91,
+											Line:   1,
+											Column: 92,
+										},
+										LastPos: meta.Position{
+											Offset: 174,
+											Line:   1,
+											Column: 175,
+										},
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 90,
+									Line:   1,
+									Column: 91,
+								},
+								LastPos: meta.Position{
+									Offset: 175,
+									Line:   1,
+									Column: 176,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 70,
+								Line:   1,
+								Column: 71,
+							},
+							LastPos: meta.Position{
+								Offset: 175,
+								Line:   1,
+								Column: 176,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -241,6 +596,34 @@ func TestParser_ParseField(t *testing.T) {
 					{
 						OptionName: "packed",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 35,
+									Line:   1,
+									Column: 36,
+								},
+								LastPos: meta.Position{
+									Offset: 38,
+									Line:   1,
+									Column: 39,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 28,
+								Line:   1,
+								Column: 29,
+							},
+							LastPos: meta.Position{
+								Offset: 38,
+								Line:   1,
+								Column: 39,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -278,6 +661,159 @@ func TestParser_ParseField(t *testing.T) {
 max_length:254
 min_length:1
 description:"Enter user email"}`,
+						Value: &parser.OptionValue{
+							Kind: parser.OptionValueKindMessage,
+							Fields: []*parser.OptionValueField{
+								{
+									Name: "pattern",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindString,
+										Scalar: `"^-!#$%&'*+\/0-9=?A-Z^_a-z{|}~@a-zA-Z0-9\.a-zA-Z+$"`,
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 92,
+												Line:   2,
+												Column: 11,
+											},
+											LastPos: meta.Position{
+												Offset: 142,
+												Line:   2,
+												Column: 61,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 83,
+											Line:   2,
+											Column: 2,
+										},
+										LastPos: meta.Position{
+											Offset: 142,
+											Line:   2,
+											Column: 61,
+										},
+									},
+								},
+								{
+									Name: "max_length",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindInt,
+										Scalar: "254",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 157,
+												Line:   3,
+												Column: 14,
+											},
+											LastPos: meta.Position{
+												Offset: 159,
+												Line:   3,
+												Column: 16,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 145,
+											Line:   3,
+											Column: 2,
+										},
+										LastPos: meta.Position{
+											Offset: 159,
+											Line:   3,
+											Column: 16,
+										},
+									},
+								},
+								{
+									Name: "min_length",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindInt,
+										Scalar: "1",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 174,
+												Line:   4,
+												Column: 14,
+											},
+											LastPos: meta.Position{
+												Offset: 174,
+												Line:   4,
+												Column: 14,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 162,
+											Line:   4,
+											Column: 2,
+										},
+										LastPos: meta.Position{
+											Offset: 174,
+											Line:   4,
+											Column: 14,
+										},
+									},
+								},
+								{
+									Name: "description",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindString,
+										Scalar: `"Enter user email"`,
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 190,
+												Line:   5,
+												Column: 15,
+											},
+											LastPos: meta.Position{
+												Offset: 207,
+												Line:   5,
+												Column: 32,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 177,
+											Line:   5,
+											Column: 2,
+										},
+										LastPos: meta.Position{
+											Offset: 207,
+											Line:   5,
+											Column: 32,
+										},
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 80,
+									Line:   1,
+									Column: 81,
+								},
+								LastPos: meta.Position{
+									Offset: 209,
+									Line:   6,
+									Column: 1,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 20,
+								Line:   1,
+								Column: 21,
+							},
+							LastPos: meta.Position{
+								Offset: 209,
+								Line:   6,
+								Column: 1,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -306,6 +842,145 @@ description:"Enter user email"}`,
 					{
 						OptionName: "(grpc.gateway.protoc_gen_swagger.options.openapiv2_field)",
 						Constant:   `{description:"Float value field",default:"0.2",required:['float_value']}`,
+						Value: &parser.OptionValue{
+							Kind: parser.OptionValueKindMessage,
+							Fields: []*parser.OptionValueField{
+								{
+									Name: "description",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindString,
+										Scalar: `"Float value field"`,
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 97,
+												Line:   1,
+												Column: 98,
+											},
+											LastPos: meta.Position{
+												Offset: 115,
+												Line:   1,
+												Column: 116,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 84,
+											Line:   1,
+											Column: 85,
+										},
+										LastPos: meta.Position{
+											Offset: 115,
+											Line:   1,
+											Column: 116,
+										},
+									},
+								},
+								{
+									Name: "default",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindString,
+										Scalar: `"0.2"`,
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 127,
+												Line:   1,
+												Column: 128,
+											},
+											LastPos: meta.Position{
+												Offset: 131,
+												Line:   1,
+												Column: 132,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 118,
+											Line:   1,
+											Column: 119,
+										},
+										LastPos: meta.Position{
+											Offset: 131,
+											Line:   1,
+											Column: 132,
+										},
+									},
+								},
+								{
+									Name: "required",
+									Value: &parser.OptionValue{
+										Kind: parser.OptionValueKindList,
+										Elements: []*parser.OptionValue{
+											{
+												Kind:   parser.OptionValueKindString,
+												Scalar: "'float_value'",
+												Meta: meta.Meta{
+													Pos: meta.Position{
+														Offset: 145,
+														Line:   1,
+														Column: 146,
+													},
+													LastPos: meta.Position{
+														Offset: 157,
+														Line:   1,
+														Column: 158,
+													},
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 144,
+												Line:   1,
+												Column: 145,
+											},
+											LastPos: meta.Position{
+												Offset: 158,
+												Line:   1,
+												Column: 159,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 134,
+											Line:   1,
+											Column: 135,
+										},
+										LastPos: meta.Position{
+											Offset: 158,
+											Line:   1,
+											Column: 159,
+										},
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 83,
+									Line:   1,
+									Column: 84,
+								},
+								LastPos: meta.Position{
+									Offset: 159,
+									Line:   1,
+									Column: 160,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 23,
+								Line:   1,
+								Column: 24,
+							},
+							LastPos: meta.Position{
+								Offset: 159,
+								Line:   1,
+								Column: 160,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
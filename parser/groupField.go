@@ -20,12 +20,19 @@ type GroupField struct {
 	MessageBody []Visitee
 	FieldNumber string
 
+	// GroupNameMeta is the meta information of the GroupName. It's set with WithDetailedPositions.
+	GroupNameMeta meta.Meta
+	// FieldNumberMeta is the meta information of the FieldNumber. It's set with WithDetailedPositions.
+	FieldNumberMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
 	// InlineCommentBehindLeftCurly is the optional one placed behind a left curly.
 	InlineCommentBehindLeftCurly *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -60,6 +67,10 @@ func (f *GroupField) Accept(v Visitor) {
 //
 // See https://developers.google.com/protocol-buffers/docs/reference/proto2-spec#group_field
 func (p *Parser) ParseGroupField() (*GroupField, error) {
+	defer p.leave()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
 	var isRepeated bool
 	var isRequired bool
 	var isOptional bool
@@ -89,13 +100,14 @@ func (p *Parser) ParseGroupField() (*GroupField, error) {
 		return nil, p.unexpectedf("groupName %q must begin with capital letter.", p.lex.Text)
 	}
 	groupName := p.lex.Text
+	groupNameMeta := p.tokenMeta()
 
 	p.lex.Next()
 	if p.lex.Token != scanner.TEQUALS {
 		return nil, p.unexpected("=")
 	}
 
-	fieldNumber, err := p.parseFieldNumber()
+	fieldNumber, fieldNumberMeta, err := p.parseFieldNumber()
 	if err != nil {
 		return nil, p.unexpected("fieldNumber")
 	}
@@ -113,6 +125,9 @@ func (p *Parser) ParseGroupField() (*GroupField, error) {
 		FieldNumber: fieldNumber,
 		MessageBody: messageBody,
 
+		GroupNameMeta:   p.partMeta(groupNameMeta),
+		FieldNumberMeta: p.partMeta(fieldNumberMeta),
+
 		InlineCommentBehindLeftCurly: inlineLeftCurly,
 		Meta: meta.Meta{
 			Pos:     startPos.Position,
@@ -127,19 +142,19 @@ func (p *Parser) peekIsGroup() bool {
 	case scanner.TREPEATED,
 		scanner.TREQUIRED,
 		scanner.TOPTIONAL:
-		defer p.lex.UnNextTo(p.lex.RawText)
+		defer p.lex.UnNext()
 	default:
 		p.lex.UnNext()
 	}
 
 	p.lex.NextKeyword()
-	defer p.lex.UnNextTo(p.lex.RawText)
+	defer p.lex.UnNext()
 	if p.lex.Token != scanner.TGROUP {
 		return false
 	}
 
 	p.lex.Next()
-	defer p.lex.UnNextTo(p.lex.RawText)
+	defer p.lex.UnNext()
 	if p.lex.Token != scanner.TIDENT {
 		return false
 	}
@@ -148,19 +163,19 @@ func (p *Parser) peekIsGroup() bool {
 	}
 
 	p.lex.Next()
-	defer p.lex.UnNextTo(p.lex.RawText)
+	defer p.lex.UnNext()
 	if p.lex.Token != scanner.TEQUALS {
 		return false
 	}
 
-	_, err := p.parseFieldNumber()
-	defer p.lex.UnNextTo(p.lex.RawText)
+	_, _, err := p.parseFieldNumber()
+	defer p.lex.UnNext()
 	if err != nil {
 		return false
 	}
 
 	p.lex.Next()
-	defer p.lex.UnNextTo(p.lex.RawText)
+	defer p.lex.UnNext()
 	if p.lex.Token != scanner.TLEFTCURLY {
 		return false
 	}
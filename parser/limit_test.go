@@ -0,0 +1,194 @@
+package parser_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+func TestParser_ParseProto_withLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		inputLexOpts []lexer.Option
+		inputOpts    []parser.ConfigOption
+		wantErr      *meta.LimitError
+	}{
+		{
+			name: "nested messages within the max depth",
+			input: `syntax = "proto3";
+message A { message B { int32 x = 1; } }
+`,
+			inputOpts: []parser.ConfigOption{parser.WithMaxNestingDepth(2)},
+		},
+		{
+			name: "nested messages beyond the max depth",
+			input: `syntax = "proto3";
+message A { message B { message C {} } }
+`,
+			inputOpts: []parser.ConfigOption{parser.WithMaxNestingDepth(2)},
+			wantErr: &meta.LimitError{
+				Pos:   meta.Position{Offset: 43, Line: 2, Column: 25},
+				Limit: meta.LimitNestingDepth,
+				Max:   2,
+			},
+		},
+		{
+			name: "nested option values beyond the max depth",
+			input: `syntax = "proto3";
+option (a) = { b: { c: { d: 1 } } };
+`,
+			inputOpts: []parser.ConfigOption{parser.WithPermissive(true), parser.WithMaxNestingDepth(3)},
+			wantErr: &meta.LimitError{
+				Pos:   meta.Position{Offset: 47, Line: 2, Column: 29},
+				Limit: meta.LimitNestingDepth,
+				Max:   3,
+			},
+		},
+		{
+			name: "nested option lists beyond the max depth",
+			input: `syntax = "proto3";
+option (a) = [[[1]]];
+`,
+			inputOpts: []parser.ConfigOption{parser.WithPermissive(true), parser.WithMaxNestingDepth(2)},
+			wantErr: &meta.LimitError{
+				Pos:   meta.Position{Offset: 34, Line: 2, Column: 16},
+				Limit: meta.LimitNestingDepth,
+				Max:   2,
+			},
+		},
+		{
+			name: "an input within the max bytes",
+			input: `syntax = "proto3";
+`,
+			inputLexOpts: []lexer.Option{lexer.WithMaxInputBytes(19)},
+		},
+		{
+			name: "an input beyond the max bytes",
+			input: `syntax = "proto3";
+message A {}
+`,
+			inputLexOpts: []lexer.Option{lexer.WithMaxInputBytes(19)},
+			wantErr: &meta.LimitError{
+				Pos:   meta.Position{Offset: 0, Line: 1, Column: 1},
+				Limit: meta.LimitInputBytes,
+				Max:   19,
+			},
+		},
+		{
+			name: "tokens of the syntax within the max count",
+			input: `syntax = "proto3";
+`,
+			inputLexOpts: []lexer.Option{lexer.WithMaxTokens(4)},
+		},
+		{
+			name: "tokens of the syntax beyond the max count",
+			input: `syntax = "proto3";
+`,
+			inputLexOpts: []lexer.Option{lexer.WithMaxTokens(3)},
+			wantErr: &meta.LimitError{
+				Pos:   meta.Position{Offset: 17, Line: 1, Column: 18},
+				Limit: meta.LimitTokens,
+				Max:   3,
+			},
+		},
+		{
+			name: "tokens within the max count",
+			input: `syntax = "proto3";
+// the comments are not counted.
+message A { int32 x = 1; }
+`,
+			inputLexOpts: []lexer.Option{lexer.WithMaxTokens(13)},
+		},
+		{
+			name: "tokens beyond the max count",
+			input: `syntax = "proto3";
+// the comments are not counted.
+message A { int32 x = 1; }
+`,
+			inputLexOpts: []lexer.Option{lexer.WithMaxTokens(12)},
+			wantErr: &meta.LimitError{
+				Pos:   meta.Position{Offset: 77, Line: 3, Column: 26},
+				Limit: meta.LimitTokens,
+				Max:   12,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		for _, errorRecovery := range []bool{false, true} {
+			errorRecovery := errorRecovery
+			t.Run(test.name, func(t *testing.T) {
+				p := parser.NewParser(
+					lexer.NewLexer(strings.NewReader(test.input), test.inputLexOpts...),
+					append(test.inputOpts, parser.WithErrorRecovery(errorRecovery))...,
+				)
+				got, err := p.ParseProto()
+				if test.wantErr == nil {
+					if err != nil {
+						t.Errorf("got err %v, but want nil", err)
+					}
+					if got == nil {
+						t.Errorf("got nil, but want the Proto")
+					}
+					return
+				}
+
+				var gotErr *meta.LimitError
+				if !errors.As(err, &gotErr) {
+					t.Fatalf("got err %v, but want *meta.LimitError", err)
+				}
+				if !reflect.DeepEqual(gotErr, test.wantErr) {
+					t.Errorf("got %v, but want %v", gotErr, test.wantErr)
+				}
+				if got != nil {
+					t.Errorf("got %v, but want nil", got)
+				}
+			})
+		}
+	}
+}
+
+func TestParser_ParseProto_withContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, errorRecovery := range []bool{false, true} {
+		p := parser.NewParser(
+			lexer.NewLexer(
+				strings.NewReader(`syntax = "proto3"; message A {}`),
+				lexer.WithContext(ctx),
+			),
+			parser.WithErrorRecovery(errorRecovery),
+		)
+		got, err := p.ParseProto()
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got err %v, but want %v", err, context.Canceled)
+		}
+		if got != nil {
+			t.Errorf("got %v, but want nil", got)
+		}
+	}
+}
+
+func TestParser_ParseProto_withMaxNestingDepthOfDeepInput(t *testing.T) {
+	const depth = 100000
+	input := `syntax = "proto3";` + strings.Repeat("message A {", depth) + strings.Repeat("}", depth)
+
+	p := parser.NewParser(
+		lexer.NewLexer(strings.NewReader(input)),
+		parser.WithMaxNestingDepth(100),
+	)
+	_, err := p.ParseProto()
+	var limitErr *meta.LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != meta.LimitNestingDepth {
+		t.Errorf("got err %v, but want the limit of the nesting depth", err)
+	}
+}
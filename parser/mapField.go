@@ -13,10 +13,21 @@ type MapField struct {
 	FieldNumber  string
 	FieldOptions []*FieldOption
 
+	// KeyTypeMeta is the meta information of the KeyType. It's set with WithDetailedPositions.
+	KeyTypeMeta meta.Meta
+	// TypeMeta is the meta information of the Type. It's set with WithDetailedPositions.
+	TypeMeta meta.Meta
+	// MapNameMeta is the meta information of the MapName. It's set with WithDetailedPositions.
+	MapNameMeta meta.Meta
+	// FieldNumberMeta is the meta information of the FieldNumber. It's set with WithDetailedPositions.
+	FieldNumberMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -57,7 +68,7 @@ func (p *Parser) ParseMapField() (*MapField, error) {
 		return nil, p.unexpected("<")
 	}
 
-	keyType, err := p.parseKeyType()
+	keyType, keyTypeMeta, err := p.parseKeyType()
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +78,7 @@ func (p *Parser) ParseMapField() (*MapField, error) {
 		return nil, p.unexpected(",")
 	}
 
-	typeValue, _, err := p.parseType()
+	typeValue, typeMeta, err := p.parseType()
 	if err != nil {
 		return nil, p.unexpected("type")
 	}
@@ -82,13 +93,14 @@ func (p *Parser) ParseMapField() (*MapField, error) {
 		return nil, p.unexpected("mapName")
 	}
 	mapName := p.lex.Text
+	mapNameMeta := p.tokenMeta()
 
 	p.lex.Next()
 	if p.lex.Token != scanner.TEQUALS {
 		return nil, p.unexpected("=")
 	}
 
-	fieldNumber, err := p.parseFieldNumber()
+	fieldNumber, fieldNumberMeta, err := p.parseFieldNumber()
 	if err != nil {
 		return nil, p.unexpected("fieldNumber")
 	}
@@ -104,12 +116,16 @@ func (p *Parser) ParseMapField() (*MapField, error) {
 	}
 
 	return &MapField{
-		KeyType:      keyType,
-		Type:         typeValue,
-		MapName:      mapName,
-		FieldNumber:  fieldNumber,
-		FieldOptions: fieldOptions,
-		Meta:         meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
+		KeyType:         keyType,
+		Type:            typeValue,
+		MapName:         mapName,
+		FieldNumber:     fieldNumber,
+		FieldOptions:    fieldOptions,
+		KeyTypeMeta:     p.partMeta(keyTypeMeta),
+		TypeMeta:        p.partMeta(typeMeta),
+		MapNameMeta:     p.partMeta(mapNameMeta),
+		FieldNumberMeta: p.partMeta(fieldNumberMeta),
+		Meta:            meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
 	}, nil
 }
 
@@ -133,10 +149,10 @@ var keyTypeConstants = map[string]struct{}{
 //	"fixed32" | "fixed64" | "sfixed32" | "sfixed64" | "bool" | "string"
 //
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#map_field
-func (p *Parser) parseKeyType() (string, error) {
+func (p *Parser) parseKeyType() (string, meta.Meta, error) {
 	p.lex.Next()
 	if _, ok := keyTypeConstants[p.lex.Text]; ok {
-		return p.lex.Text, nil
+		return p.lex.Text, p.tokenMeta(), nil
 	}
-	return "", p.unexpected("keyType constant")
+	return "", meta.Meta{}, p.unexpected("keyType constant")
 }
@@ -20,6 +20,11 @@ func (e *parseMessageBodyStatementErr) Error() string {
 	)
 }
 
+// Unwrap returns the first error.
+func (e *parseMessageBodyStatementErr) Unwrap() error {
+	return e.parseFieldErr
+}
+
 // Message consists of a message name and a message body.
 type Message struct {
 	MessageName string
@@ -27,12 +32,17 @@ type Message struct {
 	// options, oneofs, map fields, group fields(proto2 only), extends, reserved, and extensions(proto2 only) statements.
 	MessageBody []Visitee
 
+	// MessageNameMeta is the meta information of the MessageName. It's set with WithDetailedPositions.
+	MessageNameMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
 	// InlineCommentBehindLeftCurly is the optional one placed behind a left curly.
 	InlineCommentBehindLeftCurly *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -67,6 +77,10 @@ func (m *Message) Accept(v Visitor) {
 //
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#message_definition
 func (p *Parser) ParseMessage() (*Message, error) {
+	defer p.leave()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
 	p.lex.NextKeyword()
 	if p.lex.Token != scanner.TMESSAGE {
 		return nil, p.unexpected("message")
@@ -78,6 +92,7 @@ func (p *Parser) ParseMessage() (*Message, error) {
 		return nil, p.unexpected("messageName")
 	}
 	messageName := p.lex.Text
+	messageNameMeta := p.tokenMeta()
 
 	messageBody, inlineLeftCurly, lastPos, err := p.parseMessageBody()
 	if err != nil {
@@ -87,6 +102,7 @@ func (p *Parser) ParseMessage() (*Message, error) {
 	return &Message{
 		MessageName:                  messageName,
 		MessageBody:                  messageBody,
+		MessageNameMeta:              p.partMeta(messageNameMeta),
 		InlineCommentBehindLeftCurly: inlineLeftCurly,
 		Meta: meta.Meta{
 			Pos:     startPos.Position,
@@ -131,18 +147,13 @@ func (p *Parser) parseMessageBody() (
 
 	for {
 		comments := p.ParseComments()
+		depth := p.lex.CurlyDepth()
 
 		p.lex.NextKeyword()
 		token := p.lex.Token
 		p.lex.UnNext()
 
-		var stmt interface {
-			HasInlineCommentSetter
-			Visitee
-		}
-
-		switch token {
-		case scanner.TRIGHTCURLY:
+		if token == scanner.TRIGHTCURLY {
 			if p.bodyIncludingComments {
 				for _, comment := range comments {
 					stmts = append(stmts, Visitee(comment))
@@ -159,98 +170,115 @@ func (p *Parser) parseMessageBody() (
 				}
 			}
 			return stmts, inlineLeftCurly, lastPos, nil
-		case scanner.TENUM:
-			enum, err := p.ParseEnum()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			enum.Comments = comments
-			stmt = enum
-		case scanner.TMESSAGE:
-			message, err := p.ParseMessage()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			message.Comments = comments
-			stmt = message
-		case scanner.TOPTION:
-			option, err := p.ParseOption()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			option.Comments = comments
-			stmt = option
-		case scanner.TONEOF:
-			oneof, err := p.ParseOneof()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			oneof.Comments = comments
-			stmt = oneof
-		case scanner.TMAP:
-			mapField, err := p.ParseMapField()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			mapField.Comments = comments
-			stmt = mapField
-		case scanner.TEXTEND:
-			extend, err := p.ParseExtend()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			extend.Comments = comments
-			stmt = extend
-		case scanner.TRESERVED:
-			reserved, err := p.ParseReserved()
-			if err != nil {
+		}
+
+		stmt, err := p.parseMessageBodyStatement(token, comments)
+		if err != nil {
+			if !p.recoverFrom(err, depth, true) {
 				return nil, nil, scanner.Position{}, err
 			}
-			reserved.Comments = comments
-			stmt = reserved
-		case scanner.TEXTENSIONS:
-			extensions, err := p.ParseExtensions()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			extensions.Comments = comments
-			stmt = extensions
-		default:
-			var ferr error
-			isGroup := p.peekIsGroup()
-			if isGroup {
-				groupField, groupErr := p.ParseGroupField()
-				if groupErr == nil {
-					groupField.Comments = comments
-					stmt = groupField
-					break
-				}
-				ferr = groupErr
-				p.lex.UnNext()
-			} else {
-				field, fieldErr := p.ParseField()
-				if fieldErr == nil {
-					field.Comments = comments
-					stmt = field
-					break
-				}
-				ferr = fieldErr
-				p.lex.UnNext()
+			if p.IsEOF() {
+				return stmts, inlineLeftCurly, p.lex.Pos, nil
 			}
+			continue
+		}
 
-			emptyErr := p.lex.ReadEmptyStatement()
-			if emptyErr == nil {
-				stmt = &EmptyStatement{}
-				break
-			}
+		p.MaybeScanInlineComment(stmt)
+		stmts = append(stmts, stmt)
+	}
+}
+
+func (p *Parser) parseMessageBodyStatement(
+	token scanner.Token,
+	comments []*Comment,
+) (interface {
+	HasInlineCommentSetter
+	Visitee
+}, error) {
+	switch token {
+	case scanner.TENUM:
+		enum, err := p.ParseEnum()
+		if err != nil {
+			return nil, err
+		}
+		enum.Comments = comments
+		return enum, nil
+	case scanner.TMESSAGE:
+		message, err := p.ParseMessage()
+		if err != nil {
+			return nil, err
+		}
+		message.Comments = comments
+		return message, nil
+	case scanner.TOPTION:
+		option, err := p.ParseOption()
+		if err != nil {
+			return nil, err
+		}
+		option.Comments = comments
+		return option, nil
+	case scanner.TONEOF:
+		oneof, err := p.ParseOneof()
+		if err != nil {
+			return nil, err
+		}
+		oneof.Comments = comments
+		return oneof, nil
+	case scanner.TMAP:
+		mapField, err := p.ParseMapField()
+		if err != nil {
+			return nil, err
+		}
+		mapField.Comments = comments
+		return mapField, nil
+	case scanner.TEXTEND:
+		extend, err := p.ParseExtend()
+		if err != nil {
+			return nil, err
+		}
+		extend.Comments = comments
+		return extend, nil
+	case scanner.TRESERVED:
+		reserved, err := p.ParseReserved()
+		if err != nil {
+			return nil, err
+		}
+		reserved.Comments = comments
+		return reserved, nil
+	case scanner.TEXTENSIONS:
+		extensions, err := p.ParseExtensions()
+		if err != nil {
+			return nil, err
+		}
+		extensions.Comments = comments
+		return extensions, nil
+	default:
+		emptyErr := p.lex.ReadEmptyStatement()
+		if emptyErr == nil {
+			return p.newEmptyStatement(), nil
+		}
 
-			return nil, nil, scanner.Position{}, &parseMessageBodyStatementErr{
-				parseFieldErr:          ferr,
-				parseEmptyStatementErr: emptyErr,
+		var ferr error
+		isGroup := p.peekIsGroup()
+		if isGroup {
+			groupField, groupErr := p.ParseGroupField()
+			if groupErr == nil {
+				groupField.Comments = comments
+				return groupField, nil
+			}
+			ferr = groupErr
+		} else {
+			field, fieldErr := p.ParseField()
+			if fieldErr == nil {
+				field.Comments = comments
+				return field, nil
 			}
+			ferr = fieldErr
 		}
 
-		p.MaybeScanInlineComment(stmt)
-		stmts = append(stmts, stmt)
+		return nil, &parseMessageBodyStatementErr{
+			parseFieldErr:          ferr,
+			parseEmptyStatementErr: emptyErr,
+		}
 	}
 }
@@ -41,6 +41,22 @@ message Outer {
 					&parser.Option{
 						OptionName: "(my_option).a",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 42,
+									Line:   3,
+									Column: 26,
+								},
+								LastPos: meta.Position{
+									Offset: 45,
+									Line:   3,
+									Column: 29,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Offset: 19,
@@ -140,6 +156,22 @@ message outer {
 					&parser.Option{
 						OptionName: "(my_option).a",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 42,
+									Line:   3,
+									Column: 26,
+								},
+								LastPos: meta.Position{
+									Offset: 45,
+									Line:   3,
+									Column: 29,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Offset: 19,
@@ -312,6 +344,22 @@ message outer {
 					&parser.Option{
 						OptionName: "(my_option).a",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 54,
+									Line:   4,
+									Column: 26,
+								},
+								LastPos: meta.Position{
+									Offset: 57,
+									Line:   4,
+									Column: 29,
+								},
+							},
+						},
 						Comments: []*parser.Comment{
 							{
 								Raw: `// option`,
@@ -449,6 +497,22 @@ message outer {
 							&parser.Option{
 								OptionName: "allow_alias",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 237,
+											Line:   13,
+											Column: 26,
+										},
+										LastPos: meta.Position{
+											Offset: 240,
+											Line:   13,
+											Column: 29,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Offset: 216,
@@ -765,6 +829,22 @@ message SearchRequest {
 							&parser.Option{
 								OptionName: "allow_alias",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 227,
+											Line:   7,
+											Column: 26,
+										},
+										LastPos: meta.Position{
+											Offset: 230,
+											Line:   7,
+											Column: 29,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Offset: 206,
@@ -1021,6 +1101,22 @@ message Outer {
 					&parser.Option{
 						OptionName: "(my_option).a",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 42,
+									Line:   3,
+									Column: 26,
+								},
+								LastPos: meta.Position{
+									Offset: 45,
+									Line:   3,
+									Column: 29,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Offset: 19,
@@ -1109,6 +1205,18 @@ message Outer {
 							{
 								Begin: "20",
 								End:   "30",
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 158,
+										Line:   8,
+										Column: 14,
+									},
+									LastPos: meta.Position{
+										Offset: 165,
+										Line:   8,
+										Column: 21,
+									},
+								},
 							},
 						},
 						Meta: meta.Meta{
@@ -26,3 +26,57 @@ func (e *Error) SetOccured(occuredIn string, occuredAt int) {
 	e.occuredIn = occuredIn
 	e.occuredAt = occuredAt
 }
+
+// ErrorList is a list of *Errors, which is used to report all the errors found in the recovery mode.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// Limit is a kind of the limits on the input.
+type Limit int
+
+// The limits on the input.
+const (
+	// LimitNestingDepth limits the depth of the nested elements and option values.
+	LimitNestingDepth Limit = iota + 1
+	// LimitInputBytes limits the size of the input in bytes.
+	LimitInputBytes
+	// LimitTokens limits the number of the tokens in the input.
+	LimitTokens
+)
+
+func (l Limit) String() string {
+	switch l {
+	case LimitNestingDepth:
+		return "nesting depth"
+	case LimitInputBytes:
+		return "input bytes"
+	case LimitTokens:
+		return "tokens"
+	default:
+		return fmt.Sprintf("Limit(%d)", int(l))
+	}
+}
+
+// LimitError is the error returned when the input exceeds a limit.
+// The parse stops at the error even in the recovery mode.
+type LimitError struct {
+	// Pos is the source position at which the input exceeds the limit.
+	Pos Position
+	// Limit is the kind of the limit.
+	Limit Limit
+	// Max is the maximum which the limit allows.
+	Max int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: the %s exceeds the maximum %d", e.Pos, e.Limit, e.Max)
+}
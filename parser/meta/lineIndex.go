@@ -0,0 +1,110 @@
+package meta
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// LineIndex converts the positions in a source text among the byte offsets, the columns counting the characters,
+// which Position.Column holds, and the columns counting the UTF-16 code units, which editors like the Language Server
+// Protocol use.
+//
+// All of the lines and the columns start at 1. A position beyond the end of a line is regarded as the end of the line,
+// which is the position of the newline character, and a position beyond the end of the text is regarded as the end of the text.
+type LineIndex struct {
+	text string
+	// lineStarts holds the offsets at which the lines start.
+	lineStarts []int
+}
+
+// NewLineIndex creates a new LineIndex of the source text.
+func NewLineIndex(text []byte) *LineIndex {
+	x := &LineIndex{text: string(text), lineStarts: []int{0}}
+	for i := 0; i < len(x.text); i++ {
+		if x.text[i] == '\n' {
+			x.lineStarts = append(x.lineStarts, i+1)
+		}
+	}
+	return x
+}
+
+// LineCount returns the number of the lines.
+func (x *LineIndex) LineCount() int {
+	return len(x.lineStarts)
+}
+
+// Position returns the position of the byte offset, whose Column counts the characters like the scanner.
+func (x *LineIndex) Position(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if len(x.text) < offset {
+		offset = len(x.text)
+	}
+	line := sort.Search(len(x.lineStarts), func(i int) bool {
+		return offset < x.lineStarts[i]
+	})
+	start := x.lineStarts[line-1]
+	return Position{
+		Offset: offset,
+		Line:   line,
+		Column: utf8.RuneCountInString(x.text[start:offset]) + 1,
+	}
+}
+
+// Offset returns the byte offset of the line and the column, which counts the characters.
+func (x *LineIndex) Offset(line, column int) int {
+	offset, end := x.lineRange(line)
+	for i := 1; i < column && offset < end; i++ {
+		_, size := utf8.DecodeRuneInString(x.text[offset:end])
+		offset += size
+	}
+	return offset
+}
+
+// UTF16Column returns the column counting the UTF-16 code units of the line and the column, which counts the characters.
+func (x *LineIndex) UTF16Column(line, column int) int {
+	offset, end := x.lineRange(line)
+	utf16Column := 1
+	for i := 1; i < column && offset < end; i++ {
+		r, size := utf8.DecodeRuneInString(x.text[offset:end])
+		offset += size
+		utf16Column += utf16Len(r)
+	}
+	return utf16Column
+}
+
+// Column returns the column counting the characters of the line and the column, which counts the UTF-16 code units.
+// A column in the middle of a surrogate pair is regarded as the character next to the pair.
+func (x *LineIndex) Column(line, utf16Column int) int {
+	offset, end := x.lineRange(line)
+	column := 1
+	for units := 1; units < utf16Column && offset < end; column++ {
+		r, size := utf8.DecodeRuneInString(x.text[offset:end])
+		offset += size
+		units += utf16Len(r)
+	}
+	return column
+}
+
+// lineRange returns the offsets at which the line starts and ends, excluding the newline character.
+func (x *LineIndex) lineRange(line int) (int, int) {
+	if line < 1 {
+		return 0, 0
+	}
+	if len(x.lineStarts) < line {
+		return len(x.text), len(x.text)
+	}
+	if line == len(x.lineStarts) {
+		return x.lineStarts[line-1], len(x.text)
+	}
+	return x.lineStarts[line-1], x.lineStarts[line] - 1
+}
+
+// utf16Len returns the number of the UTF-16 code units which encode the rune.
+func utf16Len(r rune) int {
+	if 0x10000 <= r {
+		return 2
+	}
+	return 1
+}
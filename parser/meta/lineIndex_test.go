@@ -0,0 +1,203 @@
+package meta_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// lineIndexText has the characters which are encoded in 1, 2 and 4 bytes, a CRLF and an empty line.
+const lineIndexText = "a\né😀b\r\n\nx"
+
+func TestLineIndex_Position(t *testing.T) {
+	lines := meta.NewLineIndex([]byte(lineIndexText))
+
+	tests := []struct {
+		name        string
+		inputOffset int
+		wantPos     meta.Position
+	}{
+		{
+			name:        "the first character",
+			inputOffset: 0,
+			wantPos:     meta.Position{Offset: 0, Line: 1, Column: 1},
+		},
+		{
+			name:        "the character encoded in 4 bytes",
+			inputOffset: 4,
+			wantPos:     meta.Position{Offset: 4, Line: 2, Column: 2},
+		},
+		{
+			name:        "the character next to the one encoded in 4 bytes",
+			inputOffset: 8,
+			wantPos:     meta.Position{Offset: 8, Line: 2, Column: 3},
+		},
+		{
+			name:        "the newline character of a CRLF",
+			inputOffset: 10,
+			wantPos:     meta.Position{Offset: 10, Line: 2, Column: 5},
+		},
+		{
+			name:        "an empty line",
+			inputOffset: 11,
+			wantPos:     meta.Position{Offset: 11, Line: 3, Column: 1},
+		},
+		{
+			name:        "the end of the text",
+			inputOffset: 13,
+			wantPos:     meta.Position{Offset: 13, Line: 4, Column: 2},
+		},
+		{
+			name:        "beyond the end of the text",
+			inputOffset: 100,
+			wantPos:     meta.Position{Offset: 13, Line: 4, Column: 2},
+		},
+		{
+			name:        "a negative offset",
+			inputOffset: -1,
+			wantPos:     meta.Position{Offset: 0, Line: 1, Column: 1},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := lines.Position(test.inputOffset)
+			if !reflect.DeepEqual(got, test.wantPos) {
+				t.Errorf("got %v, but want %v", got, test.wantPos)
+			}
+			if offset := lines.Offset(got.Line, got.Column); offset != got.Offset {
+				t.Errorf("got %d, but want %d", offset, got.Offset)
+			}
+		})
+	}
+}
+
+func TestLineIndex_Position_scanner(t *testing.T) {
+	lines := meta.NewLineIndex([]byte(lineIndexText))
+
+	pos := scanner.NewPosition()
+	for _, r := range lineIndexText {
+		if got := lines.Position(pos.Offset); !reflect.DeepEqual(got, pos.Position) {
+			t.Errorf("got %v, but want %v", got, pos.Position)
+		}
+		pos.Advance(r)
+	}
+}
+
+func TestLineIndex_Offset(t *testing.T) {
+	lines := meta.NewLineIndex([]byte(lineIndexText))
+
+	tests := []struct {
+		name        string
+		inputLine   int
+		inputColumn int
+		wantOffset  int
+	}{
+		{
+			name:        "the character next to the one encoded in 4 bytes",
+			inputLine:   2,
+			inputColumn: 3,
+			wantOffset:  8,
+		},
+		{
+			name:        "beyond the end of the line",
+			inputLine:   2,
+			inputColumn: 10,
+			wantOffset:  10,
+		},
+		{
+			name:        "beyond the last line",
+			inputLine:   5,
+			inputColumn: 1,
+			wantOffset:  13,
+		},
+		{
+			name:        "the line before the first line",
+			inputLine:   0,
+			inputColumn: 1,
+			wantOffset:  0,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := lines.Offset(test.inputLine, test.inputColumn)
+			if got != test.wantOffset {
+				t.Errorf("got %d, but want %d", got, test.wantOffset)
+			}
+		})
+	}
+}
+
+func TestLineIndex_UTF16Column(t *testing.T) {
+	lines := meta.NewLineIndex([]byte(lineIndexText))
+
+	tests := []struct {
+		name             string
+		inputLine        int
+		inputColumn      int
+		wantUTF16Column  int
+		inputUTF16Column int
+		wantColumn       int
+	}{
+		{
+			name:             "the first character",
+			inputLine:        2,
+			inputColumn:      1,
+			wantUTF16Column:  1,
+			inputUTF16Column: 1,
+			wantColumn:       1,
+		},
+		{
+			name:             "the character encoded in a surrogate pair",
+			inputLine:        2,
+			inputColumn:      2,
+			wantUTF16Column:  2,
+			inputUTF16Column: 2,
+			wantColumn:       2,
+		},
+		{
+			name:             "the character next to the surrogate pair",
+			inputLine:        2,
+			inputColumn:      3,
+			wantUTF16Column:  4,
+			inputUTF16Column: 4,
+			wantColumn:       3,
+		},
+		{
+			name:             "the middle of the surrogate pair",
+			inputLine:        2,
+			inputColumn:      3,
+			wantUTF16Column:  4,
+			inputUTF16Column: 3,
+			wantColumn:       3,
+		},
+		{
+			name:             "beyond the end of the line",
+			inputLine:        2,
+			inputColumn:      100,
+			wantUTF16Column:  6,
+			inputUTF16Column: 100,
+			wantColumn:       5,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := lines.UTF16Column(test.inputLine, test.inputColumn)
+			if got != test.wantUTF16Column {
+				t.Errorf("got %d, but want %d", got, test.wantUTF16Column)
+			}
+
+			got = lines.Column(test.inputLine, test.inputUTF16Column)
+			if got != test.wantColumn {
+				t.Errorf("got %d, but want %d", got, test.wantColumn)
+			}
+		})
+	}
+}
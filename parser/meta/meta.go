@@ -4,8 +4,7 @@ package meta
 type Meta struct {
 	// Pos is the source position.
 	Pos Position
-	// LastPos is the last source position.
-	// Currently it is set when the parsed element type is
-	// syntax, package, comment, import, option, message, enum, oneof, rpc or service.
+	// LastPos is the source position of the last character.
+	// It is set for all of the parsed element types.
 	LastPos Position
 }
@@ -12,10 +12,19 @@ type OneofField struct {
 	FieldNumber  string
 	FieldOptions []*FieldOption
 
+	// TypeMeta is the meta information of the Type. It's set with WithDetailedPositions.
+	TypeMeta meta.Meta
+	// FieldNameMeta is the meta information of the FieldName. It's set with WithDetailedPositions.
+	FieldNameMeta meta.Meta
+	// FieldNumberMeta is the meta information of the FieldNumber. It's set with WithDetailedPositions.
+	FieldNumberMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -46,12 +55,17 @@ type Oneof struct {
 
 	Options []*Option
 
+	// OneofNameMeta is the meta information of the OneofName. It's set with WithDetailedPositions.
+	OneofNameMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
 	// InlineCommentBehindLeftCurly is the optional one placed behind a left curly.
 	InlineCommentBehindLeftCurly *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -87,6 +101,10 @@ func (o *Oneof) Accept(v Visitor) {
 //
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#oneof_and_oneof_field
 func (p *Parser) ParseOneof() (*Oneof, error) {
+	defer p.leave()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
 	p.lex.NextKeyword()
 	if p.lex.Token != scanner.TONEOF {
 		return nil, p.unexpected("oneof")
@@ -98,6 +116,7 @@ func (p *Parser) ParseOneof() (*Oneof, error) {
 		return nil, p.unexpected("oneofName")
 	}
 	oneofName := p.lex.Text
+	oneofNameMeta := p.tokenMeta()
 
 	p.lex.Next()
 	if p.lex.Token != scanner.TLEFTCURLY {
@@ -110,6 +129,7 @@ func (p *Parser) ParseOneof() (*Oneof, error) {
 	var options []*Option
 	for {
 		comments := p.ParseComments()
+		depth := p.lex.CurlyDepth()
 
 		err := p.lex.ReadEmptyStatement()
 		if err == nil {
@@ -123,19 +143,31 @@ func (p *Parser) ParseOneof() (*Oneof, error) {
 			// See https://github.com/yoheimuta/go-protoparser/issues/57
 			option, err := p.ParseOption()
 			if err != nil {
-				return nil, err
+				if !p.recoverFrom(err, depth, true) {
+					return nil, err
+				}
+				if p.IsEOF() {
+					break
+				}
+			} else {
+				option.Comments = comments
+				p.MaybeScanInlineComment(option)
+				options = append(options, option)
 			}
-			option.Comments = comments
-			p.MaybeScanInlineComment(option)
-			options = append(options, option)
 		} else {
 			oneofField, err := p.parseOneofField()
 			if err != nil {
-				return nil, err
+				if !p.recoverFrom(err, depth, true) {
+					return nil, err
+				}
+				if p.IsEOF() {
+					break
+				}
+			} else {
+				oneofField.Comments = comments
+				p.MaybeScanInlineComment(oneofField)
+				oneofFields = append(oneofFields, oneofField)
 			}
-			oneofField.Comments = comments
-			p.MaybeScanInlineComment(oneofField)
-			oneofFields = append(oneofFields, oneofField)
 		}
 
 		p.lex.Next()
@@ -159,6 +191,7 @@ func (p *Parser) ParseOneof() (*Oneof, error) {
 		OneofFields:                  oneofFields,
 		OneofName:                    oneofName,
 		Options:                      options,
+		OneofNameMeta:                p.partMeta(oneofNameMeta),
 		InlineCommentBehindLeftCurly: inlineLeftCurly,
 		Meta: meta.Meta{
 			Pos:     startPos.Position,
@@ -170,7 +203,7 @@ func (p *Parser) ParseOneof() (*Oneof, error) {
 // oneofField = type fieldName "=" fieldNumber [ "[" fieldOptions "]" ] ";"
 // https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#oneof_and_oneof_field
 func (p *Parser) parseOneofField() (*OneofField, error) {
-	typeValue, startPos, err := p.parseType()
+	typeValue, typeMeta, err := p.parseType()
 	if err != nil {
 		return nil, p.unexpected("type")
 	}
@@ -180,13 +213,14 @@ func (p *Parser) parseOneofField() (*OneofField, error) {
 		return nil, p.unexpected("fieldName")
 	}
 	fieldName := p.lex.Text
+	fieldNameMeta := p.tokenMeta()
 
 	p.lex.Next()
 	if p.lex.Token != scanner.TEQUALS {
 		return nil, p.unexpected("=")
 	}
 
-	fieldNumber, err := p.parseFieldNumber()
+	fieldNumber, fieldNumberMeta, err := p.parseFieldNumber()
 	if err != nil {
 		return nil, p.unexpected("fieldNumber")
 	}
@@ -202,10 +236,13 @@ func (p *Parser) parseOneofField() (*OneofField, error) {
 	}
 
 	return &OneofField{
-		Type:         typeValue,
-		FieldName:    fieldName,
-		FieldNumber:  fieldNumber,
-		FieldOptions: fieldOptions,
-		Meta:         meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
+		Type:            typeValue,
+		FieldName:       fieldName,
+		FieldNumber:     fieldNumber,
+		FieldOptions:    fieldOptions,
+		TypeMeta:        p.partMeta(typeMeta),
+		FieldNameMeta:   p.partMeta(fieldNameMeta),
+		FieldNumberMeta: p.partMeta(fieldNumberMeta),
+		Meta:            meta.Meta{Pos: typeMeta.Pos, LastPos: p.lex.Pos.Position},
 	}, nil
 }
@@ -462,6 +462,66 @@ func TestParser_ParseOneof(t *testing.T) {
 							{
 								OptionName: "(validator.field)",
 								Constant:   "{int_gt:20}",
+								Value: &parser.OptionValue{
+									Kind: parser.OptionValueKindMessage,
+									Fields: []*parser.OptionValueField{
+										{
+											Name: "int_gt",
+											Value: &parser.OptionValue{
+												Kind:   parser.OptionValueKindInt,
+												Scalar: "20",
+												Meta: meta.Meta{
+													Pos: meta.Position{
+														Offset: 118,
+														Line:   3,
+														Column: 54,
+													},
+													LastPos: meta.Position{
+														Offset: 119,
+														Line:   3,
+														Column: 55,
+													},
+												},
+											},
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Offset: 110,
+													Line:   3,
+													Column: 46,
+												},
+												LastPos: meta.Position{
+													Offset: 119,
+													Line:   3,
+													Column: 55,
+												},
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 109,
+											Line:   3,
+											Column: 45,
+										},
+										LastPos: meta.Position{
+											Offset: 120,
+											Line:   3,
+											Column: 56,
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 89,
+										Line:   3,
+										Column: 25,
+									},
+									LastPos: meta.Position{
+										Offset: 120,
+										Line:   3,
+										Column: 56,
+									},
+								},
 							},
 						},
 						Meta: meta.Meta{
@@ -485,6 +545,66 @@ func TestParser_ParseOneof(t *testing.T) {
 							{
 								OptionName: "(validator.field)",
 								Constant:   "{int_gt:100}",
+								Value: &parser.OptionValue{
+									Kind: parser.OptionValueKindMessage,
+									Fields: []*parser.OptionValueField{
+										{
+											Name: "int_gt",
+											Value: &parser.OptionValue{
+												Kind:   parser.OptionValueKindInt,
+												Scalar: "100",
+												Meta: meta.Meta{
+													Pos: meta.Position{
+														Offset: 176,
+														Line:   4,
+														Column: 53,
+													},
+													LastPos: meta.Position{
+														Offset: 178,
+														Line:   4,
+														Column: 55,
+													},
+												},
+											},
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Offset: 168,
+													Line:   4,
+													Column: 45,
+												},
+												LastPos: meta.Position{
+													Offset: 178,
+													Line:   4,
+													Column: 55,
+												},
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 167,
+											Line:   4,
+											Column: 44,
+										},
+										LastPos: meta.Position{
+											Offset: 179,
+											Line:   4,
+											Column: 56,
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 147,
+										Line:   4,
+										Column: 24,
+									},
+									LastPos: meta.Position{
+										Offset: 179,
+										Line:   4,
+										Column: 56,
+									},
+								},
 							},
 						},
 						Meta: meta.Meta{
@@ -508,6 +628,66 @@ func TestParser_ParseOneof(t *testing.T) {
 							{
 								OptionName: "(validator.field)",
 								Constant:   "{regex:\"^[a-z]{2,5}$\"}",
+								Value: &parser.OptionValue{
+									Kind: parser.OptionValueKindMessage,
+									Fields: []*parser.OptionValueField{
+										{
+											Name: "regex",
+											Value: &parser.OptionValue{
+												Kind:   parser.OptionValueKindString,
+												Scalar: `"^[a-z]{2,5}$"`,
+												Meta: meta.Meta{
+													Pos: meta.Position{
+														Offset: 236,
+														Line:   5,
+														Column: 54,
+													},
+													LastPos: meta.Position{
+														Offset: 249,
+														Line:   5,
+														Column: 67,
+													},
+												},
+											},
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Offset: 229,
+													Line:   5,
+													Column: 47,
+												},
+												LastPos: meta.Position{
+													Offset: 249,
+													Line:   5,
+													Column: 67,
+												},
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 228,
+											Line:   5,
+											Column: 46,
+										},
+										LastPos: meta.Position{
+											Offset: 250,
+											Line:   5,
+											Column: 68,
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 208,
+										Line:   5,
+										Column: 26,
+									},
+									LastPos: meta.Position{
+										Offset: 250,
+										Line:   5,
+										Column: 68,
+									},
+								},
 							},
 						},
 						Meta: meta.Meta{
@@ -528,6 +708,54 @@ func TestParser_ParseOneof(t *testing.T) {
 					{
 						OptionName: "(validator.oneof)",
 						Constant:   "{required:true}",
+						Value: &parser.OptionValue{
+							Kind: parser.OptionValueKindMessage,
+							Fields: []*parser.OptionValueField{
+								{
+									Name: "required",
+									Value: &parser.OptionValue{
+										Kind:   parser.OptionValueKindBool,
+										Scalar: "true",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 58,
+												Line:   2,
+												Column: 41,
+											},
+											LastPos: meta.Position{
+												Offset: 61,
+												Line:   2,
+												Column: 44,
+											},
+										},
+									},
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 48,
+											Line:   2,
+											Column: 31,
+										},
+										LastPos: meta.Position{
+											Offset: 61,
+											Line:   2,
+											Column: 44,
+										},
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 47,
+									Line:   2,
+									Column: 30,
+								},
+								LastPos: meta.Position{
+									Offset: 62,
+									Line:   2,
+									Column: 45,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Offset: 20,
@@ -568,6 +796,22 @@ func TestParser_ParseOneof(t *testing.T) {
 					{
 						OptionName: "(my_option).a",
 						Constant:   "true",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindBool,
+							Scalar: "true",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 43,
+									Line:   2,
+									Column: 26,
+								},
+								LastPos: meta.Position{
+									Offset: 46,
+									Line:   2,
+									Column: 29,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Offset: 20,
@@ -11,11 +11,15 @@ import (
 type Option struct {
 	OptionName string
 	Constant   string
+	// Value is the typed tree of the Constant.
+	Value *OptionValue
 
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -50,7 +54,7 @@ func (p *Parser) ParseOption() (*Option, error) {
 	}
 	startPos := p.lex.Pos
 
-	optionName, err := p.parseOptionName()
+	optionName, _, err := p.parseOptionName()
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +64,7 @@ func (p *Parser) ParseOption() (*Option, error) {
 		return nil, p.unexpected("=")
 	}
 
-	constant, err := p.parseOptionConstant()
+	constant, value, err := p.parseOptionConstant()
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +77,7 @@ func (p *Parser) ParseOption() (*Option, error) {
 	return &Option{
 		OptionName: optionName,
 		Constant:   constant,
+		Value:      value,
 		Meta: meta.Meta{
 			Pos:     startPos.Position,
 			LastPos: p.lex.Pos.Position,
@@ -83,28 +88,36 @@ func (p *Parser) ParseOption() (*Option, error) {
 // cloudEndpointsOptionConstant = "{" ident ":" constant { ( ["," | ";" ] ident ":" constant | cloudEndpointsOptionConstant ) } ["," | ";"] "}"
 //
 // See https://cloud.google.com/endpoints/docs/grpc-service-config/reference/rpc/google.api
-func (p *Parser) parseCloudEndpointsOptionConstant() (string, error) {
+func (p *Parser) parseCloudEndpointsOptionConstant() (string, *OptionValue, error) {
 	var ret string
 
 	p.lex.Next()
 	if p.lex.Token != scanner.TLEFTCURLY {
-		return "", p.unexpected("{")
+		return "", nil, p.unexpected("{")
 	}
 	ret += p.lex.Text
+	value := &OptionValue{
+		Kind: OptionValueKindMessage,
+		Meta: meta.Meta{Pos: p.lex.Pos.Position},
+	}
 
 	for {
 		p.lex.Next()
 		if p.lex.Token != scanner.TIDENT {
-			return "", p.unexpected("ident")
+			return "", nil, p.unexpected("ident")
 		}
 		ret += p.lex.Text
+		field := &OptionValueField{
+			Name: p.lex.Text,
+			Meta: meta.Meta{Pos: p.lex.Pos.Position},
+		}
 
 		needSemi := false
 		p.lex.Next()
 		switch p.lex.Token {
 		case scanner.TLEFTCURLY:
 			if !p.permissive {
-				return "", p.unexpected(":")
+				return "", nil, p.unexpected(":")
 			}
 			p.lex.UnNext()
 		case scanner.TCOLON:
@@ -114,16 +127,19 @@ func (p *Parser) parseCloudEndpointsOptionConstant() (string, error) {
 			}
 		default:
 			if p.permissive {
-				return "", p.unexpected("{ or :")
+				return "", nil, p.unexpected("{ or :")
 			}
-			return "", p.unexpected(":")
+			return "", nil, p.unexpected(":")
 		}
 
-		constant, err := p.parseOptionConstant()
+		constant, fieldValue, err := p.parseOptionConstant()
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		ret += constant
+		field.Value = fieldValue
+		field.Meta.LastPos = fieldValue.Meta.LastPos
+		value.Fields = append(value.Fields, field)
 
 		p.lex.Next()
 		if p.lex.Token == scanner.TSEMICOLON && needSemi && p.permissive {
@@ -137,11 +153,13 @@ func (p *Parser) parseCloudEndpointsOptionConstant() (string, error) {
 			if p.lex.Peek() == scanner.TRIGHTCURLY && p.permissive {
 				p.lex.Next()
 				ret += p.lex.Text
-				return ret, nil
+				value.Meta.LastPos = p.lex.Pos.Position
+				return ret, value, nil
 			}
 		case p.lex.Token == scanner.TRIGHTCURLY:
 			ret += p.lex.Text
-			return ret, nil
+			value.Meta.LastPos = p.lex.Pos.Position
+			return ret, value, nil
 		default:
 			ret += "\n"
 			p.lex.UnNext()
@@ -149,41 +167,30 @@ func (p *Parser) parseCloudEndpointsOptionConstant() (string, error) {
 	}
 }
 
-// optionName = ( ident | "(" fullIdent ")" ) { "." ident }
-func (p *Parser) parseOptionName() (string, error) {
+// optionName = ( ident | "(" fullIdent ")" ) { "." ( ident | "(" fullIdent ")" ) }
+//
+// The parenthesized name following a dot is used by editions features like "features.(pb.cpp).legacy_closed_enum".
+// See https://protobuf.dev/reference/protobuf/edition-2023-spec/#option
+func (p *Parser) parseOptionName() (string, meta.Meta, error) {
 	var optionName string
 
 	p.lex.Next()
+	startPos := p.lex.Pos
 	switch p.lex.Token {
 	case scanner.TIDENT:
 		optionName = p.lex.Text
 	case scanner.TLEFTPAREN:
-		optionName = p.lex.Text
-
-		// protoc accepts "(." fullIndent ")". See #63
-		if p.permissive {
-			p.lex.Next()
-			if p.lex.Token == scanner.TDOT {
-				optionName += "."
-			} else {
-				p.lex.UnNext()
-			}
-		}
-
-		fullIdent, _, err := p.lex.ReadFullIdent()
+		p.lex.UnNext()
+		name, err := p.parseOptionExtensionName()
 		if err != nil {
-			return "", err
+			return "", meta.Meta{}, err
 		}
-		optionName += fullIdent
-
-		p.lex.Next()
-		if p.lex.Token != scanner.TRIGHTPAREN {
-			return "", p.unexpected(")")
-		}
-		optionName += p.lex.Text
+		optionName = name
 	default:
-		return "", p.unexpected("ident or left paren")
+		return "", meta.Meta{}, p.unexpected("ident or left paren")
 	}
+	// the current token is the last one of the ident or the extension name.
+	lastPos := p.lex.Pos.AdvancedBulk(p.lex.Text)
 
 	for {
 		p.lex.Next()
@@ -194,70 +201,133 @@ func (p *Parser) parseOptionName() (string, error) {
 		optionName += p.lex.Text
 
 		p.lex.Next()
-		if p.lex.Token != scanner.TIDENT {
-			return "", p.unexpected("ident")
+		switch p.lex.Token {
+		case scanner.TIDENT:
+			optionName += p.lex.Text
+		case scanner.TLEFTPAREN:
+			p.lex.UnNext()
+			name, err := p.parseOptionExtensionName()
+			if err != nil {
+				return "", meta.Meta{}, err
+			}
+			optionName += name
+		default:
+			return "", meta.Meta{}, p.unexpected("ident or left paren")
 		}
-		optionName += p.lex.Text
+		lastPos = p.lex.Pos.AdvancedBulk(p.lex.Text)
 	}
-	return optionName, nil
+	return optionName, spanMeta(startPos, lastPos), nil
 }
 
-func (p *Parser) parseOptionConstant() (constant string, err error) {
+// "(" fullIdent ")"
+func (p *Parser) parseOptionExtensionName() (string, error) {
+	p.lex.Next()
+	if p.lex.Token != scanner.TLEFTPAREN {
+		return "", p.unexpected("(")
+	}
+	name := p.lex.Text
+
+	// protoc accepts "(." fullIndent ")". See #63
+	if p.permissive {
+		p.lex.Next()
+		if p.lex.Token == scanner.TDOT {
+			name += "."
+		} else {
+			p.lex.UnNext()
+		}
+	}
+
+	fullIdent, _, err := p.lex.ReadFullIdent()
+	if err != nil {
+		return "", err
+	}
+	name += fullIdent
+
+	p.lex.Next()
+	if p.lex.Token != scanner.TRIGHTPAREN {
+		return "", p.unexpected(")")
+	}
+	name += p.lex.Text
+	return name, nil
+}
+
+func (p *Parser) parseOptionConstant() (constant string, value *OptionValue, err error) {
+	defer p.leave()
+	if err := p.enter(); err != nil {
+		return "", nil, err
+	}
 	switch p.lex.Peek() {
 	// Cloud Endpoints requires this exception.
 	case scanner.TLEFTCURLY:
 		if !p.permissive {
-			return "", p.unexpected("constant or permissive mode")
+			return "", nil, p.unexpected("constant or permissive mode")
 		}
 
 		// parses empty fields within an option
 		if p.lex.PeekN(2) == scanner.TRIGHTCURLY {
-			p.lex.NextN(2)
-			return "{}", nil
+			p.lex.Next()
+			startPos := p.lex.Pos
+			p.lex.Next()
+			return "{}", &OptionValue{
+				Kind: OptionValueKindMessage,
+				Meta: meta.Meta{
+					Pos:     startPos.Position,
+					LastPos: p.lex.Pos.Position,
+				},
+			}, nil
 		}
 
-		constant, err = p.parseCloudEndpointsOptionConstant()
+		constant, value, err = p.parseCloudEndpointsOptionConstant()
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 
 	case scanner.TLEFTSQUARE:
 		if !p.permissive {
-			return "", p.unexpected("constant or permissive mode")
+			return "", nil, p.unexpected("constant or permissive mode")
 		}
 		p.lex.Next()
+		value = &OptionValue{
+			Kind: OptionValueKindList,
+			Meta: meta.Meta{Pos: p.lex.Pos.Position},
+		}
 
 		// parses empty fields within an option
 		if p.lex.Peek() == scanner.TRIGHTSQUARE {
 			p.lex.Next()
-			return "[]", nil
+			value.Meta.LastPos = p.lex.Pos.Position
+			return "[]", value, nil
 		}
 
-		constant, err = p.parseOptionConstants()
+		constant, value.Elements, err = p.parseOptionConstants()
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		p.lex.Next()
 		constant = "[" + constant + "]"
+		value.Meta.LastPos = p.lex.Pos.Position
 
 	default:
-		constant, _, err = p.lex.ReadConstant(p.permissive)
+		lit, err := p.lex.ReadConstantLit(p.permissive)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
+		constant = lit.Text
+		value = newScalarOptionValue(lit)
 	}
-	return constant, nil
+	return constant, value, nil
 }
 
 // optionConstants = optionConstant { ","  optionConstant }
-func (p *Parser) parseOptionConstants() (constant string, err error) {
-	opt, err := p.parseOptionConstant()
+func (p *Parser) parseOptionConstants() (constant string, values []*OptionValue, err error) {
+	opt, value, err := p.parseOptionConstant()
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	var opts []string
 	opts = append(opts, opt)
+	values = append(values, value)
 
 	for {
 		p.lex.Next()
@@ -266,11 +336,12 @@ func (p *Parser) parseOptionConstants() (constant string, err error) {
 			break
 		}
 
-		opt, err = p.parseOptionConstant()
+		opt, value, err = p.parseOptionConstant()
 		if err != nil {
-			return "", p.unexpected("optionConstant")
+			return "", nil, p.unexpected("optionConstant")
 		}
 		opts = append(opts, opt)
+		values = append(values, value)
 	}
-	return strings.Join(opts, ","), nil
+	return strings.Join(opts, ","), values, nil
 }
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// OptionValueKind is a kind of the OptionValue.
+type OptionValueKind uint
+
+// OptionValueKind values.
+const (
+	OptionValueKindInt OptionValueKind = iota
+	OptionValueKindFloat
+	OptionValueKindBool
+	OptionValueKindString
+	OptionValueKindIdent
+	OptionValueKindMessage
+	OptionValueKindList
+)
+
+// OptionValue is a typed tree of an option constant.
+type OptionValue struct {
+	Kind OptionValueKind
+	// Scalar is the text of a value whose Kind is int, float, bool, string or ident.
+	// It keeps the sign of a number and the quotes of a string. The adjacent strings are merged into one in the permissive mode.
+	Scalar string
+	// Fields are the named fields of a message literal.
+	Fields []*OptionValueField
+	// Elements are the elements of a list literal.
+	Elements []*OptionValue
+
+	// Meta is the meta information.
+	Meta meta.Meta
+}
+
+// Field returns the value of the first field named the given name within a message literal, or nil.
+func (v *OptionValue) Field(name string) *OptionValue {
+	for _, f := range v.Fields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return nil
+}
+
+// OptionValueField is a named field of a message literal.
+type OptionValueField struct {
+	Name  string
+	Value *OptionValue
+
+	// Meta is the meta information.
+	Meta meta.Meta
+}
+
+func newScalarOptionValue(lit *lexer.ConstantLit) *OptionValue {
+	var kind OptionValueKind
+	switch lit.Token {
+	case scanner.TINTLIT:
+		kind = OptionValueKindInt
+	case scanner.TFLOATLIT:
+		kind = OptionValueKindFloat
+	case scanner.TBOOLLIT:
+		kind = OptionValueKindBool
+	case scanner.TSTRLIT:
+		kind = OptionValueKindString
+	default:
+		kind = OptionValueKindIdent
+	}
+	return &OptionValue{
+		Kind:   kind,
+		Scalar: lit.Text,
+		Meta: meta.Meta{
+			Pos:     lit.Pos.Position,
+			LastPos: lit.LastPos.Position,
+		},
+	}
+}
@@ -43,6 +43,22 @@ func TestParser_ParseOption(t *testing.T) {
 			wantOption: &parser.Option{
 				OptionName: "java_package",
 				Constant:   `"com.example.foo"`,
+				Value: &parser.OptionValue{
+					Kind:   parser.OptionValueKindString,
+					Scalar: `"com.example.foo"`,
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 22,
+							Line:   1,
+							Column: 23,
+						},
+						LastPos: meta.Position{
+							Offset: 38,
+							Line:   1,
+							Column: 39,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 0,
@@ -63,6 +79,22 @@ func TestParser_ParseOption(t *testing.T) {
 			wantOption: &parser.Option{
 				OptionName: "(my_option).a",
 				Constant:   `true`,
+				Value: &parser.OptionValue{
+					Kind:   parser.OptionValueKindBool,
+					Scalar: "true",
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 23,
+							Line:   1,
+							Column: 24,
+						},
+						LastPos: meta.Position{
+							Offset: 26,
+							Line:   1,
+							Column: 27,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 0,
@@ -83,6 +115,22 @@ func TestParser_ParseOption(t *testing.T) {
 			wantOption: &parser.Option{
 				OptionName: "java_package.baz.bar",
 				Constant:   `"com.example.foo"`,
+				Value: &parser.OptionValue{
+					Kind:   parser.OptionValueKindString,
+					Scalar: `"com.example.foo"`,
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 30,
+							Line:   1,
+							Column: 31,
+						},
+						LastPos: meta.Position{
+							Offset: 46,
+							Line:   1,
+							Column: 47,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 0,
@@ -109,6 +157,85 @@ option (google.api.http) = {
 				OptionName: "(google.api.http)",
 				Constant: `{get:"/v1/projects/{project_id}/aggregated/addresses"
 rest_collection:"projects.addresses"}`,
+				Value: &parser.OptionValue{
+					Kind: parser.OptionValueKindMessage,
+					Fields: []*parser.OptionValueField{
+						{
+							Name: "get",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"/v1/projects/{project_id}/aggregated/addresses"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 39,
+										Line:   3,
+										Column: 10,
+									},
+									LastPos: meta.Position{
+										Offset: 86,
+										Line:   3,
+										Column: 57,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 34,
+									Line:   3,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 86,
+									Line:   3,
+									Column: 57,
+								},
+							},
+						},
+						{
+							Name: "rest_collection",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"projects.addresses"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 109,
+										Line:   4,
+										Column: 22,
+									},
+									LastPos: meta.Position{
+										Offset: 128,
+										Line:   4,
+										Column: 41,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 92,
+									Line:   4,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 128,
+									Line:   4,
+									Column: 41,
+								},
+							},
+						},
+					},
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 28,
+							Line:   2,
+							Column: 28,
+						},
+						LastPos: meta.Position{
+							Offset: 130,
+							Line:   5,
+							Column: 1,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 1,
@@ -135,6 +262,116 @@ option (google.api.http) = {
 			wantOption: &parser.Option{
 				OptionName: "(google.api.http)",
 				Constant:   `{post:"/v1/resources",body:"resource",rest_method_name:"insert"}`,
+				Value: &parser.OptionValue{
+					Kind: parser.OptionValueKindMessage,
+					Fields: []*parser.OptionValueField{
+						{
+							Name: "post",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"/v1/resources"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 40,
+										Line:   3,
+										Column: 11,
+									},
+									LastPos: meta.Position{
+										Offset: 54,
+										Line:   3,
+										Column: 25,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 34,
+									Line:   3,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 54,
+									Line:   3,
+									Column: 25,
+								},
+							},
+						},
+						{
+							Name: "body",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"resource"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 67,
+										Line:   4,
+										Column: 11,
+									},
+									LastPos: meta.Position{
+										Offset: 76,
+										Line:   4,
+										Column: 20,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 61,
+									Line:   4,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 76,
+									Line:   4,
+									Column: 20,
+								},
+							},
+						},
+						{
+							Name: "rest_method_name",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"insert"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 101,
+										Line:   5,
+										Column: 23,
+									},
+									LastPos: meta.Position{
+										Offset: 108,
+										Line:   5,
+										Column: 30,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 83,
+									Line:   5,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 108,
+									Line:   5,
+									Column: 30,
+								},
+							},
+						},
+					},
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 28,
+							Line:   2,
+							Column: 28,
+						},
+						LastPos: meta.Position{
+							Offset: 110,
+							Line:   6,
+							Column: 1,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 1,
@@ -162,6 +399,116 @@ option (google.api.http) = {
 			wantOption: &parser.Option{
 				OptionName: "(google.api.http)",
 				Constant:   `{post:"/v1/resources",body:"resource",rest_method_name:"insert"}`,
+				Value: &parser.OptionValue{
+					Kind: parser.OptionValueKindMessage,
+					Fields: []*parser.OptionValueField{
+						{
+							Name: "post",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"/v1/resources"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 40,
+										Line:   3,
+										Column: 11,
+									},
+									LastPos: meta.Position{
+										Offset: 54,
+										Line:   3,
+										Column: 25,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 34,
+									Line:   3,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 54,
+									Line:   3,
+									Column: 25,
+								},
+							},
+						},
+						{
+							Name: "body",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"resource"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 67,
+										Line:   4,
+										Column: 11,
+									},
+									LastPos: meta.Position{
+										Offset: 87,
+										Line:   5,
+										Column: 15,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 61,
+									Line:   4,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 87,
+									Line:   5,
+									Column: 15,
+								},
+							},
+						},
+						{
+							Name: "rest_method_name",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"insert"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 112,
+										Line:   6,
+										Column: 23,
+									},
+									LastPos: meta.Position{
+										Offset: 119,
+										Line:   6,
+										Column: 30,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 94,
+									Line:   6,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 119,
+									Line:   6,
+									Column: 30,
+								},
+							},
+						},
+					},
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 28,
+							Line:   2,
+							Column: 28,
+						},
+						LastPos: meta.Position{
+							Offset: 121,
+							Line:   7,
+							Column: 1,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 1,
@@ -189,6 +536,117 @@ option (google.api.http) = {
 			wantOption: &parser.Option{
 				OptionName: "(google.api.http)",
 				Constant:   `{post:"/v1/resources",additional_bindings:{post:"/v2/resources"};}`,
+				Value: &parser.OptionValue{
+					Kind: parser.OptionValueKindMessage,
+					Fields: []*parser.OptionValueField{
+						{
+							Name: "post",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"/v1/resources"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 40,
+										Line:   3,
+										Column: 11,
+									},
+									LastPos: meta.Position{
+										Offset: 54,
+										Line:   3,
+										Column: 25,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 34,
+									Line:   3,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 54,
+									Line:   3,
+									Column: 25,
+								},
+							},
+						},
+						{
+							Name: "additional_bindings",
+							Value: &parser.OptionValue{
+								Kind: parser.OptionValueKindMessage,
+								Fields: []*parser.OptionValueField{
+									{
+										Name: "post",
+										Value: &parser.OptionValue{
+											Kind:   parser.OptionValueKindString,
+											Scalar: `"/v2/resources"`,
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Offset: 92,
+													Line:   5,
+													Column: 9,
+												},
+												LastPos: meta.Position{
+													Offset: 106,
+													Line:   5,
+													Column: 23,
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 86,
+												Line:   5,
+												Column: 3,
+											},
+											LastPos: meta.Position{
+												Offset: 106,
+												Line:   5,
+												Column: 23,
+											},
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 82,
+										Line:   4,
+										Column: 26,
+									},
+									LastPos: meta.Position{
+										Offset: 109,
+										Line:   6,
+										Column: 2,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 61,
+									Line:   4,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 109,
+									Line:   6,
+									Column: 2,
+								},
+							},
+						},
+					},
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 28,
+							Line:   2,
+							Column: 28,
+						},
+						LastPos: meta.Position{
+							Offset: 112,
+							Line:   7,
+							Column: 1,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 1,
@@ -214,6 +672,85 @@ option (google.api.http) = {
 			wantOption: &parser.Option{
 				OptionName: "(google.api.http)",
 				Constant:   `{post:"/v1/resources",body:"data",}`,
+				Value: &parser.OptionValue{
+					Kind: parser.OptionValueKindMessage,
+					Fields: []*parser.OptionValueField{
+						{
+							Name: "post",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"/v1/resources"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 40,
+										Line:   3,
+										Column: 11,
+									},
+									LastPos: meta.Position{
+										Offset: 54,
+										Line:   3,
+										Column: 25,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 34,
+									Line:   3,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 54,
+									Line:   3,
+									Column: 25,
+								},
+							},
+						},
+						{
+							Name: "body",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"data"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 67,
+										Line:   4,
+										Column: 11,
+									},
+									LastPos: meta.Position{
+										Offset: 72,
+										Line:   4,
+										Column: 16,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 61,
+									Line:   4,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 72,
+									Line:   4,
+									Column: 16,
+								},
+							},
+						},
+					},
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 28,
+							Line:   2,
+							Column: 28,
+						},
+						LastPos: meta.Position{
+							Offset: 75,
+							Line:   5,
+							Column: 1,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 1,
@@ -241,6 +778,115 @@ option (opt) = {
 			wantOption: &parser.Option{
 				OptionName: "(opt)",
 				Constant:   `{empty:{},inner_empty:{empty:{},},}`,
+				Value: &parser.OptionValue{
+					Kind: parser.OptionValueKindMessage,
+					Fields: []*parser.OptionValueField{
+						{
+							Name: "empty",
+							Value: &parser.OptionValue{
+								Kind: parser.OptionValueKindMessage,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 30,
+										Line:   3,
+										Column: 13,
+									},
+									LastPos: meta.Position{
+										Offset: 31,
+										Line:   3,
+										Column: 14,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 22,
+									Line:   3,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 31,
+									Line:   3,
+									Column: 14,
+								},
+							},
+						},
+						{
+							Name: "inner_empty",
+							Value: &parser.OptionValue{
+								Kind: parser.OptionValueKindMessage,
+								Fields: []*parser.OptionValueField{
+									{
+										Name: "empty",
+										Value: &parser.OptionValue{
+											Kind: parser.OptionValueKindMessage,
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Offset: 67,
+													Line:   5,
+													Column: 14,
+												},
+												LastPos: meta.Position{
+													Offset: 68,
+													Line:   5,
+													Column: 15,
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 59,
+												Line:   5,
+												Column: 6,
+											},
+											LastPos: meta.Position{
+												Offset: 68,
+												Line:   5,
+												Column: 15,
+											},
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 52,
+										Line:   4,
+										Column: 19,
+									},
+									LastPos: meta.Position{
+										Offset: 72,
+										Line:   6,
+										Column: 2,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 38,
+									Line:   4,
+									Column: 5,
+								},
+								LastPos: meta.Position{
+									Offset: 72,
+									Line:   6,
+									Column: 2,
+								},
+							},
+						},
+					},
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 16,
+							Line:   2,
+							Column: 16,
+						},
+						LastPos: meta.Position{
+							Offset: 75,
+							Line:   7,
+							Column: 1,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 1,
@@ -278,6 +924,244 @@ option (google.api.http) = {
 additional_bindings:[{patch:"/v2/example/a_bit_of_everything/{abe.uuid}"
 body:"abe"},{patch:"/v2a/example/a_bit_of_everything/{abe.uuid}"
 body:"*"}]}`,
+				Value: &parser.OptionValue{
+					Kind: parser.OptionValueKindMessage,
+					Fields: []*parser.OptionValueField{
+						{
+							Name: "put",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindString,
+								Scalar: `"/v2/example/a_bit_of_everything/{abe.uuid}"`,
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 36,
+										Line:   3,
+										Column: 7,
+									},
+									LastPos: meta.Position{
+										Offset: 79,
+										Line:   3,
+										Column: 50,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 31,
+									Line:   3,
+									Column: 2,
+								},
+								LastPos: meta.Position{
+									Offset: 79,
+									Line:   3,
+									Column: 50,
+								},
+							},
+						},
+						{
+							Name: "additional_bindings",
+							Value: &parser.OptionValue{
+								Kind: parser.OptionValueKindList,
+								Elements: []*parser.OptionValue{
+									{
+										Kind: parser.OptionValueKindMessage,
+										Fields: []*parser.OptionValueField{
+											{
+												Name: "patch",
+												Value: &parser.OptionValue{
+													Kind:   parser.OptionValueKindString,
+													Scalar: `"/v2/example/a_bit_of_everything/{abe.uuid}"`,
+													Meta: meta.Meta{
+														Pos: meta.Position{
+															Offset: 119,
+															Line:   6,
+															Column: 11,
+														},
+														LastPos: meta.Position{
+															Offset: 162,
+															Line:   6,
+															Column: 54,
+														},
+													},
+												},
+												Meta: meta.Meta{
+													Pos: meta.Position{
+														Offset: 112,
+														Line:   6,
+														Column: 4,
+													},
+													LastPos: meta.Position{
+														Offset: 162,
+														Line:   6,
+														Column: 54,
+													},
+												},
+											},
+											{
+												Name: "body",
+												Value: &parser.OptionValue{
+													Kind:   parser.OptionValueKindString,
+													Scalar: `"abe"`,
+													Meta: meta.Meta{
+														Pos: meta.Position{
+															Offset: 173,
+															Line:   7,
+															Column: 10,
+														},
+														LastPos: meta.Position{
+															Offset: 177,
+															Line:   7,
+															Column: 14,
+														},
+													},
+												},
+												Meta: meta.Meta{
+													Pos: meta.Position{
+														Offset: 167,
+														Line:   7,
+														Column: 4,
+													},
+													LastPos: meta.Position{
+														Offset: 177,
+														Line:   7,
+														Column: 14,
+													},
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 107,
+												Line:   5,
+												Column: 3,
+											},
+											LastPos: meta.Position{
+												Offset: 181,
+												Line:   8,
+												Column: 3,
+											},
+										},
+									},
+									{
+										Kind: parser.OptionValueKindMessage,
+										Fields: []*parser.OptionValueField{
+											{
+												Name: "patch",
+												Value: &parser.OptionValue{
+													Kind:   parser.OptionValueKindString,
+													Scalar: `"/v2a/example/a_bit_of_everything/{abe.uuid}"`,
+													Meta: meta.Meta{
+														Pos: meta.Position{
+															Offset: 198,
+															Line:   10,
+															Column: 11,
+														},
+														LastPos: meta.Position{
+															Offset: 242,
+															Line:   10,
+															Column: 55,
+														},
+													},
+												},
+												Meta: meta.Meta{
+													Pos: meta.Position{
+														Offset: 191,
+														Line:   10,
+														Column: 4,
+													},
+													LastPos: meta.Position{
+														Offset: 242,
+														Line:   10,
+														Column: 55,
+													},
+												},
+											},
+											{
+												Name: "body",
+												Value: &parser.OptionValue{
+													Kind:   parser.OptionValueKindString,
+													Scalar: `"*"`,
+													Meta: meta.Meta{
+														Pos: meta.Position{
+															Offset: 253,
+															Line:   11,
+															Column: 10,
+														},
+														LastPos: meta.Position{
+															Offset: 255,
+															Line:   11,
+															Column: 12,
+														},
+													},
+												},
+												Meta: meta.Meta{
+													Pos: meta.Position{
+														Offset: 247,
+														Line:   11,
+														Column: 4,
+													},
+													LastPos: meta.Position{
+														Offset: 255,
+														Line:   11,
+														Column: 12,
+													},
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 186,
+												Line:   9,
+												Column: 3,
+											},
+											LastPos: meta.Position{
+												Offset: 259,
+												Line:   12,
+												Column: 3,
+											},
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 103,
+										Line:   4,
+										Column: 23,
+									},
+									LastPos: meta.Position{
+										Offset: 262,
+										Line:   13,
+										Column: 2,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 82,
+									Line:   4,
+									Column: 2,
+								},
+								LastPos: meta.Position{
+									Offset: 262,
+									Line:   13,
+									Column: 2,
+								},
+							},
+						},
+					},
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 28,
+							Line:   2,
+							Column: 28,
+						},
+						LastPos: meta.Position{
+							Offset: 264,
+							Line:   14,
+							Column: 1,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 1,
@@ -299,6 +1183,22 @@ body:"*"}]}`,
 			wantOption: &parser.Option{
 				OptionName: "(.foo.bar.name)",
 				Constant:   `"name"`,
+				Value: &parser.OptionValue{
+					Kind:   parser.OptionValueKindString,
+					Scalar: `"name"`,
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 25,
+							Line:   1,
+							Column: 26,
+						},
+						LastPos: meta.Position{
+							Offset: 30,
+							Line:   1,
+							Column: 31,
+						},
+					},
+				},
 				Meta: meta.Meta{
 					Pos: meta.Position{
 						Offset: 0,
@@ -313,6 +1213,260 @@ body:"*"}]}`,
 				},
 			},
 		},
+		{
+			name:  "parsing an editions feature with an extension name",
+			input: `option features.(pb.cpp).legacy_closed_enum = true;`,
+			wantOption: &parser.Option{
+				OptionName: "features.(pb.cpp).legacy_closed_enum",
+				Constant:   "true",
+				Value: &parser.OptionValue{
+					Kind:   parser.OptionValueKindBool,
+					Scalar: "true",
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 46,
+							Line:   1,
+							Column: 47,
+						},
+						LastPos: meta.Position{
+							Offset: 49,
+							Line:   1,
+							Column: 50,
+						},
+					},
+				},
+				Meta: meta.Meta{
+					Pos: meta.Position{
+						Offset: 0,
+						Line:   1,
+						Column: 1,
+					},
+					LastPos: meta.Position{
+						Offset: 50,
+						Line:   1,
+						Column: 51,
+					},
+				},
+			},
+		},
+		{
+			name: "parsing a message literal with lists, signed numbers, identifiers and nested messages",
+			input: `option (foo) = {
+  ints: [1, -2.5, inf]
+  name: FOO.BAR
+  nested { ok: true }
+};`,
+			permissive: true,
+			wantOption: &parser.Option{
+				OptionName: "(foo)",
+				Constant: `{ints:[1,-2.5,inf]
+name:FOO.BAR
+nested{ok:true}}`,
+				Value: &parser.OptionValue{
+					Kind: parser.OptionValueKindMessage,
+					Fields: []*parser.OptionValueField{
+						{
+							Name: "ints",
+							Value: &parser.OptionValue{
+								Kind: parser.OptionValueKindList,
+								Elements: []*parser.OptionValue{
+									{
+										Kind:   parser.OptionValueKindInt,
+										Scalar: "1",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 26,
+												Line:   2,
+												Column: 10,
+											},
+											LastPos: meta.Position{
+												Offset: 26,
+												Line:   2,
+												Column: 10,
+											},
+										},
+									},
+									{
+										Kind:   parser.OptionValueKindFloat,
+										Scalar: "-2.5",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 29,
+												Line:   2,
+												Column: 13,
+											},
+											LastPos: meta.Position{
+												Offset: 32,
+												Line:   2,
+												Column: 16,
+											},
+										},
+									},
+									{
+										Kind:   parser.OptionValueKindFloat,
+										Scalar: "inf",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 35,
+												Line:   2,
+												Column: 19,
+											},
+											LastPos: meta.Position{
+												Offset: 37,
+												Line:   2,
+												Column: 21,
+											},
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 25,
+										Line:   2,
+										Column: 9,
+									},
+									LastPos: meta.Position{
+										Offset: 38,
+										Line:   2,
+										Column: 22,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 19,
+									Line:   2,
+									Column: 3,
+								},
+								LastPos: meta.Position{
+									Offset: 38,
+									Line:   2,
+									Column: 22,
+								},
+							},
+						},
+						{
+							Name: "name",
+							Value: &parser.OptionValue{
+								Kind:   parser.OptionValueKindIdent,
+								Scalar: "FOO.BAR",
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 48,
+										Line:   3,
+										Column: 9,
+									},
+									LastPos: meta.Position{
+										Offset: 54,
+										Line:   3,
+										Column: 15,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 42,
+									Line:   3,
+									Column: 3,
+								},
+								LastPos: meta.Position{
+									Offset: 54,
+									Line:   3,
+									Column: 15,
+								},
+							},
+						},
+						{
+							Name: "nested",
+							Value: &parser.OptionValue{
+								Kind: parser.OptionValueKindMessage,
+								Fields: []*parser.OptionValueField{
+									{
+										Name: "ok",
+										Value: &parser.OptionValue{
+											Kind:   parser.OptionValueKindBool,
+											Scalar: "true",
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Offset: 71,
+													Line:   4,
+													Column: 16,
+												},
+												LastPos: meta.Position{
+													Offset: 74,
+													Line:   4,
+													Column: 19,
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Offset: 67,
+												Line:   4,
+												Column: 12,
+											},
+											LastPos: meta.Position{
+												Offset: 74,
+												Line:   4,
+												Column: 19,
+											},
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Offset: 65,
+										Line:   4,
+										Column: 10,
+									},
+									LastPos: meta.Position{
+										Offset: 76,
+										Line:   4,
+										Column: 21,
+									},
+								},
+							},
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Offset: 58,
+									Line:   4,
+									Column: 3,
+								},
+								LastPos: meta.Position{
+									Offset: 76,
+									Line:   4,
+									Column: 21,
+								},
+							},
+						},
+					},
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Offset: 15,
+							Line:   1,
+							Column: 16,
+						},
+						LastPos: meta.Position{
+							Offset: 78,
+							Line:   5,
+							Column: 1,
+						},
+					},
+				},
+				Meta: meta.Meta{
+					Pos: meta.Position{
+						Offset: 0,
+						Line:   1,
+						Column: 1,
+					},
+					LastPos: meta.Position{
+						Offset: 79,
+						Line:   5,
+						Column: 2,
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -342,3 +1496,31 @@ body:"*"}]}`,
 	}
 
 }
+
+func TestOptionValue_Field(t *testing.T) {
+	p := parser.NewParser(
+		lexer.NewLexer(strings.NewReader(`option (google.api.http) = {
+  get: "/v1/foo"
+  additional_bindings { post: "/v1/bar" body: "*" }
+};`)),
+		parser.WithPermissive(true),
+	)
+	got, err := p.ParseOption()
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	if v := got.Value.Field("get"); v == nil || v.Scalar != `"/v1/foo"` {
+		t.Errorf("got %v, but want the get field", v)
+	}
+	bindings := got.Value.Field("additional_bindings")
+	if bindings == nil || bindings.Kind != parser.OptionValueKindMessage {
+		t.Fatalf("got %v, but want the additional_bindings message", bindings)
+	}
+	if v := bindings.Field("body"); v == nil || v.Scalar != `"*"` {
+		t.Errorf("got %v, but want the body field", v)
+	}
+	if v := got.Value.Field("missing"); v != nil {
+		t.Errorf("got %v, but want nil", v)
+	}
+}
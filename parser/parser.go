@@ -1,6 +1,10 @@
 package parser
 
-import "github.com/yoheimuta/go-protoparser/v4/lexer"
+import (
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
 
 // Parser is a parser.
 type Parser struct {
@@ -8,6 +12,17 @@ type Parser struct {
 
 	permissive            bool
 	bodyIncludingComments bool
+	errorRecovery         bool
+	detailedPositions     bool
+	maxNestingDepth       int
+	protocComments        bool
+
+	// edition is set while parsing an editions-based file.
+	edition string
+	// errors are the ones recorded in the error recovery mode.
+	errors meta.ErrorList
+	// depth is the number of the nested messages, enums, services, oneofs, extends, groups and option values.
+	depth int
 }
 
 // ConfigOption is an option for Parser.
@@ -28,6 +43,39 @@ func WithBodyIncludingComments(bodyIncludingComments bool) ConfigOption {
 	}
 }
 
+// WithErrorRecovery is an option to keep parsing after an error.
+// The parser skips to the end of the erroneous statement, and ParseProto returns the partial Proto with a meta.ErrorList.
+func WithErrorRecovery(errorRecovery bool) ConfigOption {
+	return func(p *Parser) {
+		p.errorRecovery = errorRecovery
+	}
+}
+
+// WithDetailedPositions is an option to record the positions of the parts of each element,
+// like the name, the type and the number of a field, in the fields suffixed with Meta, like FieldNameMeta.
+func WithDetailedPositions(detailedPositions bool) ConfigOption {
+	return func(p *Parser) {
+		p.detailedPositions = detailedPositions
+	}
+}
+
+// WithMaxNestingDepth is an option to limit the depth of the nested messages, enums, services, oneofs, extends, groups
+// and option values. ParseProto stops with a *meta.LimitError at the element exceeding the max.
+// The max of 0 means no limit.
+func WithMaxNestingDepth(max int) ConfigOption {
+	return func(p *Parser) {
+		p.maxNestingDepth = max
+	}
+}
+
+// WithProtocComments is an option to set the ProtocComments of each element, following the rules with which protoc
+// attaches the comments to SourceCodeInfo.Location. The Comments and the InlineComment are set as usual.
+func WithProtocComments(protocComments bool) ConfigOption {
+	return func(p *Parser) {
+		p.protocComments = protocComments
+	}
+}
+
 // NewParser creates a new Parser.
 func NewParser(lex *lexer.Lexer, opts ...ConfigOption) *Parser {
 	p := &Parser{
@@ -45,3 +93,46 @@ func (p *Parser) IsEOF() bool {
 	defer p.lex.UnNext()
 	return p.lex.IsEOF()
 }
+
+// partMeta returns the meta of a part of an element, or the zero value unless detailedPositions is enabled.
+func (p *Parser) partMeta(part meta.Meta) meta.Meta {
+	if !p.detailedPositions {
+		return meta.Meta{}
+	}
+	return part
+}
+
+// tokenMeta returns the meta of the current token.
+func (p *Parser) tokenMeta() meta.Meta {
+	return spanMeta(p.lex.Pos, p.lex.Pos.AdvancedBulk(p.lex.Text))
+}
+
+func spanMeta(pos, lastPos scanner.Position) meta.Meta {
+	return meta.Meta{Pos: pos.Position, LastPos: lastPos.Position}
+}
+
+// enter increments the nesting depth, and aborts the lexer if the depth exceeds maxNestingDepth.
+// The caller must call leave after it even when it returns an error.
+func (p *Parser) enter() error {
+	p.depth++
+	if 0 < p.maxNestingDepth && p.maxNestingDepth < p.depth {
+		// points to the start of the element.
+		p.lex.Next()
+		pos := p.lex.Pos
+		p.lex.UnNext()
+
+		err := &meta.LimitError{
+			Pos:   pos.Position,
+			Limit: meta.LimitNestingDepth,
+			Max:   p.maxNestingDepth,
+		}
+		p.lex.Abort(err)
+		return err
+	}
+	return nil
+}
+
+// leave decrements the nesting depth.
+func (p *Parser) leave() {
+	p.depth--
+}
@@ -0,0 +1,119 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+const detailedInput = `syntax = "proto2";
+message Outer {
+  optional .pkg.Inner inner = 1 [deprecated = true, (my.opt).x = "y"];
+  map<string, int32> counts = 2;
+  oneof choice {
+    string name = 3;
+  }
+  optional group Result = 4 {}
+  reserved 5, 10 to max;
+  extensions 100 to 199;
+}
+enum Kind {
+  KIND_NEGATIVE = -1 [(v) = 1];
+}
+extend Outer {
+  optional int32 ext = 100;
+}
+service Svc {
+  rpc Get(stream Outer) returns (.pkg.Outer);
+}
+`
+
+func TestWithDetailedPositions(t *testing.T) {
+	p := parser.NewParser(
+		lexer.NewLexer(strings.NewReader(detailedInput)),
+		parser.WithDetailedPositions(true),
+	)
+	proto, err := p.ParseProto()
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	outer := proto.ProtoBody[0].(*parser.Message)
+	field := outer.MessageBody[0].(*parser.Field)
+	mapField := outer.MessageBody[1].(*parser.MapField)
+	oneof := outer.MessageBody[2].(*parser.Oneof)
+	group := outer.MessageBody[3].(*parser.GroupField)
+	reserved := outer.MessageBody[4].(*parser.Reserved)
+	extensions := outer.MessageBody[5].(*parser.Extensions)
+	enum := proto.ProtoBody[1].(*parser.Enum)
+	enumField := enum.EnumBody[0].(*parser.EnumField)
+	extend := proto.ProtoBody[2].(*parser.Extend)
+	service := proto.ProtoBody[3].(*parser.Service)
+	rpc := service.ServiceBody[0].(*parser.RPC)
+
+	tests := []struct {
+		name     string
+		meta     meta.Meta
+		wantText string
+	}{
+		{name: "MessageNameMeta", meta: outer.MessageNameMeta, wantText: "Outer"},
+		{name: "Field.TypeMeta", meta: field.TypeMeta, wantText: ".pkg.Inner"},
+		{name: "Field.FieldNameMeta", meta: field.FieldNameMeta, wantText: "inner"},
+		{name: "Field.FieldNumberMeta", meta: field.FieldNumberMeta, wantText: "1"},
+		{name: "FieldOption.Meta", meta: field.FieldOptions[0].Meta, wantText: "deprecated = true"},
+		{name: "FieldOption.Meta of an extension name", meta: field.FieldOptions[1].Meta, wantText: `(my.opt).x = "y"`},
+		{name: "Field.Meta", meta: field.Meta, wantText: `optional .pkg.Inner inner = 1 [deprecated = true, (my.opt).x = "y"];`},
+		{name: "MapField.KeyTypeMeta", meta: mapField.KeyTypeMeta, wantText: "string"},
+		{name: "MapField.TypeMeta", meta: mapField.TypeMeta, wantText: "int32"},
+		{name: "MapField.MapNameMeta", meta: mapField.MapNameMeta, wantText: "counts"},
+		{name: "MapField.FieldNumberMeta", meta: mapField.FieldNumberMeta, wantText: "2"},
+		{name: "OneofNameMeta", meta: oneof.OneofNameMeta, wantText: "choice"},
+		{name: "OneofField.TypeMeta", meta: oneof.OneofFields[0].TypeMeta, wantText: "string"},
+		{name: "OneofField.FieldNameMeta", meta: oneof.OneofFields[0].FieldNameMeta, wantText: "name"},
+		{name: "OneofField.FieldNumberMeta", meta: oneof.OneofFields[0].FieldNumberMeta, wantText: "3"},
+		{name: "GroupNameMeta", meta: group.GroupNameMeta, wantText: "Result"},
+		{name: "GroupField.FieldNumberMeta", meta: group.FieldNumberMeta, wantText: "4"},
+		{name: "Reserved Range.Meta", meta: reserved.Ranges[0].Meta, wantText: "5"},
+		{name: "Reserved Range.Meta with max", meta: reserved.Ranges[1].Meta, wantText: "10 to max"},
+		{name: "Extensions Range.Meta", meta: extensions.Ranges[0].Meta, wantText: "100 to 199"},
+		{name: "EnumNameMeta", meta: enum.EnumNameMeta, wantText: "Kind"},
+		{name: "EnumField.IdentMeta", meta: enumField.IdentMeta, wantText: "KIND_NEGATIVE"},
+		{name: "EnumField.NumberMeta", meta: enumField.NumberMeta, wantText: "-1"},
+		{name: "EnumValueOption.Meta", meta: enumField.EnumValueOptions[0].Meta, wantText: "(v) = 1"},
+		{name: "Extend.MessageTypeMeta", meta: extend.MessageTypeMeta, wantText: "Outer"},
+		{name: "ServiceNameMeta", meta: service.ServiceNameMeta, wantText: "Svc"},
+		{name: "RPCNameMeta", meta: rpc.RPCNameMeta, wantText: "Get"},
+		{name: "RPCRequest.MessageTypeMeta", meta: rpc.RPCRequest.MessageTypeMeta, wantText: "Outer"},
+		{name: "RPCResponse.MessageTypeMeta", meta: rpc.RPCResponse.MessageTypeMeta, wantText: ".pkg.Outer"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := detailedInput[test.meta.Pos.Offset : test.meta.LastPos.Offset+1]
+			if got != test.wantText {
+				t.Errorf("got %q, but want %q", got, test.wantText)
+			}
+		})
+	}
+}
+
+func TestWithDetailedPositions_disabled(t *testing.T) {
+	p := parser.NewParser(lexer.NewLexer(strings.NewReader(detailedInput)))
+	proto, err := p.ParseProto()
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	outer := proto.ProtoBody[0].(*parser.Message)
+	field := outer.MessageBody[0].(*parser.Field)
+	if outer.MessageNameMeta != (meta.Meta{}) || field.FieldNameMeta != (meta.Meta{}) {
+		t.Errorf("got %v and %v, but want the zero values", outer.MessageNameMeta, field.FieldNameMeta)
+	}
+	if field.FieldOptions[0].Meta == (meta.Meta{}) {
+		t.Errorf("got the zero value, but want the meta of the field option")
+	}
+}
@@ -11,6 +11,8 @@ type ProtoMeta struct {
 // Proto represents a protocol buffer definition.
 type Proto struct {
 	Syntax *Syntax
+	// Edition is set instead of Syntax when the file is editions-based.
+	Edition *Edition
 	// ProtoBody is a slice of sum type consisted of *Import, *Package, *Option, *Message, *Enum, *Service, *Extend and *EmptyStatement.
 	ProtoBody []Visitee
 	Meta      *ProtoMeta
@@ -21,6 +23,9 @@ func (p *Proto) Accept(v Visitor) {
 	if p.Syntax != nil {
 		p.Syntax.Accept(v)
 	}
+	if p.Edition != nil {
+		p.Edition.Accept(v)
+	}
 
 	for _, body := range p.ProtoBody {
 		body.Accept(v)
@@ -28,30 +33,82 @@ func (p *Proto) Accept(v Visitor) {
 }
 
 // ParseProto parses the proto.
-//  proto = syntax { import | package | option | topLevelDef | emptyStatement }
 //
-// See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#proto_file
+//	proto = ( syntax | edition ) { import | package | option | topLevelDef | emptyStatement }
+//
+// See
+//
+//	https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#proto_file
+//	https://protobuf.dev/reference/protobuf/edition-2023-spec/#proto_file
+//
+// ParseProto returns the error with which the lexer is aborted, like a *meta.LimitError, even in the error recovery mode.
 func (p *Parser) ParseProto() (*Proto, error) {
-	syntaxComments := p.ParseComments()
-	syntax, err := p.ParseSyntax()
-	if err != nil {
-		return nil, err
+	proto, err := p.parseProto()
+	if abortErr := p.lex.AbortErr(); abortErr != nil {
+		return nil, abortErr
+	}
+	return proto, err
+}
+
+func (p *Parser) parseProto() (*Proto, error) {
+	comments := p.ParseComments()
+
+	p.lex.NextKeyword()
+	token := p.lex.Token
+	p.lex.UnNext()
+
+	var syntax *Syntax
+	var edition *Edition
+	if token == scanner.TEDITION {
+		e, err := p.ParseEdition()
+		if err != nil {
+			if !p.recoverFrom(err, 0, false) {
+				return nil, err
+			}
+		} else {
+			e.Comments = comments
+			p.MaybeScanInlineComment(e)
+			edition = e
+			p.edition = e.Edition
+		}
+	} else if token == scanner.TSYNTAX || !p.errorRecovery {
+		s, err := p.ParseSyntax()
+		if err != nil {
+			if !p.recoverFrom(err, 0, false) {
+				return nil, err
+			}
+		} else {
+			s.Comments = comments
+			p.MaybeScanInlineComment(s)
+			syntax = s
+		}
+	} else {
+		// Leaves the statement to the body so that only the missing syntax is reported.
+		p.lex.NextKeyword()
+		p.recordError(p.unexpected("syntax"))
+		p.lex.UnNext()
 	}
-	syntax.Comments = syntaxComments
-	p.MaybeScanInlineComment(syntax)
 
 	protoBody, err := p.parseProtoBody()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Proto{
+	proto := &Proto{
 		Syntax:    syntax,
+		Edition:   edition,
 		ProtoBody: protoBody,
 		Meta: &ProtoMeta{
 			Filename: p.lex.Pos.Filename,
 		},
-	}, nil
+	}
+	if p.protocComments {
+		attachProtocComments(proto, p.lex.Source())
+	}
+	if 0 < len(p.errors) {
+		return proto, p.errors
+	}
+	return proto, nil
 }
 
 // protoBody = { import | package | option | topLevelDef | emptyStatement }
@@ -62,6 +119,7 @@ func (p *Parser) parseProtoBody() ([]Visitee, error) {
 
 	for {
 		comments := p.ParseComments()
+		depth := p.lex.CurlyDepth()
 
 		if p.IsEOF() {
 			if p.bodyIncludingComments {
@@ -76,70 +134,81 @@ func (p *Parser) parseProtoBody() ([]Visitee, error) {
 		token := p.lex.Token
 		p.lex.UnNext()
 
-		var stmt interface {
-			HasInlineCommentSetter
-			Visitee
-		}
-
-		switch token {
-		case scanner.TIMPORT:
-			importValue, err := p.ParseImport()
-			if err != nil {
+		stmt, err := p.parseProtoBodyStatement(token, comments)
+		if err != nil {
+			if !p.recoverFrom(err, depth, false) {
 				return nil, err
 			}
-			importValue.Comments = comments
-			stmt = importValue
-		case scanner.TPACKAGE:
-			packageValue, err := p.ParsePackage()
-			if err != nil {
-				return nil, err
-			}
-			packageValue.Comments = comments
-			stmt = packageValue
-		case scanner.TOPTION:
-			option, err := p.ParseOption()
-			if err != nil {
-				return nil, err
-			}
-			option.Comments = comments
-			stmt = option
-		case scanner.TMESSAGE:
-			message, err := p.ParseMessage()
-			if err != nil {
-				return nil, err
-			}
-			message.Comments = comments
-			stmt = message
-		case scanner.TENUM:
-			enum, err := p.ParseEnum()
-			if err != nil {
-				return nil, err
-			}
-			enum.Comments = comments
-			stmt = enum
-		case scanner.TSERVICE:
-			service, err := p.ParseService()
-			if err != nil {
-				return nil, err
-			}
-			service.Comments = comments
-			stmt = service
-		case scanner.TEXTEND:
-			extend, err := p.ParseExtend()
-			if err != nil {
-				return nil, err
-			}
-			extend.Comments = comments
-			stmt = extend
-		default:
-			err := p.lex.ReadEmptyStatement()
-			if err != nil {
-				return nil, err
-			}
-			protoBody = append(protoBody, &EmptyStatement{})
+			continue
 		}
 
 		p.MaybeScanInlineComment(stmt)
 		protoBody = append(protoBody, stmt)
 	}
 }
+
+func (p *Parser) parseProtoBodyStatement(
+	token scanner.Token,
+	comments []*Comment,
+) (interface {
+	HasInlineCommentSetter
+	Visitee
+}, error) {
+	switch token {
+	case scanner.TIMPORT:
+		importValue, err := p.ParseImport()
+		if err != nil {
+			return nil, err
+		}
+		importValue.Comments = comments
+		return importValue, nil
+	case scanner.TPACKAGE:
+		packageValue, err := p.ParsePackage()
+		if err != nil {
+			return nil, err
+		}
+		packageValue.Comments = comments
+		return packageValue, nil
+	case scanner.TOPTION:
+		option, err := p.ParseOption()
+		if err != nil {
+			return nil, err
+		}
+		option.Comments = comments
+		return option, nil
+	case scanner.TMESSAGE:
+		message, err := p.ParseMessage()
+		if err != nil {
+			return nil, err
+		}
+		message.Comments = comments
+		return message, nil
+	case scanner.TENUM:
+		enum, err := p.ParseEnum()
+		if err != nil {
+			return nil, err
+		}
+		enum.Comments = comments
+		return enum, nil
+	case scanner.TSERVICE:
+		service, err := p.ParseService()
+		if err != nil {
+			return nil, err
+		}
+		service.Comments = comments
+		return service, nil
+	case scanner.TEXTEND:
+		extend, err := p.ParseExtend()
+		if err != nil {
+			return nil, err
+		}
+		extend.Comments = comments
+		return extend, nil
+	default:
+		err := p.lex.ReadEmptyStatement()
+		if err != nil {
+			return nil, err
+		}
+		return p.newEmptyStatement(), nil
+	}
+}
@@ -89,6 +89,24 @@ message outer {
 					&parser.Option{
 						OptionName: "java_package",
 						Constant:   `"com.example.foo"`,
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindString,
+							Scalar: `"com.example.foo"`,
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Filename: "official.proto",
+									Offset:   71,
+									Line:     4,
+									Column:   23,
+								},
+								LastPos: meta.Position{
+									Filename: "official.proto",
+									Offset:   87,
+									Line:     4,
+									Column:   39,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Filename: "official.proto",
@@ -110,6 +128,24 @@ message outer {
 							&parser.Option{
 								OptionName: "allow_alias",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Filename: "official.proto",
+											Offset:   138,
+											Line:     6,
+											Column:   24,
+										},
+										LastPos: meta.Position{
+											Filename: "official.proto",
+											Offset:   141,
+											Line:     6,
+											Column:   27,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Filename: "official.proto",
@@ -168,6 +204,38 @@ message outer {
 									{
 										OptionName: "(custom_option)",
 										Constant:   `"hello world"`,
+										Value: &parser.OptionValue{
+											Kind:   parser.OptionValueKindString,
+											Scalar: `"hello world"`,
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Filename: "official.proto",
+													Offset:   207,
+													Line:     9,
+													Column:   34,
+												},
+												LastPos: meta.Position{
+													Filename: "official.proto",
+													Offset:   219,
+													Line:     9,
+													Column:   46,
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Filename: "official.proto",
+												Offset:   189,
+												Line:     9,
+												Column:   16,
+											},
+											LastPos: meta.Position{
+												Filename: "official.proto",
+												Offset:   219,
+												Line:     9,
+												Column:   46,
+											},
+										},
 									},
 								},
 								Meta: meta.Meta{
@@ -207,6 +275,24 @@ message outer {
 							&parser.Option{
 								OptionName: "(my_option).a",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Filename: "official.proto",
+											Offset:   266,
+											Line:     12,
+											Column:   26,
+										},
+										LastPos: meta.Position{
+											Filename: "official.proto",
+											Offset:   269,
+											Line:     12,
+											Column:   29,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Filename: "official.proto",
@@ -607,6 +693,24 @@ syntax2
 					&parser.Option{
 						OptionName: "java_package",
 						Constant:   `"com.example.foo"`,
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindString,
+							Scalar: `"com.example.foo"`,
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Filename: "comments.proto",
+									Offset:   146,
+									Line:     12,
+									Column:   23,
+								},
+								LastPos: meta.Position{
+									Filename: "comments.proto",
+									Offset:   162,
+									Line:     12,
+									Column:   39,
+								},
+							},
+						},
 						Comments: []*parser.Comment{
 							{
 								Raw: `// option`,
@@ -683,6 +787,24 @@ syntax2
 							&parser.Option{
 								OptionName: "allow_alias",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Filename: "comments.proto",
+											Offset:   250,
+											Line:     18,
+											Column:   24,
+										},
+										LastPos: meta.Position{
+											Filename: "comments.proto",
+											Offset:   253,
+											Line:     18,
+											Column:   27,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Filename: "comments.proto",
@@ -954,6 +1076,24 @@ service SearchService {
 					&parser.Option{
 						OptionName: "java_package",
 						Constant:   `"com.example.foo"`,
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindString,
+							Scalar: `"com.example.foo"`,
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Filename: "inlineComments.proto",
+									Offset:   122,
+									Line:     5,
+									Column:   23,
+								},
+								LastPos: meta.Position{
+									Filename: "inlineComments.proto",
+									Offset:   138,
+									Line:     5,
+									Column:   39,
+								},
+							},
+						},
 						InlineComment: &parser.Comment{
 							Raw: `// option`,
 							Meta: meta.Meta{
@@ -1026,6 +1166,24 @@ service SearchService {
 							&parser.Option{
 								OptionName: "allow_alias",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Filename: "inlineComments.proto",
+											Offset:   228,
+											Line:     9,
+											Column:   24,
+										},
+										LastPos: meta.Position{
+											Filename: "inlineComments.proto",
+											Offset:   231,
+											Line:     9,
+											Column:   27,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Filename: "inlineComments.proto",
@@ -1294,6 +1452,24 @@ service SearchService {
 					&parser.Option{
 						OptionName: "java_package",
 						Constant:   `"com.example.foo"`,
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindString,
+							Scalar: `"com.example.foo"`,
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Filename: "inlineComments.proto",
+									Offset:   122,
+									Line:     5,
+									Column:   23,
+								},
+								LastPos: meta.Position{
+									Filename: "inlineComments.proto",
+									Offset:   138,
+									Line:     5,
+									Column:   39,
+								},
+							},
+						},
 						InlineComment: &parser.Comment{
 							Raw: `// option`,
 							Meta: meta.Meta{
@@ -1366,6 +1542,24 @@ service SearchService {
 							&parser.Option{
 								OptionName: "allow_alias",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Filename: "inlineComments.proto",
+											Offset:   228,
+											Line:     9,
+											Column:   24,
+										},
+										LastPos: meta.Position{
+											Filename: "inlineComments.proto",
+											Offset:   231,
+											Line:     9,
+											Column:   27,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Filename: "inlineComments.proto",
@@ -1889,6 +2083,24 @@ message foo {
 					&parser.Option{
 						OptionName: "java_package",
 						Constant:   `"com.example.foo"`,
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindString,
+							Scalar: `"com.example.foo"`,
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Filename: "official.proto",
+									Offset:   71,
+									Line:     4,
+									Column:   23,
+								},
+								LastPos: meta.Position{
+									Filename: "official.proto",
+									Offset:   87,
+									Line:     4,
+									Column:   39,
+								},
+							},
+						},
 						Meta: meta.Meta{
 							Pos: meta.Position{
 								Filename: "official.proto",
@@ -1910,6 +2122,24 @@ message foo {
 							&parser.Option{
 								OptionName: "allow_alias",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Filename: "official.proto",
+											Offset:   138,
+											Line:     6,
+											Column:   24,
+										},
+										LastPos: meta.Position{
+											Filename: "official.proto",
+											Offset:   141,
+											Line:     6,
+											Column:   27,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Filename: "official.proto",
@@ -1968,6 +2198,38 @@ message foo {
 									{
 										OptionName: "(custom_option)",
 										Constant:   `"hello world"`,
+										Value: &parser.OptionValue{
+											Kind:   parser.OptionValueKindString,
+											Scalar: `"hello world"`,
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Filename: "official.proto",
+													Offset:   207,
+													Line:     9,
+													Column:   34,
+												},
+												LastPos: meta.Position{
+													Filename: "official.proto",
+													Offset:   219,
+													Line:     9,
+													Column:   46,
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Filename: "official.proto",
+												Offset:   189,
+												Line:     9,
+												Column:   16,
+											},
+											LastPos: meta.Position{
+												Filename: "official.proto",
+												Offset:   219,
+												Line:     9,
+												Column:   46,
+											},
+										},
 									},
 								},
 								Meta: meta.Meta{
@@ -2007,6 +2269,24 @@ message foo {
 							&parser.Option{
 								OptionName: "(my_option).a",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Filename: "official.proto",
+											Offset:   266,
+											Line:     12,
+											Column:   26,
+										},
+										LastPos: meta.Position{
+											Filename: "official.proto",
+											Offset:   269,
+											Line:     12,
+											Column:   29,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Filename: "official.proto",
@@ -2143,6 +2423,20 @@ message foo {
 									{
 										Begin: "20",
 										End:   "30",
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Filename: "official.proto",
+												Offset:   463,
+												Line:     19,
+												Column:   14,
+											},
+											LastPos: meta.Position{
+												Filename: "official.proto",
+												Offset:   470,
+												Line:     19,
+												Column:   21,
+											},
+										},
 									},
 								},
 								Meta: meta.Meta{
@@ -2242,6 +2536,203 @@ message foo {
 				},
 			},
 		},
+		{
+			name: "parsing an editions-based file",
+			input: `
+edition = "2023";
+package foo;
+option features.field_presence = EXPLICIT;
+message Foo {
+  reserved foo, bar;
+  int32 baz = 1 [features.(pb.cpp).string_type = VIEW];
+}
+`,
+			filename: "edition.proto",
+			wantProto: &parser.Proto{
+				Edition: &parser.Edition{
+					Edition:      "2023",
+					EditionQuote: `"2023"`,
+					Meta: meta.Meta{
+						Pos: meta.Position{
+							Filename: "edition.proto",
+							Offset:   1,
+							Line:     2,
+							Column:   1,
+						},
+						LastPos: meta.Position{
+							Filename: "edition.proto",
+							Offset:   17,
+							Line:     2,
+							Column:   17,
+						},
+					},
+				},
+				ProtoBody: []parser.Visitee{
+					&parser.Package{
+						Name: "foo",
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Filename: "edition.proto",
+								Offset:   19,
+								Line:     3,
+								Column:   1,
+							},
+							LastPos: meta.Position{
+								Filename: "edition.proto",
+								Offset:   30,
+								Line:     3,
+								Column:   12,
+							},
+						},
+					},
+					&parser.Option{
+						OptionName: "features.field_presence",
+						Constant:   "EXPLICIT",
+						Value: &parser.OptionValue{
+							Kind:   parser.OptionValueKindIdent,
+							Scalar: "EXPLICIT",
+							Meta: meta.Meta{
+								Pos: meta.Position{
+									Filename: "edition.proto",
+									Offset:   65,
+									Line:     4,
+									Column:   34,
+								},
+								LastPos: meta.Position{
+									Filename: "edition.proto",
+									Offset:   72,
+									Line:     4,
+									Column:   41,
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Filename: "edition.proto",
+								Offset:   32,
+								Line:     4,
+								Column:   1,
+							},
+							LastPos: meta.Position{
+								Filename: "edition.proto",
+								Offset:   73,
+								Line:     4,
+								Column:   42,
+							},
+						},
+					},
+					&parser.Message{
+						MessageName: "Foo",
+						MessageBody: []parser.Visitee{
+							&parser.Reserved{
+								FieldNames: []string{
+									"foo",
+									"bar",
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Filename: "edition.proto",
+										Offset:   91,
+										Line:     6,
+										Column:   3,
+									},
+									LastPos: meta.Position{
+										Filename: "edition.proto",
+										Offset:   108,
+										Line:     6,
+										Column:   20,
+									},
+								},
+							},
+							&parser.Field{
+								Type:        "int32",
+								FieldName:   "baz",
+								FieldNumber: "1",
+								FieldOptions: []*parser.FieldOption{
+									{
+										OptionName: "features.(pb.cpp).string_type",
+										Constant:   "VIEW",
+										Value: &parser.OptionValue{
+											Kind:   parser.OptionValueKindIdent,
+											Scalar: "VIEW",
+											Meta: meta.Meta{
+												Pos: meta.Position{
+													Filename: "edition.proto",
+													Offset:   159,
+													Line:     7,
+													Column:   50,
+												},
+												LastPos: meta.Position{
+													Filename: "edition.proto",
+													Offset:   162,
+													Line:     7,
+													Column:   53,
+												},
+											},
+										},
+										Meta: meta.Meta{
+											Pos: meta.Position{
+												Filename: "edition.proto",
+												Offset:   127,
+												Line:     7,
+												Column:   18,
+											},
+											LastPos: meta.Position{
+												Filename: "edition.proto",
+												Offset:   162,
+												Line:     7,
+												Column:   53,
+											},
+										},
+									},
+								},
+								Meta: meta.Meta{
+									Pos: meta.Position{
+										Filename: "edition.proto",
+										Offset:   112,
+										Line:     7,
+										Column:   3,
+									},
+									LastPos: meta.Position{
+										Filename: "edition.proto",
+										Offset:   164,
+										Line:     7,
+										Column:   55,
+									},
+								},
+							},
+						},
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Filename: "edition.proto",
+								Offset:   75,
+								Line:     5,
+								Column:   1,
+							},
+							LastPos: meta.Position{
+								Filename: "edition.proto",
+								Offset:   166,
+								Line:     8,
+								Column:   1,
+							},
+						},
+					},
+				},
+				Meta: &parser.ProtoMeta{
+					Filename: "edition.proto",
+				},
+			},
+		},
+		{
+			name: "parsing unquoted reserved names in a proto3 file",
+			input: `
+syntax = "proto3";
+message Foo {
+  reserved foo, bar;
+}
+`,
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,438 @@
+package parser
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// ProtocComments are the comments attached to an element as protoc attaches them to SourceCodeInfo.Location.
+// They're set with WithProtocComments.
+//
+// The text of a line comment follows the "//" and includes the newline, and consecutive line comments are
+// joined into one. The text of a block comment is between the "/*" and the "*/", without the leading asterisks.
+type ProtocComments struct {
+	// LeadingComments is the comment placed just before the element.
+	LeadingComments string
+	// TrailingComments is the comment placed behind the element on the same line,
+	// or on the next line when it's followed by a blank line.
+	TrailingComments string
+	// LeadingDetachedComments are the comments placed before the LeadingComments, which are separated by blank lines.
+	LeadingDetachedComments []string
+}
+
+// protocCommentsOf returns the ProtocComments and the meta of the node, or nil if protoc attaches no comments to it.
+func protocCommentsOf(node Visitee) (*ProtocComments, meta.Meta) {
+	switch n := node.(type) {
+	case *Syntax:
+		return &n.ProtocComments, n.Meta
+	case *Edition:
+		return &n.ProtocComments, n.Meta
+	case *Import:
+		return &n.ProtocComments, n.Meta
+	case *Package:
+		return &n.ProtocComments, n.Meta
+	case *Option:
+		return &n.ProtocComments, n.Meta
+	case *Message:
+		return &n.ProtocComments, n.Meta
+	case *Field:
+		return &n.ProtocComments, n.Meta
+	case *MapField:
+		return &n.ProtocComments, n.Meta
+	case *GroupField:
+		return &n.ProtocComments, n.Meta
+	case *Oneof:
+		return &n.ProtocComments, n.Meta
+	case *OneofField:
+		return &n.ProtocComments, n.Meta
+	case *Enum:
+		return &n.ProtocComments, n.Meta
+	case *EnumField:
+		return &n.ProtocComments, n.Meta
+	case *Service:
+		return &n.ProtocComments, n.Meta
+	case *RPC:
+		return &n.ProtocComments, n.Meta
+	case *Extend:
+		return &n.ProtocComments, n.Meta
+	case *Reserved:
+		return &n.ProtocComments, n.Meta
+	case *Extensions:
+		return &n.ProtocComments, n.Meta
+	}
+	return nil, meta.Meta{}
+}
+
+// declEnd is a token which ends a declaration, that is ";", "{" or "}".
+// protoc reads the comments only behind these tokens, and drops the other ones.
+type declEnd struct {
+	offset int
+	token  scanner.Token
+	// next is the offset of the next token, or the size of the source at the end.
+	next      int
+	nextToken scanner.Token
+}
+
+// attachProtocComments sets the ProtocComments of the nodes in the proto parsed from the src,
+// as protoc's Parser does with Tokenizer::NextWithComments at the end of each declaration.
+func attachProtocComments(proto *Proto, src []byte) {
+	type declNode struct {
+		comments *ProtocComments
+		meta     meta.Meta
+		isOption bool
+	}
+	var nodes []declNode
+	// options are the ranges of the option statements, whose values may have the curlies and the semicolons.
+	var options []meta.Meta
+	Walk(proto, func(c *Cursor) bool {
+		if c.Leaving() {
+			return true
+		}
+		comments, mt := protocCommentsOf(c.Node())
+		if comments == nil {
+			return true
+		}
+		_, isOption := c.Node().(*Option)
+		if isOption {
+			options = append(options, mt)
+		}
+		nodes = append(nodes, declNode{comments: comments, meta: mt, isOption: isOption})
+		return true
+	})
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Pos.Offset < options[j].Pos.Offset
+	})
+
+	first, ends := scanDeclEnds(src, options)
+
+	byEnd := make(map[int]*ProtocComments)
+	for _, node := range nodes {
+		if node.isOption {
+			byEnd[node.meta.LastPos.Offset] = node.comments
+			continue
+		}
+		// the first ";" or "{" in the node ends it, because the ones in the field options are not declEnds.
+		i := sort.Search(len(ends), func(i int) bool {
+			return node.meta.Pos.Offset <= ends[i].offset
+		})
+		for ; i < len(ends); i++ {
+			if ends[i].token != scanner.TRIGHTCURLY {
+				byEnd[ends[i].offset] = node.comments
+				break
+			}
+		}
+	}
+
+	// the comments before the first token are detached from the previous one.
+	_, upcomingDetached, upcomingDoc := collectComments(src, 0, first.next, first.nextToken, true)
+	for _, end := range ends {
+		trailing, detached, leading := collectComments(src, end.offset+1, end.next, end.nextToken, false)
+		leading, upcomingDoc = upcomingDoc, leading
+
+		comments, ok := byEnd[end.offset]
+		switch {
+		case ok:
+			detached, upcomingDetached = upcomingDetached, detached
+			comments.LeadingComments = leading
+			comments.TrailingComments = trailing
+			comments.LeadingDetachedComments = detached
+		case end.token == scanner.TRIGHTCURLY:
+			// the detached comments in the scope are dropped.
+			upcomingDetached = detached
+		default:
+			// an empty statement.
+			upcomingDetached = append(upcomingDetached, detached...)
+		}
+	}
+}
+
+// scanDeclEnds returns the declEnds in the src, along with the one which holds the first token as the next one.
+// The tokens in the brackets of the options and in the values of the option statements are skipped.
+func scanDeclEnds(src []byte, options []meta.Meta) (declEnd, []declEnd) {
+	s := scanner.NewScanner(bytes.NewReader(src))
+	s.Mode = scanner.ScanStrLit
+
+	first := declEnd{offset: -1}
+	last := &first
+	var ends []declEnd
+	squareDepth := 0
+	for {
+		t, _, pos, err := s.Scan()
+		if err != nil || t == scanner.TEOF {
+			break
+		}
+		if last != nil {
+			last.next = pos.Offset
+			last.nextToken = t
+			last = nil
+		}
+
+		for 0 < len(options) && options[0].LastPos.Offset < pos.Offset {
+			options = options[1:]
+		}
+		inOption := 0 < len(options) && options[0].Pos.Offset < pos.Offset && pos.Offset < options[0].LastPos.Offset
+
+		switch t {
+		case scanner.TLEFTSQUARE:
+			squareDepth++
+		case scanner.TRIGHTSQUARE:
+			squareDepth--
+		case scanner.TSEMICOLON, scanner.TLEFTCURLY, scanner.TRIGHTCURLY:
+			if squareDepth == 0 && !inOption {
+				ends = append(ends, declEnd{offset: pos.Offset, token: t})
+				last = &ends[len(ends)-1]
+			}
+		}
+	}
+	if last != nil {
+		last.next = len(src)
+		last.nextToken = scanner.TEOF
+	}
+	return first, ends
+}
+
+// collectComments reads the comments in the src from the offset behind the previous token to the offset of the next
+// token, and returns the trailing comments of the previous one, the detached comments and the leading comments of
+// the next one. It follows Tokenizer::NextWithComments of protoc.
+func collectComments(
+	src []byte,
+	from, to int,
+	nextToken scanner.Token,
+	atStart bool,
+) (trailing string, detached []string, leading string) {
+	r := &commentReader{src: src[:to], i: from}
+	c := &commentCollector{canAttachToPrev: true}
+	// trailingEnd is the offset behind the trailing block comment, or -1.
+	trailingEnd := -1
+
+	if atStart {
+		c.canAttachToPrev = false
+	} else {
+		// a comment on the same line is attached to the previous one.
+		r.skipSpaces()
+		switch r.commentStart() {
+		case lineComment:
+			c.addLine(r.consumeLine())
+			// the comments on the following lines are not attached to the trailing comment.
+			c.flush()
+		case blockComment:
+			c.addBlock(r.consumeBlock())
+			trailingEnd = r.i
+			r.skipSpaces()
+			if !r.consume('\n') {
+				// it's unclear which token the comment is attached to when the next one is on the same line.
+				return "", nil, ""
+			}
+			c.flush()
+		default:
+			if !r.consume('\n') {
+				return "", nil, ""
+			}
+		}
+	}
+
+	// now on the line after the previous token.
+	for {
+		r.skipSpaces()
+		switch r.commentStart() {
+		case lineComment:
+			c.addLine(r.consumeLine())
+			continue
+		case blockComment:
+			c.addBlock(r.consumeBlock())
+			// the rest of the line is not a blank line.
+			r.skipSpaces()
+			r.consume('\n')
+			continue
+		}
+		if r.consume('\n') {
+			// a blank line.
+			c.flush()
+			c.canAttachToPrev = false
+			continue
+		}
+		break
+	}
+
+	if nextToken == scanner.TEOF || nextToken == scanner.TRIGHTCURLY {
+		// no comment is attached to the next token at the end of a scope.
+		c.flush()
+	}
+	if !hasNewline(src[from:to]) || (0 <= trailingEnd && !hasNewline(src[trailingEnd:to])) {
+		// it's unclear which token the comment is attached to when it's on the same line as the both.
+		c.maybeDetach()
+	}
+	if c.hasComment {
+		leading = c.buf.String()
+	}
+	return c.trailing, c.detached, leading
+}
+
+func hasNewline(b []byte) bool {
+	return bytes.IndexByte(b, '\n') != -1
+}
+
+// commentCollector follows CommentCollector of protoc.
+type commentCollector struct {
+	buf           strings.Builder
+	hasComment    bool
+	isLineComment bool
+
+	trailing        string
+	hasTrailing     bool
+	detached        []string
+	canAttachToPrev bool
+	numComments     int
+}
+
+// addLine adds the line comment, which is joined with the previous line comments.
+func (c *commentCollector) addLine(text string) {
+	if c.hasComment && !c.isLineComment {
+		c.flush()
+	}
+	c.hasComment = true
+	c.isLineComment = true
+	c.buf.WriteString(text)
+}
+
+func (c *commentCollector) addBlock(text string) {
+	if c.hasComment {
+		c.flush()
+	}
+	c.hasComment = true
+	c.isLineComment = false
+	c.buf.WriteString(text)
+}
+
+// flush is called when the comment in the buffer is known not to be attached to the next token.
+func (c *commentCollector) flush() {
+	if !c.hasComment {
+		return
+	}
+	if c.canAttachToPrev {
+		c.trailing += c.buf.String()
+		c.hasTrailing = true
+		c.canAttachToPrev = false
+	} else {
+		c.detached = append(c.detached, c.buf.String())
+	}
+	c.buf.Reset()
+	c.hasComment = false
+	c.numComments++
+}
+
+// maybeDetach detaches the comment if it's the only one.
+func (c *commentCollector) maybeDetach() {
+	count := c.numComments
+	if c.hasComment {
+		count++
+	}
+	if count != 1 {
+		return
+	}
+	if c.hasTrailing {
+		c.detached = append([]string{c.trailing}, c.detached...)
+		c.trailing = ""
+	}
+	c.canAttachToPrev = false
+	c.flush()
+}
+
+type commentKind int
+
+const (
+	noComment commentKind = iota
+	lineComment
+	blockComment
+)
+
+// commentReader reads the whitespaces and the comments between two tokens.
+type commentReader struct {
+	src []byte
+	i   int
+}
+
+func (r *commentReader) peek() byte {
+	if len(r.src) <= r.i {
+		return 0
+	}
+	return r.src[r.i]
+}
+
+func (r *commentReader) consume(c byte) bool {
+	if r.i < len(r.src) && r.src[r.i] == c {
+		r.i++
+		return true
+	}
+	return false
+}
+
+// skipSpaces skips the whitespaces except the newlines.
+func (r *commentReader) skipSpaces() {
+	for {
+		switch r.peek() {
+		case ' ', '\t', '\r', '\v', '\f':
+			r.i++
+		default:
+			return
+		}
+	}
+}
+
+// commentStart consumes the start of a comment.
+func (r *commentReader) commentStart() commentKind {
+	if r.i+1 < len(r.src) && r.src[r.i] == '/' {
+		switch r.src[r.i+1] {
+		case '/':
+			r.i += 2
+			return lineComment
+		case '*':
+			r.i += 2
+			return blockComment
+		}
+	}
+	return noComment
+}
+
+// consumeLine returns the text following the "//" up to and including the newline.
+func (r *commentReader) consumeLine() string {
+	start := r.i
+	for r.i < len(r.src) && r.src[r.i] != '\n' {
+		r.i++
+	}
+	r.consume('\n')
+	return string(r.src[start:r.i])
+}
+
+// consumeBlock returns the text up to the "*/", without the whitespaces and the asterisk at the beginning of each line.
+func (r *commentReader) consumeBlock() string {
+	var text strings.Builder
+	start := r.i
+	for {
+		for r.i < len(r.src) && r.src[r.i] != '*' && r.src[r.i] != '/' && r.src[r.i] != '\n' {
+			r.i++
+		}
+
+		switch {
+		case r.consume('\n'):
+			text.Write(r.src[start:r.i])
+			r.skipSpaces()
+			if r.consume('*') && r.consume('/') {
+				return text.String()
+			}
+			start = r.i
+		case r.consume('*') && r.consume('/'):
+			text.Write(r.src[start : r.i-2])
+			return text.String()
+		case r.consume('/'):
+			// a nested "/*" is just a part of the text.
+		case len(r.src) <= r.i:
+			text.Write(r.src[start:r.i])
+			return text.String()
+		}
+	}
+}
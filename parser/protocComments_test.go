@@ -0,0 +1,204 @@
+package parser_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// protocCommentsOf returns the ProtocComments of the node in the tests, or nil.
+func protocCommentsOf(node parser.Visitee) *parser.ProtocComments {
+	switch n := node.(type) {
+	case *parser.Syntax:
+		return &n.ProtocComments
+	case *parser.Package:
+		return &n.ProtocComments
+	case *parser.Option:
+		return &n.ProtocComments
+	case *parser.Message:
+		return &n.ProtocComments
+	case *parser.Field:
+		return &n.ProtocComments
+	case *parser.Enum:
+		return &n.ProtocComments
+	case *parser.EnumField:
+		return &n.ProtocComments
+	}
+	return nil
+}
+
+func TestParser_ParseProto_withProtocComments(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantComments []parser.ProtocComments
+	}{
+		{
+			name: "the example documented in descriptor.proto",
+			input: `syntax = "proto2";
+message Foo {
+  optional int32 foo = 1;  // Comment attached to foo.
+  // Comment attached to bar.
+  optional int32 bar = 2;
+
+  optional string baz = 3;
+  // Comment attached to baz.
+  // Another line attached to baz.
+
+  // Comment attached to moo.
+  //
+  // Another line attached to moo.
+  optional double moo = 4;
+
+  // Detached comment for corge. This is not leading or trailing comments
+  // to moo or corge because there are blank lines separating it from
+  // both.
+
+  // Detached comment for corge paragraph 2.
+
+  optional string corge = 5;
+  /* Block comment attached
+   * to corge.  Leading asterisks
+   * will be removed. */
+  /* Block comment attached to
+   * grault. */
+  optional int32 grault = 6;
+
+  // ignored detached comments.
+}
+`,
+			wantComments: []parser.ProtocComments{
+				// syntax
+				{},
+				// Foo
+				{},
+				// foo
+				{TrailingComments: " Comment attached to foo.\n"},
+				// bar
+				{LeadingComments: " Comment attached to bar.\n"},
+				// baz
+				{TrailingComments: " Comment attached to baz.\n Another line attached to baz.\n"},
+				// moo
+				{LeadingComments: " Comment attached to moo.\n\n Another line attached to moo.\n"},
+				// corge
+				{
+					TrailingComments: " Block comment attached\n to corge.  Leading asterisks\n will be removed. ",
+					LeadingDetachedComments: []string{
+						" Detached comment for corge. This is not leading or trailing comments\n" +
+							" to moo or corge because there are blank lines separating it from\n" +
+							" both.\n",
+						" Detached comment for corge paragraph 2.\n",
+					},
+				},
+				// grault
+				{LeadingComments: " Block comment attached to\n grault. "},
+			},
+		},
+		{
+			name: "the comments around the scopes and the option values",
+			input: `// detached syntax.
+
+// leading syntax.
+syntax = "proto3"; // trailing syntax.
+package p; /* ambiguous */ option (a) = { b: 1; c { d: 2 } };
+message M {
+  int32 f = 1 [(o) = { x: 1 }];
+  // trailing f.
+
+  // dropped.
+}
+// leading E.
+enum E { A = 0; }
+// dropped at EOF.
+`,
+			wantComments: []parser.ProtocComments{
+				// syntax
+				{
+					LeadingComments:         " leading syntax.\n",
+					TrailingComments:        " trailing syntax.\n",
+					LeadingDetachedComments: []string{" detached syntax.\n"},
+				},
+				// p
+				{},
+				// (a)
+				{},
+				// M
+				{},
+				// f
+				{TrailingComments: " trailing f.\n"},
+				// E
+				{LeadingComments: " leading E.\n"},
+				// A
+				{},
+			},
+		},
+		{
+			name: "a nested message followed by the sibling fields",
+			input: `syntax = "proto3";
+message Root {
+  message M1 {
+    int32 f2 = 2; // f2
+  }
+  int32 f3 = 3; // f3
+  int32 f4 = 4; // f4
+}
+`,
+			wantComments: []parser.ProtocComments{
+				// syntax
+				{},
+				// Root
+				{},
+				// M1
+				{},
+				// f2
+				{TrailingComments: " f2\n"},
+				// f3
+				{TrailingComments: " f3\n"},
+				// f4
+				{TrailingComments: " f4\n"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			p := parser.NewParser(
+				lexer.NewLexer(strings.NewReader(test.input)),
+				parser.WithPermissive(true),
+				parser.WithProtocComments(true),
+			)
+			got, err := p.ParseProto()
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+
+			var gotComments []parser.ProtocComments
+			parser.Walk(got, func(c *parser.Cursor) bool {
+				if comments := protocCommentsOf(c.Node()); comments != nil && !c.Leaving() {
+					gotComments = append(gotComments, *comments)
+				}
+				return true
+			})
+			if !reflect.DeepEqual(gotComments, test.wantComments) {
+				t.Errorf("got %q, but want %q", gotComments, test.wantComments)
+			}
+		})
+	}
+}
+
+func TestParser_ParseProto_withoutProtocComments(t *testing.T) {
+	p := parser.NewParser(lexer.NewLexer(strings.NewReader(`// leading.
+syntax = "proto3"; // trailing.
+`)))
+	got, err := p.ParseProto()
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	if !reflect.DeepEqual(got.Syntax.ProtocComments, parser.ProtocComments{}) {
+		t.Errorf("got %v, but want the empty one", got.Syntax.ProtocComments)
+	}
+}
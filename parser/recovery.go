@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// recoverFrom records the err and skips to the end of the erroneous statement which started at the depth of curlies.
+// It returns false if the error recovery mode is disabled. In that case, the caller must return the err.
+func (p *Parser) recoverFrom(err error, depth int, inBody bool) bool {
+	if !p.errorRecovery {
+		return false
+	}
+	p.recordError(err)
+	p.skipStatement(depth, inBody)
+	return true
+}
+
+// recordError records the err as a diagnostic. The one at the same position as the last one is ignored.
+func (p *Parser) recordError(err error) {
+	var metaErr *meta.Error
+	if !errors.As(err, &metaErr) {
+		metaErr = &meta.Error{
+			Pos:      p.lex.Pos.Position,
+			Expected: "valid statement",
+			Found:    err.Error(),
+		}
+	}
+	if 0 < len(p.errors) && p.errors[len(p.errors)-1].Pos == metaErr.Pos {
+		return
+	}
+	p.errors = append(p.errors, metaErr)
+}
+
+// skipStatement skips to the next ";" or "}" at the depth of curlies.
+// In a body, the "}" closing the body is left to be read by the caller.
+func (p *Parser) skipStatement(depth int, inBody bool) {
+	for {
+		switch {
+		case p.lex.IsEOF():
+			return
+		case p.lex.CurlyDepth() < depth:
+			if inBody {
+				p.lex.UnNext()
+			}
+			return
+		case p.lex.CurlyDepth() == depth &&
+			(p.lex.Token == scanner.TSEMICOLON || p.lex.Token == scanner.TRIGHTCURLY):
+			return
+		}
+		p.lex.NextStrLit()
+	}
+}
@@ -0,0 +1,206 @@
+package parser_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+func TestParser_ParseProto_withErrorRecovery(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantBody      []string
+		wantErrorsPos []string
+	}{
+		{
+			name: "parsing a valid proto",
+			input: `syntax = "proto3";
+message A { int32 x = 1; }
+`,
+			wantBody: []string{
+				"message A { field x }",
+			},
+		},
+		{
+			name: "parsing errors in all kinds of bodies",
+			input: `syntax = "proto3";
+message A {
+  int32 x = ;
+  string y = 2;
+  B.C z = 3 [deprecated=];
+}
+enum E {
+  E0 = 0;
+  E1 = ;
+  E2 = 2;
+}
+service S {
+  rpc Get(A) returns ;
+  rpc List(A) returns (A);
+}
+message D {
+  oneof o {
+    int32 a = ;
+    string b = 2;
+  }
+  map<string, > m = 4;
+  message N { int32 q = 1 }
+  int32 r = 5;
+}
+extend A {
+  int32 ext = ;
+  int32 ext2 = 101;
+}
+}
+message F {}
+`,
+			wantBody: []string{
+				"message A { field y }",
+				"enum E { enumField E0 enumField E2 }",
+				"service S { rpc List }",
+				"message D { oneof o { field b } message N { } field r }",
+				"extend A { field ext2 }",
+				"message F { }",
+			},
+			wantErrorsPos: []string{
+				"3:13",
+				"5:25",
+				"9:8",
+				"13:22",
+				"18:15",
+				"21:15",
+				"22:27",
+				"26:15",
+				"29:1",
+			},
+		},
+		{
+			name: "parsing a missing syntax",
+			input: `message A { int32 x = 1; }
+`,
+			wantBody: []string{
+				"message A { field x }",
+			},
+			wantErrorsPos: []string{
+				"1:1",
+			},
+		},
+		{
+			name:  "parsing an invalid syntax",
+			input: `syntax = ; message A { int32 x = 1; } enum {`,
+			wantBody: []string{
+				"message A { field x }",
+			},
+			wantErrorsPos: []string{
+				"1:10",
+				"1:44",
+			},
+		},
+		{
+			name:  "parsing an unclosed body",
+			input: `syntax = "proto3"; message A { int32 x = 1;`,
+			wantBody: []string{
+				"message A { field x }",
+			},
+			wantErrorsPos: []string{
+				"1:44",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			p := parser.NewParser(
+				lexer.NewLexer(strings.NewReader(test.input)),
+				parser.WithErrorRecovery(true),
+			)
+			got, err := p.ParseProto()
+
+			var gotErrorsPos []string
+			if err != nil {
+				var errs meta.ErrorList
+				if !errors.As(err, &errs) {
+					t.Fatalf("got err %v, but want meta.ErrorList", err)
+				}
+				for _, e := range errs {
+					gotErrorsPos = append(gotErrorsPos, fmt.Sprintf("%d:%d", e.Pos.Line, e.Pos.Column))
+				}
+			}
+			if !reflect.DeepEqual(gotErrorsPos, test.wantErrorsPos) {
+				t.Errorf("got errors at %v, but want %v", gotErrorsPos, test.wantErrorsPos)
+			}
+
+			if got == nil {
+				t.Fatalf("got nil, but want a partial proto")
+			}
+			var gotBody []string
+			for _, v := range got.ProtoBody {
+				gotBody = append(gotBody, describeRecovered(v))
+			}
+			if !reflect.DeepEqual(gotBody, test.wantBody) {
+				t.Errorf("got %v, but want %v", gotBody, test.wantBody)
+			}
+		})
+	}
+}
+
+func TestParser_ParseProto_withoutErrorRecovery(t *testing.T) {
+	p := parser.NewParser(lexer.NewLexer(strings.NewReader(`syntax = "proto3";
+message A {
+  int32 x = ;
+}
+`)))
+	got, err := p.ParseProto()
+	if got != nil {
+		t.Errorf("got %v, but want nil", got)
+	}
+	var errs meta.ErrorList
+	if err == nil || errors.As(err, &errs) {
+		t.Errorf("got err %v, but want a single error", err)
+	}
+}
+
+// describeRecovered describes the structure of the statement.
+func describeRecovered(v parser.Visitee) string {
+	body := func(kind, name string, stmts []parser.Visitee) string {
+		var ds []string
+		for _, s := range stmts {
+			ds = append(ds, describeRecovered(s))
+		}
+		return strings.Join(append(append([]string{kind, name, "{"}, ds...), "}"), " ")
+	}
+	switch t := v.(type) {
+	case *parser.Message:
+		return body("message", t.MessageName, t.MessageBody)
+	case *parser.Enum:
+		return body("enum", t.EnumName, t.EnumBody)
+	case *parser.Service:
+		return body("service", t.ServiceName, t.ServiceBody)
+	case *parser.Extend:
+		return body("extend", t.MessageType, t.ExtendBody)
+	case *parser.Oneof:
+		var stmts []parser.Visitee
+		for _, f := range t.OneofFields {
+			stmts = append(stmts, f)
+		}
+		return body("oneof", t.OneofName, stmts)
+	case *parser.Field:
+		return "field " + t.FieldName
+	case *parser.OneofField:
+		return "field " + t.FieldName
+	case *parser.EnumField:
+		return "enumField " + t.Ident
+	case *parser.RPC:
+		return "rpc " + t.RPCName
+	default:
+		return fmt.Sprintf("%T", t)
+	}
+}
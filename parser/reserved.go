@@ -16,14 +16,23 @@ func (e *parseReservedErr) Error() string {
 	return fmt.Sprintf("%v:%v", e.parseRangesErr, e.parseFieldNamesErr)
 }
 
+// Unwrap returns the first error.
+func (e *parseReservedErr) Unwrap() error {
+	return e.parseRangesErr
+}
+
 // Range is a range of field numbers. End is an optional value.
 type Range struct {
 	Begin string
 	End   string
+
+	// Meta is the meta information.
+	Meta meta.Meta
 }
 
 // Reserved declares a range of field numbers or field names that cannot be used in this message.
 // These component Ranges and FieldNames are mutually exclusive.
+// FieldNames keep their quotes, except for the unquoted identifiers used by editions-based files.
 type Reserved struct {
 	Ranges     []*Range
 	FieldNames []string
@@ -32,6 +41,8 @@ type Reserved struct {
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -136,12 +147,14 @@ func (p *Parser) parseRange() (*Range, error) {
 		return nil, p.unexpected("intLit")
 	}
 	begin := p.lex.Text
+	beginMeta := p.tokenMeta()
 
 	p.lex.Next()
 	if p.lex.Text != "to" {
 		p.lex.UnNext()
 		return &Range{
 			Begin: begin,
+			Meta:  beginMeta,
 		}, nil
 	}
 
@@ -152,6 +165,7 @@ func (p *Parser) parseRange() (*Range, error) {
 		return &Range{
 			Begin: begin,
 			End:   p.lex.Text,
+			Meta:  meta.Meta{Pos: beginMeta.Pos, LastPos: p.tokenMeta().LastPos},
 		}, nil
 	default:
 		break
@@ -164,7 +178,7 @@ func (p *Parser) parseRange() (*Range, error) {
 func (p *Parser) parseFieldNames() ([]string, error) {
 	var fieldNames []string
 
-	fieldName, err := p.parseQuotedFieldName()
+	fieldName, err := p.parseReservedFieldName()
 	if err != nil {
 		return nil, err
 	}
@@ -177,7 +191,7 @@ func (p *Parser) parseFieldNames() ([]string, error) {
 			break
 		}
 
-		fieldName, err = p.parseQuotedFieldName()
+		fieldName, err = p.parseReservedFieldName()
 		if err != nil {
 			return nil, err
 		}
@@ -186,6 +200,20 @@ func (p *Parser) parseFieldNames() ([]string, error) {
 	return fieldNames, nil
 }
 
+// reservedFieldName = quotedFieldName | ident
+// The unquoted ident is only allowed in editions-based files.
+// See https://protobuf.dev/reference/protobuf/edition-2023-spec/#reserved
+func (p *Parser) parseReservedFieldName() (string, error) {
+	if p.edition != "" {
+		p.lex.Next()
+		if p.lex.Token == scanner.TIDENT {
+			return p.lex.Text, nil
+		}
+		p.lex.UnNext()
+	}
+	return p.parseQuotedFieldName()
+}
+
 // quotedFieldName = quote + fieldName + quote
 // TODO: Fixed according to defined documentation. Currently(2018.10.16) the reference lacks the spec.
 // See https://github.com/protocolbuffers/protobuf/issues/4558
@@ -39,13 +39,49 @@ func TestParser_ParseReserved(t *testing.T) {
 				Ranges: []*parser.Range{
 					{
 						Begin: "2",
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 9,
+								Line:   1,
+								Column: 10,
+							},
+							LastPos: meta.Position{
+								Offset: 9,
+								Line:   1,
+								Column: 10,
+							},
+						},
 					},
 					{
 						Begin: "15",
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 12,
+								Line:   1,
+								Column: 13,
+							},
+							LastPos: meta.Position{
+								Offset: 13,
+								Line:   1,
+								Column: 14,
+							},
+						},
 					},
 					{
 						Begin: "9",
 						End:   "11",
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 16,
+								Line:   1,
+								Column: 17,
+							},
+							LastPos: meta.Position{
+								Offset: 22,
+								Line:   1,
+								Column: 23,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
@@ -92,6 +128,18 @@ func TestParser_ParseReserved(t *testing.T) {
 					{
 						Begin: "9",
 						End:   "max",
+						Meta: meta.Meta{
+							Pos: meta.Position{
+								Offset: 9,
+								Line:   1,
+								Column: 10,
+							},
+							LastPos: meta.Position{
+								Offset: 16,
+								Line:   1,
+								Column: 17,
+							},
+						},
 					},
 				},
 				Meta: meta.Meta{
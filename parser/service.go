@@ -10,6 +10,8 @@ type RPCRequest struct {
 	IsStream    bool
 	MessageType string
 
+	// MessageTypeMeta is the meta information of the MessageType. It's set with WithDetailedPositions.
+	MessageTypeMeta meta.Meta
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -19,6 +21,8 @@ type RPCResponse struct {
 	IsStream    bool
 	MessageType string
 
+	// MessageTypeMeta is the meta information of the MessageType. It's set with WithDetailedPositions.
+	MessageTypeMeta meta.Meta
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -30,12 +34,17 @@ type RPC struct {
 	RPCResponse *RPCResponse
 	Options     []*Option
 
+	// RPCNameMeta is the meta information of the RPCName. It's set with WithDetailedPositions.
+	RPCNameMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
 	// InlineCommentBehindLeftCurly is the optional one placed behind a left curly.
 	InlineCommentBehindLeftCurly *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -65,12 +74,17 @@ type Service struct {
 	// ServiceBody can have options and rpcs.
 	ServiceBody []Visitee
 
+	// ServiceNameMeta is the meta information of the ServiceName. It's set with WithDetailedPositions.
+	ServiceNameMeta meta.Meta
+
 	// Comments are the optional ones placed at the beginning.
 	Comments []*Comment
 	// InlineComment is the optional one placed at the ending.
 	InlineComment *Comment
 	// InlineCommentBehindLeftCurly is the optional one placed behind a left curly.
 	InlineCommentBehindLeftCurly *Comment
+	// ProtocComments are the comments attached as protoc does. They're set with WithProtocComments.
+	ProtocComments
 	// Meta is the meta information.
 	Meta meta.Meta
 }
@@ -106,6 +120,10 @@ func (s *Service) Accept(v Visitor) {
 //
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#service_definition
 func (p *Parser) ParseService() (*Service, error) {
+	defer p.leave()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
 	p.lex.NextKeyword()
 	if p.lex.Token != scanner.TSERVICE {
 		return nil, p.unexpected("service")
@@ -117,6 +135,7 @@ func (p *Parser) ParseService() (*Service, error) {
 		return nil, p.unexpected("serviceName")
 	}
 	serviceName := p.lex.Text
+	serviceNameMeta := p.tokenMeta()
 
 	serviceBody, inlineLeftCurly, lastPos, err := p.parseServiceBody()
 	if err != nil {
@@ -126,6 +145,7 @@ func (p *Parser) ParseService() (*Service, error) {
 	return &Service{
 		ServiceName:                  serviceName,
 		ServiceBody:                  serviceBody,
+		ServiceNameMeta:              p.partMeta(serviceNameMeta),
 		InlineCommentBehindLeftCurly: inlineLeftCurly,
 		Meta: meta.Meta{
 			Pos:     startPos.Position,
@@ -152,18 +172,13 @@ func (p *Parser) parseServiceBody() (
 	var stmts []Visitee
 	for {
 		comments := p.ParseComments()
+		depth := p.lex.CurlyDepth()
 
 		p.lex.NextKeyword()
 		token := p.lex.Token
 		p.lex.UnNext()
 
-		var stmt interface {
-			HasInlineCommentSetter
-			Visitee
-		}
-
-		switch token {
-		case scanner.TRIGHTCURLY:
+		if token == scanner.TRIGHTCURLY {
 			if p.bodyIncludingComments {
 				for _, comment := range comments {
 					stmts = append(stmts, Visitee(comment))
@@ -180,25 +195,17 @@ func (p *Parser) parseServiceBody() (
 				}
 			}
 			return stmts, inlineLeftCurly, lastPos, nil
-		case scanner.TOPTION:
-			option, err := p.ParseOption()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
-			}
-			option.Comments = comments
-			stmt = option
-		case scanner.TRPC:
-			rpc, err := p.parseRPC()
-			if err != nil {
+		}
+
+		stmt, err := p.parseServiceBodyStatement(token, comments)
+		if err != nil {
+			if !p.recoverFrom(err, depth, true) {
 				return nil, nil, scanner.Position{}, err
 			}
-			rpc.Comments = comments
-			stmt = rpc
-		default:
-			err := p.lex.ReadEmptyStatement()
-			if err != nil {
-				return nil, nil, scanner.Position{}, err
+			if p.IsEOF() {
+				return stmts, inlineLeftCurly, p.lex.Pos, nil
 			}
+			continue
 		}
 
 		p.MaybeScanInlineComment(stmt)
@@ -206,6 +213,37 @@ func (p *Parser) parseServiceBody() (
 	}
 }
 
+func (p *Parser) parseServiceBodyStatement(
+	token scanner.Token,
+	comments []*Comment,
+) (interface {
+	HasInlineCommentSetter
+	Visitee
+}, error) {
+	switch token {
+	case scanner.TOPTION:
+		option, err := p.ParseOption()
+		if err != nil {
+			return nil, err
+		}
+		option.Comments = comments
+		return option, nil
+	case scanner.TRPC:
+		rpc, err := p.parseRPC()
+		if err != nil {
+			return nil, err
+		}
+		rpc.Comments = comments
+		return rpc, nil
+	default:
+		err := p.lex.ReadEmptyStatement()
+		if err != nil {
+			return nil, err
+		}
+		return p.newEmptyStatement(), nil
+	}
+}
+
 // rpc = "rpc" rpcName "(" [ "stream" ] messageType ")" "returns" "(" [ "stream" ]
 // messageType ")" (( "{" {option | emptyStatement } "}" ) | ";")
 // See https://developers.google.com/protocol-buffers/docs/reference/proto3-spec#service_definition
@@ -221,6 +259,7 @@ func (p *Parser) parseRPC() (*RPC, error) {
 		return nil, p.unexpected("serviceName")
 	}
 	rpcName := p.lex.Text
+	rpcNameMeta := p.tokenMeta()
 
 	rpcRequest, err := p.parseRPCRequest()
 	if err != nil {
@@ -267,6 +306,7 @@ func (p *Parser) parseRPC() (*RPC, error) {
 		RPCRequest:                   rpcRequest,
 		RPCResponse:                  rpcResponse,
 		Options:                      opts,
+		RPCNameMeta:                  p.partMeta(rpcNameMeta),
 		InlineCommentBehindLeftCurly: inlineLeftCurly,
 		Meta: meta.Meta{
 			Pos:     startPos.Position,
@@ -291,7 +331,7 @@ func (p *Parser) parseRPCRequest() (*RPCRequest, error) {
 		p.lex.UnNext()
 	}
 
-	messageType, _, err := p.lex.ReadMessageType()
+	messageType, messageTypePos, messageTypeLastPos, err := p.lex.ReadMessageTypeWithLastPos()
 	if err != nil {
 		return nil, err
 	}
@@ -302,9 +342,10 @@ func (p *Parser) parseRPCRequest() (*RPCRequest, error) {
 	}
 
 	return &RPCRequest{
-		IsStream:    isStream,
-		MessageType: messageType,
-		Meta:        meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
+		IsStream:        isStream,
+		MessageType:     messageType,
+		MessageTypeMeta: p.partMeta(spanMeta(messageTypePos, messageTypeLastPos)),
+		Meta:            meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
 	}, nil
 }
 
@@ -324,7 +365,7 @@ func (p *Parser) parseRPCResponse() (*RPCResponse, error) {
 		p.lex.UnNext()
 	}
 
-	messageType, _, err := p.lex.ReadMessageType()
+	messageType, messageTypePos, messageTypeLastPos, err := p.lex.ReadMessageTypeWithLastPos()
 	if err != nil {
 		return nil, err
 	}
@@ -335,9 +376,10 @@ func (p *Parser) parseRPCResponse() (*RPCResponse, error) {
 	}
 
 	return &RPCResponse{
-		IsStream:    isStream,
-		MessageType: messageType,
-		Meta:        meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
+		IsStream:        isStream,
+		MessageType:     messageType,
+		MessageTypeMeta: p.partMeta(spanMeta(messageTypePos, messageTypeLastPos)),
+		Meta:            meta.Meta{Pos: startPos.Position, LastPos: p.lex.Pos.Position},
 	}, nil
 }
 
@@ -140,6 +140,22 @@ service SearchService {
 							{
 								OptionName: "(my_option).a",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 104,
+											Line:   3,
+											Column: 80,
+										},
+										LastPos: meta.Position{
+											Offset: 107,
+											Line:   3,
+											Column: 83,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Offset: 81,
@@ -231,6 +247,22 @@ service SearchService {
 							{
 								OptionName: "(my_option).a",
 								Constant:   "true",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "true",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 106,
+											Line:   4,
+											Column: 25,
+										},
+										LastPos: meta.Position{
+											Offset: 109,
+											Line:   4,
+											Column: 28,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Offset: 83,
@@ -247,6 +279,22 @@ service SearchService {
 							{
 								OptionName: "(my_option).b",
 								Constant:   "false",
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindBool,
+									Scalar: "false",
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 137,
+											Line:   5,
+											Column: 25,
+										},
+										LastPos: meta.Position{
+											Offset: 141,
+											Line:   5,
+											Column: 29,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Offset: 114,
@@ -907,6 +955,22 @@ service SearchService {
 							{
 								OptionName: "(requestreply.Nats).Subject",
 								Constant:   `"get.addrs"`,
+								Value: &parser.OptionValue{
+									Kind:   parser.OptionValueKindString,
+									Scalar: `"get.addrs"`,
+									Meta: meta.Meta{
+										Pos: meta.Position{
+											Offset: 142,
+											Line:   4,
+											Column: 41,
+										},
+										LastPos: meta.Position{
+											Offset: 152,
+											Line:   4,
+											Column: 51,
+										},
+									},
+								},
 								Meta: meta.Meta{
 									Pos: meta.Position{
 										Offset: 106,
@@ -0,0 +1,118 @@
+package parser
+
+// Cursor describes a node which Walk encounters.
+type Cursor struct {
+	node    Visitee
+	parents []Visitee
+	leaving bool
+}
+
+// Node returns the node.
+func (c *Cursor) Node() Visitee {
+	return c.node
+}
+
+// Parent returns the node which has the node, or nil for the node passed to Walk.
+func (c *Cursor) Parent() Visitee {
+	if len(c.parents) == 0 {
+		return nil
+	}
+	return c.parents[len(c.parents)-1]
+}
+
+// Parents returns the chain of the nodes which have the node, from the node passed to Walk to the parent.
+// The slice must not be modified.
+func (c *Cursor) Parents() []Visitee {
+	return c.parents
+}
+
+// Leaving reports whether the cursor is leaving the node after walking the nodes in it.
+func (c *Cursor) Leaving() bool {
+	return c.leaving
+}
+
+// Walk traverses the node in depth-first order, like go/ast.Inspect.
+// It calls fn with the cursor entering the node. When fn returns true, Walk traverses the nodes in it,
+// and calls fn again with the cursor leaving the node, whose return value is ignored.
+// The nodes are traversed in the same order as Accept, which visits the comments of a node after its body.
+// Unlike Accept, Walk also traverses the options in the body of an RPC.
+func Walk(node Visitee, fn func(c *Cursor) bool) {
+	walk(node, nil, fn)
+}
+
+func walk(node Visitee, parents []Visitee, fn func(c *Cursor) bool) {
+	c := &Cursor{node: node, parents: parents}
+	if !fn(c) {
+		return
+	}
+	// the full slice expression keeps the siblings from sharing the backing array.
+	nodeParents := append(parents[:len(parents):len(parents)], node)
+	for _, child := range children(node) {
+		walk(child, nodeParents, fn)
+	}
+	c.leaving = true
+	fn(c)
+}
+
+// children returns the nodes in the node, which Accept visits.
+func children(node Visitee) []Visitee {
+	if p, ok := node.(*Proto); ok {
+		var nodes []Visitee
+		if p.Syntax != nil {
+			nodes = append(nodes, p.Syntax)
+		}
+		if p.Edition != nil {
+			nodes = append(nodes, p.Edition)
+		}
+		return append(nodes, p.ProtoBody...)
+	}
+
+	collector := &childrenCollector{}
+	if rpc, ok := node.(*RPC); ok {
+		// RPC.Accept doesn't visit the options in the body.
+		for _, option := range rpc.Options {
+			collector.children = append(collector.children, option)
+		}
+	}
+	node.Accept(collector)
+	return collector.children
+}
+
+// childrenCollector collects the nodes which Accept of a node visits, without visiting the nodes in them.
+type childrenCollector struct {
+	entered  bool
+	children []Visitee
+}
+
+// collect returns true only for the node itself, whose Accept is called first.
+func (c *childrenCollector) collect(node Visitee) bool {
+	if !c.entered {
+		c.entered = true
+		return true
+	}
+	c.children = append(c.children, node)
+	return false
+}
+
+func (c *childrenCollector) VisitComment(n *Comment) {
+	c.collect(n)
+}
+func (c *childrenCollector) VisitEdition(n *Edition) bool               { return c.collect(n) }
+func (c *childrenCollector) VisitEmptyStatement(n *EmptyStatement) bool { return c.collect(n) }
+func (c *childrenCollector) VisitEnum(n *Enum) bool                     { return c.collect(n) }
+func (c *childrenCollector) VisitEnumField(n *EnumField) bool           { return c.collect(n) }
+func (c *childrenCollector) VisitExtend(n *Extend) bool                 { return c.collect(n) }
+func (c *childrenCollector) VisitExtensions(n *Extensions) bool         { return c.collect(n) }
+func (c *childrenCollector) VisitField(n *Field) bool                   { return c.collect(n) }
+func (c *childrenCollector) VisitGroupField(n *GroupField) bool         { return c.collect(n) }
+func (c *childrenCollector) VisitImport(n *Import) bool                 { return c.collect(n) }
+func (c *childrenCollector) VisitMapField(n *MapField) bool             { return c.collect(n) }
+func (c *childrenCollector) VisitMessage(n *Message) bool               { return c.collect(n) }
+func (c *childrenCollector) VisitOneof(n *Oneof) bool                   { return c.collect(n) }
+func (c *childrenCollector) VisitOneofField(n *OneofField) bool         { return c.collect(n) }
+func (c *childrenCollector) VisitOption(n *Option) bool                 { return c.collect(n) }
+func (c *childrenCollector) VisitPackage(n *Package) bool               { return c.collect(n) }
+func (c *childrenCollector) VisitReserved(n *Reserved) bool             { return c.collect(n) }
+func (c *childrenCollector) VisitRPC(n *RPC) bool                       { return c.collect(n) }
+func (c *childrenCollector) VisitService(n *Service) bool               { return c.collect(n) }
+func (c *childrenCollector) VisitSyntax(n *Syntax) bool                 { return c.collect(n) }
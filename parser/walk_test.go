@@ -0,0 +1,214 @@
+package parser_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+func parseProto(t *testing.T, input string) *parser.Proto {
+	t.Helper()
+	p := parser.NewParser(lexer.NewLexer(strings.NewReader(input)))
+	proto, err := p.ParseProto()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return proto
+}
+
+func nodeName(node parser.Visitee) string {
+	switch n := node.(type) {
+	case *parser.Proto:
+		return "Proto"
+	case *parser.Syntax:
+		return "Syntax"
+	case *parser.Comment:
+		return "Comment " + n.Raw
+	case *parser.Message:
+		return "Message " + n.MessageName
+	case *parser.Field:
+		return "Field " + n.FieldName
+	case *parser.Extend:
+		return "Extend " + n.MessageType
+	case *parser.Oneof:
+		return "Oneof " + n.OneofName
+	case *parser.OneofField:
+		return "OneofField " + n.FieldName
+	case *parser.Service:
+		return "Service " + n.ServiceName
+	case *parser.RPC:
+		return "RPC " + n.RPCName
+	case *parser.Option:
+		return "Option " + n.OptionName
+	}
+	return "unknown"
+}
+
+func TestWalk(t *testing.T) {
+	proto := parseProto(t, `syntax = "proto3";
+// A is a message.
+message A {
+  string a = 1;
+  oneof o {
+    int32 b = 2;
+  }
+}
+extend A {
+  int32 c = 3;
+}
+service S {
+  rpc Get(A) returns (A);
+}
+`)
+
+	var got []string
+	parser.Walk(proto, func(c *parser.Cursor) bool {
+		var parents []string
+		for _, p := range c.Parents() {
+			parents = append(parents, nodeName(p))
+		}
+		event := "enter "
+		if c.Leaving() {
+			event = "leave "
+		}
+		got = append(got, event+nodeName(c.Node())+" in ["+strings.Join(parents, ", ")+"]")
+		// skips the nodes in the service.
+		_, isService := c.Node().(*parser.Service)
+		return !isService
+	})
+
+	want := []string{
+		"enter Proto in []",
+		"enter Syntax in [Proto]",
+		"leave Syntax in [Proto]",
+		"enter Message A in [Proto]",
+		"enter Field a in [Proto, Message A]",
+		"leave Field a in [Proto, Message A]",
+		"enter Oneof o in [Proto, Message A]",
+		"enter OneofField b in [Proto, Message A, Oneof o]",
+		"leave OneofField b in [Proto, Message A, Oneof o]",
+		"leave Oneof o in [Proto, Message A]",
+		"enter Comment // A is a message. in [Proto, Message A]",
+		"leave Comment // A is a message. in [Proto, Message A]",
+		"leave Message A in [Proto]",
+		"enter Extend A in [Proto]",
+		"enter Field c in [Proto, Extend A]",
+		"leave Field c in [Proto, Extend A]",
+		"leave Extend A in [Proto]",
+		"enter Service S in [Proto]",
+		"leave Proto in []",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestWalk_rpcOptions(t *testing.T) {
+	proto := parseProto(t, `syntax = "proto3";
+service S {
+  option deprecated = true;
+  // Get gets A.
+  rpc Get(A) returns (A) {
+    option idempotency_level = NO_SIDE_EFFECTS;
+    option deprecated = true;
+  }
+}
+`)
+
+	var got []string
+	parser.Walk(proto, func(c *parser.Cursor) bool {
+		if !c.Leaving() {
+			got = append(got, nodeName(c.Node())+" in "+nodeName(c.Parent()))
+		}
+		return true
+	})
+	want := []string{
+		"Proto in unknown",
+		"Syntax in Proto",
+		"Service S in Proto",
+		"Option deprecated in Service S",
+		"RPC Get in Service S",
+		"Option idempotency_level in RPC Get",
+		"Option deprecated in RPC Get",
+		"Comment // Get gets A. in RPC Get",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+}
+
+func TestCursor_Parent(t *testing.T) {
+	proto := parseProto(t, `syntax = "proto3";
+message A {
+  message B {
+    string a = 1;
+  }
+  string b = 2;
+}
+extend A {
+  string c = 3;
+}
+`)
+
+	// the fields are told from their parents, even though they are visited in the same way.
+	var got []string
+	parser.Walk(proto, func(c *parser.Cursor) bool {
+		if f, ok := c.Node().(*parser.Field); ok && !c.Leaving() {
+			got = append(got, f.FieldName+" in "+nodeName(c.Parent()))
+		}
+		return true
+	})
+	want := []string{
+		"a in Message B",
+		"b in Message A",
+		"c in Extend A",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+
+	parser.Walk(proto, func(c *parser.Cursor) bool {
+		if c.Parent() != nil {
+			t.Errorf("got %v, but want nil", c.Parent())
+		}
+		return false
+	})
+}
+
+// fieldCounter counts the fields, except the ones in the extends.
+type fieldCounter struct {
+	parser.BaseVisitor
+	count int
+}
+
+func (c *fieldCounter) VisitField(*parser.Field) bool {
+	c.count++
+	return true
+}
+
+func (c *fieldCounter) VisitExtend(*parser.Extend) bool {
+	return false
+}
+
+func TestBaseVisitor(t *testing.T) {
+	proto := parseProto(t, `syntax = "proto3";
+message A {
+  string a = 1;
+  message B {
+    string b = 1;
+  }
+}
+extend A {
+  string c = 3;
+}
+`)
+
+	counter := &fieldCounter{}
+	proto.Accept(counter)
+	if counter.count != 2 {
+		t.Errorf("got %d, but want 2", counter.count)
+	}
+}
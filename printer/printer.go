@@ -0,0 +1,615 @@
+// Package printer implements printing of the parser's nodes as .proto source.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Config controls the output of Fprint.
+type Config struct {
+	// Indent is the string used for each indentation level. The default is two spaces.
+	Indent string
+	// IgnorePositions disables keeping the blank lines between statements by their Meta.
+	IgnorePositions bool
+	// AlignFields aligns the "=" of the consecutive fields and enum fields, and their inline comments.
+	AlignFields bool
+	// SeparateDefinitions puts a blank line around each top-level definition and between the groups of
+	// other top-level statements, such as the imports and the options.
+	SeparateDefinitions bool
+}
+
+// Fprint prints the node with the default config.
+// The node is a *parser.Proto or any statement, such as *parser.Message.
+func Fprint(w io.Writer, node parser.Visitee) error {
+	return (&Config{}).Fprint(w, node)
+}
+
+// Fprint prints the node to w.
+// Comments, InlineComment and InlineCommentBehindLeftCurly are kept,
+// and a blank line in the source is kept between statements unless IgnorePositions is true.
+func (c *Config) Fprint(w io.Writer, node parser.Visitee) error {
+	indent := c.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	p := &printer{
+		indent:              indent,
+		ignorePositions:     c.IgnorePositions,
+		alignFields:         c.AlignFields,
+		separateDefinitions: c.SeparateDefinitions,
+	}
+	if err := p.node(node); err != nil {
+		return err
+	}
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+type printer struct {
+	indent              string
+	ignorePositions     bool
+	alignFields         bool
+	separateDefinitions bool
+
+	buf   bytes.Buffer
+	depth int
+}
+
+func (p *printer) node(node parser.Visitee) error {
+	proto, ok := node.(*parser.Proto)
+	if !ok {
+		return p.stmts([]parser.Visitee{node})
+	}
+
+	var stmts []parser.Visitee
+	if proto.Syntax != nil {
+		stmts = append(stmts, proto.Syntax)
+	}
+	if proto.Edition != nil {
+		stmts = append(stmts, proto.Edition)
+	}
+	return p.stmts(append(stmts, proto.ProtoBody...))
+}
+
+// stmts prints the statements with keeping the blank lines between them.
+func (p *printer) stmts(stmts []parser.Visitee) error {
+	for i := 0; i < len(stmts); i++ {
+		if 0 < i && p.blankLine(stmts[i-1], stmts[i]) {
+			p.buf.WriteString("\n")
+		}
+
+		if _, _, ok := p.fieldParts(stmts[i]); ok && p.alignFields {
+			j := i + 1
+			for j < len(stmts) {
+				if _, _, ok := p.fieldParts(stmts[j]); !ok || p.blankLine(stmts[j-1], stmts[j]) {
+					break
+				}
+				j++
+			}
+			p.alignedFields(stmts[i:j])
+			i = j - 1
+			continue
+		}
+
+		if err := p.stmt(stmts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blankLine reports whether a blank line is put between the statements.
+func (p *printer) blankLine(prev, stmt parser.Visitee) bool {
+	if p.separateDefinitions && p.depth == 0 {
+		prevKind, kind := topLevelKind(prev), topLevelKind(stmt)
+		if prevKind != 0 && kind != 0 && (prevKind != kind || kind == kindDefinition) {
+			return true
+		}
+	}
+	if p.ignorePositions {
+		return false
+	}
+	_, prevLastLine := lines(prev)
+	firstLine, _ := lines(stmt)
+	return 0 < prevLastLine && prevLastLine+1 < firstLine
+}
+
+// alignedFields prints the fields with aligning their "=" and their inline comments.
+func (p *printer) alignedFields(fields []parser.Visitee) {
+	type alignedField struct {
+		text          string
+		comments      []*parser.Comment
+		inlineComment *parser.Comment
+	}
+
+	var lefts, rights []string
+	leftWidth := 0
+	for _, field := range fields {
+		left, right, _ := p.fieldParts(field)
+		lefts = append(lefts, left)
+		rights = append(rights, right)
+		if w := utf8.RuneCountInString(left); leftWidth < w {
+			leftWidth = w
+		}
+	}
+
+	var aligned []alignedField
+	textWidth := 0
+	for i, field := range fields {
+		comments, inlineComment, _, _ := layout(field)
+		text := pad(lefts[i], leftWidth) + " " + rights[i]
+		aligned = append(aligned, alignedField{
+			text:          text,
+			comments:      comments,
+			inlineComment: inlineComment,
+		})
+		if inlineComment != nil && !strings.Contains(text, "\n") {
+			if w := utf8.RuneCountInString(text); textWidth < w {
+				textWidth = w
+			}
+		}
+	}
+
+	for _, field := range aligned {
+		p.comments(field.comments)
+		text := field.text
+		if field.inlineComment != nil && !strings.Contains(text, "\n") {
+			text = pad(text, textWidth)
+		}
+		p.line(text, field.inlineComment)
+	}
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func (p *printer) stmt(stmt parser.Visitee) error {
+	switch n := stmt.(type) {
+	case *parser.Comment:
+		p.line(n.Raw, nil)
+	case *parser.EmptyStatement:
+		p.line(";", n.InlineComment)
+	case *parser.Syntax:
+		p.comments(n.Comments)
+		p.line(fmt.Sprintf("syntax = %s;", n.ProtobufVersionQuote), n.InlineComment)
+	case *parser.Edition:
+		p.comments(n.Comments)
+		p.line(fmt.Sprintf("edition = %s;", n.EditionQuote), n.InlineComment)
+	case *parser.Import:
+		p.comments(n.Comments)
+		p.line(fmt.Sprintf("import %s%s;", importModifier(n.Modifier), n.Location), n.InlineComment)
+	case *parser.Package:
+		p.comments(n.Comments)
+		p.line(fmt.Sprintf("package %s;", n.Name), n.InlineComment)
+	case *parser.Option:
+		p.comments(n.Comments)
+		p.line(fmt.Sprintf("option %s = %s;", n.OptionName, p.constant(n.Constant)), n.InlineComment)
+	case *parser.Message:
+		p.comments(n.Comments)
+		return p.block("message "+n.MessageName, n.InlineCommentBehindLeftCurly, n.MessageBody, n.InlineComment)
+	case *parser.Enum:
+		p.comments(n.Comments)
+		return p.block("enum "+n.EnumName, n.InlineCommentBehindLeftCurly, n.EnumBody, n.InlineComment)
+	case *parser.Service:
+		p.comments(n.Comments)
+		return p.block("service "+n.ServiceName, n.InlineCommentBehindLeftCurly, n.ServiceBody, n.InlineComment)
+	case *parser.Extend:
+		p.comments(n.Comments)
+		return p.block("extend "+n.MessageType, n.InlineCommentBehindLeftCurly, n.ExtendBody, n.InlineComment)
+	case *parser.Field, *parser.MapField, *parser.OneofField, *parser.EnumField:
+		left, right, _ := p.fieldParts(stmt)
+		comments, inlineComment, _, _ := layout(stmt)
+		p.comments(comments)
+		p.line(left+" "+right, inlineComment)
+	case *parser.GroupField:
+		p.comments(n.Comments)
+		header := fmt.Sprintf(
+			"%sgroup %s = %s",
+			fieldLabel(n.IsRepeated, n.IsRequired, n.IsOptional),
+			n.GroupName,
+			n.FieldNumber,
+		)
+		return p.block(header, n.InlineCommentBehindLeftCurly, n.MessageBody, n.InlineComment)
+	case *parser.Oneof:
+		p.comments(n.Comments)
+		return p.block("oneof "+n.OneofName, n.InlineCommentBehindLeftCurly, oneofBody(n), n.InlineComment)
+	case *parser.Reserved:
+		p.comments(n.Comments)
+		reserved := strings.Join(n.FieldNames, ", ")
+		if 0 < len(n.Ranges) {
+			reserved = ranges(n.Ranges)
+		}
+		p.line(fmt.Sprintf("reserved %s;", reserved), n.InlineComment)
+	case *parser.Extensions:
+		p.comments(n.Comments)
+		p.line(fmt.Sprintf("extensions %s;", ranges(n.Ranges)), n.InlineComment)
+	case *parser.RPC:
+		p.comments(n.Comments)
+		header := fmt.Sprintf(
+			"rpc %s(%s) returns (%s)",
+			n.RPCName,
+			rpcMessageType(n.RPCRequest.IsStream, n.RPCRequest.MessageType),
+			rpcMessageType(n.RPCResponse.IsStream, n.RPCResponse.MessageType),
+		)
+		if len(n.Options) == 0 && n.InlineCommentBehindLeftCurly == nil {
+			p.line(header+";", n.InlineComment)
+			return nil
+		}
+		var options []parser.Visitee
+		for _, option := range n.Options {
+			options = append(options, option)
+		}
+		return p.block(header, n.InlineCommentBehindLeftCurly, options, n.InlineComment)
+	default:
+		return fmt.Errorf("printer: unsupported node type %T", stmt)
+	}
+	return nil
+}
+
+// block prints the header followed by the body enclosed in curly braces.
+func (p *printer) block(
+	header string,
+	inlineLeftCurly *parser.Comment,
+	body []parser.Visitee,
+	inlineComment *parser.Comment,
+) error {
+	if len(body) == 0 && inlineLeftCurly == nil {
+		p.line(header+" {}", inlineComment)
+		return nil
+	}
+
+	p.line(header+" {", inlineLeftCurly)
+	p.depth++
+	if err := p.stmts(body); err != nil {
+		return err
+	}
+	p.depth--
+	p.line("}", inlineComment)
+	return nil
+}
+
+func (p *printer) comments(comments []*parser.Comment) {
+	for _, comment := range comments {
+		p.line(comment.Raw, nil)
+	}
+}
+
+// line prints the text at the current indentation, followed by the inline comment.
+func (p *printer) line(text string, inlineComment *parser.Comment) {
+	p.buf.WriteString(strings.Repeat(p.indent, p.depth))
+	p.buf.WriteString(text)
+	if inlineComment != nil {
+		p.buf.WriteString(" ")
+		p.buf.WriteString(inlineComment.Raw)
+	}
+	p.buf.WriteString("\n")
+}
+
+// constant lays out the constant, putting each field of a message literal on its own line indented
+// relative to the statement. The parser reads it back into the same constant, because the constant keeps
+// the separators of the fields, which are "," and ";" or else "\n", and drops the whitespaces.
+func (p *printer) constant(constant string) string {
+	var b strings.Builder
+	// brackets holds the opening brackets of the literals which the current character is in.
+	var brackets []byte
+	newline := false
+	// last is the last character written.
+	var last byte
+	write := func(text string) {
+		if newline {
+			b.WriteString("\n" + strings.Repeat(p.indent, p.depth+len(brackets)))
+			newline = false
+		}
+		b.WriteString(text)
+		last = text[len(text)-1]
+	}
+	inMessage := func() bool {
+		return 0 < len(brackets) && brackets[len(brackets)-1] == '{'
+	}
+
+	for i := 0; i < len(constant); i++ {
+		c := constant[i]
+		switch c {
+		case '"', '\'':
+			end := literalEnd(constant, i)
+			write(constant[i:end])
+			i = end - 1
+		case '{':
+			if j := skipSpaces(constant, i+1); j < len(constant) && constant[j] == '}' {
+				write("{}")
+				i = j
+				continue
+			}
+			if isIdentChar(last) {
+				// a message field without the colon.
+				write(" ")
+			}
+			write("{")
+			brackets = append(brackets, c)
+			newline = true
+		case '[':
+			write("[")
+			brackets = append(brackets, c)
+		case '}', ']':
+			if 0 < len(brackets) {
+				brackets = brackets[:len(brackets)-1]
+			}
+			newline = newline || c == '}'
+			write(string(c))
+		case '\n':
+			newline = newline || inMessage()
+		case ',', ';':
+			write(string(c))
+			if inMessage() {
+				newline = true
+			} else {
+				write(" ")
+			}
+		case ':':
+			write(": ")
+		case ' ', '\t', '\r':
+		default:
+			write(string(c))
+		}
+	}
+	return b.String()
+}
+
+// literalEnd returns the offset next to the closing quote of the string literal which starts at the offset.
+func literalEnd(text string, start int) int {
+	q := text[start]
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case q:
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
+// skipSpaces returns the offset of the first character which is not a whitespace from the offset.
+func skipSpaces(text string, start int) int {
+	for start < len(text) && strings.ContainsRune(" \t\r\n", rune(text[start])) {
+		start++
+	}
+	return start
+}
+
+// isIdentChar reports whether the character can be a part of an identifier.
+func isIdentChar(c byte) bool {
+	return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func (p *printer) fieldOptions(options []*parser.FieldOption) string {
+	var opts []string
+	for _, option := range options {
+		opts = append(opts, fmt.Sprintf("%s = %s", option.OptionName, p.constant(option.Constant)))
+	}
+	return bracket(opts)
+}
+
+func (p *printer) enumValueOptions(options []*parser.EnumValueOption) string {
+	var opts []string
+	for _, option := range options {
+		opts = append(opts, fmt.Sprintf("%s = %s", option.OptionName, p.constant(option.Constant)))
+	}
+	return bracket(opts)
+}
+
+func bracket(opts []string) string {
+	if len(opts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(opts, ", ") + "]"
+}
+
+func importModifier(modifier parser.ImportModifier) string {
+	switch modifier {
+	case parser.ImportModifierPublic:
+		return "public "
+	case parser.ImportModifierWeak:
+		return "weak "
+	default:
+		return ""
+	}
+}
+
+func fieldLabel(isRepeated, isRequired, isOptional bool) string {
+	switch {
+	case isRepeated:
+		return "repeated "
+	case isRequired:
+		return "required "
+	case isOptional:
+		return "optional "
+	default:
+		return ""
+	}
+}
+
+func rpcMessageType(isStream bool, messageType string) string {
+	if isStream {
+		return "stream " + messageType
+	}
+	return messageType
+}
+
+func ranges(rs []*parser.Range) string {
+	var ss []string
+	for _, r := range rs {
+		if r.End == "" {
+			ss = append(ss, r.Begin)
+			continue
+		}
+		ss = append(ss, r.Begin+" to "+r.End)
+	}
+	return strings.Join(ss, ", ")
+}
+
+// oneofBody merges the options and the fields in the order of the source.
+// A statement without a position, like the one inserted by astutil, follows the previous one in its list.
+func oneofBody(oneof *parser.Oneof) []parser.Visitee {
+	var options, fields []parser.Visitee
+	for _, option := range oneof.Options {
+		options = append(options, option)
+	}
+	for _, field := range oneof.OneofFields {
+		fields = append(fields, field)
+	}
+	optionOffsets, fieldOffsets := offsets(options), offsets(fields)
+
+	var body []parser.Visitee
+	for 0 < len(options) || 0 < len(fields) {
+		if len(fields) == 0 || (0 < len(options) && optionOffsets[0] <= fieldOffsets[0]) {
+			body = append(body, options[0])
+			options, optionOffsets = options[1:], optionOffsets[1:]
+			continue
+		}
+		body = append(body, fields[0])
+		fields, fieldOffsets = fields[1:], fieldOffsets[1:]
+	}
+	return body
+}
+
+// offsets returns the offsets of the statements. A statement without a position has the offset of the previous one.
+func offsets(stmts []parser.Visitee) []int {
+	var offsets []int
+	prev := 0
+	for _, stmt := range stmts {
+		if o := offset(stmt); 0 < o {
+			prev = o
+		}
+		offsets = append(offsets, prev)
+	}
+	return offsets
+}
+
+func offset(stmt parser.Visitee) int {
+	switch n := stmt.(type) {
+	case *parser.Option:
+		return n.Meta.Pos.Offset
+	case *parser.OneofField:
+		return n.Meta.Pos.Offset
+	default:
+		return 0
+	}
+}
+
+// lines returns the first and the last lines of the statement including its comments.
+// It returns zeros if the statement has no position.
+func lines(stmt parser.Visitee) (int, int) {
+	comments, inlineComment, m, ok := layout(stmt)
+	if !ok {
+		return 0, 0
+	}
+
+	first, last := m.Pos.Line, m.LastPos.Line
+	if 0 < len(comments) {
+		first = comments[0].Meta.Pos.Line
+	}
+	if inlineComment != nil {
+		last = inlineComment.Meta.LastPos.Line
+	}
+	return first, last
+}
+
+// layout returns the comments and the meta of the statement.
+func layout(stmt parser.Visitee) ([]*parser.Comment, *parser.Comment, meta.Meta, bool) {
+	switch n := stmt.(type) {
+	case *parser.Comment:
+		return nil, nil, n.Meta, true
+	case *parser.Syntax:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Edition:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Import:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Package:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Option:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Message:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Enum:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Service:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Extend:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Field:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.MapField:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.GroupField:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Oneof:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.OneofField:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.EnumField:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Reserved:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.Extensions:
+		return n.Comments, n.InlineComment, n.Meta, true
+	case *parser.RPC:
+		return n.Comments, n.InlineComment, n.Meta, true
+	default:
+		return nil, nil, meta.Meta{}, false
+	}
+}
+
+// fieldParts returns the text of the field split before "=".
+func (p *printer) fieldParts(stmt parser.Visitee) (string, string, bool) {
+	switch n := stmt.(type) {
+	case *parser.Field:
+		left := fieldLabel(n.IsRepeated, n.IsRequired, n.IsOptional) + n.Type + " " + n.FieldName
+		return left, "= " + n.FieldNumber + p.fieldOptions(n.FieldOptions) + ";", true
+	case *parser.MapField:
+		left := fmt.Sprintf("map<%s, %s> %s", n.KeyType, n.Type, n.MapName)
+		return left, "= " + n.FieldNumber + p.fieldOptions(n.FieldOptions) + ";", true
+	case *parser.OneofField:
+		left := n.Type + " " + n.FieldName
+		return left, "= " + n.FieldNumber + p.fieldOptions(n.FieldOptions) + ";", true
+	case *parser.EnumField:
+		return n.Ident, "= " + n.Number + p.enumValueOptions(n.EnumValueOptions) + ";", true
+	default:
+		return "", "", false
+	}
+}
+
+const (
+	kindSyntax = iota + 1
+	kindPackage
+	kindImport
+	kindOption
+	kindDefinition
+)
+
+// topLevelKind returns the group of the top-level statement, or 0.
+func topLevelKind(stmt parser.Visitee) int {
+	switch stmt.(type) {
+	case *parser.Syntax, *parser.Edition:
+		return kindSyntax
+	case *parser.Package:
+		return kindPackage
+	case *parser.Import:
+		return kindImport
+	case *parser.Option:
+		return kindOption
+	case *parser.Message, *parser.Enum, *parser.Service, *parser.Extend:
+		return kindDefinition
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,267 @@
+package printer_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/internal/util_test"
+	"github.com/yoheimuta/go-protoparser/v4/lexer"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+	"github.com/yoheimuta/go-protoparser/v4/printer"
+)
+
+func parse(t *testing.T, input string, bodyIncludingComments bool) *parser.Proto {
+	t.Helper()
+	p := parser.NewParser(
+		lexer.NewLexer(strings.NewReader(input)),
+		parser.WithPermissive(true),
+		parser.WithBodyIncludingComments(bodyIncludingComments),
+	)
+	proto, err := p.ParseProto()
+	if err != nil {
+		t.Fatalf("failed to parse: %v\n%s", err, input)
+	}
+	return proto
+}
+
+func TestFprint(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "printing all kinds of statements",
+			input: `// file
+syntax   =   "proto2";  // syntax
+import public "other.proto";
+import "google/protobuf/any.proto";
+package  foo.bar;
+
+option java_package = "com.example.foo";
+option (my_option).a = true;
+message Outer {  // outer
+    option (my_option).a = true;
+    /* nested */
+    message Inner {   // Level 2
+      required int64 ival = 1;
+    }
+    repeated Inner inner_message = 2 [packed=true, (custom) = { a: 1 b: "x" }];
+    map<int32, string> my_map = 4;
+    oneof foo { string name = 4; option (opt) = 1; SubMessage sub_message = 9; }
+    reserved 2, 15, 9 to 11, 40 to max;
+    reserved "foo", "bar";
+    extensions 100 to 199;
+    ;
+    optional group Result = 5 { required string url = 6; }
+}
+enum EnumAllowingAlias {
+  option allow_alias = true;
+  UNKNOWN = 0;
+  RUNNING = 2 [(custom_option) = "hello world"];
+}
+extend Foo { optional int32 bar = 126; }
+service SearchService {
+  rpc Search (SearchRequest) returns (stream SearchResponse);
+  rpc List (stream SearchRequest) returns (SearchResponse) {
+    option (google.api.http) = { get: "/v1" };
+  }
+}
+`,
+			want: `// file
+syntax = "proto2"; // syntax
+import public "other.proto";
+import "google/protobuf/any.proto";
+package foo.bar;
+
+option java_package = "com.example.foo";
+option (my_option).a = true;
+message Outer { // outer
+  option (my_option).a = true;
+  /* nested */
+  message Inner { // Level 2
+    required int64 ival = 1;
+  }
+  repeated Inner inner_message = 2 [packed = true, (custom) = {
+    a: 1
+    b: "x"
+  }];
+  map<int32, string> my_map = 4;
+  oneof foo {
+    string name = 4;
+    option (opt) = 1;
+    SubMessage sub_message = 9;
+  }
+  reserved 2, 15, 9 to 11, 40 to max;
+  reserved "foo", "bar";
+  extensions 100 to 199;
+  ;
+  optional group Result = 5 {
+    required string url = 6;
+  }
+}
+enum EnumAllowingAlias {
+  option allow_alias = true;
+  UNKNOWN = 0;
+  RUNNING = 2 [(custom_option) = "hello world"];
+}
+extend Foo {
+  optional int32 bar = 126;
+}
+service SearchService {
+  rpc Search(SearchRequest) returns (stream SearchResponse);
+  rpc List(stream SearchRequest) returns (SearchResponse) {
+    option (google.api.http) = {
+      get: "/v1"
+    };
+  }
+}
+`,
+		},
+		{
+			name: "printing an editions file with empty bodies",
+			input: `edition = "2023";
+
+message A {}
+service S {
+}
+`,
+			want: `edition = "2023";
+
+message A {}
+service S {}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var got bytes.Buffer
+			err := printer.Fprint(&got, parse(t, test.input, false))
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			if got.String() != test.want {
+				t.Errorf("got\n%s\nbut want\n%s", got.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Fprint(t *testing.T) {
+	proto := parse(t, `syntax = "proto3";
+
+message A {
+  int32 a = 1;
+
+  int32 b = 2;
+}
+`, false)
+
+	var got bytes.Buffer
+	err := (&printer.Config{
+		Indent:          "\t",
+		IgnorePositions: true,
+	}).Fprint(&got, proto)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	want := "syntax = \"proto3\";\nmessage A {\n\tint32 a = 1;\n\tint32 b = 2;\n}\n"
+	if got.String() != want {
+		t.Errorf("got %q, but want %q", got.String(), want)
+	}
+
+	got.Reset()
+	message := proto.ProtoBody[0].(*parser.Message)
+	err = printer.Fprint(&got, message.MessageBody[1])
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	want = "int32 b = 2;\n"
+	if got.String() != want {
+		t.Errorf("got %q, but want %q", got.String(), want)
+	}
+}
+
+func TestFprint_roundTrip(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "_testdata", "*.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no testdata")
+	}
+
+	for _, file := range files {
+		file := file
+		for _, bodyIncludingComments := range []bool{false, true} {
+			bodyIncludingComments := bodyIncludingComments
+			name := fmt.Sprintf("%s with bodyIncludingComments=%v", filepath.Base(file), bodyIncludingComments)
+			t.Run(name, func(t *testing.T) {
+				src, err := ioutil.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := parse(t, string(src), bodyIncludingComments)
+
+				var printed bytes.Buffer
+				err = printer.Fprint(&printed, want)
+				if err != nil {
+					t.Fatalf("got err %v, but want nil", err)
+				}
+				got := parse(t, printed.String(), bodyIncludingComments)
+
+				var reprinted bytes.Buffer
+				err = printer.Fprint(&reprinted, got)
+				if err != nil {
+					t.Fatalf("got err %v, but want nil", err)
+				}
+				if reprinted.String() != printed.String() {
+					t.Errorf("got\n%s\nbut want\n%s", reprinted.String(), printed.String())
+				}
+
+				clearMeta(reflect.ValueOf(want))
+				clearMeta(reflect.ValueOf(got))
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("got %v, but want %v", util_test.PrettyFormat(got), util_test.PrettyFormat(want))
+				}
+			})
+		}
+	}
+}
+
+var (
+	metaType      = reflect.TypeOf(meta.Meta{})
+	protoMetaType = reflect.TypeOf(&parser.ProtoMeta{})
+)
+
+// clearMeta zeroes all of the positions which are different between the source and the printed one.
+func clearMeta(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			clearMeta(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			clearMeta(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			switch f.Type() {
+			case metaType, protoMetaType:
+				f.Set(reflect.Zero(f.Type()))
+			default:
+				clearMeta(f)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package protoparser
 
 import (
+	"context"
 	"io"
 
 	"github.com/yoheimuta/go-protoparser/v4/interpret/unordered"
@@ -13,7 +14,15 @@ type ParseConfig struct {
 	debug                 bool
 	permissive            bool
 	bodyIncludingComments bool
+	errorRecovery         bool
+	detailedPositions     bool
 	filename              string
+	concurrency           int
+	maxNestingDepth       int
+	maxInputBytes         int
+	maxTokens             int
+	protocComments        bool
+	ctx                   context.Context
 }
 
 // Option is an option for ParseConfig.
@@ -41,6 +50,22 @@ func WithBodyIncludingComments(bodyIncludingComments bool) Option {
 	}
 }
 
+// WithErrorRecovery is an option to continue parsing after syntax errors.
+// Parse returns the partial Proto along with a meta.ErrorList which holds all of the errors.
+func WithErrorRecovery(errorRecovery bool) Option {
+	return func(c *ParseConfig) {
+		c.errorRecovery = errorRecovery
+	}
+}
+
+// WithDetailedPositions is an option to record the positions of the names, the types and the numbers of the elements.
+// See parser.WithDetailedPositions.
+func WithDetailedPositions(detailedPositions bool) Option {
+	return func(c *ParseConfig) {
+		c.detailedPositions = detailedPositions
+	}
+}
+
 // WithFilename is an option to set filename to the Position.
 func WithFilename(filename string) Option {
 	return func(c *ParseConfig) {
@@ -48,23 +73,84 @@ func WithFilename(filename string) Option {
 	}
 }
 
-// Parse parses a Protocol Buffer file.
-func Parse(input io.Reader, options ...Option) (*parser.Proto, error) {
+// WithConcurrency is an option to set the number of the files which ParseFiles parses at once.
+// The default is runtime.GOMAXPROCS(0).
+func WithConcurrency(concurrency int) Option {
+	return func(c *ParseConfig) {
+		c.concurrency = concurrency
+	}
+}
+
+// WithMaxNestingDepth is an option to limit the depth of the nested elements and option values.
+// Parse returns a *meta.LimitError if the input exceeds it. See parser.WithMaxNestingDepth.
+func WithMaxNestingDepth(max int) Option {
+	return func(c *ParseConfig) {
+		c.maxNestingDepth = max
+	}
+}
+
+// WithMaxInputBytes is an option to limit the size of the input.
+// Parse returns a *meta.LimitError if the input exceeds it. See lexer.WithMaxInputBytes.
+func WithMaxInputBytes(max int) Option {
+	return func(c *ParseConfig) {
+		c.maxInputBytes = max
+	}
+}
+
+// WithMaxTokens is an option to limit the number of the tokens in the input.
+// Parse returns a *meta.LimitError if the input exceeds it. See lexer.WithMaxTokens.
+func WithMaxTokens(max int) Option {
+	return func(c *ParseConfig) {
+		c.maxTokens = max
+	}
+}
+
+// WithContext is an option to stop parsing when the ctx is done. Parse returns the ctx.Err() then.
+func WithContext(ctx context.Context) Option {
+	return func(c *ParseConfig) {
+		c.ctx = ctx
+	}
+}
+
+// WithProtocComments is an option to attach the leading, the trailing and the detached comments to each element
+// as protoc does. See parser.WithProtocComments.
+func WithProtocComments(protocComments bool) Option {
+	return func(c *ParseConfig) {
+		c.protocComments = protocComments
+	}
+}
+
+func newParseConfig(options []Option) *ParseConfig {
 	config := &ParseConfig{
 		permissive: true,
 	}
 	for _, opt := range options {
 		opt(config)
 	}
+	return config
+}
+
+// Parse parses a Protocol Buffer file.
+func Parse(input io.Reader, options ...Option) (*parser.Proto, error) {
+	return parse(input, newParseConfig(options))
+}
 
+func parse(input io.Reader, config *ParseConfig) (*parser.Proto, error) {
 	p := parser.NewParser(
 		lexer.NewLexer(
 			input,
 			lexer.WithDebug(config.debug),
 			lexer.WithFilename(config.filename),
+			lexer.WithMaxInputBytes(config.maxInputBytes),
+			lexer.WithMaxTokens(config.maxTokens),
+			lexer.WithContext(config.ctx),
 		),
 		parser.WithPermissive(config.permissive),
 		parser.WithBodyIncludingComments(config.bodyIncludingComments),
+		parser.WithErrorRecovery(config.errorRecovery),
+		parser.WithDetailedPositions(config.detailedPositions),
+		parser.WithMaxNestingDepth(config.maxNestingDepth),
+		parser.WithProtocComments(config.protocComments),
 	)
 	return p.ParseProto()
 }
@@ -0,0 +1,81 @@
+// Package token splits a .proto source into the tokens, for the tools like the syntax highlighters,
+// the code search indexers and the small parsers of their own.
+package token
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Kind is a kind of the Token.
+type Kind int
+
+// Kind values.
+const (
+	// KindIllegal means the text which is not a token, like an unterminated string literal.
+	KindIllegal Kind = iota
+	// KindEOF means the end of the input.
+	KindEOF
+	// KindKeyword means a keyword, like message or rpc.
+	KindKeyword
+	// KindIdent means an identifier.
+	KindIdent
+	// KindIntLit means an integer literal, whose Value is an uint64.
+	KindIntLit
+	// KindFloatLit means a floating-point literal including inf and nan, whose Value is a float64.
+	KindFloatLit
+	// KindBoolLit means true or false, whose Value is a bool.
+	KindBoolLit
+	// KindStrLit means a string literal, whose Value is the decoded string.
+	KindStrLit
+	// KindComment means a comment, whose Value is the text without the delimiters, like // and /* */.
+	KindComment
+	// KindPunctuation means a punctuation, like ; and {.
+	KindPunctuation
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindIllegal:
+		return "illegal"
+	case KindEOF:
+		return "EOF"
+	case KindKeyword:
+		return "keyword"
+	case KindIdent:
+		return "ident"
+	case KindIntLit:
+		return "intLit"
+	case KindFloatLit:
+		return "floatLit"
+	case KindBoolLit:
+		return "boolLit"
+	case KindStrLit:
+		return "strLit"
+	case KindComment:
+		return "comment"
+	case KindPunctuation:
+		return "punctuation"
+	default:
+		return "unknown"
+	}
+}
+
+// Token is a token of the source.
+//
+// The Kind is decided without the context, so a keyword used as a name, like message in `string message = 1;`,
+// is a keyword, and a minus sign is a punctuation apart from the number.
+type Token struct {
+	Kind Kind
+	// Token is the scanner token, which tells the keyword or the punctuation, like scanner.TMESSAGE.
+	Token scanner.Token
+	// Raw is the source text of the token.
+	Raw string
+	// Value is the decoded value of a literal or a comment. It's the Raw for the other kinds.
+	Value interface{}
+
+	// Pos is the position of the first character.
+	Pos meta.Position
+	// End is the position right after the last character.
+	End meta.Position
+}
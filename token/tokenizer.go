@@ -0,0 +1,197 @@
+package token
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yoheimuta/go-protoparser/v4/internal/strlit"
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Tokenizer reads the tokens one by one.
+type Tokenizer struct {
+	src      []byte
+	scanner  *scanner.Scanner
+	filename string
+	err      error
+}
+
+// Option is an option for NewTokenizer and Tokenize.
+type Option func(*Tokenizer)
+
+// WithFilename is an option to set filename to the positions.
+func WithFilename(filename string) Option {
+	return func(t *Tokenizer) {
+		t.filename = filename
+	}
+}
+
+// NewTokenizer creates a new Tokenizer, which reads the whole input at first.
+func NewTokenizer(input io.Reader, opts ...Option) *Tokenizer {
+	t := &Tokenizer{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.src, t.err = ioutil.ReadAll(input)
+	t.scanner = scanner.NewScanner(bytes.NewReader(t.src), scanner.WithFilename(t.filename))
+	t.scanner.Mode = scanner.ScanIdent | scanner.ScanLit | scanner.ScanKeyword | scanner.ScanComment
+	return t
+}
+
+// Next returns the next token, or the token of KindEOF at the end of the input.
+//
+// It returns a *meta.Error along with the token which is illegal or whose Value can't be decoded, which is nil then,
+// and the next call continues after it. It returns the error of the input reader at first, if any.
+func (t *Tokenizer) Next() (Token, error) {
+	if err := t.err; err != nil {
+		t.err = nil
+		return Token{Kind: KindEOF, Token: scanner.TEOF}, err
+	}
+
+	mark := t.scanner.Mark()
+	tok, text, pos, err := t.scanner.Scan()
+	if err != nil && t.isDot(pos.Offset) {
+		// a dot not followed by a digit is a punctuation, like the one of a full identifier.
+		t.scanner.Rewind(mark)
+		t.scanner.Mode &^= scanner.ScanNumberLit
+		tok, text, pos, err = t.scanner.Scan()
+		t.scanner.Mode |= scanner.ScanNumberLit
+	}
+	end := pos.Offset + len(text)
+	if text == "" {
+		// an illegal token has no text.
+		end = t.scanner.Mark().Position().Offset
+	}
+	raw := string(t.src[pos.Offset:end])
+
+	token := Token{
+		Kind:  kindOf(tok),
+		Token: tok,
+		Raw:   raw,
+		Value: raw,
+		Pos:   pos.Position,
+		End:   advance(pos.Position, raw),
+	}
+	switch {
+	case err != nil:
+		return token, err
+	case token.Kind == KindIllegal:
+		return token, &meta.Error{Pos: token.Pos, Expected: "token", Found: raw}
+	}
+
+	token.Value, err = decode(token.Kind, raw)
+	if err != nil {
+		token.Value = nil
+		return token, &meta.Error{Pos: token.Pos, Expected: "valid " + token.Kind.String(), Found: raw}
+	}
+	return token, nil
+}
+
+// isDot checks whether the character at the offset is a dot which is not followed by a decimal digit.
+func (t *Tokenizer) isDot(offset int) bool {
+	if len(t.src) <= offset || t.src[offset] != '.' {
+		return false
+	}
+	return len(t.src) <= offset+1 || t.src[offset+1] < '0' || '9' < t.src[offset+1]
+}
+
+// Tokenize returns all of the tokens of the input, without the one of KindEOF.
+// It keeps on reading after an error, and returns the tokens along with a meta.ErrorList of all the errors.
+func Tokenize(input io.Reader, opts ...Option) ([]Token, error) {
+	t := NewTokenizer(input, opts...)
+
+	var tokens []Token
+	var errs meta.ErrorList
+	for {
+		token, err := t.Next()
+		if err != nil {
+			metaErr, ok := err.(*meta.Error)
+			if !ok {
+				return tokens, err
+			}
+			errs = append(errs, metaErr)
+		}
+		if token.Kind == KindEOF {
+			break
+		}
+		tokens = append(tokens, token)
+	}
+	if 0 < len(errs) {
+		return tokens, errs
+	}
+	return tokens, nil
+}
+
+func kindOf(tok scanner.Token) Kind {
+	switch {
+	case tok == scanner.TEOF:
+		return KindEOF
+	case tok == scanner.TIDENT:
+		return KindIdent
+	case tok == scanner.TINTLIT:
+		return KindIntLit
+	case tok == scanner.TFLOATLIT:
+		return KindFloatLit
+	case tok == scanner.TBOOLLIT:
+		return KindBoolLit
+	case tok == scanner.TSTRLIT:
+		return KindStrLit
+	case tok == scanner.TCOMMENT:
+		return KindComment
+	// the misc characters are listed from TSEMICOLON to TMINUS, and the keywords from TSYNTAX to TEDITION.
+	case scanner.TSEMICOLON <= tok && tok <= scanner.TMINUS:
+		return KindPunctuation
+	case scanner.TSYNTAX <= tok && tok <= scanner.TEDITION:
+		return KindKeyword
+	default:
+		return KindIllegal
+	}
+}
+
+// decode returns the Value of the token.
+func decode(kind Kind, raw string) (interface{}, error) {
+	switch kind {
+	case KindIntLit:
+		return strconv.ParseUint(raw, 0, 64)
+	case KindFloatLit:
+		v, err := strconv.ParseFloat(raw, 64)
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			// protoc regards the value out of range as the infinity or zero.
+			return v, nil
+		}
+		return v, err
+	case KindBoolLit:
+		return raw == "true", nil
+	case KindStrLit:
+		b, err := strlit.Unquote(raw)
+		return string(b), err
+	case KindComment:
+		if strings.HasPrefix(raw, "/*") {
+			return raw[2 : len(raw)-2], nil
+		}
+		return raw[2:], nil
+	default:
+		return raw, nil
+	}
+}
+
+// advance returns the position right after the text which starts at the pos.
+func advance(pos meta.Position, text string) meta.Position {
+	for 0 < len(text) {
+		r, size := utf8.DecodeRuneInString(text)
+		pos.Offset += size
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+		text = text[size:]
+	}
+	return pos
+}
@@ -0,0 +1,297 @@
+package token_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/lexer/scanner"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+	"github.com/yoheimuta/go-protoparser/v4/token"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantTokens []token.Token
+	}{
+		{
+			name:  "a field with a full identifier",
+			input: "optional .a.B b = 1;",
+			wantTokens: []token.Token{
+				{
+					Kind:  token.KindKeyword,
+					Token: scanner.TOPTIONAL,
+					Raw:   "optional",
+					Value: "optional",
+					Pos:   meta.Position{Offset: 0, Line: 1, Column: 1},
+					End:   meta.Position{Offset: 8, Line: 1, Column: 9},
+				},
+				{
+					Kind:  token.KindPunctuation,
+					Token: scanner.TDOT,
+					Raw:   ".",
+					Value: ".",
+					Pos:   meta.Position{Offset: 9, Line: 1, Column: 10},
+					End:   meta.Position{Offset: 10, Line: 1, Column: 11},
+				},
+				{
+					Kind:  token.KindIdent,
+					Token: scanner.TIDENT,
+					Raw:   "a",
+					Value: "a",
+					Pos:   meta.Position{Offset: 10, Line: 1, Column: 11},
+					End:   meta.Position{Offset: 11, Line: 1, Column: 12},
+				},
+				{
+					Kind:  token.KindPunctuation,
+					Token: scanner.TDOT,
+					Raw:   ".",
+					Value: ".",
+					Pos:   meta.Position{Offset: 11, Line: 1, Column: 12},
+					End:   meta.Position{Offset: 12, Line: 1, Column: 13},
+				},
+				{
+					Kind:  token.KindIdent,
+					Token: scanner.TIDENT,
+					Raw:   "B",
+					Value: "B",
+					Pos:   meta.Position{Offset: 12, Line: 1, Column: 13},
+					End:   meta.Position{Offset: 13, Line: 1, Column: 14},
+				},
+				{
+					Kind:  token.KindIdent,
+					Token: scanner.TIDENT,
+					Raw:   "b",
+					Value: "b",
+					Pos:   meta.Position{Offset: 14, Line: 1, Column: 15},
+					End:   meta.Position{Offset: 15, Line: 1, Column: 16},
+				},
+				{
+					Kind:  token.KindPunctuation,
+					Token: scanner.TEQUALS,
+					Raw:   "=",
+					Value: "=",
+					Pos:   meta.Position{Offset: 16, Line: 1, Column: 17},
+					End:   meta.Position{Offset: 17, Line: 1, Column: 18},
+				},
+				{
+					Kind:  token.KindIntLit,
+					Token: scanner.TINTLIT,
+					Raw:   "1",
+					Value: uint64(1),
+					Pos:   meta.Position{Offset: 18, Line: 1, Column: 19},
+					End:   meta.Position{Offset: 19, Line: 1, Column: 20},
+				},
+				{
+					Kind:  token.KindPunctuation,
+					Token: scanner.TSEMICOLON,
+					Raw:   ";",
+					Value: ";",
+					Pos:   meta.Position{Offset: 19, Line: 1, Column: 20},
+					End:   meta.Position{Offset: 20, Line: 1, Column: 21},
+				},
+			},
+		},
+		{
+			name:  "literals",
+			input: `0x1F 017 1.5e2 .5 true 'a\x41\n' "é"`,
+			wantTokens: []token.Token{
+				{
+					Kind:  token.KindIntLit,
+					Token: scanner.TINTLIT,
+					Raw:   "0x1F",
+					Value: uint64(31),
+					Pos:   meta.Position{Offset: 0, Line: 1, Column: 1},
+					End:   meta.Position{Offset: 4, Line: 1, Column: 5},
+				},
+				{
+					Kind:  token.KindIntLit,
+					Token: scanner.TINTLIT,
+					Raw:   "017",
+					Value: uint64(15),
+					Pos:   meta.Position{Offset: 5, Line: 1, Column: 6},
+					End:   meta.Position{Offset: 8, Line: 1, Column: 9},
+				},
+				{
+					Kind:  token.KindFloatLit,
+					Token: scanner.TFLOATLIT,
+					Raw:   "1.5e2",
+					Value: float64(150),
+					Pos:   meta.Position{Offset: 9, Line: 1, Column: 10},
+					End:   meta.Position{Offset: 14, Line: 1, Column: 15},
+				},
+				{
+					Kind:  token.KindFloatLit,
+					Token: scanner.TFLOATLIT,
+					Raw:   ".5",
+					Value: float64(0.5),
+					Pos:   meta.Position{Offset: 15, Line: 1, Column: 16},
+					End:   meta.Position{Offset: 17, Line: 1, Column: 18},
+				},
+				{
+					Kind:  token.KindBoolLit,
+					Token: scanner.TBOOLLIT,
+					Raw:   "true",
+					Value: true,
+					Pos:   meta.Position{Offset: 18, Line: 1, Column: 19},
+					End:   meta.Position{Offset: 22, Line: 1, Column: 23},
+				},
+				{
+					Kind:  token.KindStrLit,
+					Token: scanner.TSTRLIT,
+					Raw:   `'a\x41\n'`,
+					Value: "aA\n",
+					Pos:   meta.Position{Offset: 23, Line: 1, Column: 24},
+					End:   meta.Position{Offset: 32, Line: 1, Column: 33},
+				},
+				{
+					Kind:  token.KindStrLit,
+					Token: scanner.TSTRLIT,
+					Raw:   `"é"`,
+					Value: "é",
+					Pos:   meta.Position{Offset: 33, Line: 1, Column: 34},
+					End:   meta.Position{Offset: 37, Line: 1, Column: 37},
+				},
+			},
+		},
+		{
+			name:  "comments",
+			input: "// a\n/* b\n c */",
+			wantTokens: []token.Token{
+				{
+					Kind:  token.KindComment,
+					Token: scanner.TCOMMENT,
+					Raw:   "// a",
+					Value: " a",
+					Pos:   meta.Position{Offset: 0, Line: 1, Column: 1},
+					End:   meta.Position{Offset: 4, Line: 1, Column: 5},
+				},
+				{
+					Kind:  token.KindComment,
+					Token: scanner.TCOMMENT,
+					Raw:   "/* b\n c */",
+					Value: " b\n c ",
+					Pos:   meta.Position{Offset: 5, Line: 2, Column: 1},
+					End:   meta.Position{Offset: 15, Line: 3, Column: 6},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := token.Tokenize(strings.NewReader(test.input))
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+			}
+			if !reflect.DeepEqual(got, test.wantTokens) {
+				t.Errorf("got %v, but want %v", got, test.wantTokens)
+			}
+		})
+	}
+}
+
+func TestTokenize_keywords(t *testing.T) {
+	keywords := []string{
+		"syntax", "edition", "service", "rpc", "returns", "message", "extend", "import", "package", "option", "repeated",
+		"required", "optional", "weak", "public", "oneof", "map", "reserved", "extensions", "enum", "stream", "group",
+	}
+
+	got, err := token.Tokenize(strings.NewReader(strings.Join(keywords, " ")))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	if len(got) != len(keywords) {
+		t.Fatalf("got %d tokens, but want %d", len(got), len(keywords))
+	}
+	seen := make(map[scanner.Token]bool)
+	for i, tok := range got {
+		if tok.Kind != token.KindKeyword || tok.Raw != keywords[i] || seen[tok.Token] {
+			t.Errorf("got %v, but want the keyword %s", tok, keywords[i])
+		}
+		seen[tok.Token] = true
+	}
+}
+
+func TestTokenize_inf(t *testing.T) {
+	got, err := token.Tokenize(strings.NewReader("inf nan 1e999"))
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %v, but want 3 tokens", got)
+	}
+	if v := got[0].Value.(float64); !math.IsInf(v, 1) {
+		t.Errorf("got %v, but want +Inf", v)
+	}
+	if v := got[1].Value.(float64); !math.IsNaN(v) {
+		t.Errorf("got %v, but want NaN", v)
+	}
+	if v := got[2].Value.(float64); !math.IsInf(v, 1) {
+		t.Errorf("got %v, but want +Inf", v)
+	}
+}
+
+func TestTokenize_errors(t *testing.T) {
+	got, err := token.Tokenize(strings.NewReader("a @ \"b\nc '\\q' 99999999999999999999"))
+
+	var gotKinds []token.Kind
+	for _, tok := range got {
+		gotKinds = append(gotKinds, tok.Kind)
+	}
+	wantKinds := []token.Kind{
+		token.KindIdent,
+		token.KindIllegal,
+		token.KindIllegal,
+		token.KindIdent,
+		token.KindStrLit,
+		token.KindIntLit,
+	}
+	if !reflect.DeepEqual(gotKinds, wantKinds) {
+		t.Errorf("got %v, but want %v", gotKinds, wantKinds)
+	}
+	if got[2].Raw != `"b` {
+		t.Errorf("got %s, but want \"b", got[2].Raw)
+	}
+	if got[4].Value != nil || got[5].Value != nil {
+		t.Errorf("got %v and %v, but want nil", got[4].Value, got[5].Value)
+	}
+
+	var list meta.ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("got err %v, but want meta.ErrorList", err)
+	}
+	var gotPos []meta.Position
+	for _, e := range list {
+		gotPos = append(gotPos, e.Pos)
+	}
+	wantPos := []meta.Position{
+		{Offset: 2, Line: 1, Column: 3},
+		{Offset: 6, Line: 1, Column: 7},
+		{Offset: 9, Line: 2, Column: 3},
+		{Offset: 14, Line: 2, Column: 8},
+	}
+	if !reflect.DeepEqual(gotPos, wantPos) {
+		t.Errorf("got %v, but want %v", gotPos, wantPos)
+	}
+}
+
+func TestTokenizer_Next(t *testing.T) {
+	tokenizer := token.NewTokenizer(strings.NewReader("a"), token.WithFilename("a.proto"))
+
+	got, err := tokenizer.Next()
+	if err != nil || got.Raw != "a" || got.Pos.Filename != "a.proto" {
+		t.Errorf("got %v and err %v, but want a in a.proto", got, err)
+	}
+	for i := 0; i < 2; i++ {
+		got, err = tokenizer.Next()
+		if err != nil || got.Kind != token.KindEOF {
+			t.Errorf("got %v and err %v, but want EOF", got, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Kind is a kind of the Diagnostic.
+type Kind int
+
+// Kind values.
+const (
+	// KindDuplicateFieldNumber means the field number is already used in the message.
+	KindDuplicateFieldNumber Kind = iota
+	// KindInvalidFieldNumber means the field number is out of 1 to 536870911.
+	KindInvalidFieldNumber
+	// KindImplementationReservedNumber means the field number is in 19000 to 19999.
+	KindImplementationReservedNumber
+	// KindReservedNumber means the number is in a reserved range.
+	KindReservedNumber
+	// KindReservedName means the name is reserved.
+	KindReservedName
+	// KindExtensionRangeNumber means the field number is in an extension range.
+	KindExtensionRangeNumber
+	// KindDuplicateName means the name is already defined in the scope.
+	KindDuplicateName
+	// KindDuplicateEnumNumber means the enum value number is already used without allow_alias.
+	KindDuplicateEnumNumber
+	// KindFirstEnumValueNotZero means the first value of a proto3 enum is not zero.
+	KindFirstEnumValueNotZero
+	// KindNotAllowedInProto3 means the statement is not allowed in proto3, like a required field.
+	KindNotAllowedInProto3
+)
+
+// Diagnostic is a violation found by the validation.
+type Diagnostic struct {
+	Pos     meta.Position
+	Kind    Kind
+	Message string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
@@ -0,0 +1,325 @@
+// Package validate checks the semantic rules of proto2 and proto3 which the parser doesn't check, as protoc does.
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+const (
+	// maxFieldNumber is the largest field number.
+	maxFieldNumber = 536870911
+	// maxEnumNumber is the largest enum value number.
+	maxEnumNumber = 2147483647
+	// firstReservedNumber and lastReservedNumber are the field numbers reserved for the protobuf implementation.
+	firstReservedNumber = 19000
+	lastReservedNumber  = 19999
+)
+
+// Proto validates the proto and returns the diagnostics in the order of the statements.
+func Proto(proto *parser.Proto) []*Diagnostic {
+	v := &validator{
+		proto3: proto.Syntax != nil && proto.Syntax.ProtobufVersion == "proto3",
+	}
+	v.body(make(scope), proto.ProtoBody)
+	sort.SliceStable(v.diagnostics, func(i, j int) bool {
+		return v.diagnostics[i].Pos.Offset < v.diagnostics[j].Pos.Offset
+	})
+	return v.diagnostics
+}
+
+type validator struct {
+	proto3      bool
+	diagnostics []*Diagnostic
+}
+
+func (v *validator) report(pos meta.Position, kind Kind, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, &Diagnostic{
+		Pos:     pos,
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// scope holds the positions of the names defined in a scope.
+type scope map[string]meta.Position
+
+// declare adds the name to the scope. It reports the duplicate when the name is already defined.
+func (v *validator) declare(s scope, name string, pos meta.Position) {
+	if defined, ok := s[name]; ok {
+		v.report(pos, KindDuplicateName, "%q is already defined at %s", name, defined)
+		return
+	}
+	s[name] = pos
+}
+
+// body validates the top-level definitions.
+func (v *validator) body(s scope, body []parser.Visitee) {
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *parser.Message:
+			v.declare(s, n.MessageName, n.Meta.Pos)
+			v.message(n.MessageBody)
+		case *parser.Enum:
+			v.declare(s, n.EnumName, n.Meta.Pos)
+			v.enum(s, n)
+		case *parser.Service:
+			v.declare(s, n.ServiceName, n.Meta.Pos)
+		case *parser.Extend:
+			v.extend(s, n)
+		}
+	}
+}
+
+// extend validates the extension fields. Their names are declared in the scope which encloses the extend.
+// Their numbers are not checked against the extended message, which may be defined in another file.
+func (v *validator) extend(s scope, extend *parser.Extend) {
+	for _, stmt := range extend.ExtendBody {
+		switch n := stmt.(type) {
+		case *parser.Field:
+			v.label(n.IsRequired, n.Meta.Pos)
+			v.declare(s, n.FieldName, n.Meta.Pos)
+			v.fieldNumber(n.FieldNumber, n.Meta.Pos)
+		case *parser.GroupField:
+			v.group(n)
+			v.declare(s, strings.ToLower(n.GroupName), n.Meta.Pos)
+			v.declare(s, n.GroupName, n.Meta.Pos)
+			v.fieldNumber(n.FieldNumber, n.Meta.Pos)
+			v.message(n.MessageBody)
+		}
+	}
+}
+
+// numbered is a field or an enum value.
+type numbered struct {
+	name   string
+	number string
+	pos    meta.Position
+}
+
+// message validates the message body.
+func (v *validator) message(body []parser.Visitee) {
+	var reserved, extensions []numberRange
+	reservedNames := make(map[string]bool)
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *parser.Reserved:
+			reserved = append(reserved, ranges(n.Ranges, maxFieldNumber)...)
+			for _, name := range n.FieldNames {
+				reservedNames[strings.Trim(name, `"'`)] = true
+			}
+		case *parser.Extensions:
+			if v.proto3 {
+				v.report(n.Meta.Pos, KindNotAllowedInProto3, "extension ranges are not allowed in proto3")
+			}
+			extensions = append(extensions, ranges(n.Ranges, maxFieldNumber)...)
+		}
+	}
+
+	s := make(scope)
+	var fields []numbered
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *parser.Field:
+			v.label(n.IsRequired, n.Meta.Pos)
+			if v.proto3 {
+				for _, option := range n.FieldOptions {
+					if option.OptionName == "default" {
+						v.report(n.Meta.Pos, KindNotAllowedInProto3, "explicit default values are not allowed in proto3")
+					}
+				}
+			}
+			v.declare(s, n.FieldName, n.Meta.Pos)
+			fields = append(fields, numbered{name: n.FieldName, number: n.FieldNumber, pos: n.Meta.Pos})
+		case *parser.MapField:
+			v.declare(s, n.MapName, n.Meta.Pos)
+			fields = append(fields, numbered{name: n.MapName, number: n.FieldNumber, pos: n.Meta.Pos})
+		case *parser.GroupField:
+			v.group(n)
+			name := strings.ToLower(n.GroupName)
+			v.declare(s, name, n.Meta.Pos)
+			v.declare(s, n.GroupName, n.Meta.Pos)
+			fields = append(fields, numbered{name: name, number: n.FieldNumber, pos: n.Meta.Pos})
+			v.message(n.MessageBody)
+		case *parser.Oneof:
+			v.declare(s, n.OneofName, n.Meta.Pos)
+			for _, field := range n.OneofFields {
+				v.declare(s, field.FieldName, field.Meta.Pos)
+				fields = append(fields, numbered{name: field.FieldName, number: field.FieldNumber, pos: field.Meta.Pos})
+			}
+		case *parser.Message:
+			v.declare(s, n.MessageName, n.Meta.Pos)
+			v.message(n.MessageBody)
+		case *parser.Enum:
+			v.declare(s, n.EnumName, n.Meta.Pos)
+			v.enum(s, n)
+		case *parser.Extend:
+			v.extend(s, n)
+		}
+	}
+
+	used := make(map[int64]numbered)
+	for _, field := range fields {
+		number, ok := v.fieldNumber(field.number, field.pos)
+		if reservedNames[field.name] {
+			v.report(field.pos, KindReservedName, "field name %q is reserved", field.name)
+		}
+		if !ok {
+			continue
+		}
+		if r, ok := contains(reserved, number); ok {
+			v.report(field.pos, KindReservedNumber, "field %q uses the reserved number %d (%s)", field.name, number, r)
+		}
+		if r, ok := contains(extensions, number); ok {
+			v.report(field.pos, KindExtensionRangeNumber, "field %q uses the number %d in the extension range %s", field.name, number, r)
+		}
+		if prev, ok := used[number]; ok {
+			v.report(field.pos, KindDuplicateFieldNumber, "field number %d is already used by %q at %s", number, prev.name, prev.pos)
+			continue
+		}
+		used[number] = field
+	}
+}
+
+// label reports the required label in proto3.
+func (v *validator) label(isRequired bool, pos meta.Position) {
+	if v.proto3 && isRequired {
+		v.report(pos, KindNotAllowedInProto3, "required fields are not allowed in proto3")
+	}
+}
+
+// group reports the group in proto3.
+func (v *validator) group(group *parser.GroupField) {
+	if v.proto3 {
+		v.report(group.Meta.Pos, KindNotAllowedInProto3, "groups are not allowed in proto3")
+	}
+}
+
+// fieldNumber validates the range of the field number, and returns the parsed number.
+func (v *validator) fieldNumber(text string, pos meta.Position) (int64, bool) {
+	number, err := strconv.ParseInt(text, 0, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case number < 1 || maxFieldNumber < number:
+		v.report(pos, KindInvalidFieldNumber, "field number %d must be in 1 to %d", number, maxFieldNumber)
+		return number, false
+	case firstReservedNumber <= number && number <= lastReservedNumber:
+		v.report(
+			pos,
+			KindImplementationReservedNumber,
+			"field number %d is reserved for the protocol buffer library implementation (%d to %d)",
+			number, firstReservedNumber, lastReservedNumber,
+		)
+	}
+	return number, true
+}
+
+// enum validates the enum. Its value names are declared in the enclosing scope s, as protoc does.
+func (v *validator) enum(s scope, enum *parser.Enum) {
+	var reserved []numberRange
+	reservedNames := make(map[string]bool)
+	allowAlias := false
+	for _, stmt := range enum.EnumBody {
+		switch n := stmt.(type) {
+		case *parser.Reserved:
+			reserved = append(reserved, ranges(n.Ranges, maxEnumNumber)...)
+			for _, name := range n.FieldNames {
+				reservedNames[strings.Trim(name, `"'`)] = true
+			}
+		case *parser.Option:
+			if n.OptionName == "allow_alias" && n.Constant == "true" {
+				allowAlias = true
+			}
+		}
+	}
+
+	first := true
+	used := make(map[int64]*parser.EnumField)
+	for _, stmt := range enum.EnumBody {
+		field, ok := stmt.(*parser.EnumField)
+		if !ok {
+			continue
+		}
+		v.declare(s, field.Ident, field.Meta.Pos)
+		if reservedNames[field.Ident] {
+			v.report(field.Meta.Pos, KindReservedName, "enum value name %q is reserved", field.Ident)
+		}
+
+		number, err := strconv.ParseInt(field.Number, 0, 64)
+		if err != nil {
+			first = false
+			continue
+		}
+		if first && v.proto3 && number != 0 {
+			v.report(field.Meta.Pos, KindFirstEnumValueNotZero, "the first enum value %q must be zero in proto3", field.Ident)
+		}
+		first = false
+		if r, ok := contains(reserved, number); ok {
+			v.report(field.Meta.Pos, KindReservedNumber, "enum value %q uses the reserved number %d (%s)", field.Ident, number, r)
+		}
+		if prev, ok := used[number]; ok && !allowAlias {
+			v.report(
+				field.Meta.Pos,
+				KindDuplicateEnumNumber,
+				"enum value number %d is already used by %q at %s. Set allow_alias to true to use aliases",
+				number, prev.Ident, prev.Meta.Pos,
+			)
+			continue
+		}
+		used[number] = field
+	}
+}
+
+// numberRange is an inclusive range of numbers.
+type numberRange struct {
+	begin int64
+	end   int64
+}
+
+func (r numberRange) String() string {
+	if r.begin == r.end {
+		return strconv.FormatInt(r.begin, 10)
+	}
+	return fmt.Sprintf("%d to %d", r.begin, r.end)
+}
+
+// ranges converts the ranges. The max is used for the end "max".
+func ranges(src []*parser.Range, max int64) []numberRange {
+	var rs []numberRange
+	for _, r := range src {
+		begin, err := strconv.ParseInt(r.Begin, 0, 64)
+		if err != nil {
+			continue
+		}
+		end := begin
+		switch r.End {
+		case "":
+		case "max":
+			end = max
+		default:
+			end, err = strconv.ParseInt(r.End, 0, 64)
+			if err != nil {
+				continue
+			}
+		}
+		rs = append(rs, numberRange{begin: begin, end: end})
+	}
+	return rs
+}
+
+// contains returns the range which contains the number.
+func contains(rs []numberRange, number int64) (numberRange, bool) {
+	for _, r := range rs {
+		if r.begin <= number && number <= r.end {
+			return r, true
+		}
+	}
+	return numberRange{}, false
+}
@@ -0,0 +1,172 @@
+package validate_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/validate"
+)
+
+func TestProto(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantKinds []validate.Kind
+		wantLines []int
+	}{
+		{
+			name: "validating a valid proto3 file",
+			input: `syntax = "proto3";
+message A {
+  reserved 2, 15 to 17;
+  reserved "b";
+  int32 a = 1;
+  map<string, A> m = 3;
+  oneof o {
+    string c = 4;
+  }
+  message B {
+    int32 a = 1;
+  }
+  enum E {
+    option allow_alias = true;
+    E_UNSPECIFIED = 0;
+    E_DEFAULT = 0;
+  }
+}
+`,
+		},
+		{
+			name: "reporting the field numbers",
+			input: `syntax = "proto2";
+message A {
+  reserved 10 to max;
+  extensions 5 to 9;
+  optional int32 a = 1;
+  optional int32 b = 1;
+  optional int32 c = 0;
+  optional int32 d = 19000;
+  optional int32 e = 12;
+  optional int32 f = 6;
+  oneof o {
+    string g = 2;
+  }
+  optional group H = 2 {}
+}
+`,
+			wantKinds: []validate.Kind{
+				validate.KindDuplicateFieldNumber,
+				validate.KindInvalidFieldNumber,
+				validate.KindImplementationReservedNumber,
+				validate.KindReservedNumber,
+				validate.KindReservedNumber,
+				validate.KindExtensionRangeNumber,
+				validate.KindDuplicateFieldNumber,
+			},
+			wantLines: []int{6, 7, 8, 8, 9, 10, 14},
+		},
+		{
+			name: "reporting the names",
+			input: `syntax = "proto2";
+message A {
+  reserved "r", "V";
+  optional int32 a = 1;
+  optional int32 r = 2;
+  message a {}
+  oneof o {
+    string o = 3;
+  }
+  optional group G = 4 {}
+  optional int32 g = 5;
+  enum E {
+    V = 0;
+  }
+  optional E E = 6;
+}
+message A {}
+enum F {
+  F0 = 0;
+  F0 = 1;
+}
+`,
+			wantKinds: []validate.Kind{
+				validate.KindReservedName,
+				validate.KindDuplicateName,
+				validate.KindDuplicateName,
+				validate.KindDuplicateName,
+				validate.KindDuplicateName,
+				validate.KindDuplicateName,
+				validate.KindDuplicateName,
+			},
+			wantLines: []int{5, 6, 8, 11, 15, 17, 20},
+		},
+		{
+			name: "reporting the enum values",
+			input: `syntax = "proto3";
+enum E {
+  reserved 5 to max;
+  reserved "E3";
+  E1 = 1;
+  E2 = 1;
+  E3 = 2;
+  E4 = 5;
+  E5 = -1;
+}
+`,
+			wantKinds: []validate.Kind{
+				validate.KindFirstEnumValueNotZero,
+				validate.KindDuplicateEnumNumber,
+				validate.KindReservedName,
+				validate.KindReservedNumber,
+			},
+			wantLines: []int{5, 6, 7, 8},
+		},
+		{
+			name: "reporting the statements not allowed in proto3",
+			input: `syntax = "proto3";
+message A {
+  required int32 a = 1;
+  int32 b = 2 [default = 1];
+  extensions 100 to 200;
+  group G = 3 {}
+}
+extend A {
+  required int32 c = 100;
+}
+`,
+			wantKinds: []validate.Kind{
+				validate.KindNotAllowedInProto3,
+				validate.KindNotAllowedInProto3,
+				validate.KindNotAllowedInProto3,
+				validate.KindNotAllowedInProto3,
+				validate.KindNotAllowedInProto3,
+			},
+			wantLines: []int{3, 4, 5, 6, 9},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			proto, err := protoparser.Parse(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			var kinds []validate.Kind
+			var lines []int
+			for _, d := range validate.Proto(proto) {
+				kinds = append(kinds, d.Kind)
+				lines = append(lines, d.Pos.Line)
+			}
+			if !reflect.DeepEqual(kinds, test.wantKinds) {
+				t.Errorf("got %v, but want %v", kinds, test.wantKinds)
+			}
+			if !reflect.DeepEqual(lines, test.wantLines) {
+				t.Errorf("got %v, but want %v", lines, test.wantLines)
+			}
+		})
+	}
+}